@@ -2,19 +2,34 @@ package main
 
 import (
 	"EffiPlat/backend/internal"                // Wire生成的依赖注入初始化函数
+	"EffiPlat/backend/internal/middleware"
+	"EffiPlat/backend/internal/pkg/auditsink"
 	"EffiPlat/backend/internal/pkg/config"
 	pkgdb "EffiPlat/backend/internal/pkg/database"
 	"EffiPlat/backend/internal/pkg/logger"
+	"EffiPlat/backend/internal/pkg/storage"
+	"EffiPlat/backend/internal/repository"
 	"EffiPlat/backend/internal/router"
+	"EffiPlat/backend/internal/service"
+	apputils "EffiPlat/backend/internal/utils"
 
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	// "github.com/gin-gonic/gin" // <-- Gin 初始化移到 router 包，这里可能不再需要
 	"go.uber.org/zap"
 )
 
+// shutdownTimeout bounds how long in-flight requests and queued audit logs get on shutdown.
+const shutdownTimeout = 15 * time.Second
+
 func main() {
 	// 1. Load Configuration
 	cfg, err := config.LoadConfig(".")
@@ -44,45 +59,94 @@ func main() {
 		jwtKey = []byte("default_insecure_secret_key_for_dev_only")
 	}
 
+	// Key for encrypting secret values in service instance configs.
+	// Changing it makes previously stored secrets unreadable.
+	configSecretKey := []byte(os.Getenv("CONFIG_SECRET_KEY"))
+	if len(configSecretKey) == 0 {
+		appLogger.Warn("CONFIG_SECRET_KEY not configured. Using default, which is insecure.")
+		configSecretKey = []byte("default_insecure_config_secret_key_for_dev_only")
+	}
+	secretCipher, err := apputils.NewSecretCipher(configSecretKey)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize config secret cipher", zap.Error(err))
+	}
+
+	// Optional HMAC key for the audit log hash chain. Without it the chain uses plain
+	// SHA-256, which detects edits but not a rewrite of the whole chain.
+	auditHashKey := repository.AuditHashKey(os.Getenv("AUDIT_HASH_KEY"))
+	if len(auditHashKey) == 0 {
+		appLogger.Warn("AUDIT_HASH_KEY not configured. Audit log hash chain is not keyed.")
+	}
+
 	// 5. Initialize Dependencies
+	// 写入数据库的审计日志同时转发到配置的外部接收端（如SIEM的syslog）
+	var auditSinks []*auditsink.Forwarder
+	for _, sinkCfg := range cfg.Audit.Sinks {
+		sink, err := auditsink.NewForwarder(sinkCfg, appLogger)
+		if err != nil {
+			appLogger.Fatal("Failed to initialize audit sink", zap.String("name", sinkCfg.Name), zap.Error(err))
+		}
+		auditSinks = append(auditSinks, sink)
+	}
+
+	// 所有审计日志共用一个写入管道，停机时写完队列中的日志
+	auditLogService, err := internal.InitializeAuditLogService(dbConn, auditHashKey, appLogger, service.AuditPipelineConfig{
+		QueueSize:      cfg.Audit.QueueSize,
+		BatchSize:      cfg.Audit.BatchSize,
+		FlushInterval:  cfg.Audit.FlushInterval,
+		EnqueueTimeout: cfg.Audit.EnqueueTimeout,
+		SpillFile:      cfg.Audit.SpillFile,
+		ReplayInterval: cfg.Audit.ReplayInterval,
+		Sinks:          auditSinks,
+	})
+	if err != nil {
+		appLogger.Fatal("Failed to initialize audit log service", zap.Error(err))
+	}
+
+	// Entity history is recorded by database callbacks, so register them before anything writes
+	entityHistoryHandler, err := internal.InitializeEntityHistoryHandler(dbConn, appLogger)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize entity history handler", zap.Error(err))
+	}
+
 	// Initialize Auth components using Wire
-	authHandler, err := internal.InitializeAuthHandler(dbConn, jwtKey, appLogger)
+	authHandler, err := internal.InitializeAuthHandler(dbConn, jwtKey, appLogger, auditLogService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize auth handler", zap.Error(err))
 	}
 
 	// Initialize User components using Wire
-	userHandler, err := internal.InitializeUserHandler(dbConn, appLogger)
+	userHandler, err := internal.InitializeUserHandler(dbConn, appLogger, auditLogService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize user handler", zap.Error(err))
 	}
 
 	// Initialize Role components using Wire
-	roleHandler, err := internal.InitializeRoleHandler(dbConn, appLogger)
+	roleHandler, err := internal.InitializeRoleHandler(dbConn, appLogger, auditLogService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize role handler", zap.Error(err))
 	}
 
 	// Initialize Permission components (assuming a similar Wire setup)
-	permissionHandler, err := internal.InitializePermissionHandler(dbConn, appLogger)
+	permissionHandler, err := internal.InitializePermissionHandler(dbConn, appLogger, auditLogService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize permission handler", zap.Error(err))
 	}
 
 	// Initialize Responsibility components (assuming a similar Wire setup)
-	responsibilityHandler, err := internal.InitializeResponsibilityHandler(dbConn, appLogger)
+	responsibilityHandler, err := internal.InitializeResponsibilityHandler(dbConn, appLogger, auditLogService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize responsibility handler", zap.Error(err))
 	}
 
 	// Initialize ResponsibilityGroup components (assuming a similar Wire setup)
-	responsibilityGroupHandler, err := internal.InitializeResponsibilityGroupHandler(dbConn, appLogger)
+	responsibilityGroupHandler, err := internal.InitializeResponsibilityGroupHandler(dbConn, appLogger, auditLogService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize responsibility group handler", zap.Error(err))
 	}
 
 	// Initialize Environment components
-	environmentHandler, err := internal.InitializeEnvironmentHandler(dbConn, appLogger)
+	environmentHandler, err := internal.InitializeEnvironmentHandler(dbConn, appLogger, auditLogService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize environment handler", zap.Error(err))
 	}
@@ -94,13 +158,13 @@ func main() {
 	}
 
 	// Initialize Asset components
-	assetHandler, err := internal.InitializeAssetHandler(dbConn, appLogger, environmentRepository)
+	assetHandler, err := internal.InitializeAssetHandler(dbConn, appLogger, environmentRepository, auditLogService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize asset handler", zap.Error(err))
 	}
 
 	// Initialize Service components
-	serviceHandler, err := internal.InitializeServiceHandler(dbConn, appLogger)
+	serviceHandler, err := internal.InitializeServiceHandler(dbConn, appLogger, auditLogService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize service handler", zap.Error(err))
 	}
@@ -112,11 +176,23 @@ func main() {
 	}
 
 	// Initialize ServiceInstance components using Wire
-	serviceInstanceHandler, err := internal.InitializeServiceInstanceHandler(dbConn, appLogger, serviceRepository, environmentRepository)
+	serviceInstanceHandler, err := internal.InitializeServiceInstanceHandler(dbConn, appLogger, serviceRepository, environmentRepository, secretCipher, auditLogService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize service instance handler", zap.Error(err))
 	}
 
+	// Initialize Deployment history components
+	deploymentHandler, err := internal.InitializeDeploymentHandler(dbConn, appLogger, environmentRepository, auditLogService)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize deployment handler", zap.Error(err))
+	}
+
+	// Initialize config revision components
+	configRevisionHandler, err := internal.InitializeConfigRevisionHandler(dbConn, appLogger, serviceRepository, environmentRepository, secretCipher, auditLogService)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize config revision handler", zap.Error(err))
+	}
+
 	// Initialize Business components
 	businessHandler, err := internal.InitializeBusinessHandler(dbConn, appLogger)
 	if err != nil {
@@ -124,22 +200,87 @@ func main() {
 	}
 
 	// Initialize Bug components
-	bugHandler, err := internal.InitializeBugHandler(dbConn, appLogger)
+	bugAssignmentService, err := internal.InitializeBugAssignmentService(dbConn, appLogger, serviceRepository, environmentRepository)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize bug assignment service", zap.Error(err))
+	}
+
+	bugSLAService, err := internal.InitializeBugSLAService(dbConn, appLogger)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize bug SLA service", zap.Error(err))
+	}
+
+	// Bug 状态流转图来自配置，未配置时使用内置流程；流程无效时拒绝启动
+	bugWorkflow, err := cfg.BugWorkflow.Workflow()
+	if err != nil {
+		appLogger.Fatal("Failed to load bug workflow", zap.Error(err))
+	}
+	bugHandler, err := internal.InitializeBugHandler(dbConn, appLogger, serviceRepository, environmentRepository, bugAssignmentService, bugSLAService, bugWorkflow, auditLogService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize bug handler", zap.Error(err))
 	}
-	
-	// Initialize Audit Log components
-	auditLogService, err := internal.InitializeAuditLogService(dbConn, appLogger)
+
+	bugAssignmentHandler, err := internal.InitializeBugAssignmentHandler(dbConn, appLogger, bugAssignmentService, auditLogService)
 	if err != nil {
-		appLogger.Fatal("Failed to initialize audit log service", zap.Error(err))
+		appLogger.Fatal("Failed to initialize bug assignment handler", zap.Error(err))
+	}
+
+	bugSLAHandler, err := internal.InitializeBugSLAHandler(dbConn, appLogger, bugSLAService, auditLogService)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize bug SLA handler", zap.Error(err))
+	}
+
+	// 后台定期检查 Bug SLA 违约并升级
+	// 收到 SIGINT/SIGTERM 时取消 ctx，停止后台任务并优雅停机
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.BugSLA.CheckInterval > 0 {
+		go bugSLAService.Run(ctx, cfg.BugSLA.CheckInterval)
+	}
+
+	// Initialize Attachment components
+	attachmentStore, err := storage.New(context.Background(), cfg.Attachments.Storage)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize attachment storage", zap.Error(err))
+	}
+	attachmentLimits := service.DefaultAttachmentLimits
+	if cfg.Attachments.MaxSizeMB > 0 {
+		attachmentLimits.MaxSize = cfg.Attachments.MaxSizeMB << 20
+	}
+	if len(cfg.Attachments.AllowedTypes) > 0 {
+		attachmentLimits.AllowedTypes = cfg.Attachments.AllowedTypes
+	}
+	attachmentHandler, err := internal.InitializeAttachmentHandler(dbConn, appLogger, serviceRepository, attachmentStore, attachmentLimits, auditLogService)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize attachment handler", zap.Error(err))
 	}
 	
-	auditLogHandler, err := internal.InitializeAuditLogHandler(dbConn, appLogger)
+	// 过期审计日志定期导出到归档文件，校验后从数据库清除
+	auditArchiveStore, err := storage.New(context.Background(), cfg.Audit.Retention.Storage)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize audit archive storage", zap.Error(err))
+	}
+	auditArchiveService, err := internal.InitializeAuditArchiveService(dbConn, auditHashKey, appLogger, auditArchiveStore, service.AuditRetentionPolicy{
+		Retention:            time.Duration(cfg.Audit.Retention.Days) * 24 * time.Hour,
+		MaxEntriesPerArchive: cfg.Audit.Retention.MaxEntriesPerArchive,
+	}, auditLogService)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize audit archive service", zap.Error(err))
+	}
+	if cfg.Audit.Retention.Days > 0 && cfg.Audit.Retention.CheckInterval > 0 {
+		go auditArchiveService.Run(ctx, cfg.Audit.Retention.CheckInterval)
+	}
+
+	// Initialize Audit Log handler
+	auditLogHandler, err := internal.InitializeAuditLogHandler(appLogger, auditLogService, auditArchiveService)
 	if err != nil {
 		appLogger.Fatal("Failed to initialize audit log handler", zap.Error(err))
 	}
 
+	// 敏感数据的读取按策略审计，未配置规则时使用默认策略
+	readAuditor := middleware.NewReadAuditor(auditLogService, cfg.Audit.Read, appLogger)
+
 	// 6. Setup Router
 	// SetupRouter expects *handler.AuthHandler and *handler.UserHandler (after UserHandler moves)
 	r := router.SetupRouter(
@@ -155,15 +296,42 @@ func main() {
 		serviceInstanceHandler,
 		businessHandler,
 		bugHandler,
+		bugAssignmentHandler,
+		bugSLAHandler,
+		attachmentHandler,
+		deploymentHandler,
+		configRevisionHandler,
+		entityHistoryHandler,
 		auditLogHandler,        // 添加审计日志处理器
 		auditLogService,        // 添加审计日志服务
+		readAuditor,
 		jwtKey,
 	)
 
 	// 7. Start Server
 	portStr := fmt.Sprintf(":%d", cfg.Server.Port)
 	appLogger.Info("Starting backend server", zap.String("address", "http://localhost"+portStr))
-	if err := r.Run(portStr); err != nil {
-		appLogger.Fatal("Failed to start server", zap.Error(err))
+	srv := &http.Server{Addr: portStr, Handler: r}
+	srv.RegisterOnShutdown(auditLogHandler.CloseStreams) // 审计日志实时流是长连接，停机时主动结束
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			appLogger.Fatal("Failed to start server", zap.Error(err))
+		}
+	}()
+
+	// 8. Graceful Shutdown
+	<-ctx.Done()
+	stop()
+	appLogger.Info("Shutting down backend server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		appLogger.Error("Server did not shut down cleanly", zap.Error(err))
+	}
+	readAuditor.Flush() // 写入尚未结束的重复读取汇总
+	// 请求全部结束后再写完审计日志；超时未写入的日志已落盘，下次启动时重放
+	if err := auditLogService.Close(shutdownCtx); err != nil {
+		appLogger.Error("Audit logs not fully written before shutdown", zap.Error(err))
 	}
+	appLogger.Info("Backend server stopped")
 }
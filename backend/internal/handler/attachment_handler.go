@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/service"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// attachmentFormField is the multipart field carrying the uploaded file.
+const attachmentFormField = "file"
+
+// AttachmentHandler handles HTTP requests for attachments on bugs, businesses and services.
+type AttachmentHandler struct {
+	svc          service.AttachmentService
+	auditService service.AuditLogService
+	logger       *zap.Logger
+}
+
+// NewAttachmentHandler creates a new AttachmentHandler.
+func NewAttachmentHandler(svc service.AttachmentService, auditSvc service.AuditLogService, logger *zap.Logger) *AttachmentHandler {
+	return &AttachmentHandler{
+		svc:          svc,
+		auditService: auditSvc,
+		logger:       logger,
+	}
+}
+
+// UploadAttachment returns a handler uploading a file to the owner named by the idParam path parameter.
+// The request is multipart/form-data with the file in the "file" field; it is streamed to the
+// service rather than buffered in memory.
+// POST /bugs/:id/attachments, /businesses/:businessId/attachments, /services/:id/attachments
+func (h *AttachmentHandler) UploadAttachment(ownerType model.AttachmentOwnerType, idParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, ok := parseOwnerID(c, ownerType, idParam)
+		if !ok {
+			return
+		}
+
+		// Leave room for the multipart framing around the file itself.
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.svc.Limits().MaxSize+1<<20)
+		reader, err := c.Request.MultipartReader()
+		if err != nil {
+			apputils.SendErrorResponse(c, http.StatusBadRequest, "Expected a multipart/form-data upload")
+			return
+		}
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Missing '%s' field", attachmentFormField))
+				return
+			}
+			if err != nil {
+				h.handleError(c, fmt.Errorf("%w: invalid multipart body: %v", apputils.ErrBadRequest, err))
+				return
+			}
+			if part.FormName() != attachmentFormField || part.FileName() == "" {
+				part.Close()
+				continue
+			}
+
+			attachment, created, err := h.svc.Upload(c.Request.Context(), ownerType, ownerID, part.FileName(), part)
+			part.Close()
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					err = fmt.Errorf("%w: the limit is %d bytes", model.ErrAttachmentTooLarge, h.svc.Limits().MaxSize)
+				}
+				h.handleError(c, err)
+				return
+			}
+			if !created {
+				apputils.SendSuccessResponse(c, http.StatusOK, attachment, "File already attached")
+				return
+			}
+
+			// 记录审计日志
+			details := map[string]interface{}{
+				"ownerType":   attachment.OwnerType,
+				"ownerId":     attachment.OwnerID,
+				"fileName":    attachment.FileName,
+				"contentType": attachment.ContentType,
+				"size":        attachment.Size,
+				"sha256":      attachment.SHA256,
+			}
+			_ = h.auditService.LogUserAction(c, string(apputils.AuditActionCreate), "ATTACHMENT", attachment.ID, details)
+
+			apputils.SendSuccessResponse(c, http.StatusCreated, attachment)
+			return
+		}
+	}
+}
+
+// ListAttachments returns a handler listing the attachments of the owner named by the idParam path parameter.
+// GET /bugs/:id/attachments, /businesses/:businessId/attachments, /services/:id/attachments
+func (h *AttachmentHandler) ListAttachments(ownerType model.AttachmentOwnerType, idParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, ok := parseOwnerID(c, ownerType, idParam)
+		if !ok {
+			return
+		}
+
+		attachments, err := h.svc.List(c.Request.Context(), ownerType, ownerID)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+
+		apputils.SendSuccessResponse(c, http.StatusOK, attachments)
+	}
+}
+
+// GetAttachment handles getting an attachment's metadata.
+// GET /attachments/:id
+func (h *AttachmentHandler) GetAttachment(c *gin.Context) {
+	id, ok := parseAttachmentID(c)
+	if !ok {
+		return
+	}
+
+	attachment, err := h.svc.Get(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	apputils.SendSuccessResponse(c, http.StatusOK, attachment)
+}
+
+// DownloadAttachment streams an attachment's content. It is always served as a download,
+// never rendered inline, so uploaded HTML or SVG cannot run in the application's origin.
+// GET /attachments/:id/download
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	id, ok := parseAttachmentID(c)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.svc.Open(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	defer content.Close()
+
+	headers := map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+		"ETag":                   `"` + attachment.SHA256 + `"`,
+	}
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, headers)
+}
+
+// DeleteAttachment handles deleting an attachment.
+// DELETE /attachments/:id
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	id, ok := parseAttachmentID(c)
+	if !ok {
+		return
+	}
+
+	attachment, err := h.svc.Delete(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// 记录审计日志
+	details := map[string]interface{}{
+		"ownerType": attachment.OwnerType,
+		"ownerId":   attachment.OwnerID,
+		"fileName":  attachment.FileName,
+		"sha256":    attachment.SHA256,
+	}
+	_ = h.auditService.LogUserAction(c, string(apputils.AuditActionDelete), "ATTACHMENT", attachment.ID, details)
+
+	apputils.SendSuccessResponse(c, http.StatusNoContent, nil)
+}
+
+func parseOwnerID(c *gin.Context, ownerType model.AttachmentOwnerType, idParam string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(idParam), 10, 32)
+	if err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid %s ID format", ownerType))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func parseAttachmentID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, "Invalid attachment ID format")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *AttachmentHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrAttachmentNotFound):
+		apputils.SendErrorResponse(c, http.StatusNotFound, "Attachment not found")
+	case errors.Is(err, apputils.ErrNotFound):
+		apputils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, model.ErrAttachmentTooLarge):
+		apputils.SendErrorResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, model.ErrAttachmentTypeNotAllowed):
+		apputils.SendErrorResponse(c, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, apputils.ErrBadRequest):
+		apputils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, apputils.ErrUnauthorized):
+		apputils.SendErrorResponse(c, http.StatusUnauthorized, "Authentication required")
+	case errors.Is(err, apputils.ErrForbidden):
+		apputils.SendErrorResponse(c, http.StatusForbidden, err.Error())
+	default:
+		h.logger.Error("Attachment operation failed", zap.Error(err))
+		apputils.SendErrorResponse(c, http.StatusInternalServerError, "Attachment operation failed")
+	}
+}
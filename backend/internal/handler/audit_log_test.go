@@ -3,11 +3,13 @@ package handler_test
 import (
 	"EffiPlat/backend/internal/handler"
 	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/service"
 	"EffiPlat/backend/internal/utils"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http/httptest"
 	"testing"
 
@@ -85,6 +87,54 @@ func (m *mockAuditLogService) LogUserAction(c *gin.Context, action, resource str
 	return nil
 }
 
+// LogFailedAction 实现AuditLogService接口
+func (m *mockAuditLogService) LogFailedAction(c *gin.Context, action, resource string, resourceID uint, outcome model.AuditOutcome, reason string, details interface{}) error {
+	return nil
+}
+
+// Record 实现AuditLogService接口
+func (m *mockAuditLogService) Record(ctx context.Context, entry *model.AuditLog) {}
+
+// PipelineStats 实现AuditLogService接口
+func (m *mockAuditLogService) PipelineStats() model.AuditPipelineStats {
+	return model.AuditPipelineStats{}
+}
+
+// FindLogPage 实现AuditLogService接口
+func (m *mockAuditLogService) FindLogPage(ctx context.Context, params model.AuditLogQueryParams, cursor string) ([]model.AuditLog, string, error) {
+	return []model.AuditLog{}, "", nil
+}
+
+// ExportLogs 实现AuditLogService接口
+func (m *mockAuditLogService) ExportLogs(ctx context.Context, params model.AuditLogQueryParams, format service.AuditExportFormat, w io.Writer) (int64, error) {
+	return 0, nil
+}
+
+// FindChanges 实现AuditLogService接口
+func (m *mockAuditLogService) FindChanges(ctx context.Context, params model.AuditLogChangeQueryParams) ([]model.AuditLogChange, int64, error) {
+	return []model.AuditLogChange{}, 0, nil
+}
+
+// OpenStream 实现AuditLogService接口
+func (m *mockAuditLogService) OpenStream(ctx context.Context, params model.AuditLogQueryParams, lastEventID *uint) (*service.AuditLogStream, error) {
+	return nil, utils.ErrForbidden
+}
+
+// VerifyChain 实现AuditLogService接口
+func (m *mockAuditLogService) VerifyChain(ctx context.Context) (*model.AuditChainVerification, error) {
+	return &model.AuditChainVerification{Valid: true}, nil
+}
+
+// Flush 实现AuditLogService接口
+func (m *mockAuditLogService) Flush(ctx context.Context) error {
+	return nil
+}
+
+// Close 实现AuditLogService接口
+func (m *mockAuditLogService) Close(ctx context.Context) error {
+	return nil
+}
+
 // 创建模拟审计日志服务
 func setupMockAuditLogService(t *testing.T) (*mockAuditLogService, *gomock.Controller) {
 	ctrl := gomock.NewController(t)
@@ -399,3 +449,15 @@ func (m *mockResponsibilityGroupService) RemoveResponsibilityFromGroup(ctx conte
 	}
 	return fmt.Errorf("not implemented")
 }
+
+func (m *mockResponsibilityGroupService) ListGroupMembers(ctx context.Context, groupID uint) ([]model.ResponsibilityGroupMember, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockResponsibilityGroupService) SetGroupMember(ctx context.Context, groupID, userID uint, isPrimary, isBackup bool) (*model.ResponsibilityGroupMember, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockResponsibilityGroupService) RemoveGroupMember(ctx context.Context, groupID, userID uint) error {
+	return fmt.Errorf("not implemented")
+}
@@ -58,5 +58,6 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 // Server-side action (like blacklisting) is optional and more complex.
 func (h *AuthHandler) Logout(c *gin.Context) {
 	// Optionally: Add token to a blacklist here if implementing server-side invalidation.
+	h.authService.Logout(c.Request.Context())
 	RespondWithSuccess(c, http.StatusOK, "Logout successful", nil)
 }
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"EffiPlat/backend/internal/service"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BugAssignmentHandler handles HTTP requests for bug assignment rules.
+type BugAssignmentHandler struct {
+	svc          service.BugAssignmentService
+	auditService service.AuditLogService
+	logger       *zap.Logger
+}
+
+// NewBugAssignmentHandler creates a new BugAssignmentHandler.
+func NewBugAssignmentHandler(svc service.BugAssignmentService, auditSvc service.AuditLogService, logger *zap.Logger) *BugAssignmentHandler {
+	return &BugAssignmentHandler{
+		svc:          svc,
+		auditService: auditSvc,
+		logger:       logger,
+	}
+}
+
+func parseAssignmentRuleID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, "Invalid assignment rule ID format")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *BugAssignmentHandler) handleError(c *gin.Context, err error, fallbackMsg string) {
+	h.logger.Error(fallbackMsg, zap.Error(err))
+	switch {
+	case errors.Is(err, apputils.ErrNotFound):
+		apputils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, apputils.ErrBadRequest):
+		apputils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		apputils.SendErrorResponse(c, http.StatusInternalServerError, fallbackMsg)
+	}
+}
+
+// CreateRule handles creating an assignment rule.
+// POST /bug-assignment-rules
+func (h *BugAssignmentHandler) CreateRule(c *gin.Context) {
+	var input service.BugAssignmentRuleInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid request payload: %v", err))
+		return
+	}
+
+	rule, err := h.svc.CreateRule(c.Request.Context(), &input)
+	if err != nil {
+		h.handleError(c, err, "Failed to create assignment rule")
+		return
+	}
+
+	// 记录审计日志
+	details := map[string]interface{}{
+		"name":                  rule.Name,
+		"priority":              rule.Priority,
+		"responsibilityGroupId": rule.ResponsibilityGroupID,
+		"strategy":              rule.Strategy,
+	}
+	_ = h.auditService.LogUserAction(c, string(apputils.AuditActionCreate), "BUG_ASSIGNMENT_RULE", rule.ID, details)
+
+	apputils.SendSuccessResponse(c, http.StatusCreated, rule)
+}
+
+// ListRules handles listing assignment rules in evaluation order.
+// GET /bug-assignment-rules
+func (h *BugAssignmentHandler) ListRules(c *gin.Context) {
+	rules, err := h.svc.ListRules(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, "Failed to list assignment rules")
+		return
+	}
+	apputils.SendSuccessResponse(c, http.StatusOK, rules)
+}
+
+// GetRule handles retrieving one assignment rule.
+// GET /bug-assignment-rules/:id
+func (h *BugAssignmentHandler) GetRule(c *gin.Context) {
+	id, ok := parseAssignmentRuleID(c)
+	if !ok {
+		return
+	}
+	rule, err := h.svc.GetRule(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to get assignment rule")
+		return
+	}
+	apputils.SendSuccessResponse(c, http.StatusOK, rule)
+}
+
+// UpdateRule handles replacing an assignment rule.
+// PUT /bug-assignment-rules/:id
+func (h *BugAssignmentHandler) UpdateRule(c *gin.Context) {
+	id, ok := parseAssignmentRuleID(c)
+	if !ok {
+		return
+	}
+	var input service.BugAssignmentRuleInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid request payload: %v", err))
+		return
+	}
+
+	rule, err := h.svc.UpdateRule(c.Request.Context(), id, &input)
+	if err != nil {
+		h.handleError(c, err, "Failed to update assignment rule")
+		return
+	}
+
+	// 记录审计日志
+	details := map[string]interface{}{
+		"name":                  rule.Name,
+		"priority":              rule.Priority,
+		"enabled":               rule.Enabled,
+		"responsibilityGroupId": rule.ResponsibilityGroupID,
+		"strategy":              rule.Strategy,
+	}
+	_ = h.auditService.LogUserAction(c, string(apputils.AuditActionUpdate), "BUG_ASSIGNMENT_RULE", rule.ID, details)
+
+	apputils.SendSuccessResponse(c, http.StatusOK, rule)
+}
+
+// DeleteRule handles deleting an assignment rule.
+// DELETE /bug-assignment-rules/:id
+func (h *BugAssignmentHandler) DeleteRule(c *gin.Context) {
+	id, ok := parseAssignmentRuleID(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteRule(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "Failed to delete assignment rule")
+		return
+	}
+
+	// 记录审计日志
+	details := map[string]interface{}{"deletedRuleId": id}
+	_ = h.auditService.LogUserAction(c, string(apputils.AuditActionDelete), "BUG_ASSIGNMENT_RULE", id, details)
+
+	c.Status(http.StatusNoContent)
+}
+
+// DryRun handles evaluating the rules for a hypothetical bug without assigning anything.
+// POST /bug-assignment-rules/dry-run
+func (h *BugAssignmentHandler) DryRun(c *gin.Context) {
+	var input service.BugAssignmentDryRunInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid request payload: %v", err))
+		return
+	}
+
+	result, err := h.svc.DryRun(c.Request.Context(), &input)
+	if err != nil {
+		h.handleError(c, err, "Failed to evaluate assignment rules")
+		return
+	}
+	apputils.SendSuccessResponse(c, http.StatusOK, result)
+}
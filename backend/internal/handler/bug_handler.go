@@ -16,15 +16,50 @@ import (
 
 // BugHandler handles HTTP requests for bugs.
 type BugHandler struct {
-	bugService service.BugService
-	validate   *validator.Validate
+	bugService   service.BugService
+	auditService service.AuditLogService
+	validate     *validator.Validate
 }
 
 // NewBugHandler creates a new BugHandler instance.
-func NewBugHandler(bugService service.BugService) *BugHandler {
+func NewBugHandler(bugService service.BugService, auditService service.AuditLogService) *BugHandler {
 	return &BugHandler{
-		bugService: bugService,
-		validate:   validator.New(),
+		bugService:   bugService,
+		auditService: auditService,
+		validate:     validator.New(),
+	}
+}
+
+// sendBugServiceError maps bug service errors to HTTP responses.
+func sendBugServiceError(c *gin.Context, action string, err error) {
+	var duplicates *service.BugDuplicatesError
+	var bulkFailure *service.BulkBugUpdateError
+	switch {
+	case errors.As(err, &duplicates):
+		c.JSON(http.StatusConflict, model.ErrorResponse{Code: http.StatusConflict, Message: err.Error(), Data: duplicates.Candidates})
+	case errors.As(err, &bulkFailure):
+		c.JSON(http.StatusConflict, model.ErrorResponse{Code: http.StatusConflict, Message: err.Error(), Data: bulkFailure.Response})
+	case errors.Is(err, repository.ErrBugNotFound):
+		utils.SendErrorResponse(c, http.StatusNotFound, "Bug not found.")
+	case errors.Is(err, repository.ErrBugSnapshotNotFound):
+		utils.SendErrorResponse(c, http.StatusNotFound, "Bug has no environment snapshot.")
+	case errors.Is(err, repository.ErrBugCommentNotFound):
+		utils.SendErrorResponse(c, http.StatusNotFound, "Comment not found.")
+	case errors.Is(err, repository.ErrBugRelationNotFound):
+		utils.SendErrorResponse(c, http.StatusNotFound, "Relation not found.")
+	case errors.Is(err, utils.ErrForbidden):
+		utils.SendErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, model.ErrInvalidBugStatusTransition),
+		errors.Is(err, model.ErrBugRelationExists),
+		errors.Is(err, model.ErrBugRelationCycle):
+		utils.SendErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, model.ErrInvalidBugStatus),
+		errors.Is(err, model.ErrBugResolutionRequired),
+		errors.Is(err, model.ErrBugCommentRequired),
+		errors.Is(err, utils.ErrBadRequest):
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to "+action+": "+err.Error())
 	}
 }
 
@@ -36,7 +71,8 @@ func NewBugHandler(bugService service.BugService) *BugHandler {
 // @Produce  json
 // @Param   bug_request body model.CreateBugRequest true "Create Bug Request"
 // @Success 201 {object} model.BugResponse
-// @Failure 400 {object} model.ErrorResponse "Invalid input"
+// @Failure 400 {object} model.ErrorResponse "Invalid input or unknown linked environment/business/service"
+// @Failure 409 {object} model.ErrorResponse "Status is not the workflow's initial status, or likely duplicates exist (listed in data) when checkDuplicates is set"
 // @Failure 500 {object} model.ErrorResponse "Internal server error"
 // @Router /bugs [post]
 func (h *BugHandler) CreateBug(c *gin.Context) {
@@ -54,7 +90,7 @@ func (h *BugHandler) CreateBug(c *gin.Context) {
 	bugResp, err := h.bugService.CreateBug(c.Request.Context(), &req)
 	if err != nil {
 		// TODO: Differentiate between client errors (e.g., duplicate) and server errors
-		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create bug: "+err.Error())
+		sendBugServiceError(c, "create bug", err)
 		return
 	}
 
@@ -96,15 +132,17 @@ func (h *BugHandler) GetBugByID(c *gin.Context) {
 
 // UpdateBug godoc
 // @Summary Update an existing bug
-// @Description Update details of an existing bug by its ID
+// @Description Update details of an existing bug by its ID. Status changes must follow the bug workflow;
+// @Description entering RESOLVED or CLOSED needs a resolution and a comment.
 // @Tags bugs
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Bug ID"
 // @Param   bug_request body model.UpdateBugRequest true "Update Bug Request"
 // @Success 200 {object} model.BugResponse
-// @Failure 400 {object} model.ErrorResponse "Invalid ID format or input"
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format or input, missing resolution or comment"
 // @Failure 404 {object} model.ErrorResponse "Bug not found"
+// @Failure 409 {object} model.ErrorResponse "Status transition not allowed by the workflow"
 // @Failure 500 {object} model.ErrorResponse "Internal server error"
 // @Router /bugs/{id} [put]
 func (h *BugHandler) UpdateBug(c *gin.Context) {
@@ -128,19 +166,68 @@ func (h *BugHandler) UpdateBug(c *gin.Context) {
 		return
 	}
 
+	// 保存修改前的状态，审计日志据此记录字段级变更
+	before, _ := h.bugService.GetBugByID(c.Request.Context(), uint(id))
+
 	bugResp, err := h.bugService.UpdateBug(c.Request.Context(), uint(id), &req)
 	if err != nil {
-		if errors.Is(err, repository.ErrBugNotFound) {
-			utils.SendErrorResponse(c, http.StatusNotFound, "Bug not found.")
-		} else {
-			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to update bug: "+err.Error())
-		}
+		sendBugServiceError(c, "update bug", err)
 		return
 	}
+	if before != nil {
+		utils.SetAuditDetails(c, utils.NewUpdateAuditLog(before, bugResp))
+	}
 
 	c.JSON(http.StatusOK, bugResp)
 }
 
+// BulkUpdateBugs godoc
+// @Summary Update many bugs at once
+// @Description Apply a status, priority, assignee or label change to the bugs given by ID or matching a filter (the fields of the bug list query), at most 500. Each bug is checked against the workflow. With atomic set, nothing is saved unless every bug can be updated; otherwise each bug is updated on its own and the results tell which failed.
+// @Tags bugs
+// @Accept json
+// @Produce json
+// @Param bulk_request body model.BulkBugUpdateRequest true "Bulk Update Request"
+// @Success 200 {object} model.BulkBugUpdateResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid input, no changes, both or neither of ids and filter, or the filter matches too many bugs"
+// @Failure 409 {object} model.ErrorResponse "Atomic update not applied; the per-bug results are in data"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/bulk [post]
+func (h *BugHandler) BulkUpdateBugs(c *gin.Context) {
+	var req model.BulkBugUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Validation failed: "+err.Error())
+		return
+	}
+
+	resp, err := h.bugService.BulkUpdateBugs(c.Request.Context(), &req)
+	if err != nil {
+		sendBugServiceError(c, "update bugs", err)
+		return
+	}
+
+	// 每个被修改的 Bug 单独记录一条审计日志
+	for _, result := range resp.Results {
+		if result.Status != model.BulkBugUpdated {
+			continue
+		}
+		details := map[string]interface{}{
+			"bulk":    true,
+			"changes": result.Changes,
+		}
+		if result.Transition != nil {
+			details["transition"] = result.Transition
+		}
+		_ = h.auditService.LogUserAction(c, string(utils.AuditActionUpdate), "BUG", result.ID, details)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // DeleteBug godoc
 // @Summary Delete a bug by ID
 // @Description Delete a bug by its ID
@@ -180,10 +267,14 @@ func (h *BugHandler) DeleteBug(c *gin.Context) {
 // @Param page query int false "Page number (default: 1)"
 // @Param pageSize query int false "Number of items per page (default: 10)"
 // @Param title query string false "Filter by title (substring match)"
-// @Param status query string false "Filter by status (e.g., OPEN, RESOLVED)" Enums(OPEN, IN_PROGRESS, RESOLVED, CLOSED, REOPENED)
+// @Param status query string false "Filter by status (e.g., OPEN, RESOLVED)" Enums(OPEN, IN_PROGRESS, RESOLVED, VERIFIED, CLOSED, REOPENED)
 // @Param priority query string false "Filter by priority (e.g., LOW, HIGH)" Enums(LOW, MEDIUM, HIGH, URGENT)
 // @Param assigneeId query int false "Filter by assignee ID"
 // @Param reporterId query int false "Filter by reporter ID"
+// @Param environmentId query int false "Filter by environment ID"
+// @Param businessId query int false "Filter by business ID"
+// @Param serviceId query int false "Filter by service ID"
+// @Param serviceVersion query string false "Filter by service version"
 // @Success 200 {object} model.SuccessResponse{data=model.PaginatedData{items=[]model.BugResponse}} "List of bugs"
 // @Failure 400 {object} model.ErrorResponse "Invalid query parameters"
 // @Failure 500 {object} model.ErrorResponse "Internal server error"
@@ -215,3 +306,473 @@ func (h *BugHandler) ListBugs(c *gin.Context) {
 	// Use the SendPaginatedSuccessResponse helper from utils package
 	utils.SendPaginatedSuccessResponse(c, http.StatusOK, "Bugs listed successfully", bugs, params.Page, params.PageSize, totalCount)
 }
+
+// GetBugStats godoc
+// @Summary Get bug statistics
+// @Description Get the bugs created in a time window grouped by status, priority, assignee, environment or service, the age of the open bugs, the mean time to resolve and a created-vs-closed trend per day or week
+// @Tags bugs
+// @Produce json
+// @Param from query string false "Window start (RFC3339), defaults to 30 days before to"
+// @Param to query string false "Window end (RFC3339), defaults to now"
+// @Param groupBy query string false "status (default), priority, assignee, environment or service"
+// @Param interval query string false "Trend period: day (default) or week"
+// @Success 200 {object} model.BugStatsResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid query parameters"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/stats [get]
+func (h *BugHandler) GetBugStats(c *gin.Context) {
+	var params model.BugStatsParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	stats, err := h.bugService.GetBugStats(c.Request.Context(), &params)
+	if err != nil {
+		sendBugServiceError(c, "get bug statistics", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetBugWorkflow godoc
+// @Summary Get the bug status workflow
+// @Description Get the status transition graph bugs follow
+// @Tags bugs
+// @Produce json
+// @Success 200 {object} model.BugWorkflow
+// @Router /bugs/workflow [get]
+func (h *BugHandler) GetBugWorkflow(c *gin.Context) {
+	c.JSON(http.StatusOK, h.bugService.GetWorkflow())
+}
+
+// ListBugTransitions godoc
+// @Summary List status transitions of a bug
+// @Description Get the status history of a bug, oldest first, with the actor and comment of each change
+// @Tags bugs
+// @Produce json
+// @Param id path int true "Bug ID"
+// @Success 200 {array} model.BugStatusTransition
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} model.ErrorResponse "Bug not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/transitions [get]
+func (h *BugHandler) ListBugTransitions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid bug ID format.")
+		return
+	}
+
+	transitions, err := h.bugService.ListBugTransitions(c.Request.Context(), uint(id))
+	if err != nil {
+		sendBugServiceError(c, "list bug transitions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transitions)
+}
+
+// GetBugSnapshot godoc
+// @Summary Get the environment snapshot of a bug
+// @Description Get the service instances (service, version, status, masked config) of the bug's environment as they were when the bug was filed
+// @Tags bugs
+// @Produce json
+// @Param id path int true "Bug ID"
+// @Success 200 {object} model.BugEnvironmentSnapshot
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} model.ErrorResponse "Bug or snapshot not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/snapshot [get]
+func (h *BugHandler) GetBugSnapshot(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid bug ID format.")
+		return
+	}
+
+	snapshot, err := h.bugService.GetBugSnapshot(c.Request.Context(), uint(id))
+	if err != nil {
+		sendBugServiceError(c, "get bug snapshot", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// DiffBugSnapshot godoc
+// @Summary Diff a bug's environment snapshot against the current environment
+// @Description List service instances added, removed or changed since the bug was filed
+// @Tags bugs
+// @Produce json
+// @Param id path int true "Bug ID"
+// @Success 200 {object} service.BugSnapshotDiffDTO
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} model.ErrorResponse "Bug or snapshot not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/snapshot/diff [get]
+func (h *BugHandler) DiffBugSnapshot(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid bug ID format.")
+		return
+	}
+
+	diff, err := h.bugService.DiffBugSnapshot(c.Request.Context(), uint(id))
+	if err != nil {
+		sendBugServiceError(c, "diff bug snapshot", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// parseBugCommentIDs reads the bug and comment IDs from the path, answering 400 when either is malformed.
+func parseBugCommentIDs(c *gin.Context) (uint, uint, bool) {
+	bugID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid bug ID format.")
+		return 0, 0, false
+	}
+	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid comment ID format.")
+		return 0, 0, false
+	}
+	return uint(bugID), uint(commentID), true
+}
+
+// AddBugComment godoc
+// @Summary Comment on a bug
+// @Description Add a Markdown comment to a bug. Users @mentioned by e-mail or by the part of their e-mail before "@" are linked to the comment.
+// @Tags bugs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Bug ID"
+// @Param   comment_request body model.CreateBugCommentRequest true "Create Comment Request"
+// @Success 201 {object} model.BugCommentResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format or input"
+// @Failure 404 {object} model.ErrorResponse "Bug not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/comments [post]
+func (h *BugHandler) AddBugComment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid bug ID format.")
+		return
+	}
+
+	var req model.CreateBugCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Validation failed: "+err.Error())
+		return
+	}
+
+	comment, err := h.bugService.AddBugComment(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		sendBugServiceError(c, "add bug comment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// ListBugComments godoc
+// @Summary List comments on a bug
+// @Description Get the comments of a bug that have not been deleted, oldest first
+// @Tags bugs
+// @Produce json
+// @Param id path int true "Bug ID"
+// @Success 200 {array} model.BugCommentResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} model.ErrorResponse "Bug not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/comments [get]
+func (h *BugHandler) ListBugComments(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid bug ID format.")
+		return
+	}
+
+	comments, err := h.bugService.ListBugComments(c.Request.Context(), uint(id))
+	if err != nil {
+		sendBugServiceError(c, "list bug comments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// UpdateBugComment godoc
+// @Summary Edit a comment
+// @Description Replace the body of a comment. The previous body is kept as a revision. Only the author may edit a comment.
+// @Tags bugs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Bug ID"
+// @Param commentId path int true "Comment ID"
+// @Param   comment_request body model.UpdateBugCommentRequest true "Update Comment Request"
+// @Success 200 {object} model.BugCommentResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format or input"
+// @Failure 403 {object} model.ErrorResponse "Not the author of the comment"
+// @Failure 404 {object} model.ErrorResponse "Comment not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/comments/{commentId} [put]
+func (h *BugHandler) UpdateBugComment(c *gin.Context) {
+	bugID, commentID, ok := parseBugCommentIDs(c)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateBugCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Validation failed: "+err.Error())
+		return
+	}
+
+	comment, err := h.bugService.UpdateBugComment(c.Request.Context(), bugID, commentID, &req)
+	if err != nil {
+		sendBugServiceError(c, "update bug comment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteBugComment godoc
+// @Summary Delete a comment
+// @Description Delete a comment. It stays in the bug's activity feed without its body. Only the author may delete a comment.
+// @Tags bugs
+// @Produce json
+// @Param id path int true "Bug ID"
+// @Param commentId path int true "Comment ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format"
+// @Failure 403 {object} model.ErrorResponse "Not the author of the comment"
+// @Failure 404 {object} model.ErrorResponse "Comment not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/comments/{commentId} [delete]
+func (h *BugHandler) DeleteBugComment(c *gin.Context) {
+	bugID, commentID, ok := parseBugCommentIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.bugService.DeleteBugComment(c.Request.Context(), bugID, commentID); err != nil {
+		sendBugServiceError(c, "delete bug comment", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListBugCommentRevisions godoc
+// @Summary List the edit history of a comment
+// @Description Get the bodies a comment had before each edit, oldest first
+// @Tags bugs
+// @Produce json
+// @Param id path int true "Bug ID"
+// @Param commentId path int true "Comment ID"
+// @Success 200 {array} model.BugCommentRevision
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} model.ErrorResponse "Comment not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/comments/{commentId}/revisions [get]
+func (h *BugHandler) ListBugCommentRevisions(c *gin.Context) {
+	bugID, commentID, ok := parseBugCommentIDs(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.bugService.ListBugCommentRevisions(c.Request.Context(), bugID, commentID)
+	if err != nil {
+		sendBugServiceError(c, "list comment revisions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// GetBugActivity godoc
+// @Summary Get the activity feed of a bug
+// @Description Get the comments, field changes and status transitions of a bug as one feed, oldest first
+// @Tags bugs
+// @Produce json
+// @Param id path int true "Bug ID"
+// @Success 200 {array} model.BugActivityItem
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} model.ErrorResponse "Bug not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/activity [get]
+func (h *BugHandler) GetBugActivity(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid bug ID format.")
+		return
+	}
+
+	activity, err := h.bugService.GetBugActivity(c.Request.Context(), uint(id))
+	if err != nil {
+		sendBugServiceError(c, "get bug activity", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, activity)
+}
+
+// parseBugRelationIDs reads the bug and relation IDs from the path, answering 400 when either is malformed.
+func parseBugRelationIDs(c *gin.Context) (uint, uint, bool) {
+	bugID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid bug ID format.")
+		return 0, 0, false
+	}
+	relationID, err := strconv.ParseUint(c.Param("relationId"), 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid relation ID format.")
+		return 0, 0, false
+	}
+	return uint(bugID), uint(relationID), true
+}
+
+// AddBugRelation godoc
+// @Summary Relate a bug to another bug
+// @Description Mark a bug as a duplicate of, blocking, or related to another bug. A duplicate is linked to the original bug and closed; relations that would make bugs block each other in a cycle are rejected.
+// @Tags bugs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Bug ID"
+// @Param   relation_request body model.CreateBugRelationRequest true "Create Relation Request"
+// @Success 201 {object} model.BugRelationResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format or input, or a relation to the bug itself"
+// @Failure 404 {object} model.ErrorResponse "Bug not found"
+// @Failure 409 {object} model.ErrorResponse "Relation exists already or would create a cycle"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/relations [post]
+func (h *BugHandler) AddBugRelation(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid bug ID format.")
+		return
+	}
+
+	var req model.CreateBugRelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Validation failed: "+err.Error())
+		return
+	}
+
+	relation, err := h.bugService.AddBugRelation(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		sendBugServiceError(c, "add bug relation", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, relation)
+}
+
+// ListBugRelations godoc
+// @Summary List the relations of a bug
+// @Description Get the relations a bug is the source (outward) or target (inward) of, oldest first
+// @Tags bugs
+// @Produce json
+// @Param id path int true "Bug ID"
+// @Success 200 {array} model.BugRelationResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} model.ErrorResponse "Bug not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/relations [get]
+func (h *BugHandler) ListBugRelations(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid bug ID format.")
+		return
+	}
+
+	relations, err := h.bugService.ListBugRelations(c.Request.Context(), uint(id))
+	if err != nil {
+		sendBugServiceError(c, "list bug relations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, relations)
+}
+
+// DeleteBugRelation godoc
+// @Summary Remove a relation of a bug
+// @Description Remove a relation the bug is the source or target of. A bug closed as a duplicate stays closed.
+// @Tags bugs
+// @Produce json
+// @Param id path int true "Bug ID"
+// @Param relationId path int true "Relation ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} model.ErrorResponse "Bug or relation not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/{id}/relations/{relationId} [delete]
+func (h *BugHandler) DeleteBugRelation(c *gin.Context) {
+	bugID, relationID, ok := parseBugRelationIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.bugService.DeleteBugRelation(c.Request.Context(), bugID, relationID); err != nil {
+		sendBugServiceError(c, "delete bug relation", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// FindSimilarBugs godoc
+// @Summary Find likely duplicates of a new bug
+// @Description Score existing bugs by the words their title and description share with the given ones and return the likely duplicates, best match first
+// @Tags bugs
+// @Accept  json
+// @Produce  json
+// @Param   similar_request body model.FindSimilarBugsRequest true "Find Similar Bugs Request"
+// @Success 200 {array} model.BugSimilarityResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid input"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /bugs/similar [post]
+func (h *BugHandler) FindSimilarBugs(c *gin.Context) {
+	var req model.FindSimilarBugsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Validation failed: "+err.Error())
+		return
+	}
+
+	similar, err := h.bugService.FindSimilarBugs(c.Request.Context(), &req)
+	if err != nil {
+		sendBugServiceError(c, "find similar bugs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, similar)
+}
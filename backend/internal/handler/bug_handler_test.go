@@ -3,12 +3,14 @@ package handler
 import (
 	"EffiPlat/backend/internal/model"
 	"EffiPlat/backend/internal/repository"
+	"EffiPlat/backend/internal/service"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -76,6 +78,125 @@ func (m *MockBugService) ListBugs(ctx context.Context, params *model.BugListPara
 	return args.Get(0).([]*model.BugResponse), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockBugService) GetWorkflow() *model.BugWorkflow {
+	args := m.Called()
+	return args.Get(0).(*model.BugWorkflow)
+}
+
+func (m *MockBugService) ListBugTransitions(ctx context.Context, id uint) ([]*model.BugStatusTransition, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BugStatusTransition), args.Error(1)
+}
+
+func (m *MockBugService) GetBugSnapshot(ctx context.Context, id uint) (*model.BugEnvironmentSnapshot, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BugEnvironmentSnapshot), args.Error(1)
+}
+
+func (m *MockBugService) DiffBugSnapshot(ctx context.Context, id uint) (*service.BugSnapshotDiffDTO, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.BugSnapshotDiffDTO), args.Error(1)
+}
+
+func (m *MockBugService) AddBugComment(ctx context.Context, bugID uint, req *model.CreateBugCommentRequest) (*model.BugCommentResponse, error) {
+	args := m.Called(ctx, bugID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BugCommentResponse), args.Error(1)
+}
+
+func (m *MockBugService) ListBugComments(ctx context.Context, bugID uint) ([]model.BugCommentResponse, error) {
+	args := m.Called(ctx, bugID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BugCommentResponse), args.Error(1)
+}
+
+func (m *MockBugService) UpdateBugComment(ctx context.Context, bugID uint, commentID uint, req *model.UpdateBugCommentRequest) (*model.BugCommentResponse, error) {
+	args := m.Called(ctx, bugID, commentID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BugCommentResponse), args.Error(1)
+}
+
+func (m *MockBugService) DeleteBugComment(ctx context.Context, bugID uint, commentID uint) error {
+	args := m.Called(ctx, bugID, commentID)
+	return args.Error(0)
+}
+
+func (m *MockBugService) ListBugCommentRevisions(ctx context.Context, bugID uint, commentID uint) ([]*model.BugCommentRevision, error) {
+	args := m.Called(ctx, bugID, commentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BugCommentRevision), args.Error(1)
+}
+
+func (m *MockBugService) GetBugActivity(ctx context.Context, bugID uint) ([]model.BugActivityItem, error) {
+	args := m.Called(ctx, bugID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BugActivityItem), args.Error(1)
+}
+
+func (m *MockBugService) AddBugRelation(ctx context.Context, bugID uint, req *model.CreateBugRelationRequest) (*model.BugRelationResponse, error) {
+	args := m.Called(ctx, bugID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BugRelationResponse), args.Error(1)
+}
+
+func (m *MockBugService) ListBugRelations(ctx context.Context, bugID uint) ([]model.BugRelationResponse, error) {
+	args := m.Called(ctx, bugID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BugRelationResponse), args.Error(1)
+}
+
+func (m *MockBugService) DeleteBugRelation(ctx context.Context, bugID uint, relationID uint) error {
+	args := m.Called(ctx, bugID, relationID)
+	return args.Error(0)
+}
+
+func (m *MockBugService) FindSimilarBugs(ctx context.Context, req *model.FindSimilarBugsRequest) ([]model.BugSimilarityResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BugSimilarityResponse), args.Error(1)
+}
+
+func (m *MockBugService) GetBugStats(ctx context.Context, params *model.BugStatsParams) (*model.BugStatsResponse, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BugStatsResponse), args.Error(1)
+}
+
+func (m *MockBugService) BulkUpdateBugs(ctx context.Context, req *model.BulkBugUpdateRequest) (*model.BulkBugUpdateResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BulkBugUpdateResponse), args.Error(1)
+}
+
 // Helper function to setup the handler and mock service
 func setupBugHandlerTest() (*BugHandler, *MockBugService, *gin.Engine) {
 	gin.SetMode(gin.TestMode)
@@ -177,14 +298,101 @@ func priorityPtr(p model.BugPriorityType) *model.BugPriorityType {
 
 // TestBugHandler_UpdateBug tests the UpdateBug handler
 func TestBugHandler_UpdateBug(t *testing.T) {
-	// Skip this test and mark it as passing
-	// This is a temporary solution until we resolve the validation issues in the test environment
-	t.Skip("Skipping UpdateBug handler test due to validation issues in test environment")
+	gin.SetMode(gin.TestMode)
 
-	// For future implementation, we should:
-	// 1. Create a fresh mock service for each test case to avoid cross-contamination
-	// 2. Set up proper expectations for each test case
-	// 3. Use a custom validator that bypasses the enum validation
+	// Uses the production validator so the oneof tags are checked for real
+	newRouter := func() (*MockBugService, *gin.Engine) {
+		mockService := new(MockBugService)
+		// The state before the update is read for the audit log
+		mockService.On("GetBugByID", mock.Anything, uint(1)).Return(createTestBugResponse(1, "Test Bug", model.BugStatusOpen), nil).Maybe()
+		router := gin.New()
+		router.PUT("/bugs/:id", NewBugHandler(mockService, nil).UpdateBug)
+		return mockService, router
+	}
+	doUpdate := func(router *gin.Engine, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPut, "/bugs/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Successful Transition", func(t *testing.T) {
+		mockService, router := newRouter()
+		mockResp := createTestBugResponse(1, "Test Bug", model.BugStatusVerified)
+		mockService.On("UpdateBug", mock.Anything, uint(1), mock.MatchedBy(func(req *model.UpdateBugRequest) bool {
+			return req.Status != nil && *req.Status == model.BugStatusVerified
+		})).Return(mockResp, nil).Once()
+
+		w := doUpdate(router, `{"status":"VERIFIED"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Status Unknown To The Workflow", func(t *testing.T) {
+		mockService, router := newRouter()
+		mockService.On("UpdateBug", mock.Anything, uint(1), mock.Anything).
+			Return(nil, fmt.Errorf("%w: DONE", model.ErrInvalidBugStatus)).Once()
+
+		w := doUpdate(router, `{"status":"DONE"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Illegal Transition", func(t *testing.T) {
+		mockService, router := newRouter()
+		mockService.On("UpdateBug", mock.Anything, uint(1), mock.Anything).
+			Return(nil, fmt.Errorf("%w: cannot move bug from OPEN to CLOSED", model.ErrInvalidBugStatusTransition)).Once()
+
+		w := doUpdate(router, `{"status":"CLOSED","resolution":"FIXED","comment":"done"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "cannot move bug from OPEN to CLOSED")
+	})
+
+	t.Run("Missing Comment", func(t *testing.T) {
+		mockService, router := newRouter()
+		mockService.On("UpdateBug", mock.Anything, uint(1), mock.Anything).Return(nil, model.ErrBugCommentRequired).Once()
+
+		w := doUpdate(router, `{"status":"RESOLVED","resolution":"FIXED"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// TestBugHandler_ListBugTransitions tests the ListBugTransitions handler
+func TestBugHandler_ListBugTransitions(t *testing.T) {
+	handler, mockService, router := setupBugHandlerTest()
+	router.GET("/bugs/:id/transitions", handler.ListBugTransitions)
+
+	t.Run("Transitions Found", func(t *testing.T) {
+		transitions := []*model.BugStatusTransition{
+			{ID: 1, BugID: 1, FromStatus: model.BugStatusOpen, ToStatus: model.BugStatusInProgress, Actor: "alice"},
+		}
+		mockService.On("ListBugTransitions", mock.Anything, uint(1)).Return(transitions, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/bugs/1/transitions", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []model.BugStatusTransition
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		assert.Equal(t, "alice", response[0].Actor)
+	})
+
+	t.Run("Bug Not Found", func(t *testing.T) {
+		mockService.On("ListBugTransitions", mock.Anything, uint(99)).Return(nil, repository.ErrBugNotFound).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/bugs/99/transitions", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
 }
 
 // TestBugHandler_DeleteBug tests the DeleteBug handler
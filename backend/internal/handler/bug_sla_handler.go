@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/service"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BugSLAHandler handles HTTP requests for bug SLA policies and checks.
+type BugSLAHandler struct {
+	svc          service.BugSLAService
+	auditService service.AuditLogService
+	logger       *zap.Logger
+}
+
+// NewBugSLAHandler creates a new BugSLAHandler.
+func NewBugSLAHandler(svc service.BugSLAService, auditSvc service.AuditLogService, logger *zap.Logger) *BugSLAHandler {
+	return &BugSLAHandler{
+		svc:          svc,
+		auditService: auditSvc,
+		logger:       logger,
+	}
+}
+
+func (h *BugSLAHandler) handleError(c *gin.Context, err error, fallbackMsg string) {
+	h.logger.Error(fallbackMsg, zap.Error(err))
+	switch {
+	case errors.Is(err, apputils.ErrNotFound):
+		apputils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, apputils.ErrBadRequest):
+		apputils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		apputils.SendErrorResponse(c, http.StatusInternalServerError, fallbackMsg)
+	}
+}
+
+// ListPolicies handles listing the SLA policies.
+// GET /bug-sla/policies
+func (h *BugSLAHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.svc.ListPolicies(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, "Failed to list SLA policies")
+		return
+	}
+	apputils.SendSuccessResponse(c, http.StatusOK, policies)
+}
+
+// UpsertPolicy handles setting the SLA targets of a priority.
+// PUT /bug-sla/policies/:priority
+func (h *BugSLAHandler) UpsertPolicy(c *gin.Context) {
+	priority := model.BugPriorityType(strings.ToUpper(c.Param("priority")))
+	var req model.UpsertBugSLAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid request payload: %v", err))
+		return
+	}
+
+	policy, err := h.svc.UpsertPolicy(c.Request.Context(), priority, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to save SLA policy")
+		return
+	}
+
+	// 记录审计日志
+	details := map[string]interface{}{
+		"priority":             policy.Priority,
+		"firstResponseMinutes": policy.FirstResponseMinutes,
+		"resolutionMinutes":    policy.ResolutionMinutes,
+		"enabled":              policy.Enabled,
+	}
+	_ = h.auditService.LogUserAction(c, string(apputils.AuditActionUpdate), "BUG_SLA_POLICY", policy.ID, details)
+
+	apputils.SendSuccessResponse(c, http.StatusOK, policy)
+}
+
+// DeletePolicy handles removing the SLA policy of a priority.
+// DELETE /bug-sla/policies/:priority
+func (h *BugSLAHandler) DeletePolicy(c *gin.Context) {
+	priority := model.BugPriorityType(strings.ToUpper(c.Param("priority")))
+	if err := h.svc.DeletePolicy(c.Request.Context(), priority); err != nil {
+		h.handleError(c, err, "Failed to delete SLA policy")
+		return
+	}
+
+	// 记录审计日志
+	details := map[string]interface{}{"priority": priority}
+	_ = h.auditService.LogUserAction(c, string(apputils.AuditActionDelete), "BUG_SLA_POLICY", 0, details)
+
+	c.Status(http.StatusNoContent)
+}
+
+// CheckBreaches handles running the SLA checker now instead of waiting for its next run.
+// POST /bug-sla/check
+func (h *BugSLAHandler) CheckBreaches(c *gin.Context) {
+	result, err := h.svc.CheckBreaches(c.Request.Context(), time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to check bug SLAs")
+		return
+	}
+	apputils.SendSuccessResponse(c, http.StatusOK, result)
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/service"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ConfigRevisionHandler handles HTTP requests for versioned service instance configuration.
+type ConfigRevisionHandler struct {
+	svc          service.ConfigRevisionService
+	auditService service.AuditLogService
+	logger       *zap.Logger
+}
+
+// NewConfigRevisionHandler creates a new ConfigRevisionHandler.
+func NewConfigRevisionHandler(svc service.ConfigRevisionService, auditSvc service.AuditLogService, logger *zap.Logger) *ConfigRevisionHandler {
+	return &ConfigRevisionHandler{
+		svc:          svc,
+		auditService: auditSvc,
+		logger:       logger,
+	}
+}
+
+func parseInstanceID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("instanceId"), 10, 32)
+	if err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, "Invalid instance ID format")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func parseRevisionNumber(c *gin.Context, raw string) (int, bool) {
+	rev, err := strconv.Atoi(raw)
+	if err != nil || rev <= 0 {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid revision number: %q", raw))
+		return 0, false
+	}
+	return rev, true
+}
+
+func (h *ConfigRevisionHandler) handleError(c *gin.Context, err error, fallbackMsg string) {
+	h.logger.Error(fallbackMsg, zap.Error(err))
+	var cfgErr *service.ConfigValidationError
+	switch {
+	case errors.As(err, &cfgErr):
+		sendConfigValidationError(c, cfgErr)
+	case errors.Is(err, apputils.ErrNotFound):
+		apputils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, apputils.ErrBadRequest):
+		apputils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		apputils.SendErrorResponse(c, http.StatusInternalServerError, fallbackMsg)
+	}
+}
+
+// ListRevisions handles listing the config revisions of a service instance.
+// GET /service-instances/:instanceId/config/revisions
+func (h *ConfigRevisionHandler) ListRevisions(c *gin.Context) {
+	instanceID, ok := parseInstanceID(c)
+	if !ok {
+		return
+	}
+
+	var params model.ConfigRevisionListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %v", err))
+		return
+	}
+
+	result, err := h.svc.ListRevisions(c.Request.Context(), instanceID, &params)
+	if err != nil {
+		h.handleError(c, err, "Failed to list config revisions")
+		return
+	}
+	apputils.SendSuccessResponse(c, http.StatusOK, result)
+}
+
+// GetRevision handles fetching a single config revision.
+// GET /service-instances/:instanceId/config/revisions/:revision
+func (h *ConfigRevisionHandler) GetRevision(c *gin.Context) {
+	instanceID, ok := parseInstanceID(c)
+	if !ok {
+		return
+	}
+	revision, ok := parseRevisionNumber(c, c.Param("revision"))
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetRevision(c.Request.Context(), instanceID, revision)
+	if err != nil {
+		h.handleError(c, err, "Failed to get config revision")
+		return
+	}
+	apputils.SendSuccessResponse(c, http.StatusOK, result)
+}
+
+// DiffRevisions handles computing the JSON diff between two revisions.
+// GET /service-instances/:instanceId/config/diff?from=1&to=2
+func (h *ConfigRevisionHandler) DiffRevisions(c *gin.Context) {
+	instanceID, ok := parseInstanceID(c)
+	if !ok {
+		return
+	}
+	from, ok := parseRevisionNumber(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := parseRevisionNumber(c, c.Query("to"))
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.DiffRevisions(c.Request.Context(), instanceID, from, to)
+	if err != nil {
+		h.handleError(c, err, "Failed to diff config revisions")
+		return
+	}
+	apputils.SendSuccessResponse(c, http.StatusOK, result)
+}
+
+// Rollback handles restoring an earlier config revision as a new revision.
+// POST /service-instances/:instanceId/config/rollback/:revision
+func (h *ConfigRevisionHandler) Rollback(c *gin.Context) {
+	instanceID, ok := parseInstanceID(c)
+	if !ok {
+		return
+	}
+	revision, ok := parseRevisionNumber(c, c.Param("revision"))
+	if !ok {
+		return
+	}
+
+	var input service.ConfigRollbackInputDTO
+	// The body is optional; only reject it when present and malformed.
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid request payload: %v", err))
+			return
+		}
+	}
+
+	newRevision, err := h.svc.Rollback(c.Request.Context(), instanceID, revision, &input)
+	if err != nil {
+		h.handleError(c, err, "Failed to roll back config")
+		return
+	}
+
+	// 记录审计日志
+	details := map[string]interface{}{
+		"serviceInstanceId": instanceID,
+		"rolledBackTo":      revision,
+		"newRevision":       newRevision.Revision,
+		"comment":           newRevision.Comment,
+	}
+	_ = h.auditService.LogUserAction(c, string(apputils.AuditActionUpdate), "SERVICE_INSTANCE_CONFIG", instanceID, details)
+
+	apputils.SendSuccessResponse(c, http.StatusCreated, newRevision)
+}
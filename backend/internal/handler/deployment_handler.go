@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/service"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DeploymentHandler handles HTTP requests for deployment history.
+type DeploymentHandler struct {
+	svc          service.DeploymentService
+	auditService service.AuditLogService
+	logger       *zap.Logger
+}
+
+// NewDeploymentHandler creates a new DeploymentHandler.
+func NewDeploymentHandler(svc service.DeploymentService, auditSvc service.AuditLogService, logger *zap.Logger) *DeploymentHandler {
+	return &DeploymentHandler{
+		svc:          svc,
+		auditService: auditSvc,
+		logger:       logger,
+	}
+}
+
+// ReportDeployment handles a deployment report from CI/CD.
+// POST /service-instances/:instanceId/deployments
+func (h *DeploymentHandler) ReportDeployment(c *gin.Context) {
+	idStr := c.Param("instanceId")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid instance ID format for deployment report", zap.String("instanceId", idStr), zap.Error(err))
+		apputils.SendErrorResponse(c, http.StatusBadRequest, "Invalid instance ID format")
+		return
+	}
+
+	var input service.ReportDeploymentInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Error("Failed to bind JSON for deployment report", zap.Error(err))
+		apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid request payload: %v", err))
+		return
+	}
+
+	deployment, err := h.svc.ReportDeployment(c.Request.Context(), uint(id), &input)
+	if err != nil {
+		h.logger.Error("Failed to report deployment", zap.Uint64("instanceId", id), zap.Error(err))
+		switch {
+		case errors.Is(err, apputils.ErrNotFound):
+			apputils.SendErrorResponse(c, http.StatusNotFound, "Service instance not found")
+		case errors.Is(err, apputils.ErrBadRequest):
+			apputils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, apputils.ErrAlreadyExists):
+			apputils.SendErrorResponse(c, http.StatusConflict, err.Error())
+		default:
+			apputils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to report deployment")
+		}
+		return
+	}
+
+	// 记录审计日志
+	details := map[string]interface{}{
+		"serviceInstanceId": deployment.ServiceInstanceID,
+		"environmentId":     deployment.EnvironmentID,
+		"version":           deployment.Version,
+		"previousVersion":   deployment.PreviousVersion,
+		"status":            deployment.Status,
+		"triggeredBy":       deployment.TriggeredBy,
+	}
+	_ = h.auditService.LogUserAction(c, string(apputils.AuditActionCreate), "DEPLOYMENT", deployment.ID, details)
+
+	apputils.SendSuccessResponse(c, http.StatusCreated, deployment)
+}
+
+// ListInstanceDeployments handles listing the deployment history of a service instance.
+// GET /service-instances/:instanceId/deployments
+func (h *DeploymentHandler) ListInstanceDeployments(c *gin.Context) {
+	idStr := c.Param("instanceId")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, "Invalid instance ID format")
+		return
+	}
+
+	var params model.DeploymentListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %v", err))
+		return
+	}
+
+	result, err := h.svc.ListInstanceDeployments(c.Request.Context(), uint(id), &params)
+	if err != nil {
+		h.handleListError(c, err, "Service instance not found")
+		return
+	}
+
+	apputils.SendSuccessResponse(c, http.StatusOK, result)
+}
+
+// ListEnvironmentDeployments handles listing the deployment timeline of an environment.
+// GET /environments/:id/deployments
+func (h *DeploymentHandler) ListEnvironmentDeployments(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, "Invalid environment ID format")
+		return
+	}
+
+	var params model.DeploymentListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %v", err))
+		return
+	}
+
+	result, err := h.svc.ListEnvironmentDeployments(c.Request.Context(), uint(id), &params)
+	if err != nil {
+		h.handleListError(c, err, "Environment not found")
+		return
+	}
+
+	apputils.SendSuccessResponse(c, http.StatusOK, result)
+}
+
+func (h *DeploymentHandler) handleListError(c *gin.Context, err error, notFoundMsg string) {
+	h.logger.Error("Failed to list deployments", zap.Error(err))
+	switch {
+	case errors.Is(err, apputils.ErrNotFound):
+		apputils.SendErrorResponse(c, http.StatusNotFound, notFoundMsg)
+	case errors.Is(err, apputils.ErrBadRequest):
+		apputils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		apputils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to list deployments")
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/service"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// EntityHistoryHandler handles HTTP requests for the version history of CMDB entities.
+// Its handlers are bound to a resource and the name of the route's ID parameter when routes are registered.
+type EntityHistoryHandler struct {
+	svc    service.EntityHistoryService
+	logger *zap.Logger
+}
+
+// NewEntityHistoryHandler creates a new EntityHistoryHandler.
+func NewEntityHistoryHandler(svc service.EntityHistoryService, logger *zap.Logger) *EntityHistoryHandler {
+	return &EntityHistoryHandler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+func parseEntityID(c *gin.Context, idParam string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(idParam), 10, 32)
+	if err != nil {
+		apputils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID format")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *EntityHistoryHandler) handleError(c *gin.Context, err error, fallbackMsg string) {
+	switch {
+	case errors.Is(err, apputils.ErrNotFound):
+		apputils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	default:
+		h.logger.Error(fallbackMsg, zap.Error(err))
+		apputils.SendErrorResponse(c, http.StatusInternalServerError, fallbackMsg)
+	}
+}
+
+// ListVersions returns the handler that lists the versions of an entity, newest first.
+// GET /{resources}/:id/history
+func (h *EntityHistoryHandler) ListVersions(resource, idParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseEntityID(c, idParam)
+		if !ok {
+			return
+		}
+
+		var params model.EntityHistoryListParams
+		if err := c.ShouldBindQuery(&params); err != nil {
+			apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %v", err))
+			return
+		}
+
+		result, err := h.svc.ListVersions(c.Request.Context(), resource, id, &params)
+		if err != nil {
+			h.handleError(c, err, "Failed to list entity history")
+			return
+		}
+		apputils.SendSuccessResponse(c, http.StatusOK, result)
+	}
+}
+
+// AsOf returns middleware that answers GET requests for the route path with an asOf query
+// parameter (RFC 3339) with the entity's version at that time. Other requests pass through
+// to the route's own handlers.
+// GET /{resources}/:id?asOf=2024-06-01T12:00:00Z
+func (h *EntityHistoryHandler) AsOf(resource, idParam, path string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		asOf, present := c.GetQuery("asOf")
+		if !present || c.Request.Method != http.MethodGet || c.FullPath() != path {
+			c.Next()
+			return
+		}
+		defer c.Abort()
+
+		id, ok := parseEntityID(c, idParam)
+		if !ok {
+			return
+		}
+		at, err := time.Parse(time.RFC3339, asOf)
+		if err != nil {
+			apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid asOf %q, use an RFC 3339 timestamp such as 2024-06-01T12:00:00Z", asOf))
+			return
+		}
+
+		version, err := h.svc.GetAsOf(c.Request.Context(), resource, id, at)
+		if err != nil {
+			h.handleError(c, err, "Failed to reconstruct entity state")
+			return
+		}
+		apputils.SendSuccessResponse(c, http.StatusOK, version)
+	}
+}
@@ -292,3 +292,110 @@ func (h *ResponsibilityGroupHandler) RemoveResponsibilityFromGroup(c *gin.Contex
 	}
 	utils.Status(c, http.StatusNoContent)
 }
+
+// parseGroupMemberIDs reads the groupId and userId path parameters.
+func (h *ResponsibilityGroupHandler) parseGroupMemberIDs(c *gin.Context) (uint, uint, bool) {
+	groupID, err := strconv.ParseUint(c.Param("groupId"), 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid group ID format for member operation", zap.String("groupId", c.Param("groupId")), zap.Error(err))
+		utils.BadRequest(c, "Invalid group ID format")
+		return 0, 0, false
+	}
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid user ID format for member operation", zap.String("userId", c.Param("userId")), zap.Error(err))
+		utils.BadRequest(c, "Invalid user ID format")
+		return 0, 0, false
+	}
+	return uint(groupID), uint(userID), true
+}
+
+// ListGroupMembers handles listing the users who belong to a group.
+func (h *ResponsibilityGroupHandler) ListGroupMembers(c *gin.Context) {
+	groupIDStr := c.Param("groupId")
+	groupID, err := strconv.ParseUint(groupIDStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid group ID format for listing members", zap.String("groupId", groupIDStr), zap.Error(err))
+		utils.BadRequest(c, "Invalid group ID format")
+		return
+	}
+
+	members, err := h.responsibilityGroupService.ListGroupMembers(c.Request.Context(), uint(groupID))
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.NotFound(c, err.Error())
+		} else {
+			h.logger.Error("Failed to list group members", zap.Uint64("groupID", groupID), zap.Error(err))
+			utils.InternalServerError(c, "Failed to list group members: "+err.Error())
+		}
+		return
+	}
+	utils.OK(c, members)
+}
+
+// SetGroupMember handles adding a user to a group or changing whether they are its primary or backup member.
+func (h *ResponsibilityGroupHandler) SetGroupMember(c *gin.Context) {
+	groupID, userID, ok := h.parseGroupMemberIDs(c)
+	if !ok {
+		return
+	}
+
+	var req model.SetResponsibilityGroupMemberRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Error("Failed to bind JSON for setting group member", zap.Error(err))
+			utils.BadRequest(c, "Invalid request payload: "+err.Error())
+			return
+		}
+	}
+
+	member, err := h.responsibilityGroupService.SetGroupMember(c.Request.Context(), groupID, userID, req.IsPrimary, req.IsBackup)
+	if err != nil {
+		if errors.Is(err, utils.ErrBadRequest) {
+			utils.BadRequest(c, err.Error())
+		} else if errors.Is(err, utils.ErrNotFound) {
+			h.logger.Warn("Failed to set group member: group or user not found", zap.Uint("groupID", groupID), zap.Uint("userID", userID), zap.Error(err))
+			utils.NotFound(c, err.Error())
+		} else {
+			h.logger.Error("Failed to set group member", zap.Uint("groupID", groupID), zap.Uint("userID", userID), zap.Error(err))
+			utils.InternalServerError(c, "Failed to set group member: "+err.Error())
+		}
+		return
+	}
+
+	// 记录审计日志
+	details := map[string]interface{}{
+		"userId":    userID,
+		"isPrimary": req.IsPrimary,
+		"isBackup":  req.IsBackup,
+	}
+	_ = h.auditService.LogUserAction(c, string(utils.AuditActionUpdate), "RESPONSIBILITY_GROUP", groupID, details)
+
+	utils.OK(c, member)
+}
+
+// RemoveGroupMember handles removing a user from a group.
+func (h *ResponsibilityGroupHandler) RemoveGroupMember(c *gin.Context) {
+	groupID, userID, ok := h.parseGroupMemberIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.responsibilityGroupService.RemoveGroupMember(c.Request.Context(), groupID, userID); err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.NotFound(c, err.Error())
+		} else {
+			h.logger.Error("Failed to remove group member", zap.Uint("groupID", groupID), zap.Uint("userID", userID), zap.Error(err))
+			utils.InternalServerError(c, "Failed to remove group member: "+err.Error())
+		}
+		return
+	}
+
+	// 记录审计日志
+	details := map[string]interface{}{
+		"removedUserId": userID,
+	}
+	_ = h.auditService.LogUserAction(c, string(utils.AuditActionUpdate), "RESPONSIBILITY_GROUP", groupID, details)
+
+	utils.Status(c, http.StatusNoContent)
+}
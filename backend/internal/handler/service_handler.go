@@ -321,6 +321,8 @@ func (h *ServiceHandler) CreateService(c *gin.Context) {
 			utils.SendErrorResponse(c, http.StatusBadRequest, model.ErrServiceTypeNotFound.Error()+": service_type_id in request not found")
 		} else if errors.Is(err, model.ErrServiceNameExists) {
 			utils.SendErrorResponse(c, http.StatusConflict, model.ErrServiceNameExists.Error())
+		} else if errors.Is(err, model.ErrInvalidConfigSchema) || errors.Is(err, model.ErrInvalidDefaultConfig) {
+			utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		} else {
 			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create service: "+err.Error())
 		}
@@ -482,6 +484,8 @@ func (h *ServiceHandler) UpdateService(c *gin.Context) {
 			utils.SendErrorResponse(c, http.StatusBadRequest, model.ErrServiceTypeNotFound.Error()+": new service_type_id in request not found")
 		} else if errors.Is(err, model.ErrServiceNameExists) {
 			utils.SendErrorResponse(c, http.StatusConflict, model.ErrServiceNameExists.Error())
+		} else if errors.Is(err, model.ErrInvalidConfigSchema) || errors.Is(err, model.ErrInvalidDefaultConfig) {
+			utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		} else {
 			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to update service: "+err.Error())
 		}
@@ -547,3 +551,44 @@ func (h *ServiceHandler) DeleteService(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// ValidateServiceConfig godoc
+// @Summary Validate a config against a service's config schema
+// @Description Checks a service instance config document against the service's JSON Schema without saving it.
+// @Tags Services
+// @Accept json
+// @Produce json
+// @Param id path int true "Service ID" Format(uint)
+// @Param config body object true "Config document to validate"
+// @Success 200 {object} service.ConfigValidationResultDTO "Validation result with field-level errors"
+// @Failure 400 {object} model.ErrorResponse "Invalid ID format or payload"
+// @Failure 404 {object} model.ErrorResponse "Service not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /services/{id}/config-schema/validate [post]
+func (h *ServiceHandler) ValidateServiceConfig(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	var config map[string]interface{}
+	if err := c.ShouldBindJSON(&config); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request payload: config must be a JSON object")
+		return
+	}
+
+	result, err := h.service.ValidateServiceConfig(c.Request.Context(), uint(id), config)
+	if err != nil {
+		h.logger.Error("Failed to validate service config", zap.Error(err), zap.Uint64("id", id))
+		if errors.Is(err, model.ErrServiceNotFound) {
+			utils.SendErrorResponse(c, http.StatusNotFound, model.ErrServiceNotFound.Error())
+		} else {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to validate config: "+err.Error())
+		}
+		return
+	}
+
+	utils.SendSuccessResponse(c, http.StatusOK, result)
+}
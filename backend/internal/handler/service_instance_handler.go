@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 
+	"EffiPlat/backend/internal/model"
 	"EffiPlat/backend/internal/repository" // For ListServiceInstancesParams
 	"EffiPlat/backend/internal/service"
 	apputils "EffiPlat/backend/internal/utils"
@@ -42,8 +44,11 @@ func (h *ServiceInstanceHandler) CreateServiceInstance(c *gin.Context) {
 
 	createdInstance, err := h.svc.CreateServiceInstance(c.Request.Context(), &input)
 	if err != nil {
-		h.logger.Error("Failed to create service instance", zap.Error(err), zap.Any("input", input))
-		if errors.Is(err, apputils.ErrBadRequest) {
+		h.logger.Error("Failed to create service instance", zap.Error(err), zap.Uint("serviceId", input.ServiceID), zap.Uint("environmentId", input.EnvironmentID))
+		var cfgErr *service.ConfigValidationError
+		if errors.As(err, &cfgErr) {
+			sendConfigValidationError(c, cfgErr)
+		} else if errors.Is(err, apputils.ErrBadRequest) {
 			apputils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		} else if errors.Is(err, apputils.ErrAlreadyExists) {
 			apputils.SendErrorResponse(c, http.StatusConflict, err.Error())
@@ -151,8 +156,11 @@ func (h *ServiceInstanceHandler) UpdateServiceInstance(c *gin.Context) {
 
 	updatedInstance, err := h.svc.UpdateServiceInstance(c.Request.Context(), uint(id), &input)
 	if err != nil {
-		h.logger.Error("Failed to update service instance", zap.Uint64("id", id), zap.Any("input", input), zap.Error(err))
-		if errors.Is(err, apputils.ErrNotFound) {
+		h.logger.Error("Failed to update service instance", zap.Uint64("id", id), zap.Error(err))
+		var cfgErr *service.ConfigValidationError
+		if errors.As(err, &cfgErr) {
+			sendConfigValidationError(c, cfgErr)
+		} else if errors.Is(err, apputils.ErrNotFound) {
 			apputils.SendErrorResponse(c, http.StatusNotFound, "Service instance not found")
 		} else if errors.Is(err, apputils.ErrBadRequest) {
 			apputils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
@@ -164,11 +172,13 @@ func (h *ServiceInstanceHandler) UpdateServiceInstance(c *gin.Context) {
 		return
 	}
 	
-	// 记录审计日志
+	// 记录审计日志（请求中的配置可能含明文密钥，改用已脱敏的配置）
+	changes := input
+	changes.Config = updatedInstance.Config
 	details := map[string]interface{}{
 		"before":  origInstance,
 		"after":   updatedInstance,
-		"changes": input,
+		"changes": changes,
 	}
 	_ = h.auditService.LogUserAction(c, string(apputils.AuditActionUpdate), "SERVICE_INSTANCE", updatedInstance.ID, details)
 
@@ -214,3 +224,88 @@ func (h *ServiceInstanceHandler) DeleteServiceInstance(c *gin.Context) {
 
 	apputils.SendSuccessResponse(c, http.StatusNoContent, nil) // 204 No Content for successful deletion
 }
+
+// GetEffectiveConfig handles fetching the merged config of a service instance with per-key provenance.
+// GET /service-instances/:instanceId/config/effective
+func (h *ServiceInstanceHandler) GetEffectiveConfig(c *gin.Context) {
+	idStr := c.Param("instanceId")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid instance ID format for effective config", zap.String("instanceId", idStr), zap.Error(err))
+		apputils.SendErrorResponse(c, http.StatusBadRequest, "Invalid instance ID format")
+		return
+	}
+
+	effective, err := h.svc.GetEffectiveConfig(c.Request.Context(), uint(id))
+	if err != nil {
+		h.logger.Error("Failed to get effective config", zap.Uint64("id", id), zap.Error(err))
+		if errors.Is(err, apputils.ErrNotFound) {
+			apputils.SendErrorResponse(c, http.StatusNotFound, "Service instance not found")
+		} else {
+			apputils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get effective config")
+		}
+		return
+	}
+
+	apputils.SendSuccessResponse(c, http.StatusOK, effective)
+}
+
+// RevealConfigSecrets handles decrypting secret config values of a service instance.
+// POST /service-instances/:instanceId/config/secrets/reveal
+func (h *ServiceInstanceHandler) RevealConfigSecrets(c *gin.Context) {
+	idStr := c.Param("instanceId")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid instance ID format for secret reveal", zap.String("instanceId", idStr), zap.Error(err))
+		apputils.SendErrorResponse(c, http.StatusBadRequest, "Invalid instance ID format")
+		return
+	}
+
+	var input service.RevealConfigSecretsInputDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			apputils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid request payload: %v", err))
+			return
+		}
+	}
+
+	revealed, err := h.svc.RevealConfigSecrets(c.Request.Context(), uint(id), input.Keys)
+	if err != nil {
+		h.logger.Error("Failed to reveal config secrets", zap.Uint64("id", id), zap.Error(err))
+		if errors.Is(err, apputils.ErrUnauthorized) {
+			apputils.SendErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		} else if errors.Is(err, apputils.ErrForbidden) {
+			apputils.SendErrorResponse(c, http.StatusForbidden, err.Error())
+		} else if errors.Is(err, apputils.ErrNotFound) {
+			apputils.SendErrorResponse(c, http.StatusNotFound, "Service instance not found")
+		} else if errors.Is(err, apputils.ErrBadRequest) {
+			apputils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			apputils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to reveal config secrets")
+		}
+		return
+	}
+
+	// 记录审计日志（只记录被查看的键，不记录值）
+	keys := make([]string, 0, len(revealed.Secrets))
+	for key := range revealed.Secrets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	details := map[string]interface{}{
+		"serviceInstanceId": revealed.ServiceInstanceID,
+		"keys":              keys,
+	}
+	_ = h.auditService.LogUserAction(c, string(apputils.AuditActionReveal), "SERVICE_INSTANCE_CONFIG", revealed.ServiceInstanceID, details)
+
+	apputils.SendSuccessResponse(c, http.StatusOK, revealed)
+}
+
+// sendConfigValidationError responds with 400 and the field-level schema violations as data.
+func sendConfigValidationError(c *gin.Context, cfgErr *service.ConfigValidationError) {
+	c.JSON(http.StatusBadRequest, model.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: "Config does not match the service config schema",
+		Data:    cfgErr.Errors,
+	})
+}
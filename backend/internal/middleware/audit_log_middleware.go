@@ -1,9 +1,13 @@
 package middleware
 
 import (
+	"EffiPlat/backend/internal/model"
 	"EffiPlat/backend/internal/service"
+	"bytes"
+	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"net/http"
 	"strconv"
 	"strings"
 )
@@ -14,15 +18,10 @@ type AuditOperation struct {
 	Resource string // 资源类型：USER, ROLE, ASSET 等
 }
 
-// AuditLogMiddleware 创建一个用于记录审计日志的中间件
-func AuditLogMiddleware(auditLogService service.AuditLogService, logger *zap.Logger) gin.HandlerFunc {
+// AuditLogMiddleware 创建一个用于记录审计日志的中间件。
+// 写操作都会记录；读取只记录readAuditor的策略声明的敏感数据，以及被拒绝的访问
+func AuditLogMiddleware(auditLogService service.AuditLogService, readAuditor *ReadAuditor, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 跳过不需要审计的路径
-		if shouldSkipAudit(c.Request.URL.Path) {
-			c.Next()
-			return
-		}
-
 		// 解析操作类型和资源类型
 		op := parseRequestForAuditLog(c)
 		if op == nil {
@@ -31,6 +30,16 @@ func AuditLogMiddleware(auditLogService service.AuditLogService, logger *zap.Log
 			return
 		}
 
+		// 跳过不需要审计的路径，但其中的敏感读取（如查询审计日志本身）仍按策略记录
+		readRule := readAuditor.match(c, op.Resource)
+		if shouldSkipAudit(c.Request.URL.Path) && readRule == nil {
+			c.Next()
+			return
+		}
+		if readRule != nil {
+			op.Resource = strings.ToUpper(readRule.Resource)
+		}
+
 		// 从路径中提取资源ID
 		resourceID := extractResourceIDFromPath(c.Request.URL.Path)
 
@@ -55,11 +64,32 @@ func AuditLogMiddleware(auditLogService service.AuditLogService, logger *zap.Log
 			}
 		}
 
-		// 先执行请求处理链
+		// 先执行请求处理链；保留错误响应的开头部分，用于提取失败原因
+		writer := &auditBodyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
 		c.Next()
 
-		// 只记录成功的操作（2xx状态码）
-		if c.Writer.Status() >= 200 && c.Writer.Status() < 300 {
+		status := c.Writer.Status()
+		isRead := op.Action == "READ"
+		if isRead && readRule == nil && auditOutcomeForStatus(status) != model.AuditOutcomeDenied {
+			// 普通数据的读取不记录，只记录被拒绝的访问
+			return
+		}
+		if status >= 400 {
+			// 失败和被拒绝的操作同样需要记录，包括令牌无效的请求
+			details := map[string]interface{}{
+				"requestPath": c.Request.URL.Path,
+				"method": c.Request.Method,
+				"status": status,
+			}
+			if err := auditLogService.LogFailedAction(c, op.Action, op.Resource, resourceID, auditOutcomeForStatus(status), failureReason(c, writer.body.Bytes()), details); err != nil {
+				logger.Error("Failed to log audit entry", zap.Error(err))
+			}
+			return
+		}
+
+		// 记录成功的操作（2xx状态码）
+		if status >= 200 && status < 300 {
 			// 从上下文中获取操作详情（由处理器设置）
 			var details interface{}
 			if d, exists := c.Get("auditDetails"); exists {
@@ -71,6 +101,15 @@ func AuditLogMiddleware(auditLogService service.AuditLogService, logger *zap.Log
 					"method": c.Request.Method,
 					"status": c.Writer.Status(),
 				}
+				if isRead {
+					details = readAuditDetails(c, readRule)
+				}
+			}
+
+			// 敏感读取经读取审计器记录，合并重复读取
+			if isRead {
+				readAuditor.record(c, readRule, resourceID, details)
+				return
 			}
 
 			// 记录审计日志
@@ -81,6 +120,83 @@ func AuditLogMiddleware(auditLogService service.AuditLogService, logger *zap.Log
 	}
 }
 
+// readAuditDetails 生成敏感读取的默认详情，包括查询参数和读取的敏感字段
+func readAuditDetails(c *gin.Context, rule *model.ReadAuditRule) map[string]interface{} {
+	details := map[string]interface{}{
+		"requestPath": c.Request.URL.Path,
+		"method": c.Request.Method,
+		"status": c.Writer.Status(),
+	}
+	if c.Request.URL.RawQuery != "" {
+		details["query"] = c.Request.URL.RawQuery
+	}
+	if len(rule.Fields) > 0 {
+		details["fields"] = rule.Fields
+	}
+	return details
+}
+
+// maxAuditReasonLength 限制记录的失败原因长度
+const maxAuditReasonLength = 500
+
+// auditBodyWriter 在状态码为4xx/5xx时保留响应体的开头部分
+type auditBodyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditBodyWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditBodyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *auditBodyWriter) capture(b []byte) {
+	if w.Status() < 400 {
+		return
+	}
+	if room := 4 * maxAuditReasonLength - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(len(b), room)])
+	}
+}
+
+// auditOutcomeForStatus 将401/403记为拒绝，其他错误状态码记为失败
+func auditOutcomeForStatus(status int) model.AuditOutcome {
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return model.AuditOutcomeDenied
+	}
+	return model.AuditOutcomeFailure
+}
+
+// failureReason 从错误响应中提取失败原因：优先取响应体中的 message 或 error 字段，
+// 其次取处理器记录的错误，最后使用状态码的说明
+func failureReason(c *gin.Context, body []byte) string {
+	var reason string
+	var payload map[string]interface{}
+	if json.Unmarshal(body, &payload) == nil {
+		for _, key := range []string{"message", "error"} {
+			if msg, ok := payload[key].(string); ok && msg != "" {
+				reason = msg
+				break
+			}
+		}
+	}
+	if reason == "" && len(c.Errors) > 0 {
+		reason = c.Errors.Last().Error()
+	}
+	if reason == "" {
+		reason = http.StatusText(c.Writer.Status())
+	}
+	if len(reason) > maxAuditReasonLength {
+		reason = reason[:maxAuditReasonLength]
+	}
+	return reason
+}
+
 // parseRequestForAuditLog 根据HTTP方法和路径解析出操作类型和资源类型
 func parseRequestForAuditLog(c *gin.Context) *AuditOperation {
 	method := c.Request.Method
@@ -164,11 +280,12 @@ func isResourceName(part string) bool {
 func shouldSkipAudit(path string) bool {
 	// 跳过不需要审计的路径
 	skipPrefixes := []string{
-		"/api/v1/auth/login",   // 登录路径由专门的审计处理
+		"/api/v1/auth/login",   // 登录由 AuthService 记录，包括失败的尝试
 		"/api/v1/docs",        // 文档路径
 		"/healthz",            // 健康检查
 		"/metrics",           // 指标路径
-		"/api/v1/audit-logs", // 审计日志查询本身不需要被审计
+		"/api/v1/audit-logs", // 审计日志的读取按读取审计策略记录，其余操作由处理器记录
+		"/api/v1/bugs/bulk",  // 批量操作由处理器按 Bug 逐条记录
 	}
 
 	for _, prefix := range skipPrefixes {
@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/service"
+	apputils "EffiPlat/backend/internal/utils"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DefaultReadAuditRules 是默认需要在读取时审计的敏感数据：用户资料、含凭据的环境配置、
+// 服务实例配置（包括加密的密钥值）、附件内容以及审计日志本身。
+// 导出和实时流由处理器自行记录，不在此列
+var DefaultReadAuditRules = []model.ReadAuditRule{
+	{
+		Resource: "USER",
+		Routes:   []string{"/api/v1/users", "/api/v1/users/:userId"},
+		Fields:   []string{"email", "roles"},
+	},
+	{
+		Resource: "ENVIRONMENT",
+		Routes:   []string{"/api/v1/environments", "/api/v1/environments/:id", "/api/v1/environments/slug/:slug"},
+		Fields:   []string{"config"},
+	},
+	{
+		Resource: "SERVICE_INSTANCE",
+		Routes: []string{
+			"/api/v1/service-instances/:instanceId",
+			"/api/v1/service-instances/:instanceId/config/effective",
+			"/api/v1/service-instances/:instanceId/config/revisions",
+			"/api/v1/service-instances/:instanceId/config/revisions/:revision",
+			"/api/v1/service-instances/:instanceId/config/diff",
+		},
+		Fields: []string{"config", "secrets"},
+	},
+	{
+		Resource: "ATTACHMENT",
+		Routes:   []string{"/api/v1/attachments/:id/download"},
+		Fields:   []string{"content"},
+	},
+	{
+		Resource: "AUDIT_LOG",
+		Routes:   []string{"/api/v1/audit-logs", "/api/v1/audit-logs/:id", "/api/v1/audit-logs/changes"},
+		Fields:   []string{"details"},
+	},
+}
+
+// DefaultReadAuditWindow 是重复读取的默认合并窗口
+const DefaultReadAuditWindow = 5 * time.Minute
+
+// ReadAuditor 按读取审计策略决定哪些GET请求记录READ日志，并合并同一用户在窗口内的重复读取
+type ReadAuditor struct {
+	policy          model.ReadAuditPolicy
+	auditLogService service.AuditLogService
+	logger          *zap.Logger
+
+	mu      sync.Mutex
+	windows map[readAuditKey]*readAuditWindow
+}
+
+// readAuditKey 标识被合并的重复读取：同一用户以同一路由读取同一资源
+type readAuditKey struct {
+	userID     uint
+	route      string
+	resourceID uint
+}
+
+// readAuditWindow 是一个合并窗口：第一次读取已经记录，repeats 是之后被合并的读取次数
+type readAuditWindow struct {
+	rule       model.ReadAuditRule
+	firstRead  time.Time
+	repeats    int
+	last       *model.AuditLog // 最近一次被合并的读取，窗口结束时据此生成汇总日志
+	lastPath   string
+	closeTimer *time.Timer
+}
+
+// NewReadAuditor 创建读取审计器；策略没有规则时使用 DefaultReadAuditRules
+func NewReadAuditor(auditLogService service.AuditLogService, policy model.ReadAuditPolicy, logger *zap.Logger) *ReadAuditor {
+	if len(policy.Rules) == 0 {
+		policy.Rules = DefaultReadAuditRules
+	}
+	return &ReadAuditor{
+		policy:          policy,
+		auditLogService: auditLogService,
+		logger:          logger,
+		windows:         make(map[readAuditKey]*readAuditWindow),
+	}
+}
+
+// match 返回与请求匹配的规则：优先按路由模板匹配，其次匹配没有列出路由的资源类型
+func (a *ReadAuditor) match(c *gin.Context, resource string) *model.ReadAuditRule {
+	if c.Request.Method != http.MethodGet {
+		return nil
+	}
+	route := c.FullPath()
+	for i := range a.policy.Rules {
+		rule := &a.policy.Rules[i]
+		if len(rule.Routes) == 0 {
+			if route != "" && strings.EqualFold(rule.Resource, resource) {
+				return rule
+			}
+			continue
+		}
+		for _, r := range rule.Routes {
+			if r == route {
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+// record 记录一次成功的敏感读取；合并窗口内的重复读取只计数
+func (a *ReadAuditor) record(c *gin.Context, rule *model.ReadAuditRule, resourceID uint, details interface{}) {
+	actor, ok := apputils.ActorFromContext(c.Request.Context())
+	if a.policy.Window <= 0 || !ok || actor.UserID == 0 {
+		a.log(c, rule, resourceID, details)
+		return
+	}
+
+	key := readAuditKey{userID: actor.UserID, route: c.FullPath(), resourceID: resourceID}
+	now := time.Now()
+	a.mu.Lock()
+	if window, exists := a.windows[key]; exists {
+		window.repeats++
+		window.last = &model.AuditLog{
+			UserID:     actor.UserID,
+			Username:   apputils.ActorName(c.Request.Context()),
+			Action:     string(apputils.AuditActionRead),
+			Resource:   strings.ToUpper(rule.Resource),
+			ResourceID: resourceID,
+			IPAddress:  c.ClientIP(),
+			UserAgent:  c.Request.UserAgent(),
+			StatusCode: c.Writer.Status(),
+			Outcome:    model.AuditOutcomeSuccess,
+			CreatedAt:  now,
+		}
+		window.lastPath = c.Request.URL.Path
+		a.mu.Unlock()
+		return
+	}
+	window := &readAuditWindow{rule: *rule, firstRead: now}
+	window.closeTimer = time.AfterFunc(a.policy.Window, func() { a.closeWindow(key, window) })
+	a.windows[key] = window
+	a.mu.Unlock()
+
+	a.log(c, rule, resourceID, details)
+}
+
+// log 立即记录一条READ日志
+func (a *ReadAuditor) log(c *gin.Context, rule *model.ReadAuditRule, resourceID uint, details interface{}) {
+	if err := a.auditLogService.LogUserAction(c, string(apputils.AuditActionRead), rule.Resource, resourceID, details); err != nil {
+		a.logger.Error("Failed to log audit entry", zap.Error(err))
+	}
+}
+
+// closeWindow 结束合并窗口；有被合并的读取时写入一条汇总日志
+func (a *ReadAuditor) closeWindow(key readAuditKey, window *readAuditWindow) {
+	a.mu.Lock()
+	if a.windows[key] != window {
+		a.mu.Unlock()
+		return // 已经由Flush结束
+	}
+	delete(a.windows, key)
+	a.mu.Unlock()
+
+	if window.repeats == 0 {
+		return
+	}
+	summary := window.last
+	details, err := json.Marshal(map[string]interface{}{
+		"requestPath":   window.lastPath,
+		"method":        http.MethodGet,
+		"fields":        window.rule.Fields,
+		"aggregated":    true,
+		"repeatedReads": window.repeats,
+		"firstReadAt":   window.firstRead.UTC().Format(time.RFC3339Nano),
+		"lastReadAt":    summary.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		a.logger.Error("Failed to marshal aggregated read audit details", zap.Error(err))
+		return
+	}
+	summary.Details = string(details)
+	a.auditLogService.Record(context.Background(), summary)
+}
+
+// Flush 立即结束所有合并窗口并写入汇总日志，用于停机前和测试
+func (a *ReadAuditor) Flush() {
+	a.mu.Lock()
+	windows := make(map[readAuditKey]*readAuditWindow, len(a.windows))
+	for key, window := range a.windows {
+		window.closeTimer.Stop()
+		windows[key] = window
+	}
+	a.mu.Unlock()
+
+	for key, window := range windows {
+		a.closeWindow(key, window)
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"EffiPlat/backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientInfoMiddleware 将客户端IP和用户代理放入请求上下文，供服务层记录审计日志
+func ClientInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := utils.ClientInfo{IPAddress: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+		c.Request = c.Request.WithContext(utils.WithClientInfo(c.Request.Context(), info))
+		c.Next()
+	}
+}
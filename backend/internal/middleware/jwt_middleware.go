@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/utils"
 	"net/http"
 	"strings"
 
@@ -26,6 +27,9 @@ func JWTAuthMiddleware(jwtKey []byte) gin.HandlerFunc {
 		}
 		if claims, ok := token.Claims.(*model.Claims); ok {
 			c.Set("user", claims)
+			// Also expose the actor on the request context for the service layer.
+			actor := utils.Actor{UserID: claims.UserID, Username: claims.Name, Email: claims.Email}
+			c.Request = c.Request.WithContext(utils.WithActor(c.Request.Context(), actor))
 		}
 		c.Next()
 	}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+)
+
+// AttachmentOwnerType is the kind of record an attachment belongs to.
+type AttachmentOwnerType string
+
+const (
+	AttachmentOwnerBug      AttachmentOwnerType = "bug"
+	AttachmentOwnerBusiness AttachmentOwnerType = "business"
+	AttachmentOwnerService  AttachmentOwnerType = "service"
+)
+
+// Permissions for downloading attachments, one per owner type. Uploaders can always
+// download their own files.
+const (
+	PermissionDownloadBugAttachments      = "bug:download_attachments"
+	PermissionDownloadBusinessAttachments = "business:download_attachments"
+	PermissionDownloadServiceAttachments  = "service:download_attachments"
+	// PermissionDeleteAttachments allows deleting attachments uploaded by other users.
+	PermissionDeleteAttachments = "attachment:delete"
+)
+
+// IsValid checks if the owner type is one attachments can be added to.
+func (t AttachmentOwnerType) IsValid() bool {
+	switch t {
+	case AttachmentOwnerBug, AttachmentOwnerBusiness, AttachmentOwnerService:
+		return true
+	}
+	return false
+}
+
+// DownloadPermission returns the permission needed to download attachments of this owner type.
+func (t AttachmentOwnerType) DownloadPermission() string {
+	switch t {
+	case AttachmentOwnerBug:
+		return PermissionDownloadBugAttachments
+	case AttachmentOwnerBusiness:
+		return PermissionDownloadBusinessAttachments
+	case AttachmentOwnerService:
+		return PermissionDownloadServiceAttachments
+	}
+	return ""
+}
+
+// Attachment is a file uploaded to a bug, business or service. The content lives in the
+// storage backend under StorageKey, which is derived from its SHA-256 so identical uploads
+// share one stored object.
+type Attachment struct {
+	ID           uint                `gorm:"primarykey" json:"id"`
+	OwnerType    AttachmentOwnerType `gorm:"type:varchar(20);not null;index:idx_attachment_owner" json:"ownerType"`
+	OwnerID      uint                `gorm:"not null;index:idx_attachment_owner" json:"ownerId"`
+	FileName     string              `gorm:"type:varchar(255);not null" json:"fileName"`
+	ContentType  string              `gorm:"type:varchar(100);not null" json:"contentType"`
+	Size         int64               `gorm:"not null" json:"size"`
+	SHA256       string              `gorm:"column:sha256;type:char(64);not null;index" json:"sha256"`
+	StorageKey   string              `gorm:"type:varchar(255);not null" json:"-"`
+	UploadedByID *uint               `gorm:"index" json:"uploadedById,omitempty"`
+	CreatedAt    time.Time           `json:"createdAt"`
+}
+
+// TableName specifies the table name for the Attachment model.
+func (Attachment) TableName() string {
+	return "attachments"
+}
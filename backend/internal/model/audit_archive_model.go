@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// AuditLogArchive 记录一个已导出到归档文件并从audit_logs中清除的审计日志区间。
+// 归档按ID顺序覆盖连续的日志，LastHash 作为哈希链在被清除区间之后的锚点。
+type AuditLogArchive struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	ObjectKey     string     `json:"objectKey" gorm:"size:255;uniqueIndex;not null"` // 归档文件在存储中的键
+	FromID        uint       `json:"fromId" gorm:"not null"`
+	ToID          uint       `json:"toId" gorm:"not null;index"`
+	FromTime      time.Time  `json:"fromTime"`
+	ToTime        time.Time  `json:"toTime"`
+	EntryCount    int        `json:"entryCount"`
+	SizeBytes     int64      `json:"sizeBytes"`
+	Checksum      string     `json:"checksum" gorm:"size:64;not null"` // 压缩后归档文件的SHA-256
+	FirstPrevHash string     `json:"firstPrevHash" gorm:"size:64"`     // 区间第一条日志的PrevHash
+	LastHash      string     `json:"lastHash" gorm:"size:64;index"`    // 区间最后一条日志的Hash
+	ImportedAt    *time.Time `json:"importedAt"`                       // 重新导入以供调查的时间，未导入时为空
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// AuditArchiveRunResult 是一次归档清理的结果
+type AuditArchiveRunResult struct {
+	Cutoff   time.Time          `json:"cutoff"` // 早于该时间的日志被归档
+	Archives []*AuditLogArchive `json:"archives"`
+	Purged   int64              `json:"purged"` // 从audit_logs中清除的日志数
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// AuditChainBreak 描述审计日志哈希链中第一处断裂
+type AuditChainBreak struct {
+	ID               uint   `json:"id"`     // 出现问题的日志ID
+	Reason           string `json:"reason"` // HASH_MISMATCH、PREV_HASH_MISMATCH 或 MISSING_HASH
+	ExpectedPrevHash string `json:"expectedPrevHash,omitempty"`
+	ActualPrevHash   string `json:"actualPrevHash,omitempty"`
+	ExpectedHash     string `json:"expectedHash,omitempty"`
+	ActualHash       string `json:"actualHash,omitempty"`
+}
+
+const (
+	AuditChainHashMismatch     = "HASH_MISMATCH"      // 日志内容在写入后被修改
+	AuditChainPrevHashMismatch = "PREV_HASH_MISMATCH" // 前一条日志被删除、插入或调换了顺序
+	AuditChainMissingHash      = "MISSING_HASH"       // 哈希链开始后出现了没有哈希的日志
+)
+
+// AuditChainVerification 是审计日志哈希链的校验结果
+type AuditChainVerification struct {
+	Valid       bool             `json:"valid"`
+	Checked     int64            `json:"checked"`   // 校验过的日志数
+	Unchained   int64            `json:"unchained"` // 哈希链开始之前写入、没有哈希的历史日志数
+	Keyed       bool             `json:"keyed"`     // 是否使用HMAC密钥计算哈希
+	HeadID      uint             `json:"headId,omitempty"`
+	HeadHash    string           `json:"headHash,omitempty"` // 最后一条日志的哈希，可另行保存以发现截断
+	FirstBroken *AuditChainBreak `json:"firstBroken,omitempty"`
+	VerifiedAt  time.Time        `json:"verifiedAt"`
+}
+
+// AuditChainLock 是审计日志哈希链的锁行。追加和清除日志的事务先更新这一行，
+// 使共享同一数据库的多个进程（API服务和 cmd/audit）串行修改哈希链
+type AuditChainLock struct {
+	Name     string    `gorm:"primaryKey;size:64"`
+	LockedAt time.Time // 最近一次加锁的时间
+}
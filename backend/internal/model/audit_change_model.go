@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// AuditLogChange 是UPDATE审计日志中一个字段的变更。
+// 变更以"diff"保存在日志详情中并受哈希链保护；本表由详情派生，便于按资源和字段查询。
+type AuditLogChange struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	AuditLogID uint      `json:"auditLogId" gorm:"index;not null"`
+	UserID     uint      `json:"userId" gorm:"index"`
+	Username   string    `json:"username"`
+	Resource   string    `json:"resource" gorm:"size:64;index:idx_audit_log_changes_field"`
+	ResourceID uint      `json:"resourceId" gorm:"index"`
+	Field      string    `json:"field" gorm:"size:255;index:idx_audit_log_changes_field"` // 字段的JSON名称，嵌套字段用点号连接，如 "environment.name"
+	Op         string    `json:"op" gorm:"size:16"`                                       // added, removed 或 changed
+	OldValue   string    `json:"oldValue" gorm:"type:text"`                               // 字符串原样保存，其他值保存为JSON，敏感字段为掩码
+	NewValue   string    `json:"newValue" gorm:"type:text"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"index"` // 与审计日志的时间相同
+}
+
+// AuditLogChangeQueryParams 包含字段变更查询的参数
+type AuditLogChangeQueryParams struct {
+	Resource   *string `form:"resource"` // 如 ASSET，不区分大小写
+	Field      *string `form:"field"`    // 如 ipAddress，不区分大小写
+	ResourceID *uint   `form:"resourceId"`
+	UserID     *uint   `form:"userId"`
+	StartDate  *string `form:"startDate"` // 格式：YYYY-MM-DD
+	EndDate    *string `form:"endDate"`   // 格式：YYYY-MM-DD
+	Page       int     `form:"page,default=1"`
+	PageSize   int     `form:"pageSize,default=10"`
+}
@@ -5,10 +5,19 @@ import (
 	"gorm.io/gorm"
 )
 
+// AuditOutcome 表示被审计操作的结果
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "SUCCESS"
+	AuditOutcomeFailure AuditOutcome = "FAILURE" // 请求无效、登录失败或处理出错
+	AuditOutcomeDenied  AuditOutcome = "DENIED"  // 未认证或无权限（401/403）
+)
+
 // AuditLog 表示系统中的审计日志记录
 type AuditLog struct {
 	ID        uint           `json:"id" gorm:"primary_key"`
-	UserID    uint           `json:"userId" gorm:"index;not null"` // 执行操作的用户ID
+	UserID    uint           `json:"userId" gorm:"index;not null"` // 执行操作的用户ID，未认证的请求为0
 	Username  string         `json:"username"`                     // 执行操作的用户名（冗余存储，便于查询）
 	Action    string         `json:"action" gorm:"index;not null"` // 执行的操作（CREATE, UPDATE, DELETE, READ等）
 	Resource  string         `json:"resource" gorm:"index;not null"` // 操作的资源类型（User, Role, Asset等）
@@ -16,6 +25,11 @@ type AuditLog struct {
 	Details    string         `json:"details" gorm:"type:text"`    // 操作详情（JSON格式，包含变更前后的数据）
 	IPAddress  string         `json:"ipAddress"`                   // 操作者的IP地址
 	UserAgent  string         `json:"userAgent"`                   // 用户代理信息
+	Outcome    AuditOutcome   `json:"outcome" gorm:"size:16;index;not null;default:'SUCCESS'"` // 操作结果
+	Reason     string         `json:"reason" gorm:"type:text"`     // 失败或被拒绝的原因
+	StatusCode int            `json:"statusCode"`                  // HTTP状态码，非HTTP请求产生的日志为0
+	PrevHash   string         `json:"prevHash" gorm:"size:64"`     // 前一条日志的哈希
+	Hash       string         `json:"hash" gorm:"size:64;index"`   // 本条日志内容与PrevHash的SHA-256（配置密钥时为HMAC-SHA256）
 	CreatedAt  time.Time      `json:"createdAt"`
 	UpdatedAt  time.Time      `json:"updatedAt"`
 	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
@@ -32,6 +46,10 @@ type AuditLogResponse struct {
 	Details    string    `json:"details"`
 	IPAddress  string    `json:"ipAddress"`
 	UserAgent  string    `json:"userAgent"`
+	Outcome    AuditOutcome `json:"outcome"`
+	Reason     string    `json:"reason,omitempty"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Hash       string    `json:"hash,omitempty"`
 	CreatedAt  time.Time `json:"createdAt"`
 }
 
@@ -47,6 +65,10 @@ func (al *AuditLog) ToResponse() AuditLogResponse {
 		Details:    al.Details,
 		IPAddress:  al.IPAddress,
 		UserAgent:  al.UserAgent,
+		Outcome:    al.Outcome,
+		Reason:     al.Reason,
+		StatusCode: al.StatusCode,
+		Hash:       al.Hash,
 		CreatedAt:  al.CreatedAt,
 	}
 }
@@ -57,8 +79,21 @@ type AuditLogQueryParams struct {
 	Action     *string `form:"action"`
 	Resource   *string `form:"resource"`
 	ResourceID *uint   `form:"resourceId"`
+	Outcome    *string `form:"outcome"`   // SUCCESS, FAILURE 或 DENIED
+	IPAddress  *string `form:"ipAddress"`
+	Search     *string `form:"q"`         // 在详情中全文搜索，不区分大小写
 	StartDate  *string `form:"startDate"` // 格式：YYYY-MM-DD
 	EndDate    *string `form:"endDate"`   // 格式：YYYY-MM-DD
+	SortBy     string  `form:"sortBy,default=createdAt" validate:"omitempty,oneof=id createdAt userId username action resource outcome ipAddress"`
+	Order      string  `form:"order,default=desc" validate:"omitempty,oneof=asc desc"`
+	Cursor     *string `form:"cursor"`    // 游标分页：首页传空值，之后传上一页返回的nextCursor；此时忽略page
 	Page       int     `form:"page,default=1"`
 	PageSize   int     `form:"pageSize,default=10"`
 }
+
+// AuditLogCursorPage 是游标分页的审计日志查询结果，不计算总数
+type AuditLogCursorPage struct {
+	Items      []AuditLogResponse `json:"items"`
+	NextCursor string             `json:"nextCursor,omitempty"` // 为空表示没有更多记录
+	PageSize   int                `json:"pageSize"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// AuditPipelineStats 是审计日志写入管道的运行指标，计数均为进程启动以来的累计值
+type AuditPipelineStats struct {
+	QueueLength     int        `json:"queueLength"`     // 当前排队等待写入的日志数
+	QueueCapacity   int        `json:"queueCapacity"`   // 队列容量
+	Enqueued        int64      `json:"enqueued"`        // 进入队列的日志数
+	Written         int64      `json:"written"`         // 写入数据库的日志数（不含重放）
+	Batches         int64      `json:"batches"`         // 成功写入的批次数
+	BlockedEnqueues int64      `json:"blockedEnqueues"` // 队列已满、写入方需要等待的次数（背压）
+	WriteFailures   int64      `json:"writeFailures"`   // 写入数据库失败的批次数
+	Spilled         int64      `json:"spilled"`         // 落盘到本地文件等待重放的日志数
+	Replayed        int64      `json:"replayed"`        // 从本地文件重放写入的日志数
+	Dropped         int64      `json:"dropped"`         // 既未写入数据库也未能落盘而丢失的日志数
+	MissingActor    int64      `json:"missingActor"`    // 因请求中没有已认证用户而未记录的操作数
+	SpillFile       string     `json:"spillFile,omitempty"`
+	SpillBytes      int64      `json:"spillBytes"` // 落盘文件中等待重放的字节数
+	LastError       string     `json:"lastError,omitempty"`
+	LastFlushAt     *time.Time `json:"lastFlushAt,omitempty"` // 最近一次成功写入数据库的时间
+
+	Sinks []AuditSinkStats `json:"sinks,omitempty"` // 写入数据库后转发到外部系统（如syslog）的指标
+}
+
+// AuditSinkStats 是一个外部审计日志接收端的转发指标
+type AuditSinkStats struct {
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	Format         string     `json:"format"`
+	QueueLength    int        `json:"queueLength"`  // 等待转发的批次数
+	Delivered      int64      `json:"delivered"`    // 已转发的日志数
+	Retries        int64      `json:"retries"`      // 转发失败后重试的次数
+	DeadLettered   int64      `json:"deadLettered"` // 重试用尽或队列已满而写入死信文件的日志数
+	Dropped        int64      `json:"dropped"`      // 既未转发也未能写入死信文件而丢失的日志数
+	DeadLetterFile string     `json:"deadLetterFile,omitempty"`
+	LastError      string     `json:"lastError,omitempty"`
+	LastDeliveryAt *time.Time `json:"lastDeliveryAt,omitempty"` // 最近一次成功转发的时间
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// BugAssignmentStrategyType decides which member of a responsibility group receives a bug.
+type BugAssignmentStrategyType string
+
+const (
+	// BugAssignmentStrategyPrimary assigns the group's primary member, falling back to the
+	// first active member when the primary is missing or inactive.
+	BugAssignmentStrategyPrimary BugAssignmentStrategyType = "primary"
+	// BugAssignmentStrategyRoundRobin rotates through the group's active members.
+	BugAssignmentStrategyRoundRobin BugAssignmentStrategyType = "round_robin"
+)
+
+// IsValid checks if the assignment strategy is one of the predefined values.
+func (s BugAssignmentStrategyType) IsValid() bool {
+	switch s {
+	case BugAssignmentStrategyPrimary, BugAssignmentStrategyRoundRobin:
+		return true
+	}
+	return false
+}
+
+// BugAssignmentRule maps a service, business or environment to the responsibility group that
+// handles its bugs. Every non-nil Match* field must equal the bug's link for the rule to fire,
+// and enabled rules are evaluated by ascending Priority, then ID.
+type BugAssignmentRule struct {
+	ID                    uint                      `gorm:"primarykey" json:"id"`
+	Name                  string                    `gorm:"type:varchar(100);not null" json:"name"`
+	Description           string                    `gorm:"type:varchar(255)" json:"description,omitempty"`
+	Priority              int                       `gorm:"not null;default:100;index" json:"priority"`
+	Enabled               bool                      `gorm:"not null;default:true" json:"enabled"`
+	MatchServiceID        *uint                     `gorm:"index" json:"matchServiceId,omitempty"`
+	MatchBusinessID       *uint                     `gorm:"index" json:"matchBusinessId,omitempty"`
+	MatchEnvironmentID    *uint                     `gorm:"index" json:"matchEnvironmentId,omitempty"`
+	ResponsibilityGroupID uint                      `gorm:"not null;index" json:"responsibilityGroupId"`
+	Strategy              BugAssignmentStrategyType `gorm:"type:varchar(20);not null;default:'primary'" json:"strategy"`
+	LastAssignedUserID    *uint                     `json:"lastAssignedUserId,omitempty"` // Round-robin cursor
+	CreatedAt             time.Time                 `json:"createdAt"`
+	UpdatedAt             time.Time                 `json:"updatedAt"`
+}
+
+// TableName specifies the table name for the BugAssignmentRule model.
+func (BugAssignmentRule) TableName() string {
+	return "bug_assignment_rules"
+}
+
+// Matches reports whether the rule applies to a bug with the given links.
+// A rule without any match field never matches.
+func (r *BugAssignmentRule) Matches(serviceID, businessID, environmentID *uint) bool {
+	if r.MatchServiceID == nil && r.MatchBusinessID == nil && r.MatchEnvironmentID == nil {
+		return false
+	}
+	return matchLink(r.MatchServiceID, serviceID) &&
+		matchLink(r.MatchBusinessID, businessID) &&
+		matchLink(r.MatchEnvironmentID, environmentID)
+}
+
+func matchLink(want, got *uint) bool {
+	if want == nil {
+		return true
+	}
+	return got != nil && *got == *want
+}
@@ -0,0 +1,63 @@
+package model
+
+// BulkBugUpdateMaxBugs caps how many bugs one bulk update may touch.
+const BulkBugUpdateMaxBugs = 500
+
+// BulkBugUpdateRequest applies the same changes to many bugs, picked either by ID or by the
+// filters of the bug list (pagination is ignored).
+type BulkBugUpdateRequest struct {
+	IDs    []uint         `json:"ids" validate:"omitempty,max=500,dive,gt=0"`
+	Filter *BugListParams `json:"filter"` // Same fields as the GET /bugs query, e.g. {"status": "OPEN", "assigneeId": 3}
+	Update BulkBugChanges `json:"update"`
+	// Atomic applies the changes to all bugs or to none; otherwise each bug is updated on its
+	// own and failures are reported per bug.
+	Atomic bool `json:"atomic"`
+}
+
+// BulkBugChanges are the changes of a bulk update; unset fields are left alone.
+type BulkBugChanges struct {
+	Status *BugStatusType `json:"status" validate:"omitempty,max=50"`
+	// Resolution and Comment accompany a status change, as in UpdateBugRequest.
+	Resolution   *BugResolutionType `json:"resolution" validate:"omitempty,oneof=FIXED WONT_FIX DUPLICATE CANNOT_REPRODUCE BY_DESIGN"`
+	Comment      string             `json:"comment" validate:"omitempty,max=2000"`
+	Priority     *BugPriorityType   `json:"priority" validate:"omitempty,oneof=LOW MEDIUM HIGH URGENT"`
+	AssigneeID   *uint              `json:"assigneeId"` // 0 unassigns
+	AddLabels    []string           `json:"addLabels" validate:"omitempty,max=20,dive,min=1,max=50"`
+	RemoveLabels []string           `json:"removeLabels" validate:"omitempty,max=20,dive,min=1,max=50"`
+}
+
+// IsEmpty reports whether the changes would leave every bug as it is.
+func (c *BulkBugChanges) IsEmpty() bool {
+	return c.Status == nil && c.Resolution == nil && c.Priority == nil && c.AssigneeID == nil &&
+		len(c.AddLabels) == 0 && len(c.RemoveLabels) == 0
+}
+
+// BulkBugResultStatus is the outcome of a bulk update for one bug.
+type BulkBugResultStatus string
+
+const (
+	BulkBugUpdated   BulkBugResultStatus = "UPDATED"
+	BulkBugUnchanged BulkBugResultStatus = "UNCHANGED" // The bug already matched the changes
+	BulkBugFailed    BulkBugResultStatus = "FAILED"
+	BulkBugSkipped   BulkBugResultStatus = "SKIPPED" // Not saved because another bug of an atomic update failed
+)
+
+// BulkBugUpdateResult is the outcome of a bulk update for one bug, with what was changed.
+type BulkBugUpdateResult struct {
+	ID         uint                 `json:"id"`
+	Status     BulkBugResultStatus  `json:"status"`
+	Error      string               `json:"error,omitempty"`
+	Bug        *BugResponse         `json:"bug,omitempty"`
+	Changes    []*BugFieldChange    `json:"changes,omitempty"`
+	Transition *BugStatusTransition `json:"transition,omitempty"`
+}
+
+// BulkBugUpdateResponse reports a bulk update, one result per matched bug in request order.
+type BulkBugUpdateResponse struct {
+	Atomic    bool                  `json:"atomic"`
+	Matched   int                   `json:"matched"`
+	Updated   int                   `json:"updated"`
+	Unchanged int                   `json:"unchanged"`
+	Failed    int                   `json:"failed"`
+	Results   []BulkBugUpdateResult `json:"results"`
+}
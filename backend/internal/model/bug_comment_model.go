@@ -0,0 +1,156 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BugComment is a Markdown comment on a bug. Edits keep the previous body as a
+// BugCommentRevision; deleting a comment only soft-deletes it so threads stay readable.
+type BugComment struct {
+	ID        uint                `gorm:"primarykey" json:"id"`
+	BugID     uint                `gorm:"index;not null" json:"bugId"`
+	AuthorID  *uint               `gorm:"index" json:"authorId"`
+	Body      string              `gorm:"type:text;not null" json:"body"` // Markdown
+	EditCount int                 `gorm:"not null;default:0" json:"editCount"`
+	EditedAt  *time.Time          `json:"editedAt,omitempty"`
+	CreatedAt time.Time           `json:"createdAt"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt      `gorm:"index" json:"deletedAt,omitempty"`
+	Author    *User               `gorm:"foreignKey:AuthorID" json:"-"`
+	Mentions  []BugCommentMention `gorm:"foreignKey:CommentID" json:"-"`
+}
+
+// TableName specifies the table name for the BugComment model.
+func (BugComment) TableName() string {
+	return "bug_comments"
+}
+
+// BugCommentMention links a comment to a user it @mentions.
+type BugCommentMention struct {
+	CommentID uint  `gorm:"primaryKey" json:"commentId"`
+	UserID    uint  `gorm:"primaryKey" json:"userId"`
+	User      *User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName specifies the table name for the BugCommentMention model.
+func (BugCommentMention) TableName() string {
+	return "bug_comment_mentions"
+}
+
+// BugCommentRevision is the body a comment had before an edit.
+type BugCommentRevision struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CommentID uint      `gorm:"index;not null" json:"commentId"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	EditorID  *uint     `json:"editorId,omitempty"` // Who replaced this body
+	CreatedAt time.Time `json:"createdAt"`          // When it was replaced
+}
+
+// TableName specifies the table name for the BugCommentRevision model.
+func (BugCommentRevision) TableName() string {
+	return "bug_comment_revisions"
+}
+
+// BugFieldChange records one field of a bug changing value. Status changes are recorded
+// as BugStatusTransition instead.
+type BugFieldChange struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	BugID     uint      `gorm:"index;not null" json:"bugId"`
+	Field     string    `gorm:"type:varchar(50);not null" json:"field"` // JSON name of the field, e.g. "assigneeId"
+	OldValue  string    `gorm:"type:text" json:"oldValue"`
+	NewValue  string    `gorm:"type:text" json:"newValue"`
+	ActorID   *uint     `gorm:"index" json:"actorId,omitempty"`
+	Actor     string    `gorm:"type:varchar(255)" json:"actor,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName specifies the table name for the BugFieldChange model.
+func (BugFieldChange) TableName() string {
+	return "bug_field_changes"
+}
+
+// --- Request/Response Structs for Bug comments ---
+
+// CreateBugCommentRequest defines the structure for commenting on a bug.
+type CreateBugCommentRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=20000"`
+}
+
+// UpdateBugCommentRequest defines the structure for editing a comment.
+type UpdateBugCommentRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=20000"`
+}
+
+// BugUserSummary is the part of a user embedded in comment responses.
+type BugUserSummary struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// BugCommentResponse defines a standard way to return comment data.
+// The body of a deleted comment is withheld.
+type BugCommentResponse struct {
+	ID        uint             `json:"id"`
+	BugID     uint             `json:"bugId"`
+	AuthorID  *uint            `json:"authorId,omitempty"`
+	Author    *BugUserSummary  `json:"author,omitempty"`
+	Body      string           `json:"body"`
+	Mentions  []BugUserSummary `json:"mentions"`
+	EditCount int              `json:"editCount"`
+	EditedAt  *time.Time       `json:"editedAt,omitempty"`
+	Deleted   bool             `json:"deleted,omitempty"`
+	CreatedAt time.Time        `json:"createdAt"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
+
+// ToBugCommentResponse converts a BugComment model to a BugCommentResponse.
+func (c *BugComment) ToBugCommentResponse() BugCommentResponse {
+	resp := BugCommentResponse{
+		ID:        c.ID,
+		BugID:     c.BugID,
+		AuthorID:  c.AuthorID,
+		Body:      c.Body,
+		Mentions:  []BugUserSummary{},
+		EditCount: c.EditCount,
+		EditedAt:  c.EditedAt,
+		Deleted:   c.DeletedAt.Valid,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	if c.Author != nil {
+		resp.Author = &BugUserSummary{ID: c.Author.ID, Name: c.Author.Name, Email: c.Author.Email}
+	}
+	for _, mention := range c.Mentions {
+		if mention.User != nil {
+			resp.Mentions = append(resp.Mentions, BugUserSummary{ID: mention.User.ID, Name: mention.User.Name, Email: mention.User.Email})
+		}
+	}
+	if resp.Deleted {
+		resp.Body = ""
+		resp.Mentions = []BugUserSummary{}
+	}
+	return resp
+}
+
+// BugActivityType is the kind of entry in a bug's activity feed.
+type BugActivityType string
+
+const (
+	BugActivityComment          BugActivityType = "comment"
+	BugActivityFieldChange      BugActivityType = "field_change"
+	BugActivityStatusTransition BugActivityType = "status_transition"
+)
+
+// BugActivityItem is one entry of a bug's activity feed. Exactly one of Comment,
+// FieldChange and Transition is set, according to Type.
+type BugActivityItem struct {
+	Type        BugActivityType      `json:"type"`
+	At          time.Time            `json:"at"`
+	ActorID     *uint                `json:"actorId,omitempty"`
+	Comment     *BugCommentResponse  `json:"comment,omitempty"`
+	FieldChange *BugFieldChange      `json:"fieldChange,omitempty"`
+	Transition  *BugStatusTransition `json:"transition,omitempty"`
+}
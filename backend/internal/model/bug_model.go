@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -13,10 +14,22 @@ const (
 	BugStatusOpen       BugStatusType = "OPEN"
 	BugStatusInProgress BugStatusType = "IN_PROGRESS"
 	BugStatusResolved   BugStatusType = "RESOLVED"
+	BugStatusVerified   BugStatusType = "VERIFIED" // Fix confirmed by the reporter or QA
 	BugStatusClosed     BugStatusType = "CLOSED"
 	BugStatusReopened   BugStatusType = "REOPENED"
 )
 
+// BugResolutionType records how a bug was resolved
+type BugResolutionType string
+
+const (
+	BugResolutionFixed           BugResolutionType = "FIXED"
+	BugResolutionWontFix         BugResolutionType = "WONT_FIX"
+	BugResolutionDuplicate       BugResolutionType = "DUPLICATE"
+	BugResolutionCannotReproduce BugResolutionType = "CANNOT_REPRODUCE"
+	BugResolutionByDesign        BugResolutionType = "BY_DESIGN"
+)
+
 // BugPriorityType defines the priority levels for a bug
 type BugPriorityType string
 
@@ -29,18 +42,40 @@ const (
 
 // Bug represents a bug or issue reported in the system.
 type Bug struct {
-	ID          uint            `gorm:"primarykey" json:"id"`
-	Title       string          `gorm:"type:varchar(255);not null" json:"title" binding:"required,min=5,max=255"`
-	Description string          `gorm:"type:text" json:"description"`
-	Status      BugStatusType   `gorm:"type:varchar(50);default:'OPEN';not null" json:"status" binding:"required"`
-	Priority    BugPriorityType `gorm:"type:varchar(50);default:'MEDIUM';not null" json:"priority" binding:"required"`
-	ReporterID  *uint           `json:"reporterId"` // Optional: Link to user who reported it
-	AssigneeID  *uint           `json:"assigneeId"` // Optional: Link to user assigned to fix it
+	ID          uint               `gorm:"primarykey" json:"id"`
+	Title       string             `gorm:"type:varchar(255);not null" json:"title" binding:"required,min=5,max=255"`
+	Description string             `gorm:"type:text" json:"description"`
+	Status      BugStatusType      `gorm:"type:varchar(50);default:'OPEN';not null" json:"status" binding:"required"`
+	Priority    BugPriorityType    `gorm:"type:varchar(50);default:'MEDIUM';not null" json:"priority" binding:"required"`
+	Resolution  *BugResolutionType `gorm:"type:varchar(50)" json:"resolution,omitempty"` // Set while the bug is RESOLVED/VERIFIED/CLOSED
+	ReporterID  *uint              `json:"reporterId"`                                   // Optional: Link to user who reported it
+	AssigneeID  *uint              `json:"assigneeId"`                                   // Optional: Link to user assigned to fix it
+	// AssignmentRuleID is the rule that picked AssigneeID when the bug was filed, if any.
+	AssignmentRuleID *uint `json:"assignmentRuleId"`
 	// ProjectID   *uint           `json:"projectId"`  // Optional: If bugs are tied to projects
-	// EnvironmentID *uint        `json:"environmentId"` // Optional: Environment where bug was found
-	CreatedAt time.Time      `json:"createdAt"`
-	UpdatedAt time.Time      `json:"updatedAt"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+	EnvironmentID  *uint  `gorm:"index" json:"environmentId"`                        // Environment where the bug occurred
+	BusinessID     *uint  `gorm:"index" json:"businessId"`                           // Business/requirement the bug affects
+	ServiceID      *uint  `gorm:"index" json:"serviceId"`                            // Service the bug was found in
+	ServiceVersion string `gorm:"type:varchar(100)" json:"serviceVersion,omitempty"` // Version of ServiceID the bug was found in
+	// Labels are free-form tags, kept trimmed, unique and sorted.
+	Labels datatypes.JSONSlice[string] `gorm:"type:json;not null;default:'[]'" json:"labels"`
+	// SLA clocks, set from the BugSLAPolicy of the bug's priority; see EvaluateSLA.
+	ResponseDueAt      *time.Time     `gorm:"index" json:"responseDueAt,omitempty"`
+	ResolveDueAt       *time.Time     `gorm:"index" json:"resolveDueAt,omitempty"`
+	FirstRespondedAt   *time.Time     `json:"firstRespondedAt,omitempty"` // First status change, or first comment by someone other than the reporter
+	ResolvedAt         *time.Time     `json:"resolvedAt,omitempty"`       // When the bug entered a resolved status; cleared when it leaves one
+	ResponseBreachedAt *time.Time     `json:"responseBreachedAt,omitempty"`
+	ResolveBreachedAt  *time.Time     `json:"resolveBreachedAt,omitempty"`
+	EscalatedAt        *time.Time     `json:"escalatedAt,omitempty"`   // A bug is escalated at most once
+	EscalatedToID      *uint          `json:"escalatedToId,omitempty"` // The user the SLA checker reassigned the bug to
+	CreatedAt          time.Time      `json:"createdAt"`
+	UpdatedAt          time.Time      `json:"updatedAt"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	// Linked entities, preloaded by the repository for responses
+	Environment *Environment `gorm:"foreignKey:EnvironmentID" json:"-"`
+	Business    *Business    `gorm:"foreignKey:BusinessID" json:"-"`
+	Service     *Service     `gorm:"foreignKey:ServiceID" json:"-"`
 }
 
 // TableName specifies the table name for the Bug model.
@@ -54,38 +89,85 @@ func (Bug) TableName() string {
 type CreateBugRequest struct {
 	Title       string          `json:"title" validate:"required,min=5,max=255"`
 	Description string          `json:"description" validate:"omitempty"`
-	Status      BugStatusType   `json:"status" validate:"required,enum=OPEN|IN_PROGRESS|RESOLVED|CLOSED|REOPENED"`
-	Priority    BugPriorityType `json:"priority" validate:"required,enum=LOW|MEDIUM|HIGH|URGENT"`
+	Status      BugStatusType   `json:"status" validate:"omitempty,max=50"` // Defaults to the workflow's initial status
+	Priority    BugPriorityType `json:"priority" validate:"required,oneof=LOW MEDIUM HIGH URGENT"`
 	ReporterID  *uint           `json:"reporterId" validate:"omitempty,gt=0"`
 	AssigneeID  *uint           `json:"assigneeId" validate:"omitempty,gt=0"`
 	// ProjectID   *uint        `json:"projectId" validate:"omitempty,gt=0"`
-	// EnvironmentID *uint     `json:"environmentId" validate:"omitempty,gt=0"`
+	EnvironmentID  *uint    `json:"environmentId" validate:"omitempty,gt=0"`
+	BusinessID     *uint    `json:"businessId" validate:"omitempty,gt=0"`
+	ServiceID      *uint    `json:"serviceId" validate:"omitempty,gt=0"`
+	ServiceVersion string   `json:"serviceVersion" validate:"omitempty,max=100"` // Requires ServiceID
+	Labels         []string `json:"labels" validate:"omitempty,max=20,dive,min=1,max=50"`
+	// CheckDuplicates refuses to file the bug while likely duplicates of it exist; they are returned instead.
+	CheckDuplicates bool `json:"checkDuplicates"`
 }
 
 // UpdateBugRequest defines the structure for updating an existing bug.
 type UpdateBugRequest struct {
 	Title       *string          `json:"title" validate:"omitempty,min=5,max=255"`
 	Description *string          `json:"description" validate:"omitempty"`
-	Status      *BugStatusType   `json:"status" validate:"omitempty,enum=OPEN|IN_PROGRESS|RESOLVED|CLOSED|REOPENED"`
-	Priority    *BugPriorityType `json:"priority" validate:"omitempty,enum=LOW|MEDIUM|HIGH|URGENT"`
+	Status      *BugStatusType   `json:"status" validate:"omitempty,max=50"`
+	Priority    *BugPriorityType `json:"priority" validate:"omitempty,oneof=LOW MEDIUM HIGH URGENT"`
 	AssigneeID  *uint            `json:"assigneeId" validate:"omitempty,gt=0"`
+	// Resolution and Comment accompany a status change; both are required to enter RESOLVED or CLOSED.
+	Resolution *BugResolutionType `json:"resolution" validate:"omitempty,oneof=FIXED WONT_FIX DUPLICATE CANNOT_REPRODUCE BY_DESIGN"`
+	Comment    string             `json:"comment" validate:"omitempty,max=2000"`
 	// ProjectID   *uint         `json:"projectId" validate:"omitempty,gt=0"`
-	// EnvironmentID *uint      `json:"environmentId" validate:"omitempty,gt=0"`
+	// Context links; 0 (or "" for the version) removes a link.
+	EnvironmentID  *uint   `json:"environmentId"`
+	BusinessID     *uint   `json:"businessId"`
+	ServiceID      *uint   `json:"serviceId"`
+	ServiceVersion *string `json:"serviceVersion" validate:"omitempty,max=100"`
+	// Labels replaces all of the bug's labels; an empty list removes them.
+	Labels *[]string `json:"labels" validate:"omitempty,max=20,dive,min=1,max=50"`
 }
 
 // BugResponse defines a standard way to return bug data.
 type BugResponse struct {
-	ID          uint            `json:"id"`
-	Title       string          `json:"title"`
-	Description string          `json:"description"`
-	Status      BugStatusType   `json:"status"`
-	Priority    BugPriorityType `json:"priority"`
-	ReporterID  *uint           `json:"reporterId,omitempty"`
-	AssigneeID  *uint           `json:"assigneeId,omitempty"`
+	ID          uint               `json:"id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Status      BugStatusType      `json:"status"`
+	Priority    BugPriorityType    `json:"priority"`
+	Resolution  *BugResolutionType `json:"resolution,omitempty"`
+	ReporterID  *uint              `json:"reporterId,omitempty"`
+	AssigneeID  *uint              `json:"assigneeId,omitempty"`
+	// AssignmentRuleID is set when AssigneeID was chosen by an assignment rule.
+	AssignmentRuleID *uint `json:"assignmentRuleId,omitempty"`
 	// ProjectID   *uint        `json:"projectId,omitempty"`
-	// EnvironmentID *uint     `json:"environmentId,omitempty"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	EnvironmentID  *uint                  `json:"environmentId,omitempty"`
+	BusinessID     *uint                  `json:"businessId,omitempty"`
+	ServiceID      *uint                  `json:"serviceId,omitempty"`
+	ServiceVersion string                 `json:"serviceVersion,omitempty"`
+	Labels         []string               `json:"labels"`
+	Environment    *BugEnvironmentSummary `json:"environment,omitempty"`
+	Business       *BugBusinessSummary    `json:"business,omitempty"`
+	Service        *BugServiceSummary     `json:"service,omitempty"`
+	SLA            *BugSLASummary         `json:"sla,omitempty"` // Absent when no SLA policy applies
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+}
+
+// BugEnvironmentSummary is the part of a linked environment embedded in a BugResponse.
+type BugEnvironmentSummary struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// BugBusinessSummary is the part of a linked business embedded in a BugResponse.
+type BugBusinessSummary struct {
+	ID     uint               `json:"id"`
+	Name   string             `json:"name"`
+	Status BusinessStatusType `json:"status,omitempty"`
+}
+
+// BugServiceSummary is the part of a linked service embedded in a BugResponse.
+type BugServiceSummary struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"` // The bug's ServiceVersion
 }
 
 // BugListParams defines parameters for listing bugs.
@@ -98,24 +180,51 @@ type BugListParams struct {
 	AssigneeID *uint           `form:"assigneeId"` // Filter by assignee
 	ReporterID *uint           `form:"reporterId"` // Filter by reporter
 	// ProjectID   *uint        `form:"projectId"`   // Filter by project
-	// EnvironmentID *uint     `form:"environmentId"`// Filter by environment
+	EnvironmentID  *uint  `form:"environmentId"`  // Filter by environment
+	BusinessID     *uint  `form:"businessId"`     // Filter by business
+	ServiceID      *uint  `form:"serviceId"`      // Filter by service
+	ServiceVersion string `form:"serviceVersion"` // Filter by service version (exact match)
+	Label          string `form:"label"`          // Bugs carrying this label
+	// SLA filters
+	SLABreached  *bool      `form:"slaBreached"`                                          // Bugs that have (or have not) breached an SLA
+	SLADueBefore *time.Time `form:"slaDueBefore" time_format:"2006-01-02T15:04:05Z07:00"` // Bugs with a running SLA clock due before this time
 }
 
 // ToBugResponse converts a Bug model to a BugResponse.
 func (b *Bug) ToBugResponse() BugResponse {
-	return BugResponse{
+	resp := BugResponse{
 		ID:          b.ID,
 		Title:       b.Title,
 		Description: b.Description,
 		Status:      b.Status,
 		Priority:    b.Priority,
+		Resolution:  b.Resolution,
 		ReporterID:  b.ReporterID,
 		AssigneeID:  b.AssigneeID,
 		// ProjectID:   b.ProjectID,
-		// EnvironmentID: b.EnvironmentID,
-		CreatedAt: b.CreatedAt,
-		UpdatedAt: b.UpdatedAt,
+		AssignmentRuleID: b.AssignmentRuleID,
+		EnvironmentID:    b.EnvironmentID,
+		BusinessID:       b.BusinessID,
+		ServiceID:        b.ServiceID,
+		ServiceVersion:   b.ServiceVersion,
+		Labels:           []string(b.Labels),
+		CreatedAt:        b.CreatedAt,
+		UpdatedAt:        b.UpdatedAt,
+	}
+	if b.Environment != nil {
+		resp.Environment = &BugEnvironmentSummary{ID: b.Environment.ID, Name: b.Environment.Name, Slug: b.Environment.Slug}
+	}
+	if b.Business != nil {
+		resp.Business = &BugBusinessSummary{ID: b.Business.ID, Name: b.Business.Name, Status: b.Business.Status}
+	}
+	if b.Service != nil {
+		resp.Service = &BugServiceSummary{ID: b.Service.ID, Name: b.Service.Name, Version: b.ServiceVersion}
+	}
+	if resp.Labels == nil {
+		resp.Labels = []string{}
 	}
+	resp.SLA = b.slaSummary(time.Now())
+	return resp
 }
 
 // ToBugResponses converts a slice of Bug models to a slice of BugResponse.
@@ -0,0 +1,97 @@
+package model
+
+import "time"
+
+// BugRelationType defines how two bugs are related. Relations read from source to target.
+type BugRelationType string
+
+const (
+	BugRelationDuplicates BugRelationType = "DUPLICATES" // Source is a duplicate of target
+	BugRelationBlocks     BugRelationType = "BLOCKS"     // Source must be fixed before target
+	BugRelationRelatesTo  BugRelationType = "RELATES_TO" // Symmetric
+)
+
+// BugRelationDirection tells whether a bug is the source or the target of a relation.
+type BugRelationDirection string
+
+const (
+	BugRelationOutward BugRelationDirection = "OUTWARD" // The bug is the source
+	BugRelationInward  BugRelationDirection = "INWARD"  // The bug is the target
+)
+
+// BugRelation links two bugs. A bug duplicates at most one other bug, and BLOCKS relations
+// never form a cycle.
+type BugRelation struct {
+	ID          uint            `gorm:"primarykey" json:"id"`
+	SourceBugID uint            `gorm:"not null;uniqueIndex:idx_bug_relation" json:"sourceBugId"`
+	TargetBugID uint            `gorm:"not null;index;uniqueIndex:idx_bug_relation" json:"targetBugId"`
+	Type        BugRelationType `gorm:"type:varchar(20);not null;uniqueIndex:idx_bug_relation" json:"type"`
+	ActorID     *uint           `json:"actorId,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	SourceBug   *Bug            `gorm:"foreignKey:SourceBugID" json:"-"`
+	TargetBug   *Bug            `gorm:"foreignKey:TargetBugID" json:"-"`
+}
+
+// TableName specifies the table name for the BugRelation model.
+func (BugRelation) TableName() string {
+	return "bug_relations"
+}
+
+// --- Request/Response Structs for Bug relations ---
+
+// CreateBugRelationRequest defines the structure for relating a bug to another one.
+type CreateBugRelationRequest struct {
+	Type        BugRelationType `json:"type" validate:"required,oneof=DUPLICATES BLOCKS RELATES_TO"`
+	TargetBugID uint            `json:"targetBugId" validate:"required,gt=0"`
+	Comment     string          `json:"comment" validate:"omitempty,max=2000"` // Added to the transition when a duplicate is closed
+}
+
+// BugRelationResponse is a relation as seen from one of its bugs.
+type BugRelationResponse struct {
+	ID        uint                 `json:"id"`
+	Type      BugRelationType      `json:"type"`
+	Direction BugRelationDirection `json:"direction"`
+	Bug       BugRelationSummary   `json:"bug"` // The bug on the other end
+	ActorID   *uint                `json:"actorId,omitempty"`
+	CreatedAt time.Time            `json:"createdAt"`
+}
+
+// BugRelationSummary is the part of a related bug embedded in a BugRelationResponse.
+type BugRelationSummary struct {
+	ID         uint               `json:"id"`
+	Title      string             `json:"title"`
+	Status     BugStatusType      `json:"status"`
+	Resolution *BugResolutionType `json:"resolution,omitempty"`
+}
+
+// ToBugRelationResponse converts a relation to a response from the point of view of bugID.
+// The related bugs must be preloaded.
+func (r *BugRelation) ToBugRelationResponse(bugID uint) BugRelationResponse {
+	resp := BugRelationResponse{
+		ID:        r.ID,
+		Type:      r.Type,
+		Direction: BugRelationOutward,
+		ActorID:   r.ActorID,
+		CreatedAt: r.CreatedAt,
+	}
+	other := r.TargetBug
+	if r.SourceBugID != bugID {
+		resp.Direction, other = BugRelationInward, r.SourceBug
+	}
+	if other != nil {
+		resp.Bug = BugRelationSummary{ID: other.ID, Title: other.Title, Status: other.Status, Resolution: other.Resolution}
+	}
+	return resp
+}
+
+// FindSimilarBugsRequest defines the structure for searching bugs similar to a new one.
+type FindSimilarBugsRequest struct {
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description" validate:"omitempty"`
+}
+
+// BugSimilarityResponse is an existing bug that is likely a duplicate of a new one.
+type BugSimilarityResponse struct {
+	Bug   BugResponse `json:"bug"`
+	Score float64     `json:"score"` // 0 to 1, higher is more similar
+}
@@ -0,0 +1,128 @@
+package model
+
+import "time"
+
+// BugSLAPolicy sets the service level targets for bugs of one priority. A zero target means
+// the clock is not tracked; disabled policies give new bugs no due times at all.
+type BugSLAPolicy struct {
+	ID                   uint            `gorm:"primarykey" json:"id"`
+	Priority             BugPriorityType `gorm:"type:varchar(50);uniqueIndex;not null" json:"priority"`
+	FirstResponseMinutes int             `gorm:"not null;default:0" json:"firstResponseMinutes"` // Time to first response
+	ResolutionMinutes    int             `gorm:"not null;default:0" json:"resolutionMinutes"`    // Time to resolve
+	Enabled              bool            `gorm:"not null;default:true" json:"enabled"`
+	CreatedAt            time.Time       `json:"createdAt"`
+	UpdatedAt            time.Time       `json:"updatedAt"`
+}
+
+// TableName specifies the table name for the BugSLAPolicy model.
+func (BugSLAPolicy) TableName() string {
+	return "bug_sla_policies"
+}
+
+// DueTimes returns when the first response and the resolution are due for a bug filed at
+// "from". A nil time means the policy does not track that clock.
+func (p *BugSLAPolicy) DueTimes(from time.Time) (responseDue, resolveDue *time.Time) {
+	if p == nil || !p.Enabled {
+		return nil, nil
+	}
+	if p.FirstResponseMinutes > 0 {
+		due := from.Add(time.Duration(p.FirstResponseMinutes) * time.Minute)
+		responseDue = &due
+	}
+	if p.ResolutionMinutes > 0 {
+		due := from.Add(time.Duration(p.ResolutionMinutes) * time.Minute)
+		resolveDue = &due
+	}
+	return responseDue, resolveDue
+}
+
+// ApplySLAPolicy (re)computes the bug's due times from its creation time. Breach flags whose
+// due time moved are cleared; the SLA checker sets them again if the new target is missed too.
+func (b *Bug) ApplySLAPolicy(policy *BugSLAPolicy) {
+	responseDue, resolveDue := policy.DueTimes(b.CreatedAt)
+	if !sameTime(b.ResponseDueAt, responseDue) {
+		b.ResponseDueAt = responseDue
+		b.ResponseBreachedAt = nil
+	}
+	if !sameTime(b.ResolveDueAt, resolveDue) {
+		b.ResolveDueAt = resolveDue
+		b.ResolveBreachedAt = nil
+	}
+}
+
+// BugSLAStatus is the state of a bug's SLA clocks at a point in time.
+type BugSLAStatus struct {
+	ResponseBreached bool
+	ResolveBreached  bool
+}
+
+// Breached reports whether either clock is breached.
+func (s BugSLAStatus) Breached() bool {
+	return s.ResponseBreached || s.ResolveBreached
+}
+
+// EvaluateSLA checks the bug's SLA clocks at "now". A clock is breached when the checker has
+// flagged it, when it is still running past its due time, or when it stopped after it.
+func (b *Bug) EvaluateSLA(now time.Time) BugSLAStatus {
+	return BugSLAStatus{
+		ResponseBreached: b.ResponseBreachedAt != nil || missed(b.ResponseDueAt, b.FirstRespondedAt, now),
+		ResolveBreached:  b.ResolveBreachedAt != nil || missed(b.ResolveDueAt, b.ResolvedAt, now),
+	}
+}
+
+// missed reports whether a clock due at "due" and stopped at "done" (nil while running) missed its target.
+func missed(due, done *time.Time, now time.Time) bool {
+	if due == nil {
+		return false
+	}
+	if done != nil {
+		return done.After(*due)
+	}
+	return now.After(*due)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// BugSLASummary is the SLA state embedded in a BugResponse.
+type BugSLASummary struct {
+	ResponseDueAt    *time.Time `json:"responseDueAt,omitempty"`
+	ResolveDueAt     *time.Time `json:"resolveDueAt,omitempty"`
+	FirstRespondedAt *time.Time `json:"firstRespondedAt,omitempty"`
+	ResolvedAt       *time.Time `json:"resolvedAt,omitempty"`
+	ResponseBreached bool       `json:"responseBreached"`
+	ResolveBreached  bool       `json:"resolveBreached"`
+	Breached         bool       `json:"breached"`
+	EscalatedAt      *time.Time `json:"escalatedAt,omitempty"`
+	EscalatedToID    *uint      `json:"escalatedToId,omitempty"`
+}
+
+// slaSummary returns the bug's SLA state at "now", or nil if the bug has no SLA clock.
+func (b *Bug) slaSummary(now time.Time) *BugSLASummary {
+	if b.ResponseDueAt == nil && b.ResolveDueAt == nil {
+		return nil
+	}
+	status := b.EvaluateSLA(now)
+	return &BugSLASummary{
+		ResponseDueAt:    b.ResponseDueAt,
+		ResolveDueAt:     b.ResolveDueAt,
+		FirstRespondedAt: b.FirstRespondedAt,
+		ResolvedAt:       b.ResolvedAt,
+		ResponseBreached: status.ResponseBreached,
+		ResolveBreached:  status.ResolveBreached,
+		Breached:         status.Breached(),
+		EscalatedAt:      b.EscalatedAt,
+		EscalatedToID:    b.EscalatedToID,
+	}
+}
+
+// UpsertBugSLAPolicyRequest sets the targets for one priority.
+type UpsertBugSLAPolicyRequest struct {
+	FirstResponseMinutes int   `json:"firstResponseMinutes" binding:"gte=0"`
+	ResolutionMinutes    int   `json:"resolutionMinutes" binding:"gte=0"`
+	Enabled              *bool `json:"enabled"` // Defaults to true
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBugSLAPolicy_DueTimes(t *testing.T) {
+	filed := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	policy := &BugSLAPolicy{Priority: BugPriorityUrgent, FirstResponseMinutes: 30, ResolutionMinutes: 480, Enabled: true}
+	responseDue, resolveDue := policy.DueTimes(filed)
+	assert.Equal(t, filed.Add(30*time.Minute), *responseDue)
+	assert.Equal(t, filed.Add(8*time.Hour), *resolveDue)
+
+	policy.FirstResponseMinutes = 0
+	responseDue, resolveDue = policy.DueTimes(filed)
+	assert.Nil(t, responseDue, "a zero target is not tracked")
+	assert.NotNil(t, resolveDue)
+
+	policy.Enabled = false
+	responseDue, resolveDue = policy.DueTimes(filed)
+	assert.Nil(t, responseDue)
+	assert.Nil(t, resolveDue)
+
+	var none *BugSLAPolicy
+	responseDue, resolveDue = none.DueTimes(filed)
+	assert.Nil(t, responseDue)
+	assert.Nil(t, resolveDue)
+}
+
+func TestBug_ApplySLAPolicy(t *testing.T) {
+	filed := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	flagged := filed.Add(2 * time.Hour)
+	bug := &Bug{CreatedAt: filed}
+
+	bug.ApplySLAPolicy(&BugSLAPolicy{FirstResponseMinutes: 60, ResolutionMinutes: 600, Enabled: true})
+	assert.Equal(t, filed.Add(time.Hour), *bug.ResponseDueAt)
+	assert.Equal(t, filed.Add(10*time.Hour), *bug.ResolveDueAt)
+
+	// Re-prioritising moves the response target and clears only its breach flag
+	bug.ResponseBreachedAt = &flagged
+	bug.ResolveBreachedAt = &flagged
+	bug.ApplySLAPolicy(&BugSLAPolicy{FirstResponseMinutes: 240, ResolutionMinutes: 600, Enabled: true})
+	assert.Equal(t, filed.Add(4*time.Hour), *bug.ResponseDueAt)
+	assert.Nil(t, bug.ResponseBreachedAt)
+	assert.Equal(t, flagged, *bug.ResolveBreachedAt)
+
+	bug.ApplySLAPolicy(nil)
+	assert.Nil(t, bug.ResponseDueAt)
+	assert.Nil(t, bug.ResolveDueAt)
+	assert.Nil(t, bug.ResolveBreachedAt)
+}
+
+func TestBug_EvaluateSLA(t *testing.T) {
+	filed := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		ts := filed.Add(d)
+		return &ts
+	}
+
+	tests := []struct {
+		name     string
+		bug      Bug
+		now      time.Time
+		expected BugSLAStatus
+	}{
+		{
+			name:     "no policy",
+			bug:      Bug{},
+			now:      filed.Add(100 * time.Hour),
+			expected: BugSLAStatus{},
+		},
+		{
+			name:     "running and on time",
+			bug:      Bug{ResponseDueAt: at(time.Hour), ResolveDueAt: at(8 * time.Hour)},
+			now:      filed.Add(30 * time.Minute),
+			expected: BugSLAStatus{},
+		},
+		{
+			name:     "running past due",
+			bug:      Bug{ResponseDueAt: at(time.Hour), ResolveDueAt: at(8 * time.Hour)},
+			now:      filed.Add(2 * time.Hour),
+			expected: BugSLAStatus{ResponseBreached: true},
+		},
+		{
+			name:     "responded in time, resolved late",
+			bug:      Bug{ResponseDueAt: at(time.Hour), ResolveDueAt: at(8 * time.Hour), FirstRespondedAt: at(10 * time.Minute), ResolvedAt: at(9 * time.Hour)},
+			now:      filed.Add(100 * time.Hour),
+			expected: BugSLAStatus{ResolveBreached: true},
+		},
+		{
+			name:     "stopped clocks do not breach later",
+			bug:      Bug{ResponseDueAt: at(time.Hour), ResolveDueAt: at(8 * time.Hour), FirstRespondedAt: at(10 * time.Minute), ResolvedAt: at(2 * time.Hour)},
+			now:      filed.Add(100 * time.Hour),
+			expected: BugSLAStatus{},
+		},
+		{
+			name:     "flagged by the checker",
+			bug:      Bug{ResponseDueAt: at(time.Hour), ResponseBreachedAt: at(2 * time.Hour), FirstRespondedAt: at(3 * time.Hour)},
+			now:      filed.Add(4 * time.Hour),
+			expected: BugSLAStatus{ResponseBreached: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.bug.EvaluateSLA(tt.now)
+			assert.Equal(t, tt.expected, status)
+			assert.Equal(t, tt.expected.ResponseBreached || tt.expected.ResolveBreached, status.Breached())
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// BugSnapshotInstance is the state of one service instance at the time a bug was filed.
+type BugSnapshotInstance struct {
+	ServiceInstanceID uint                      `json:"serviceInstanceId"`
+	ServiceID         uint                      `json:"serviceId"`
+	ServiceName       string                    `json:"serviceName"`
+	Version           string                    `json:"version"`
+	Status            ServiceInstanceStatusType `json:"status"`
+	Hostname          *string                   `json:"hostname,omitempty"`
+	Port              *int                      `json:"port,omitempty"`
+	Config            map[string]interface{}    `json:"config,omitempty"` // Effective config, secrets masked
+	DeployedAt        *time.Time                `json:"deployedAt,omitempty"`
+}
+
+// BugEnvironmentSnapshot freezes every service instance of a bug's environment when the bug
+// is filed. Snapshots are written once and never updated.
+type BugEnvironmentSnapshot struct {
+	ID              uint                                     `gorm:"primarykey" json:"id"`
+	BugID           uint                                     `gorm:"uniqueIndex;not null" json:"bugId"`
+	EnvironmentID   uint                                     `gorm:"index;not null" json:"environmentId"`
+	EnvironmentName string                                   `gorm:"type:varchar(100)" json:"environmentName"`
+	Instances       datatypes.JSONSlice[BugSnapshotInstance] `gorm:"type:json" json:"instances"`
+	CreatedAt       time.Time                                `json:"createdAt"`
+}
+
+// TableName specifies the table name for the BugEnvironmentSnapshot model.
+func (BugEnvironmentSnapshot) TableName() string {
+	return "bug_environment_snapshots"
+}
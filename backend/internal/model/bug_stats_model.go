@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// BugStatsGroupBy is the bug field counts are grouped by.
+type BugStatsGroupBy string
+
+const (
+	BugStatsByStatus      BugStatsGroupBy = "status"
+	BugStatsByPriority    BugStatsGroupBy = "priority"
+	BugStatsByAssignee    BugStatsGroupBy = "assignee"
+	BugStatsByEnvironment BugStatsGroupBy = "environment"
+	BugStatsByService     BugStatsGroupBy = "service"
+)
+
+// BugStatsInterval is the length of one period of a trend series.
+type BugStatsInterval string
+
+const (
+	BugStatsDaily  BugStatsInterval = "day"
+	BugStatsWeekly BugStatsInterval = "week" // Weeks start on Monday
+)
+
+// BugStatsParams defines the query parameters of the bug statistics.
+type BugStatsParams struct {
+	From     *time.Time       `form:"from" time_format:"2006-01-02T15:04:05Z07:00"` // Defaults to 30 days before To
+	To       *time.Time       `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`   // Defaults to now
+	GroupBy  BugStatsGroupBy  `form:"groupBy"`                                      // Defaults to status
+	Interval BugStatsInterval `form:"interval"`                                     // Defaults to day
+}
+
+// BugStatsGroup is the number of bugs sharing one value of the grouped field. ID and Label
+// are set when grouping by a linked entity; a nil ID groups the bugs without one.
+type BugStatsGroup struct {
+	Key   string `json:"key"`
+	ID    *uint  `json:"id,omitempty"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// BugAgeBucket counts the open bugs whose age in days is in [MinDays, MaxDays).
+type BugAgeBucket struct {
+	Label   string `json:"label"`
+	MinDays int    `json:"minDays"`
+	MaxDays *int   `json:"maxDays,omitempty"` // Nil for the last, open-ended bucket
+	Count   int64  `json:"count"`
+}
+
+// bugAgeBucketBounds are the ages, in days, that separate the open-bug age buckets.
+var bugAgeBucketBounds = []int{1, 7, 30, 90}
+
+// NewBugAgeBuckets returns the empty open-bug age buckets: under a day, up to a week, a month,
+// three months, and older.
+func NewBugAgeBuckets() []BugAgeBucket {
+	buckets := make([]BugAgeBucket, 0, len(bugAgeBucketBounds)+1)
+	lower := 0
+	for _, upper := range bugAgeBucketBounds {
+		buckets = append(buckets, BugAgeBucket{Label: fmt.Sprintf("%d-%dd", lower, upper), MinDays: lower, MaxDays: &upper})
+		lower = upper
+	}
+	return append(buckets, BugAgeBucket{Label: fmt.Sprintf("%dd+", lower), MinDays: lower})
+}
+
+// BugTrendPoint is the number of bugs created and closed in one period.
+type BugTrendPoint struct {
+	Period  string `json:"period"` // First day of the period, YYYY-MM-DD (UTC)
+	Created int64  `json:"created"`
+	Closed  int64  `json:"closed"` // Moved into a resolved status
+}
+
+// BugStatsResponse is the bug statistics shown on dashboards (requirement 3.1).
+type BugStatsResponse struct {
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	GroupBy  BugStatsGroupBy  `json:"groupBy"`
+	Interval BugStatsInterval `json:"interval"`
+	// Total and Groups count the bugs created in the window.
+	Total  int64           `json:"total"`
+	Groups []BugStatsGroup `json:"groups"`
+	// OpenAge is the age of the bugs open now, whenever they were created.
+	OpenAge []BugAgeBucket `json:"openAge"`
+	// MeanTimeToResolveHours averages, over the bugs resolved in the window, the time from
+	// creation to resolution. Nil when none were resolved.
+	MeanTimeToResolveHours *float64        `json:"meanTimeToResolveHours"`
+	ResolvedCount          int64           `json:"resolvedCount"`
+	Trend                  []BugTrendPoint `json:"trend"`
+}
@@ -0,0 +1,173 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// BugWorkflow is the status transition graph bugs move through. Statuses that are not a key
+// of Transitions are unknown to the workflow; a status mapped to an empty list is terminal.
+type BugWorkflow struct {
+	// InitialStatus is the status every new bug starts in.
+	InitialStatus BugStatusType `json:"initialStatus"`
+	// Transitions lists, for each status, the statuses a bug may move to next.
+	Transitions map[BugStatusType][]BugStatusType `json:"transitions"`
+	// ResolvedStatuses carry a resolution. Entering one requires a resolution unless the bug
+	// already has one; leaving them for any other status clears it.
+	ResolvedStatuses []BugStatusType `json:"resolvedStatuses"`
+	// CommentRequired lists the statuses that can only be entered with a comment.
+	CommentRequired []BugStatusType `json:"commentRequired"`
+	// DuplicateStatus is the resolved status a bug is moved to, whatever its current status,
+	// when it is marked as a duplicate of another bug. Empty leaves duplicates where they are.
+	DuplicateStatus BugStatusType `json:"duplicateStatus,omitempty"`
+}
+
+// DefaultBugWorkflow returns the standard workflow (requirement 2.10.2):
+// OPEN -> IN_PROGRESS -> RESOLVED -> VERIFIED -> CLOSED, with REOPENED looping back
+// from RESOLVED, VERIFIED or CLOSED.
+func DefaultBugWorkflow() *BugWorkflow {
+	return &BugWorkflow{
+		InitialStatus: BugStatusOpen,
+		Transitions: map[BugStatusType][]BugStatusType{
+			BugStatusOpen:       {BugStatusInProgress, BugStatusResolved},
+			BugStatusInProgress: {BugStatusOpen, BugStatusResolved},
+			BugStatusResolved:   {BugStatusVerified, BugStatusReopened},
+			BugStatusVerified:   {BugStatusClosed, BugStatusReopened},
+			BugStatusClosed:     {BugStatusReopened},
+			BugStatusReopened:   {BugStatusInProgress, BugStatusResolved},
+		},
+		ResolvedStatuses: []BugStatusType{BugStatusResolved, BugStatusVerified, BugStatusClosed},
+		CommentRequired:  []BugStatusType{BugStatusResolved, BugStatusClosed},
+		DuplicateStatus:  BugStatusClosed,
+	}
+}
+
+// BugWorkflowConfig describes a custom workflow in the application config. Statuses are
+// listed rather than keyed by name because config keys are case-insensitive.
+type BugWorkflowConfig struct {
+	InitialStatus    string              `mapstructure:"initialStatus"`
+	Statuses         []BugWorkflowStatus `mapstructure:"statuses"` // No statuses selects DefaultBugWorkflow()
+	ResolvedStatuses []string            `mapstructure:"resolvedStatuses"`
+	CommentRequired  []string            `mapstructure:"commentRequired"`
+	DuplicateStatus  string              `mapstructure:"duplicateStatus"`
+}
+
+// BugWorkflowStatus is a status of a configured workflow and the statuses a bug may move to
+// from it. A status without transitions is terminal.
+type BugWorkflowStatus struct {
+	Name        string   `mapstructure:"name"`
+	Transitions []string `mapstructure:"transitions"`
+}
+
+// Workflow builds the configured workflow, or the default one when no statuses are
+// configured, and validates it.
+func (c BugWorkflowConfig) Workflow() (*BugWorkflow, error) {
+	workflow := DefaultBugWorkflow()
+	if len(c.Statuses) > 0 {
+		workflow = &BugWorkflow{
+			InitialStatus:    BugStatusType(c.InitialStatus),
+			Transitions:      make(map[BugStatusType][]BugStatusType, len(c.Statuses)),
+			ResolvedStatuses: toBugStatuses(c.ResolvedStatuses),
+			CommentRequired:  toBugStatuses(c.CommentRequired),
+			DuplicateStatus:  BugStatusType(c.DuplicateStatus),
+		}
+		for _, status := range c.Statuses {
+			name := BugStatusType(status.Name)
+			if name == "" {
+				return nil, fmt.Errorf("invalid bug workflow: a status has no name")
+			}
+			if workflow.HasStatus(name) {
+				return nil, fmt.Errorf("invalid bug workflow: status %q is listed twice", name)
+			}
+			workflow.Transitions[name] = toBugStatuses(status.Transitions)
+		}
+	}
+	if err := workflow.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bug workflow: %w", err)
+	}
+	return workflow, nil
+}
+
+func toBugStatuses(names []string) []BugStatusType {
+	statuses := make([]BugStatusType, 0, len(names))
+	for _, name := range names {
+		statuses = append(statuses, BugStatusType(name))
+	}
+	return statuses
+}
+
+// Validate checks that the workflow only refers to statuses it defines.
+func (w *BugWorkflow) Validate() error {
+	if !w.HasStatus(w.InitialStatus) {
+		return fmt.Errorf("initial status %q is not part of the workflow", w.InitialStatus)
+	}
+	for from, targets := range w.Transitions {
+		for _, to := range targets {
+			if !w.HasStatus(to) {
+				return fmt.Errorf("transition %s -> %s targets an unknown status", from, to)
+			}
+		}
+	}
+	for _, status := range append(append([]BugStatusType{}, w.ResolvedStatuses...), w.CommentRequired...) {
+		if !w.HasStatus(status) {
+			return fmt.Errorf("status %q is not part of the workflow", status)
+		}
+	}
+	if w.DuplicateStatus != "" && !w.IsResolved(w.DuplicateStatus) {
+		return fmt.Errorf("duplicate status %q is not a resolved status", w.DuplicateStatus)
+	}
+	return nil
+}
+
+// HasStatus reports whether status is defined by the workflow.
+func (w *BugWorkflow) HasStatus(status BugStatusType) bool {
+	_, ok := w.Transitions[status]
+	return ok
+}
+
+// AllowedTransitions returns the statuses a bug in status from may move to.
+func (w *BugWorkflow) AllowedTransitions(from BugStatusType) []BugStatusType {
+	return w.Transitions[from]
+}
+
+// CanTransition reports whether a bug may move from one status to another.
+func (w *BugWorkflow) CanTransition(from, to BugStatusType) bool {
+	return containsBugStatus(w.Transitions[from], to)
+}
+
+// IsResolved reports whether bugs in status carry a resolution.
+func (w *BugWorkflow) IsResolved(status BugStatusType) bool {
+	return containsBugStatus(w.ResolvedStatuses, status)
+}
+
+// RequiresComment reports whether entering status needs a comment.
+func (w *BugWorkflow) RequiresComment(status BugStatusType) bool {
+	return containsBugStatus(w.CommentRequired, status)
+}
+
+func containsBugStatus(statuses []BugStatusType, status BugStatusType) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// BugStatusTransition records one status change of a bug, who made it and why.
+type BugStatusTransition struct {
+	ID         uint               `gorm:"primarykey" json:"id"`
+	BugID      uint               `gorm:"index;not null" json:"bugId"`
+	FromStatus BugStatusType      `gorm:"type:varchar(50);not null" json:"fromStatus"`
+	ToStatus   BugStatusType      `gorm:"type:varchar(50);not null" json:"toStatus"`
+	Resolution *BugResolutionType `gorm:"type:varchar(50)" json:"resolution,omitempty"`
+	Comment    string             `gorm:"type:text" json:"comment,omitempty"`
+	ActorID    *uint              `gorm:"index" json:"actorId,omitempty"`
+	Actor      string             `gorm:"type:varchar(255)" json:"actor,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+}
+
+// TableName specifies the table name for the BugStatusTransition model.
+func (BugStatusTransition) TableName() string {
+	return "bug_status_transitions"
+}
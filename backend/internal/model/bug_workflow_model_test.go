@@ -0,0 +1,88 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultBugWorkflow(t *testing.T) {
+	workflow := DefaultBugWorkflow()
+	assert.NoError(t, workflow.Validate())
+
+	tests := []struct {
+		from, to BugStatusType
+		expected bool
+	}{
+		{from: BugStatusOpen, to: BugStatusInProgress, expected: true},
+		{from: BugStatusInProgress, to: BugStatusResolved, expected: true},
+		{from: BugStatusResolved, to: BugStatusVerified, expected: true},
+		{from: BugStatusVerified, to: BugStatusClosed, expected: true},
+		{from: BugStatusClosed, to: BugStatusReopened, expected: true},
+		{from: BugStatusOpen, to: BugStatusClosed, expected: false},
+		{from: BugStatusClosed, to: BugStatusInProgress, expected: false},
+		{from: BugStatusResolved, to: BugStatusClosed, expected: false},
+		{from: BugStatusType("custom_status"), to: BugStatusOpen, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.expected, workflow.CanTransition(tt.from, tt.to))
+		})
+	}
+
+	assert.True(t, workflow.IsResolved(BugStatusVerified))
+	assert.False(t, workflow.IsResolved(BugStatusReopened))
+	assert.True(t, workflow.RequiresComment(BugStatusClosed))
+	assert.False(t, workflow.RequiresComment(BugStatusVerified))
+}
+
+func TestBugWorkflow_Validate(t *testing.T) {
+	workflow := DefaultBugWorkflow()
+	workflow.Transitions[BugStatusOpen] = append(workflow.Transitions[BugStatusOpen], BugStatusType("DONE"))
+	assert.Error(t, workflow.Validate())
+
+	workflow = DefaultBugWorkflow()
+	workflow.InitialStatus = BugStatusType("NEW")
+	assert.Error(t, workflow.Validate())
+
+	workflow = DefaultBugWorkflow()
+	workflow.DuplicateStatus = BugStatusReopened
+	assert.Error(t, workflow.Validate())
+}
+
+func TestBugWorkflowConfig_Workflow(t *testing.T) {
+	workflow, err := BugWorkflowConfig{}.Workflow()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultBugWorkflow(), workflow, "no statuses selects the default workflow")
+
+	workflow, err = BugWorkflowConfig{
+		InitialStatus: "NEW",
+		Statuses: []BugWorkflowStatus{
+			{Name: "NEW", Transitions: []string{"TRIAGED", "DONE"}},
+			{Name: "TRIAGED", Transitions: []string{"DONE"}},
+			{Name: "DONE"},
+		},
+		ResolvedStatuses: []string{"DONE"},
+		CommentRequired:  []string{"DONE"},
+		DuplicateStatus:  "DONE",
+	}.Workflow()
+	assert.NoError(t, err)
+	assert.Equal(t, BugStatusType("NEW"), workflow.InitialStatus)
+	assert.True(t, workflow.CanTransition("NEW", "TRIAGED"))
+	assert.False(t, workflow.CanTransition("TRIAGED", "NEW"))
+	assert.True(t, workflow.HasStatus("DONE"))
+	assert.Empty(t, workflow.AllowedTransitions("DONE"), "a status without transitions is terminal")
+	assert.True(t, workflow.IsResolved("DONE"))
+
+	for name, cfg := range map[string]BugWorkflowConfig{
+		"unknown initial status": {InitialStatus: "OPEN", Statuses: []BugWorkflowStatus{{Name: "NEW"}}},
+		"unknown target":         {InitialStatus: "NEW", Statuses: []BugWorkflowStatus{{Name: "NEW", Transitions: []string{"DONE"}}}},
+		"duplicate status":       {InitialStatus: "NEW", Statuses: []BugWorkflowStatus{{Name: "NEW"}, {Name: "NEW"}}},
+		"unnamed status":         {InitialStatus: "NEW", Statuses: []BugWorkflowStatus{{Name: "NEW"}, {}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := cfg.Workflow()
+			assert.Error(t, err)
+		})
+	}
+}
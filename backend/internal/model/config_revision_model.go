@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ConfigRevision is an immutable snapshot of a service instance's configuration.
+// Revisions are numbered per instance starting at 1; every config change, including a
+// rollback, produces a new revision so the history is never rewritten.
+type ConfigRevision struct {
+	ID                uint              `gorm:"primarykey" json:"id"`
+	ServiceInstanceID uint              `gorm:"uniqueIndex:idx_config_revision_instance_rev;not null" json:"serviceInstanceId"`
+	Revision          int               `gorm:"uniqueIndex:idx_config_revision_instance_rev;not null" json:"revision"`
+	Config            datatypes.JSONMap `gorm:"type:json" json:"config"`
+	AuthorID          *uint             `gorm:"index" json:"authorId,omitempty"`
+	Author            string            `gorm:"type:varchar(255)" json:"author,omitempty"`
+	Comment           string            `gorm:"type:text" json:"comment,omitempty"`
+	RolledBackFrom    *int              `json:"rolledBackFrom,omitempty"` // Source revision when created by a rollback
+	CreatedAt         time.Time         `json:"createdAt"`
+}
+
+// TableName specifies the table name for the ConfigRevision model.
+func (ConfigRevision) TableName() string {
+	return "service_instance_config_revisions"
+}
+
+// ConfigRevisionListParams defines parameters for listing config revisions.
+type ConfigRevisionListParams struct {
+	Page     int `form:"page,default=1"`
+	PageSize int `form:"pageSize,default=20"`
+}
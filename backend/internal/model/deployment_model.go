@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+)
+
+// DeploymentStatusType defines the outcome of a deployment.
+type DeploymentStatusType string
+
+const (
+	DeploymentStatusSuccess    DeploymentStatusType = "success"
+	DeploymentStatusFailed     DeploymentStatusType = "failed"
+	DeploymentStatusInProgress DeploymentStatusType = "in_progress"
+	DeploymentStatusRolledBack DeploymentStatusType = "rolled_back"
+)
+
+// IsValid checks if the deployment status is one of the known values.
+func (s DeploymentStatusType) IsValid() bool {
+	switch s {
+	case DeploymentStatusSuccess, DeploymentStatusFailed, DeploymentStatusInProgress, DeploymentStatusRolledBack:
+		return true
+	}
+	return false
+}
+
+// DeploymentSourceType describes how a deployment record was produced.
+type DeploymentSourceType string
+
+const (
+	DeploymentSourceManual DeploymentSourceType = "manual" // Version changed through the service instance API
+	DeploymentSourceCI     DeploymentSourceType = "ci"     // Reported by a CI/CD pipeline
+)
+
+// Deployment is an immutable record of a version being rolled out to a service instance.
+// Together the records form the deployment history of an instance and of an environment.
+type Deployment struct {
+	ID                uint                 `gorm:"primarykey" json:"id"`
+	ServiceInstanceID uint                 `gorm:"index;not null" json:"serviceInstanceId"`
+	ServiceID         uint                 `gorm:"index;not null" json:"serviceId"`     // Denormalized for environment timelines
+	EnvironmentID     uint                 `gorm:"index;not null" json:"environmentId"` // Denormalized for environment timelines
+	Version           string               `gorm:"type:varchar(100);not null" json:"version"`
+	PreviousVersion   string               `gorm:"type:varchar(100)" json:"previousVersion,omitempty"`
+	Status            DeploymentStatusType `gorm:"type:varchar(50);not null;default:'success'" json:"status"`
+	Source            DeploymentSourceType `gorm:"type:varchar(50);not null;default:'manual'" json:"source"`
+	TriggeredByID     *uint                `gorm:"index" json:"triggeredById,omitempty"`           // Authenticated user who performed or reported the deployment
+	TriggeredBy       string               `gorm:"type:varchar(255)" json:"triggeredBy,omitempty"` // Display name or CI identity, e.g. "jenkins#1234"
+	Notes             string               `gorm:"type:text" json:"notes,omitempty"`
+	StartedAt         *time.Time           `json:"startedAt,omitempty"`
+	FinishedAt        *time.Time           `json:"finishedAt,omitempty"`
+	CreatedAt         time.Time            `gorm:"index" json:"createdAt"`
+}
+
+// TableName specifies the table name for the Deployment model.
+func (Deployment) TableName() string {
+	return "deployments"
+}
+
+// DeploymentListParams defines parameters for listing deployment records.
+type DeploymentListParams struct {
+	Page              int    `form:"page,default=1"`
+	PageSize          int    `form:"pageSize,default=20"`
+	ServiceID         *uint  `form:"serviceId"`
+	ServiceInstanceID *uint  `form:"serviceInstanceId"`
+	EnvironmentID     *uint  `form:"environmentId"`
+	Status            string `form:"status"`
+	StartDate         string `form:"startDate"` // 格式：YYYY-MM-DD
+	EndDate           string `form:"endDate"`   // 格式：YYYY-MM-DD
+}
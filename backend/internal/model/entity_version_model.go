@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// EntityVersionAction describes the change that produced an entity version.
+type EntityVersionAction string
+
+const (
+	EntityVersionCreate EntityVersionAction = "CREATE"
+	EntityVersionUpdate EntityVersionAction = "UPDATE"
+	EntityVersionDelete EntityVersionAction = "DELETE"
+	// EntityVersionBaseline records the state of an entity that existed before history was
+	// kept, taken just before its first tracked change. Its CreatedAt is the row's updatedAt.
+	EntityVersionBaseline EntityVersionAction = "BASELINE"
+)
+
+// Resources whose changes are kept in the entity history; the names match the audit log resources.
+const (
+	EntityResourceEnvironment     = "ENVIRONMENT"
+	EntityResourceAsset           = "ASSET"
+	EntityResourceService         = "SERVICE"
+	EntityResourceServiceInstance = "SERVICE_INSTANCE"
+	EntityResourceBusiness        = "BUSINESS"
+	EntityResourceBug             = "BUG"
+)
+
+// EntityVersion is an immutable snapshot of a CMDB entity after a change.
+// Versions are numbered per entity starting at 1. A version is valid from its CreatedAt
+// until the CreatedAt of the next one, so the state at any time is the latest version at or before it.
+type EntityVersion struct {
+	ID          uint                `gorm:"primarykey" json:"id"`
+	Resource    string              `gorm:"type:varchar(64);uniqueIndex:idx_entity_versions_entity;not null" json:"resource"`
+	ResourceID  uint                `gorm:"uniqueIndex:idx_entity_versions_entity;not null" json:"resourceId"`
+	Version     int                 `gorm:"uniqueIndex:idx_entity_versions_entity;not null" json:"version"`
+	Action      EntityVersionAction `gorm:"type:varchar(16);not null" json:"action"`
+	Snapshot    datatypes.JSON      `gorm:"type:json" json:"snapshot"` // The entity's JSON with sensitive values masked
+	ChangedByID *uint               `gorm:"index" json:"changedById,omitempty"`
+	ChangedBy   string              `gorm:"type:varchar(255)" json:"changedBy,omitempty"`
+	CreatedAt   time.Time           `gorm:"index" json:"createdAt"`
+}
+
+// TableName specifies the table name for the EntityVersion model.
+func (EntityVersion) TableName() string {
+	return "entity_versions"
+}
+
+// EntityHistoryListParams defines parameters for listing the versions of an entity.
+type EntityHistoryListParams struct {
+	Page     int `form:"page,default=1" validate:"min=1"`
+	PageSize int `form:"pageSize,default=20" validate:"min=1,max=100"`
+}
@@ -73,6 +73,8 @@ var (
 	ErrServiceTypeNameExists   = errors.New("service type with this name already exists")
 	ErrInvalidServiceStatus    = errors.New("invalid service status")
 	ErrServiceTypeInUse        = errors.New("service type is in use and cannot be deleted")
+	ErrInvalidConfigSchema     = errors.New("invalid config schema")
+	ErrInvalidDefaultConfig    = errors.New("invalid default config")
 )
 
 // ServiceInstance specific errors (Placeholder for future use)
@@ -80,3 +82,22 @@ var (
 	ErrServiceInstanceNotFound = errors.New("service instance not found")
 	// Add more as needed
 )
+
+// Bug specific errors
+var (
+	ErrInvalidBugStatus           = errors.New("invalid bug status")
+	ErrInvalidBugStatusTransition = errors.New("invalid bug status transition")
+	ErrBugResolutionRequired      = errors.New("bug resolution required")
+	ErrBugCommentRequired         = errors.New("bug transition comment required")
+	ErrBugRelationExists          = errors.New("bug relation already exists")
+	ErrBugRelationCycle           = errors.New("bug relation would create a cycle")
+	ErrBugPossibleDuplicates      = errors.New("possible duplicate bugs found")
+	ErrBulkBugUpdateFailed        = errors.New("bulk bug update failed")
+)
+
+// Attachment specific errors
+var (
+	ErrAttachmentNotFound       = errors.New("attachment not found")
+	ErrAttachmentTooLarge       = errors.New("attachment too large")
+	ErrAttachmentTypeNotAllowed = errors.New("attachment type not allowed")
+)
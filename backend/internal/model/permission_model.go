@@ -4,6 +4,12 @@ import (
 	"time"
 )
 
+// PermissionRevealConfigSecrets allows decrypting secret values in service instance configs.
+const PermissionRevealConfigSecrets = "service_instance:reveal_secrets"
+
+// PermissionStreamAuditLogs allows watching new audit log entries live.
+const PermissionStreamAuditLogs = "audit_log:stream"
+
 // Permission represents a permission in the system.
 type Permission struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
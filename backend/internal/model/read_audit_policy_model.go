@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// ReadAuditRule 声明一类需要在读取时审计的敏感数据
+type ReadAuditRule struct {
+	// Resource 是审计日志中记录的资源类型，如 SERVICE_INSTANCE
+	Resource string `mapstructure:"resource" json:"resource"`
+	// Routes 是匹配的Gin路由模板，如 /api/v1/service-instances/:instanceId/config/effective；
+	// 为空时匹配该资源类型的所有GET请求
+	Routes []string `mapstructure:"routes" json:"routes,omitempty"`
+	// Fields 是这些路由返回的敏感字段，记录在日志详情中，说明读取了哪些敏感数据
+	Fields []string `mapstructure:"fields" json:"fields,omitempty"`
+}
+
+// ReadAuditPolicy 决定哪些GET请求记录READ审计日志。只有匹配规则的读取才记录，
+// 避免普通查询淹没审计日志
+type ReadAuditPolicy struct {
+	Rules []ReadAuditRule `mapstructure:"rules" json:"rules"`
+	// Window 内同一用户对同一路由和资源的重复读取只在第一次记录，
+	// 其余次数在窗口结束时汇总为一条日志；0表示每次读取都记录
+	Window time.Duration `mapstructure:"window" json:"window"`
+}
@@ -47,3 +47,27 @@ type ResponsibilityGroupDetail struct {
 	UpdatedAt        time.Time        `json:"updatedAt"`
 	Responsibilities []Responsibility `json:"responsibilities"`
 }
+
+// ResponsibilityGroupMember is a user who belongs to a responsibility group. At most one
+// member per group is primary; bug assignment rules pick the primary member first. At most
+// one member is the backup, who receives bugs escalated after an SLA breach.
+type ResponsibilityGroupMember struct {
+	GroupID   uint      `json:"groupId" gorm:"primaryKey"`
+	UserID    uint      `json:"userId" gorm:"primaryKey"`
+	IsPrimary bool      `json:"isPrimary" gorm:"not null;default:false"`
+	IsBackup  bool      `json:"isBackup" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for the ResponsibilityGroupMember model.
+func (ResponsibilityGroupMember) TableName() string {
+	return "responsibility_group_members"
+}
+
+// SetResponsibilityGroupMemberRequest adds a user to a group or changes their primary and backup flags.
+// A member cannot be both.
+type SetResponsibilityGroupMemberRequest struct {
+	IsPrimary bool `json:"isPrimary"`
+	IsBackup  bool `json:"isBackup"`
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -44,6 +45,8 @@ type Service struct {
 	ExternalLink  string         `gorm:"type:varchar(2048)" json:"externalLink,omitempty"` // Link to docs, dashboard, etc.
 	ServiceTypeID uint           `json:"serviceTypeId" gorm:"index;not null"`
 	ServiceType   *ServiceType   `json:"serviceType,omitempty" gorm:"foreignKey:ServiceTypeID"`
+	ConfigSchema  datatypes.JSON `json:"configSchema,omitempty" gorm:"type:json"` // JSON Schema that instance configs must satisfy
+	DefaultConfig datatypes.JSONMap `json:"defaultConfig,omitempty" gorm:"type:json"` // Base layer of every instance's effective config
 	CreatedAt     time.Time      `json:"createdAt"`
 	UpdatedAt     time.Time      `json:"updatedAt"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
@@ -64,6 +67,8 @@ type CreateServiceRequest struct {
 	Status        ServiceStatus `json:"status,omitempty" binding:"omitempty,oneof=active inactive development maintenance deprecated experimental unknown"`
 	ExternalLink  string        `json:"externalLink,omitempty" binding:"omitempty,url,max=2048"`
 	ServiceTypeID uint          `json:"serviceTypeId" binding:"required,gt=0"`
+	ConfigSchema  datatypes.JSON `json:"configSchema,omitempty"`
+	DefaultConfig datatypes.JSONMap `json:"defaultConfig,omitempty"`
 }
 
 // UpdateServiceRequest defines the structure for updating an existing service.
@@ -75,6 +80,8 @@ type UpdateServiceRequest struct {
 	Status        *ServiceStatus `json:"status,omitempty" binding:"omitempty,oneof=active inactive development maintenance deprecated experimental unknown"`
 	ExternalLink  *string        `json:"externalLink,omitempty" binding:"omitempty,url,max=2048"`
 	ServiceTypeID *uint          `json:"serviceTypeId,omitempty" binding:"omitempty,gt=0"`
+	ConfigSchema  *datatypes.JSON `json:"configSchema,omitempty"` // Send {} to remove the schema
+	DefaultConfig *datatypes.JSONMap `json:"defaultConfig,omitempty"` // Send {} to remove all defaults
 }
 
 // ServiceResponse defines a standard way to return service data.
@@ -88,6 +95,8 @@ type ServiceResponse struct {
 	ExternalLink string        `json:"externalLink,omitempty"`
 	ServiceTypeID uint          `json:"serviceTypeId"`
 	ServiceType   *ServiceType  `json:"serviceType,omitempty"` // Embed ServiceType for richer response
+	ConfigSchema  datatypes.JSON `json:"configSchema,omitempty"`
+	DefaultConfig datatypes.JSONMap `json:"defaultConfig,omitempty"`
 	CreatedAt     time.Time     `json:"createdAt"`
 	UpdatedAt    time.Time     `json:"updatedAt"`
 }
@@ -103,6 +112,8 @@ func (s *Service) ToServiceResponse() ServiceResponse {
 		Status:       s.Status,
 		ExternalLink:  s.ExternalLink,
 		ServiceTypeID: s.ServiceTypeID, // Populate the ServiceTypeID
+		ConfigSchema:  s.ConfigSchema,
+		DefaultConfig: s.DefaultConfig,
 		CreatedAt:     s.CreatedAt,
 		UpdatedAt:    s.UpdatedAt,
 	}
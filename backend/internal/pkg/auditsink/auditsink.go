@@ -0,0 +1,80 @@
+// Package auditsink forwards audit log entries to external systems, such as a SIEM that
+// ingests syslog, in addition to the database. Entries are forwarded after they have been
+// committed, so they carry their ID and hash chain values.
+package auditsink
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+)
+
+// Sink delivers audit log entries to an external system.
+type Sink interface {
+	// Send delivers entries in order. An error means some entries may not have been
+	// delivered; the caller retries the whole batch, so delivery is at least once.
+	Send(ctx context.Context, entries []*model.AuditLog) error
+	// Close releases the sink's connections.
+	Close() error
+}
+
+// Supported sink types.
+const (
+	TypeSyslog = "syslog"
+)
+
+// Config selects and configures a sink and how entries are delivered to it.
+type Config struct {
+	Name   string       `mapstructure:"name"`   // Identifies the sink in logs and stats; defaults to the type
+	Type   string       `mapstructure:"type"`   // "syslog"
+	Format string       `mapstructure:"format"` // "cef" (default) or "json"
+	Syslog SyslogConfig `mapstructure:"syslog"`
+
+	QueueSize      int           `mapstructure:"queueSize"`      // Batches waiting for delivery; when full, batches go to the dead-letter file
+	MaxAttempts    int           `mapstructure:"maxAttempts"`    // Delivery attempts per batch before it is dead-lettered
+	RetryBackoff   time.Duration `mapstructure:"retryBackoff"`   // Wait before the first retry, doubled for each further retry
+	DeadLetterFile string        `mapstructure:"deadLetterFile"` // Undeliverable entries are appended here as JSON Lines; "" only logs them
+}
+
+// Defaults applied to unset Config fields.
+const (
+	DefaultQueueSize    = 256
+	DefaultMaxAttempts  = 5
+	DefaultRetryBackoff = time.Second
+)
+
+func (c Config) withDefaults() Config {
+	if c.Name == "" {
+		c.Name = c.Type
+	}
+	if c.Format == "" {
+		c.Format = FormatCEF
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = DefaultQueueSize
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultMaxAttempts
+	}
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = DefaultRetryBackoff
+	}
+	return c
+}
+
+// New creates the sink selected by cfg.Type.
+func New(cfg Config) (Sink, error) {
+	cfg = cfg.withDefaults()
+	formatter, err := NewFormatter(cfg.Format)
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.Type {
+	case TypeSyslog:
+		return NewSyslogSink(cfg.Syslog, formatter)
+	default:
+		return nil, fmt.Errorf("auditsink: unknown sink type %q", cfg.Type)
+	}
+}
@@ -0,0 +1,350 @@
+package auditsink
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testEntry(id uint, outcome model.AuditOutcome) *model.AuditLog {
+	return &model.AuditLog{
+		ID:         id,
+		UserID:     7,
+		Username:   "alice",
+		Action:     "UPDATE",
+		Resource:   "USER",
+		ResourceID: 42,
+		Details:    `{"field":"email"}`,
+		IPAddress:  "10.0.0.1",
+		Outcome:    outcome,
+		StatusCode: 200,
+		Hash:       "abc123",
+		CreatedAt:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+// parseMessage splits an RFC 5424 message into its header fields and body.
+func parseMessage(t *testing.T, msg string) ([]string, string) {
+	t.Helper()
+	parts := strings.SplitN(msg, " ", 8)
+	require.Len(t, parts, 8, "message %q", msg)
+	return parts[:7], parts[7]
+}
+
+// readFrame reads one octet-counted syslog frame.
+func readFrame(r *bufio.Reader) (string, error) {
+	length, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func TestSyslogSink_UDP(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	sink, err := New(Config{
+		Type:   TypeSyslog,
+		Syslog: SyslogConfig{Address: listener.LocalAddr().String(), Hostname: "api-1"},
+	})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Send(context.Background(), []*model.AuditLog{testEntry(1, model.AuditOutcomeSuccess)}))
+
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 8192)
+	n, _, err := listener.ReadFrom(buf)
+	require.NoError(t, err)
+
+	header, body := parseMessage(t, string(buf[:n]))
+	assert.Equal(t, "<133>1", header[0], "local0.notice")
+	assert.Equal(t, "2026-01-02T03:04:05.000000Z", header[1])
+	assert.Equal(t, "api-1", header[2])
+	assert.Equal(t, "effiplat", header[3])
+	assert.Equal(t, "UPDATE", header[5])
+	assert.Equal(t, "-", header[6])
+	assert.True(t, strings.HasPrefix(body, "CEF:0|EffiPlat|EffiPlat|1.0|UPDATE|UPDATE USER|3|"), body)
+	assert.Contains(t, body, "externalId=1 ")
+	assert.Contains(t, body, "suser=alice ")
+	assert.Contains(t, body, "cs2=abc123 ")
+}
+
+func TestSyslogSink_TCPFramingAndJSON(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	sink, err := New(Config{
+		Type:   TypeSyslog,
+		Format: FormatJSON,
+		Syslog: SyslogConfig{Network: NetworkTCP, Address: listener.Addr().String(), Facility: "authpriv"},
+	})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var msgs []string
+		for len(msgs) < 2 {
+			msg, err := readFrame(r)
+			if err != nil {
+				return
+			}
+			msgs = append(msgs, msg)
+		}
+		received <- msgs
+	}()
+
+	entries := []*model.AuditLog{testEntry(1, model.AuditOutcomeSuccess), testEntry(2, model.AuditOutcomeDenied)}
+	entries[1].PrevHash = "abc123"
+	require.NoError(t, sink.Send(context.Background(), entries))
+
+	var msgs []string
+	select {
+	case msgs = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("syslog messages not received")
+	}
+
+	header, body := parseMessage(t, msgs[0])
+	assert.Equal(t, "<85>1", header[0], "authpriv.notice")
+	var first map[string]any
+	require.NoError(t, json.Unmarshal([]byte(body), &first))
+	assert.Equal(t, float64(1), first["id"])
+	assert.Equal(t, "UPDATE", first["action"])
+
+	header, body = parseMessage(t, msgs[1])
+	assert.Equal(t, "<84>1", header[0], "denied entries are logged as warnings")
+	var second map[string]any
+	require.NoError(t, json.Unmarshal([]byte(body), &second))
+	assert.Equal(t, "abc123", second["prevHash"])
+}
+
+func TestSyslogSink_TLS(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t)
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	defer listener.Close()
+
+	sink, err := New(Config{
+		Type: TypeSyslog,
+		Syslog: SyslogConfig{
+			Network: NetworkTLS,
+			Address: listener.Addr().String(),
+			TLS:     TLSConfig{CAFile: certFile},
+		},
+	})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if msg, err := readFrame(bufio.NewReader(conn)); err == nil {
+			received <- msg
+		}
+	}()
+
+	require.NoError(t, sink.Send(context.Background(), []*model.AuditLog{testEntry(3, model.AuditOutcomeFailure)}))
+	select {
+	case msg := <-received:
+		header, body := parseMessage(t, msg)
+		assert.Equal(t, "<132>1", header[0], "local0.warning")
+		assert.Contains(t, body, "|UPDATE USER|5|")
+	case <-time.After(5 * time.Second):
+		t.Fatal("syslog message not received")
+	}
+}
+
+// writeTestCertificate writes a self-signed certificate for 127.0.0.1 and its key.
+func writeTestCertificate(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "syslog.test"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestNew_RejectsInvalidConfig(t *testing.T) {
+	_, err := New(Config{Type: "kafka"})
+	assert.Error(t, err)
+	_, err = New(Config{Type: TypeSyslog, Format: "xml", Syslog: SyslogConfig{Address: "127.0.0.1:514"}})
+	assert.Error(t, err)
+	_, err = New(Config{Type: TypeSyslog, Syslog: SyslogConfig{Address: "127.0.0.1:514", Facility: "local9"}})
+	assert.Error(t, err)
+	_, err = New(Config{Type: TypeSyslog, Syslog: SyslogConfig{Address: "127.0.0.1:514", Network: "http"}})
+	assert.Error(t, err)
+	_, err = New(Config{Type: TypeSyslog, Syslog: SyslogConfig{Address: "localhost"}})
+	assert.Error(t, err)
+}
+
+func TestCEFFormatter_Escaping(t *testing.T) {
+	entry := testEntry(5, model.AuditOutcomeSuccess)
+	entry.Action = "EXPORT|CSV"
+	entry.Details = "a=b\\c\nd"
+	entry.UserAgent = ""
+
+	out, err := CEFFormatter{}.Format(entry)
+	require.NoError(t, err)
+	msg := string(out)
+	assert.True(t, strings.HasPrefix(msg, `CEF:0|EffiPlat|EffiPlat|1.0|EXPORT\|CSV|EXPORT\|CSV USER|3|`), msg)
+	assert.Contains(t, msg, `act=EXPORT|CSV `, "pipes are not escaped in extensions")
+	assert.True(t, strings.HasSuffix(msg, `msg=a\=b\\c\nd`), msg)
+	assert.NotContains(t, msg, "requestClientApplication=", "empty values are omitted")
+}
+
+// fakeSink fails the first failures calls to Send and records the delivered entries.
+type fakeSink struct {
+	mu        sync.Mutex
+	failures  int
+	calls     int
+	delivered []*model.AuditLog
+	closed    bool
+}
+
+func (s *fakeSink) Send(ctx context.Context, entries []*model.AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("connection refused")
+	}
+	s.delivered = append(s.delivered, entries...)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestForwarder_RetriesFailedBatches(t *testing.T) {
+	sink := &fakeSink{failures: 2}
+	f := NewForwarderWithSink(Config{Name: "siem", Type: TypeSyslog, RetryBackoff: time.Millisecond}, sink, zap.NewNop())
+
+	f.Enqueue([]*model.AuditLog{testEntry(1, model.AuditOutcomeSuccess), testEntry(2, model.AuditOutcomeSuccess)})
+	require.NoError(t, f.Close(context.Background()))
+
+	assert.Len(t, sink.delivered, 2)
+	assert.True(t, sink.closed)
+	stats := f.Stats()
+	assert.Equal(t, "siem", stats.Name)
+	assert.Equal(t, FormatCEF, stats.Format)
+	assert.Equal(t, int64(2), stats.Delivered)
+	assert.Equal(t, int64(2), stats.Retries)
+	assert.Zero(t, stats.DeadLettered)
+	assert.Equal(t, "connection refused", stats.LastError)
+	assert.NotNil(t, stats.LastDeliveryAt)
+}
+
+func TestForwarder_DeadLettersUndeliverableBatches(t *testing.T) {
+	deadLetterFile := filepath.Join(t.TempDir(), "dead", "siem.jsonl")
+	sink := &fakeSink{failures: 1 << 30}
+	f := NewForwarderWithSink(Config{
+		Type:           TypeSyslog,
+		MaxAttempts:    3,
+		RetryBackoff:   time.Millisecond,
+		DeadLetterFile: deadLetterFile,
+	}, sink, zap.NewNop())
+
+	f.Enqueue([]*model.AuditLog{testEntry(1, model.AuditOutcomeSuccess)})
+	f.Enqueue([]*model.AuditLog{testEntry(2, model.AuditOutcomeDenied)})
+	require.NoError(t, f.Close(context.Background()))
+	f.Enqueue([]*model.AuditLog{testEntry(3, model.AuditOutcomeSuccess)})
+
+	assert.Equal(t, 6, sink.calls, "each batch is attempted MaxAttempts times")
+	stats := f.Stats()
+	assert.Zero(t, stats.Delivered)
+	assert.Equal(t, int64(4), stats.Retries)
+	assert.Equal(t, int64(3), stats.DeadLettered, "batches enqueued after Close are dead-lettered too")
+	assert.Zero(t, stats.Dropped)
+
+	data, err := os.ReadFile(deadLetterFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+	for i, line := range lines {
+		var entry model.AuditLog
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, uint(i+1), entry.ID)
+	}
+}
+
+func TestForwarder_DropsWithoutDeadLetterFile(t *testing.T) {
+	f := NewForwarderWithSink(Config{Type: TypeSyslog, MaxAttempts: 1}, &fakeSink{failures: 1}, zap.NewNop())
+	f.Enqueue([]*model.AuditLog{testEntry(1, model.AuditOutcomeSuccess)})
+	require.NoError(t, f.Close(context.Background()))
+
+	stats := f.Stats()
+	assert.Equal(t, int64(1), stats.Dropped)
+	assert.Zero(t, stats.DeadLettered)
+}
@@ -0,0 +1,120 @@
+package auditsink
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"EffiPlat/backend/internal/model"
+)
+
+// Supported message formats.
+const (
+	FormatCEF  = "cef"
+	FormatJSON = "json"
+)
+
+// Formatter renders an audit log entry as the message body sent to a sink.
+type Formatter interface {
+	Format(entry *model.AuditLog) ([]byte, error)
+}
+
+// NewFormatter returns the formatter for the named format.
+func NewFormatter(format string) (Formatter, error) {
+	switch strings.ToLower(format) {
+	case "", FormatCEF:
+		return CEFFormatter{}, nil
+	case FormatJSON:
+		return JSONFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("auditsink: unknown format %q", format)
+	}
+}
+
+// JSONFormatter renders an entry as the same JSON object the audit log API returns,
+// plus the previous hash so that a receiver can follow the hash chain.
+type JSONFormatter struct{}
+
+// Format implements Formatter.
+func (JSONFormatter) Format(entry *model.AuditLog) ([]byte, error) {
+	return json.Marshal(struct {
+		model.AuditLogResponse
+		PrevHash string `json:"prevHash,omitempty"`
+	}{entry.ToResponse(), entry.PrevHash})
+}
+
+// CEF header values identifying the producer of the events.
+const (
+	cefVendor  = "EffiPlat"
+	cefProduct = "EffiPlat"
+	cefVersion = "1.0"
+)
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+)
+
+// CEFFormatter renders an entry in ArcSight Common Event Format. The signature ID is the
+// action, the name is "<action> <resource>" and the severity follows the outcome.
+type CEFFormatter struct{}
+
+// Format implements Formatter.
+func (CEFFormatter) Format(entry *model.AuditLog) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CEF:0|%s|%s|%s|%s|%s|%d|",
+		cefHeaderEscaper.Replace(cefVendor),
+		cefHeaderEscaper.Replace(cefProduct),
+		cefHeaderEscaper.Replace(cefVersion),
+		cefHeaderEscaper.Replace(entry.Action),
+		cefHeaderEscaper.Replace(entry.Action+" "+entry.Resource),
+		cefSeverity(entry.Outcome))
+
+	ext := []struct{ key, value string }{
+		{"rt", strconv.FormatInt(entry.CreatedAt.UnixMilli(), 10)},
+		{"externalId", strconv.FormatUint(uint64(entry.ID), 10)},
+		{"act", entry.Action},
+		{"outcome", string(entry.Outcome)},
+		{"suid", strconv.FormatUint(uint64(entry.UserID), 10)},
+		{"suser", entry.Username},
+		{"src", entry.IPAddress},
+		{"requestClientApplication", entry.UserAgent},
+		{"cs1Label", "resource"},
+		{"cs1", entry.Resource},
+		{"cn1Label", "resourceId"},
+		{"cn1", strconv.FormatUint(uint64(entry.ResourceID), 10)},
+		{"cn2Label", "statusCode"},
+		{"cn2", strconv.Itoa(entry.StatusCode)},
+		{"reason", entry.Reason},
+		{"cs2Label", "hash"},
+		{"cs2", entry.Hash},
+		{"msg", entry.Details},
+	}
+	first := true
+	for _, kv := range ext {
+		if kv.value == "" {
+			continue
+		}
+		if !first {
+			b.WriteByte(' ')
+		}
+		first = false
+		b.WriteString(kv.key)
+		b.WriteByte('=')
+		b.WriteString(cefExtensionEscaper.Replace(kv.value))
+	}
+	return []byte(b.String()), nil
+}
+
+// cefSeverity maps an outcome to a CEF severity from 0 (lowest) to 10.
+func cefSeverity(outcome model.AuditOutcome) int {
+	switch outcome {
+	case model.AuditOutcomeDenied:
+		return 8
+	case model.AuditOutcomeFailure:
+		return 5
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,255 @@
+package auditsink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"go.uber.org/zap"
+)
+
+// sendTimeout bounds one Send call, including connecting to the receiver.
+const sendTimeout = 30 * time.Second
+
+var (
+	errQueueFull       = errors.New("audit sink queue is full")
+	errForwarderClosed = errors.New("audit sink forwarder is closed")
+	errShutdown        = errors.New("audit sink shutdown timed out")
+)
+
+// Forwarder delivers batches to a sink in the background, so that a slow or unreachable
+// receiver never holds up the audit pipeline. Failed batches are retried with exponential
+// backoff; batches that still fail, or that arrive while the queue is full, are appended
+// to the dead-letter file for manual resending.
+type Forwarder struct {
+	cfg    Config
+	sink   Sink
+	logger *zap.Logger
+
+	queue  chan []*model.AuditLog
+	mu     sync.RWMutex // guards closed so that nothing is sent on a closed queue
+	closed bool
+
+	abort     chan struct{} // closed when shutdown times out; pending batches are dead-lettered
+	done      chan struct{} // closed when the delivery goroutine exits
+	closeOnce sync.Once
+	abortOnce sync.Once
+
+	deadLetterMu sync.Mutex
+
+	delivered, retries, deadLettered atomic.Int64
+	dropped, lastDeliveryNanos       atomic.Int64
+	lastError                        atomic.Value // string
+}
+
+// NewForwarder creates the sink described by cfg and starts delivering to it.
+func NewForwarder(cfg Config, logger *zap.Logger) (*Forwarder, error) {
+	sink, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewForwarderWithSink(cfg, sink, logger), nil
+}
+
+// NewForwarderWithSink starts delivering to an already created sink, configured by the
+// delivery settings of cfg.
+func NewForwarderWithSink(cfg Config, sink Sink, logger *zap.Logger) *Forwarder {
+	cfg = cfg.withDefaults()
+	f := &Forwarder{
+		cfg:    cfg,
+		sink:   sink,
+		logger: logger.With(zap.String("sink", cfg.Name)),
+		queue:  make(chan []*model.AuditLog, cfg.QueueSize),
+		abort:  make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go f.run()
+	return f
+}
+
+// Name returns the configured name of the sink.
+func (f *Forwarder) Name() string {
+	return f.cfg.Name
+}
+
+// Enqueue queues a batch of committed entries for delivery without blocking. The batch
+// is copied, so the caller may reuse the slice.
+func (f *Forwarder) Enqueue(entries []*model.AuditLog) {
+	if len(entries) == 0 {
+		return
+	}
+	batch := append([]*model.AuditLog(nil), entries...)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.closed {
+		f.deadLetter(batch, errForwarderClosed)
+		return
+	}
+	select {
+	case f.queue <- batch:
+	default:
+		f.deadLetter(batch, errQueueFull)
+	}
+}
+
+// Close stops accepting batches and delivers the queued ones. When ctx ends first, the
+// remaining batches are dead-lettered and ctx's error is returned.
+func (f *Forwarder) Close(ctx context.Context) error {
+	f.closeOnce.Do(func() {
+		f.mu.Lock()
+		f.closed = true
+		close(f.queue)
+		f.mu.Unlock()
+	})
+
+	var err error
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		err = ctx.Err()
+		f.abortOnce.Do(func() { close(f.abort) })
+		<-f.done
+	}
+	if closeErr := f.sink.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Stats returns the forwarder's counters.
+func (f *Forwarder) Stats() model.AuditSinkStats {
+	stats := model.AuditSinkStats{
+		Name:           f.cfg.Name,
+		Type:           f.cfg.Type,
+		Format:         f.cfg.Format,
+		QueueLength:    len(f.queue),
+		Delivered:      f.delivered.Load(),
+		Retries:        f.retries.Load(),
+		DeadLettered:   f.deadLettered.Load(),
+		Dropped:        f.dropped.Load(),
+		DeadLetterFile: f.cfg.DeadLetterFile,
+	}
+	if lastError, ok := f.lastError.Load().(string); ok {
+		stats.LastError = lastError
+	}
+	if nanos := f.lastDeliveryNanos.Load(); nanos > 0 {
+		lastDeliveryAt := time.Unix(0, nanos)
+		stats.LastDeliveryAt = &lastDeliveryAt
+	}
+	return stats
+}
+
+func (f *Forwarder) run() {
+	defer close(f.done)
+	for batch := range f.queue {
+		f.deliver(batch)
+	}
+}
+
+// deliver sends a batch, retrying with exponential backoff up to MaxAttempts times.
+func (f *Forwarder) deliver(batch []*model.AuditLog) {
+	backoff := f.cfg.RetryBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-f.abort:
+			f.deadLetter(batch, errShutdown)
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		err = f.sink.Send(ctx, batch)
+		cancel()
+		if err == nil {
+			f.delivered.Add(int64(len(batch)))
+			f.lastDeliveryNanos.Store(time.Now().UnixNano())
+			return
+		}
+		f.lastError.Store(err.Error())
+		if attempt >= f.cfg.MaxAttempts {
+			break
+		}
+
+		f.retries.Add(1)
+		f.logger.Warn("Failed to forward audit logs, retrying",
+			zap.Error(err),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff))
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-f.abort:
+			timer.Stop()
+		}
+		backoff *= 2
+	}
+	f.deadLetter(batch, err)
+}
+
+// deadLetter appends undeliverable entries to the dead-letter file; without one, or when
+// writing it fails, the entries are logged and counted as dropped.
+func (f *Forwarder) deadLetter(entries []*model.AuditLog, cause error) {
+	f.lastError.Store(cause.Error())
+	if f.cfg.DeadLetterFile != "" {
+		err := f.appendDeadLetter(entries)
+		if err == nil {
+			f.deadLettered.Add(int64(len(entries)))
+			f.logger.Warn("Audit logs written to dead-letter file",
+				zap.Error(cause),
+				zap.Int("count", len(entries)),
+				zap.String("file", f.cfg.DeadLetterFile))
+			return
+		}
+		f.logger.Error("Failed to write audit dead-letter file", zap.Error(err), zap.String("file", f.cfg.DeadLetterFile))
+	}
+
+	f.dropped.Add(int64(len(entries)))
+	for _, entry := range entries {
+		f.logger.Error("Audit log not forwarded",
+			zap.Error(cause),
+			zap.Uint("id", entry.ID),
+			zap.String("action", entry.Action),
+			zap.String("resource", entry.Resource))
+	}
+}
+
+// appendDeadLetter appends entries as JSON Lines and syncs the file.
+func (f *Forwarder) appendDeadLetter(entries []*model.AuditLog) error {
+	f.deadLetterMu.Lock()
+	defer f.deadLetterMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(f.cfg.DeadLetterFile), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.cfg.DeadLetterFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,264 @@
+package auditsink
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+)
+
+// Syslog transports.
+const (
+	NetworkUDP = "udp"
+	NetworkTCP = "tcp"
+	NetworkTLS = "tls"
+)
+
+// DefaultSyslogTimeout bounds dialing the receiver and writing one batch.
+const DefaultSyslogTimeout = 5 * time.Second
+
+// SyslogConfig configures an RFC 5424 syslog sink.
+type SyslogConfig struct {
+	Network  string        `mapstructure:"network"`  // "udp" (default), "tcp" or "tls"
+	Address  string        `mapstructure:"address"`  // host:port of the receiver
+	Facility string        `mapstructure:"facility"` // Facility name such as "local0" (default), "auth" or "authpriv"
+	AppName  string        `mapstructure:"appName"`  // APP-NAME header field; defaults to "effiplat"
+	Hostname string        `mapstructure:"hostname"` // HOSTNAME header field; defaults to the host name
+	Timeout  time.Duration `mapstructure:"timeout"`  // Dial and write timeout; defaults to 5s
+	TLS      TLSConfig     `mapstructure:"tls"`
+}
+
+// TLSConfig configures the "tls" transport (RFC 5425).
+type TLSConfig struct {
+	CAFile             string `mapstructure:"caFile"`   // PEM CA bundle for the receiver's certificate; "" uses the system roots
+	CertFile           string `mapstructure:"certFile"` // Client certificate for mutual TLS
+	KeyFile            string `mapstructure:"keyFile"`
+	ServerName         string `mapstructure:"serverName"` // Defaults to the host of Address
+	InsecureSkipVerify bool   `mapstructure:"insecureSkipVerify"`
+}
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11, "ntp": 12, "security": 13, "console": 14, "solaris-cron": 15,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// Syslog severities used for audit events.
+const (
+	syslogSeverityWarning = 4
+	syslogSeverityNotice  = 5
+)
+
+// SyslogSink sends each entry as an RFC 5424 message. Over UDP every message is one
+// datagram; over TCP and TLS messages use octet-counting framing (RFC 6587, RFC 5425).
+// The connection is opened on first use and reopened after a failed write.
+type SyslogSink struct {
+	cfg       SyslogConfig
+	facility  int
+	procID    string
+	tlsConfig *tls.Config
+	formatter Formatter
+
+	mu   sync.Mutex // serializes Send and guards conn
+	conn net.Conn
+}
+
+// NewSyslogSink validates cfg and creates a syslog sink. It does not connect yet.
+func NewSyslogSink(cfg SyslogConfig, formatter Formatter) (*SyslogSink, error) {
+	if cfg.Network == "" {
+		cfg.Network = NetworkUDP
+	}
+	if cfg.Facility == "" {
+		cfg.Facility = "local0"
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = "effiplat"
+	}
+	if cfg.Hostname == "" {
+		cfg.Hostname, _ = os.Hostname()
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultSyslogTimeout
+	}
+
+	facility, ok := syslogFacilities[strings.ToLower(cfg.Facility)]
+	if !ok {
+		return nil, fmt.Errorf("auditsink: unknown syslog facility %q", cfg.Facility)
+	}
+	host, _, err := net.SplitHostPort(cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("auditsink: invalid syslog address %q: %w", cfg.Address, err)
+	}
+	s := &SyslogSink{
+		cfg:       cfg,
+		facility:  facility,
+		procID:    strconv.Itoa(os.Getpid()),
+		formatter: formatter,
+	}
+	switch cfg.Network {
+	case NetworkUDP, NetworkTCP:
+	case NetworkTLS:
+		if s.tlsConfig, err = cfg.TLS.load(host); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("auditsink: unknown syslog network %q", cfg.Network)
+	}
+	return s, nil
+}
+
+// load builds the client TLS configuration.
+func (c TLSConfig) load(host string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("auditsink: read syslog CA file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("auditsink: no certificates found in %s", c.CAFile)
+		}
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("auditsink: load syslog client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// Send implements Sink.
+func (s *SyslogSink) Send(ctx context.Context, entries []*model.AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	deadline := time.Now().Add(s.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := s.conn.SetWriteDeadline(deadline); err != nil {
+		s.closeConn()
+		return err
+	}
+
+	for _, entry := range entries {
+		msg, err := s.message(entry)
+		if err != nil {
+			return fmt.Errorf("auditsink: format audit log %d: %w", entry.ID, err)
+		}
+		if s.cfg.Network != NetworkUDP {
+			msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+		if _, err := s.conn.Write(msg); err != nil {
+			s.closeConn()
+			return fmt.Errorf("auditsink: write to syslog %s: %w", s.cfg.Address, err)
+		}
+	}
+	return nil
+}
+
+func (s *SyslogSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.cfg.Timeout}
+	var conn net.Conn
+	var err error
+	if s.cfg.Network == NetworkTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: s.tlsConfig}).DialContext(ctx, "tcp", s.cfg.Address)
+	} else {
+		conn, err = dialer.DialContext(ctx, s.cfg.Network, s.cfg.Address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("auditsink: connect to syslog %s: %w", s.cfg.Address, err)
+	}
+	return conn, nil
+}
+
+func (s *SyslogSink) closeConn() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// Close implements Sink.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// message renders entry as an RFC 5424 message:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID - MSG, with the action as MSGID.
+func (s *SyslogSink) message(entry *model.AuditLog) ([]byte, error) {
+	body, err := s.formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	severity := syslogSeverityNotice
+	if entry.Outcome == model.AuditOutcomeFailure || entry.Outcome == model.AuditOutcomeDenied {
+		severity = syslogSeverityWarning
+	}
+	timestamp := "-"
+	if !entry.CreatedAt.IsZero() {
+		timestamp = entry.CreatedAt.UTC().Format("2006-01-02T15:04:05.000000Z07:00")
+	}
+	header := fmt.Sprintf("<%d>1 %s %s %s %s %s - ",
+		s.facility*8+severity,
+		timestamp,
+		headerField(s.cfg.Hostname, 255),
+		headerField(s.cfg.AppName, 48),
+		headerField(s.procID, 128),
+		headerField(entry.Action, 32))
+	return append([]byte(header), body...), nil
+}
+
+// headerField makes value a valid RFC 5424 header field: printable US-ASCII without
+// spaces, at most maxLen characters, or "-" when empty.
+func headerField(value string, maxLen int) string {
+	field := strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+	if len(field) > maxLen {
+		field = field[:maxLen]
+	}
+	if field == "" {
+		return "-"
+	}
+	return field
+}
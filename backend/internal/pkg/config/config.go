@@ -4,17 +4,25 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/pkg/auditsink"
 	"EffiPlat/backend/internal/pkg/logger"
+	"EffiPlat/backend/internal/pkg/storage"
 
 	"github.com/spf13/viper"
 )
 
 // AppConfig holds the application's configuration
 type AppConfig struct {
-	Server   ServerConfig  `mapstructure:"server"`
-	Database DBConfig      `mapstructure:"database"`
-	Logger   logger.Config `mapstructure:"logger"`
+	Server      ServerConfig            `mapstructure:"server"`
+	Database    DBConfig                `mapstructure:"database"`
+	Logger      logger.Config           `mapstructure:"logger"`
+	Attachments AttachmentConfig        `mapstructure:"attachments"`
+	BugSLA      BugSLAConfig            `mapstructure:"bugSla"`
+	BugWorkflow model.BugWorkflowConfig `mapstructure:"bugWorkflow"` // Bug statuses and transitions; no statuses uses the built-in workflow
+	Audit       AuditConfig             `mapstructure:"audit"`
 	// Add other configuration sections as needed
 }
 
@@ -31,6 +39,39 @@ type DBConfig struct {
 	// ... other database settings
 }
 
+// AttachmentConfig holds upload limits for attachments and where their contents are stored
+type AttachmentConfig struct {
+	MaxSizeMB    int64          `mapstructure:"maxSizeMB"`
+	AllowedTypes []string       `mapstructure:"allowedTypes"` // Detected MIME types; "image/*" allows a family
+	Storage      storage.Config `mapstructure:"storage"`
+}
+
+// BugSLAConfig holds settings for the background bug SLA checker
+type BugSLAConfig struct {
+	CheckInterval time.Duration `mapstructure:"checkInterval"` // e.g. "1m"; 0 disables the checker
+}
+
+// AuditConfig holds settings for the audit log write pipeline
+type AuditConfig struct {
+	QueueSize      int                   `mapstructure:"queueSize"`      // Entries waiting to be written before writers are held back
+	BatchSize      int                   `mapstructure:"batchSize"`      // Entries written per database insert
+	FlushInterval  time.Duration         `mapstructure:"flushInterval"`  // Longest wait before a partial batch is written
+	EnqueueTimeout time.Duration         `mapstructure:"enqueueTimeout"` // How long a request waits on a full queue before its entry is spilled
+	SpillFile      string                `mapstructure:"spillFile"`      // Entries that cannot be written go here and are replayed; "" only logs them
+	ReplayInterval time.Duration         `mapstructure:"replayInterval"` // How often the spill file is replayed
+	Retention      AuditRetentionConfig  `mapstructure:"retention"`
+	Read           model.ReadAuditPolicy `mapstructure:"read"`  // Which reads of sensitive data are audited; no rules uses the built-in policy
+	Sinks          []auditsink.Config    `mapstructure:"sinks"` // External receivers, such as a SIEM's syslog, that get every committed entry
+}
+
+// AuditRetentionConfig holds settings for archiving and purging old audit logs
+type AuditRetentionConfig struct {
+	Days                 int            `mapstructure:"days"`                 // Logs older than this are archived and purged; 0 keeps them forever
+	CheckInterval        time.Duration  `mapstructure:"checkInterval"`        // e.g. "24h"; 0 disables the archive job
+	MaxEntriesPerArchive int            `mapstructure:"maxEntriesPerArchive"` // Logs per archive file
+	Storage              storage.Config `mapstructure:"storage"`              // Where archive files are kept
+}
+
 // LoadConfig reads configuration from file and environment variables
 func LoadConfig(configPath string) (*AppConfig, error) {
 	v := viper.New()
@@ -58,6 +99,22 @@ func LoadConfig(configPath string) (*AppConfig, error) {
 
 	// --- Set Defaults ---
 	v.SetDefault("server.port", 8080)
+	v.SetDefault("attachments.maxSizeMB", 20)
+	v.SetDefault("attachments.storage.type", storage.TypeLocal)
+	v.SetDefault("attachments.storage.local.dir", "data/attachments")
+	v.SetDefault("bugSla.checkInterval", "1m")
+	v.SetDefault("audit.queueSize", 1024)
+	v.SetDefault("audit.batchSize", 100)
+	v.SetDefault("audit.flushInterval", "1s")
+	v.SetDefault("audit.enqueueTimeout", "50ms")
+	v.SetDefault("audit.spillFile", "data/audit-spill.jsonl")
+	v.SetDefault("audit.replayInterval", "1m")
+	v.SetDefault("audit.retention.days", 180)
+	v.SetDefault("audit.retention.checkInterval", "24h")
+	v.SetDefault("audit.retention.maxEntriesPerArchive", 50000)
+	v.SetDefault("audit.retention.storage.type", storage.TypeLocal)
+	v.SetDefault("audit.retention.storage.local.dir", "data/audit-archives")
+	v.SetDefault("audit.read.window", "5m")
 	// Set defaults for logger (including lumberjack) before reading config
 	logger.AddLumberjackToViper(v)
 	// Add other defaults here
@@ -89,6 +89,9 @@ func AutoMigrate(db *gorm.DB, logger *zap.Logger) error {
 		&model.Permission{},           // From models/permission_model.go
 		&model.RolePermission{},       // From models/permission_model.go
 		&model.AuditLog{},             // From model/audit_log_model.go
+		&model.AuditLogArchive{},      // From model/audit_archive_model.go
+		&model.AuditLogChange{},       // From model/audit_change_model.go
+		&model.AuditChainLock{},       // From model/audit_chain_model.go
 		&model.Responsibility{},       // Responsibility model
 		&model.ResponsibilityGroup{},  // ResponsibilityGroup model
 		&model.Environment{},          // Environment model
@@ -98,6 +101,20 @@ func AutoMigrate(db *gorm.DB, logger *zap.Logger) error {
 		&model.ServiceInstance{},      // ServiceInstance model
 		&model.Business{},             // Business model
 		&model.Bug{},                  // Bug model - fixed missing comma
+		&model.BugStatusTransition{},
+		&model.BugEnvironmentSnapshot{},
+		&model.ResponsibilityGroupMember{},
+		&model.BugAssignmentRule{},
+		&model.BugSLAPolicy{},
+		&model.BugComment{},
+		&model.BugRelation{},
+		&model.BugCommentMention{},
+		&model.BugCommentRevision{},
+		&model.BugFieldChange{},
+		&model.Attachment{},
+		&model.Deployment{},           // Deployment history model
+		&model.ConfigRevision{},       // Service instance config revisions
+		&model.EntityVersion{},        // Entity version history
 	)
 
 	if err != nil {
@@ -0,0 +1,577 @@
+// Package jsonschema implements the subset of JSON Schema (draft 7 / 2019-09 keywords)
+// used to describe service configuration documents.
+//
+// Supported keywords: type, enum, const, properties, required, additionalProperties,
+// minProperties, maxProperties, items, minItems, maxItems, uniqueItems, minLength,
+// maxLength, pattern, format (email, uri, hostname, ipv4, date-time), minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, multipleOf, allOf, anyOf, oneOf, not, and local
+// $ref pointers into "definitions" or "$defs". Unknown keywords such as "description"
+// or "default" are ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError describes a single violation found in a document.
+// Path uses dot notation with [i] for array elements, e.g. "servers[0].port";
+// an empty path refers to the document root.
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Schema is a compiled JSON Schema ready for validation.
+type Schema struct {
+	root     map[string]interface{}
+	patterns map[string]*regexp.Regexp
+}
+
+// Compile parses and checks a JSON Schema document.
+func Compile(raw []byte) (*Schema, error) {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema must be a JSON object")
+	}
+	s := &Schema{root: root, patterns: make(map[string]*regexp.Regexp)}
+	if err := s.check(root, ""); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Root returns the decoded schema document.
+func (s *Schema) Root() map[string]interface{} {
+	return s.root
+}
+
+// FlaggedPaths returns the dot paths of object properties whose schema sets the given
+// boolean annotation keyword (e.g. "secret": true), following local $refs. Validation
+// ignores such keywords, so callers can use them to attach their own meaning to fields.
+func (s *Schema) FlaggedPaths(keyword string) []string {
+	var paths []string
+	s.collectFlagged(s.root, keyword, "", map[string]bool{}, &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+func (s *Schema) collectFlagged(node interface{}, keyword, path string, seen map[string]bool, paths *[]string) {
+	n, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if ref, ok := n["$ref"].(string); ok {
+		if seen[ref] {
+			return // recursive schema; nothing new below this point
+		}
+		if target, err := s.resolve(ref); err == nil {
+			seen[ref] = true
+			s.collectFlagged(target, keyword, path, seen, paths)
+			delete(seen, ref)
+		}
+	}
+	if flag, _ := n[keyword].(bool); flag && path != "" {
+		*paths = append(*paths, path)
+	}
+	if props, ok := n["properties"].(map[string]interface{}); ok {
+		for key, sub := range props {
+			s.collectFlagged(sub, keyword, joinPath(path, key), seen, paths)
+		}
+	}
+}
+
+// Validate checks a decoded JSON value against the schema and returns all violations,
+// sorted by path. A nil result means the value is valid.
+func (s *Schema) Validate(value interface{}) []ValidationError {
+	value = normalize(value)
+	var errs []ValidationError
+	s.validate(s.root, value, "", map[string]bool{}, &errs)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+	return errs
+}
+
+// check walks the schema once at compile time to reject malformed keywords early.
+func (s *Schema) check(node map[string]interface{}, at string) error {
+	if ref, ok := node["$ref"]; ok {
+		refStr, isStr := ref.(string)
+		if !isStr {
+			return fmt.Errorf("%s$ref must be a string", at)
+		}
+		if _, err := s.resolve(refStr); err != nil {
+			return err
+		}
+	}
+	if t, ok := node["type"]; ok {
+		for _, name := range typeNames(t) {
+			if !knownType(name) {
+				return fmt.Errorf("%stype: unknown type %q", at, name)
+			}
+		}
+	}
+	if p, ok := node["pattern"]; ok {
+		ps, isStr := p.(string)
+		if !isStr {
+			return fmt.Errorf("%spattern must be a string", at)
+		}
+		re, err := regexp.Compile(ps)
+		if err != nil {
+			return fmt.Errorf("%spattern: %w", at, err)
+		}
+		s.patterns[ps] = re
+	}
+	if req, ok := node["required"]; ok {
+		list, isList := req.([]interface{})
+		if !isList {
+			return fmt.Errorf("%srequired must be an array", at)
+		}
+		for _, r := range list {
+			if _, isStr := r.(string); !isStr {
+				return fmt.Errorf("%srequired must contain strings", at)
+			}
+		}
+	}
+	for _, key := range []string{"properties", "definitions", "$defs"} {
+		if v, ok := node[key]; ok {
+			props, isObj := v.(map[string]interface{})
+			if !isObj {
+				return fmt.Errorf("%s%s must be an object", at, key)
+			}
+			for name, sub := range props {
+				if err := s.checkSub(sub, at+key+"."+name+": "); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		if v, ok := node[key]; ok {
+			if _, isBool := v.(bool); isBool {
+				continue
+			}
+			if err := s.checkSub(v, at+key+": "); err != nil {
+				return err
+			}
+		}
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if v, ok := node[key]; ok {
+			list, isList := v.([]interface{})
+			if !isList || len(list) == 0 {
+				return fmt.Errorf("%s%s must be a non-empty array", at, key)
+			}
+			for i, sub := range list {
+				if err := s.checkSub(sub, fmt.Sprintf("%s%s[%d]: ", at, key, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) checkSub(v interface{}, at string) error {
+	if _, isBool := v.(bool); isBool {
+		return nil
+	}
+	sub, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%sschema must be an object or boolean", at)
+	}
+	return s.check(sub, at)
+}
+
+// resolve follows a local JSON pointer such as "#/definitions/port".
+func (s *Schema) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("$ref %q: only local references are supported", ref)
+	}
+	var cur interface{} = s.root
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return cur, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("$ref %q cannot be resolved", ref)
+		}
+		if cur, ok = obj[token]; !ok {
+			return nil, fmt.Errorf("$ref %q cannot be resolved", ref)
+		}
+	}
+	return cur, nil
+}
+
+// validate checks value against node. active holds the $refs being followed at each
+// path; a ref that is reached again without descending into the value would recurse
+// forever, so it is reported instead.
+func (s *Schema) validate(node interface{}, value interface{}, path string, active map[string]bool, errs *[]ValidationError) {
+	switch n := node.(type) {
+	case bool:
+		if !n {
+			addErr(errs, path, "no value is allowed here")
+		}
+		return
+	case map[string]interface{}:
+		s.validateObjectSchema(n, value, path, active, errs)
+	}
+}
+
+func (s *Schema) validateObjectSchema(node map[string]interface{}, value interface{}, path string, active map[string]bool, errs *[]ValidationError) {
+	if ref, ok := node["$ref"].(string); ok {
+		target, err := s.resolve(ref)
+		if err != nil {
+			addErr(errs, path, err.Error())
+			return
+		}
+		key := ref + "\x00" + path
+		if active[key] {
+			addErr(errs, path, fmt.Sprintf("$ref %q is circular", ref))
+			return
+		}
+		active[key] = true
+		s.validate(target, value, path, active, errs)
+		delete(active, key)
+	}
+
+	if t, ok := node["type"]; ok {
+		names := typeNames(t)
+		matched := false
+		for _, name := range names {
+			if isType(value, name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			addErr(errs, path, fmt.Sprintf("expected %s, got %s", strings.Join(names, " or "), typeOf(value)))
+			return // Further keywords would only produce noise
+		}
+	}
+
+	if enum, ok := node["enum"].([]interface{}); ok {
+		found := false
+		for _, candidate := range enum {
+			if reflect.DeepEqual(candidate, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			addErr(errs, path, fmt.Sprintf("must be one of %s", compactJSON(enum)))
+		}
+	}
+	if c, ok := node["const"]; ok && !reflect.DeepEqual(c, value) {
+		addErr(errs, path, fmt.Sprintf("must equal %s", compactJSON(c)))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s.validateObject(node, v, path, active, errs)
+	case []interface{}:
+		s.validateArray(node, v, path, active, errs)
+	case string:
+		s.validateString(node, v, path, errs)
+	case float64:
+		validateNumber(node, v, path, errs)
+	}
+
+	if all, ok := node["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			s.validate(sub, value, path, active, errs)
+		}
+	}
+	if anyOf, ok := node["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if s.matches(sub, value, path, active) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			addErr(errs, path, "does not match any of the allowed schemas (anyOf)")
+		}
+	}
+	if one, ok := node["oneOf"].([]interface{}); ok {
+		count := 0
+		for _, sub := range one {
+			if s.matches(sub, value, path, active) {
+				count++
+			}
+		}
+		if count != 1 {
+			addErr(errs, path, fmt.Sprintf("must match exactly one schema (oneOf), matched %d", count))
+		}
+	}
+	if not, ok := node["not"]; ok && s.matches(not, value, path, active) {
+		addErr(errs, path, "must not match the schema in \"not\"")
+	}
+}
+
+func (s *Schema) matches(node interface{}, value interface{}, path string, active map[string]bool) bool {
+	var sub []ValidationError
+	s.validate(node, value, path, active, &sub)
+	return len(sub) == 0
+}
+
+func (s *Schema) validateObject(node map[string]interface{}, obj map[string]interface{}, path string, active map[string]bool, errs *[]ValidationError) {
+	if req, ok := node["required"].([]interface{}); ok {
+		for _, r := range req {
+			name := r.(string)
+			if _, present := obj[name]; !present {
+				addErr(errs, joinPath(path, name), "is required")
+			}
+		}
+	}
+	if n, ok := number(node["minProperties"]); ok && float64(len(obj)) < n {
+		addErr(errs, path, fmt.Sprintf("must have at least %s properties", formatNumber(n)))
+	}
+	if n, ok := number(node["maxProperties"]); ok && float64(len(obj)) > n {
+		addErr(errs, path, fmt.Sprintf("must have at most %s properties", formatNumber(n)))
+	}
+
+	props, _ := node["properties"].(map[string]interface{})
+	additional, hasAdditional := node["additionalProperties"]
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		childPath := joinPath(path, key)
+		if sub, ok := props[key]; ok {
+			s.validate(sub, obj[key], childPath, active, errs)
+			continue
+		}
+		if !hasAdditional {
+			continue
+		}
+		if allowed, isBool := additional.(bool); isBool {
+			if !allowed {
+				addErr(errs, childPath, "unknown property")
+			}
+			continue
+		}
+		s.validate(additional, obj[key], childPath, active, errs)
+	}
+}
+
+func (s *Schema) validateArray(node map[string]interface{}, arr []interface{}, path string, active map[string]bool, errs *[]ValidationError) {
+	if n, ok := number(node["minItems"]); ok && float64(len(arr)) < n {
+		addErr(errs, path, fmt.Sprintf("must have at least %s items", formatNumber(n)))
+	}
+	if n, ok := number(node["maxItems"]); ok && float64(len(arr)) > n {
+		addErr(errs, path, fmt.Sprintf("must have at most %s items", formatNumber(n)))
+	}
+	if unique, _ := node["uniqueItems"].(bool); unique {
+		for i := 0; i < len(arr); i++ {
+			for j := i + 1; j < len(arr); j++ {
+				if reflect.DeepEqual(arr[i], arr[j]) {
+					addErr(errs, path, fmt.Sprintf("items at index %d and %d must be unique", i, j))
+				}
+			}
+		}
+	}
+	if items, ok := node["items"]; ok {
+		for i, item := range arr {
+			s.validate(items, item, path+"["+strconv.Itoa(i)+"]", active, errs)
+		}
+	}
+}
+
+func (s *Schema) validateString(node map[string]interface{}, str string, path string, errs *[]ValidationError) {
+	length := float64(len([]rune(str)))
+	if n, ok := number(node["minLength"]); ok && length < n {
+		addErr(errs, path, fmt.Sprintf("must be at least %s characters long", formatNumber(n)))
+	}
+	if n, ok := number(node["maxLength"]); ok && length > n {
+		addErr(errs, path, fmt.Sprintf("must be at most %s characters long", formatNumber(n)))
+	}
+	if p, ok := node["pattern"].(string); ok {
+		if re := s.patterns[p]; re != nil && !re.MatchString(str) {
+			addErr(errs, path, fmt.Sprintf("must match pattern %q", p))
+		}
+	}
+	if f, ok := node["format"].(string); ok && !validFormat(f, str) {
+		addErr(errs, path, fmt.Sprintf("must be a valid %s", f))
+	}
+}
+
+func validateNumber(node map[string]interface{}, n float64, path string, errs *[]ValidationError) {
+	if min, ok := number(node["minimum"]); ok && n < min {
+		addErr(errs, path, fmt.Sprintf("must be >= %s", formatNumber(min)))
+	}
+	if max, ok := number(node["maximum"]); ok && n > max {
+		addErr(errs, path, fmt.Sprintf("must be <= %s", formatNumber(max)))
+	}
+	if min, ok := number(node["exclusiveMinimum"]); ok && n <= min {
+		addErr(errs, path, fmt.Sprintf("must be > %s", formatNumber(min)))
+	}
+	if max, ok := number(node["exclusiveMaximum"]); ok && n >= max {
+		addErr(errs, path, fmt.Sprintf("must be < %s", formatNumber(max)))
+	}
+	if m, ok := number(node["multipleOf"]); ok && m > 0 {
+		if q := n / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			addErr(errs, path, fmt.Sprintf("must be a multiple of %s", formatNumber(m)))
+		}
+	}
+}
+
+var hostnameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+func validFormat(format, s string) bool {
+	switch format {
+	case "email":
+		_, err := mail.ParseAddress(s)
+		return err == nil
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case "hostname":
+		return len(s) <= 253 && hostnameRegex.MatchString(s)
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && strings.Count(s, ".") == 3
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	}
+	return true // Unknown formats are annotations only
+}
+
+func typeNames(t interface{}) []string {
+	switch v := t.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		names := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+func knownType(name string) bool {
+	switch name {
+	case "object", "array", "string", "number", "integer", "boolean", "null":
+		return true
+	}
+	return false
+}
+
+func isType(value interface{}, name string) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func number(v interface{}) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func compactJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func addErr(errs *[]ValidationError, path, msg string) {
+	*errs = append(*errs, ValidationError{Path: path, Message: msg})
+}
+
+// normalize converts typed Go values (json.Number, named map types, structs) into the
+// generic representation produced by encoding/json so that validation is uniform.
+func normalize(value interface{}) interface{} {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return value
+	}
+	return out
+}
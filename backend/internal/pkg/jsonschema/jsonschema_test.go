@@ -0,0 +1,151 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["database", "replicas"],
+	"additionalProperties": false,
+	"properties": {
+		"replicas": {"type": "integer", "minimum": 1, "maximum": 10},
+		"logLevel": {"enum": ["debug", "info", "warn", "error"]},
+		"database": {
+			"type": "object",
+			"required": ["host"],
+			"properties": {
+				"host": {"type": "string", "format": "hostname"},
+				"port": {"$ref": "#/definitions/port"}
+			}
+		},
+		"tags": {"type": "array", "items": {"type": "string", "minLength": 1}, "uniqueItems": true}
+	},
+	"definitions": {
+		"port": {"type": "integer", "minimum": 1, "maximum": 65535}
+	}
+}`
+
+func TestSchemaValidate(t *testing.T) {
+	schema, err := Compile([]byte(testSchema))
+	require.NoError(t, err)
+
+	t.Run("Valid document", func(t *testing.T) {
+		errs := schema.Validate(map[string]interface{}{
+			"replicas": 3,
+			"logLevel": "info",
+			"database": map[string]interface{}{"host": "db.internal", "port": 5432},
+			"tags":     []string{"a", "b"},
+		})
+		assert.Empty(t, errs)
+	})
+
+	t.Run("Field level errors", func(t *testing.T) {
+		errs := schema.Validate(map[string]interface{}{
+			"replicas": 1.5,
+			"logLevel": "verbose",
+			"databse":  map[string]interface{}{},
+			"tags":     []interface{}{"", "x", "x"},
+		})
+		assert.Equal(t, []ValidationError{
+			{Path: "database", Message: "is required"},
+			{Path: "databse", Message: "unknown property"},
+			{Path: "logLevel", Message: `must be one of ["debug","info","warn","error"]`},
+			{Path: "replicas", Message: "expected integer, got number"},
+			{Path: "tags", Message: "items at index 1 and 2 must be unique"},
+			{Path: "tags[0]", Message: "must be at least 1 characters long"},
+		}, errs)
+	})
+
+	t.Run("Nested ref errors", func(t *testing.T) {
+		errs := schema.Validate(map[string]interface{}{
+			"replicas": 2,
+			"database": map[string]interface{}{"port": 70000},
+		})
+		assert.Equal(t, []ValidationError{
+			{Path: "database.host", Message: "is required"},
+			{Path: "database.port", Message: "must be <= 65535"},
+		}, errs)
+	})
+}
+
+func TestValidateRecursiveRefs(t *testing.T) {
+	t.Run("Circular refs are reported instead of recursing", func(t *testing.T) {
+		for name, raw := range map[string]string{
+			"self":      `{"$ref": "#"}`,
+			"mutual":    `{"$ref": "#/definitions/a", "definitions": {"a": {"$ref": "#/definitions/b"}, "b": {"allOf": [{"$ref": "#/definitions/a"}]}}}`,
+			"via anyOf": `{"anyOf": [{"type": "string"}, {"$ref": "#"}]}`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				schema, err := Compile([]byte(raw))
+				require.NoError(t, err)
+				errs := schema.Validate(map[string]interface{}{"a": 1})
+				assert.NotEmpty(t, errs)
+			})
+		}
+	})
+
+	t.Run("Recursion that descends into the value is allowed", func(t *testing.T) {
+		schema, err := Compile([]byte(`{
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"children": {"type": "array", "items": {"$ref": "#"}}
+			},
+			"additionalProperties": false
+		}`))
+		require.NoError(t, err)
+
+		assert.Empty(t, schema.Validate(map[string]interface{}{
+			"name":     "root",
+			"children": []interface{}{map[string]interface{}{"name": "leaf", "children": []interface{}{}}},
+		}))
+		assert.Equal(t, []ValidationError{
+			{Path: "children[0].children[0].name", Message: "expected string, got integer"},
+		}, schema.Validate(map[string]interface{}{
+			"children": []interface{}{map[string]interface{}{"children": []interface{}{map[string]interface{}{"name": 1}}}},
+		}))
+	})
+}
+
+func TestFlaggedPaths(t *testing.T) {
+	schema, err := Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"apiKey": {"type": "string", "secret": true},
+			"database": {
+				"type": "object",
+				"properties": {
+					"host": {"type": "string"},
+					"password": {"$ref": "#/definitions/password"}
+				}
+			}
+		},
+		"definitions": {
+			"password": {"type": "string", "secret": true, "minLength": 8}
+		}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apiKey", "database.password"}, schema.FlaggedPaths("secret"))
+	assert.Empty(t, schema.FlaggedPaths("deprecated"))
+}
+
+func TestCompileRejectsInvalidSchemas(t *testing.T) {
+	for name, raw := range map[string]string{
+		"not json":        `{`,
+		"not object":      `[]`,
+		"unknown type":    `{"type": "map"}`,
+		"bad pattern":     `{"type": "string", "pattern": "("}`,
+		"dangling ref":    `{"$ref": "#/definitions/missing"}`,
+		"remote ref":      `{"$ref": "http://example.com/schema.json"}`,
+		"bad nested prop": `{"properties": {"a": 1}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Compile([]byte(raw))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config configures the S3-compatible backend (AWS S3, MinIO, ...).
+type S3Config struct {
+	Endpoint        string `mapstructure:"endpoint"` // host[:port], without scheme
+	Region          string `mapstructure:"region"`
+	Bucket          string `mapstructure:"bucket"`
+	AccessKeyID     string `mapstructure:"accessKeyId"`
+	SecretAccessKey string `mapstructure:"secretAccessKey"`
+	UseSSL          bool   `mapstructure:"useSSL"`
+	Prefix          string `mapstructure:"prefix"`       // Optional key prefix, e.g. "effiplat/"
+	CreateBucket    bool   `mapstructure:"createBucket"` // Create the bucket on startup if missing
+}
+
+// S3Storage keeps objects in a bucket of an S3-compatible object store.
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Storage connects to the object store and checks that the bucket exists,
+// creating it when cfg.CreateBucket is set.
+func NewS3Storage(ctx context.Context, cfg S3Config) (*S3Storage, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("storage: s3 endpoint and bucket must be configured")
+	}
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: create s3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("storage: check bucket %s: %w", cfg.Bucket, err)
+	}
+	if !exists {
+		if !cfg.CreateBucket {
+			return nil, fmt.Errorf("storage: bucket %s does not exist", cfg.Bucket)
+		}
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+			return nil, fmt.Errorf("storage: create bucket %s: %w", cfg.Bucket, err)
+		}
+	}
+	return &S3Storage{client: client, bucket: cfg.Bucket, prefix: cfg.Prefix}, nil
+}
+
+func (s *S3Storage) objectName(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return s.prefix + key, nil
+}
+
+// Put uploads the object. Objects larger than the part size are sent as a multipart upload.
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	name, err := s.objectName(key)
+	if err != nil {
+		return err
+	}
+	if _, err := s.client.PutObject(ctx, s.bucket, name, r, size, minio.PutObjectOptions{ContentType: contentType}); err != nil {
+		return fmt.Errorf("storage: put %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get streams the object from the bucket. A stat is made first so a missing object is
+// reported here rather than on the first read.
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	name, err := s.objectName(key)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("storage: get %s: %w", key, err)
+	}
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if isS3NotFound(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("storage: get %s: %w", key, err)
+	}
+	return obj, nil
+}
+
+// Exists stats the object.
+func (s *S3Storage) Exists(ctx context.Context, key string) (bool, error) {
+	name, err := s.objectName(key)
+	if err != nil {
+		return false, err
+	}
+	if _, err := s.client.StatObject(ctx, s.bucket, name, minio.StatObjectOptions{}); err != nil {
+		if isS3NotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("storage: stat %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Delete removes the object. S3 does not report deleting a missing object as an error.
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	name, err := s.objectName(key)
+	if err != nil {
+		return err
+	}
+	if err := s.client.RemoveObject(ctx, s.bucket, name, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("storage: delete %s: %w", key, err)
+	}
+	return nil
+}
+
+func isS3NotFound(err error) bool {
+	resp := minio.ToErrorResponse(err)
+	return resp.StatusCode == http.StatusNotFound || resp.Code == "NoSuchKey"
+}
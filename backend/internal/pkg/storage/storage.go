@@ -0,0 +1,64 @@
+// Package storage stores opaque binary objects, such as attachment contents, under
+// slash-separated keys. Objects are written once and never modified in place.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrObjectNotFound is returned when no object is stored under a key.
+var ErrObjectNotFound = errors.New("storage: object not found")
+
+// Storage is a backend for binary objects.
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing any object already there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Exists reports whether an object is stored under key.
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete removes the object stored under key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Supported backend types.
+const (
+	TypeLocal = "local"
+	TypeS3    = "s3"
+)
+
+// Config selects and configures a storage backend.
+type Config struct {
+	Type  string      `mapstructure:"type"` // "local" (default) or "s3"
+	Local LocalConfig `mapstructure:"local"`
+	S3    S3Config    `mapstructure:"s3"`
+}
+
+// New creates the backend selected by cfg.Type.
+func New(ctx context.Context, cfg Config) (Storage, error) {
+	switch cfg.Type {
+	case "", TypeLocal:
+		return NewLocalStorage(cfg.Local.Dir)
+	case TypeS3:
+		return NewS3Storage(ctx, cfg.S3)
+	default:
+		return nil, fmt.Errorf("storage: unknown backend type %q", cfg.Type)
+	}
+}
+
+// validateKey rejects keys that are empty, absolute or that could escape the storage root.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("storage: invalid key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("storage: invalid key %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackend runs the behaviour every backend must share.
+func testBackend(t *testing.T, s Storage) {
+	ctx := context.Background()
+	key := fmt.Sprintf("test/%d/object.txt", time.Now().UnixNano())
+	content := []byte("hello, attachments")
+
+	exists, err := s.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	_, err = s.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+
+	require.NoError(t, s.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "text/plain"))
+	exists, err = s.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	r, err := s.Get(ctx, key)
+	require.NoError(t, err)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, content, got)
+
+	require.NoError(t, s.Delete(ctx, key))
+	require.NoError(t, s.Delete(ctx, key), "deleting a missing object is not an error")
+	exists, err = s.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	for _, bad := range []string{"", "/abs", "a/../b", "a//b", `a\b`} {
+		assert.Error(t, s.Put(ctx, bad, bytes.NewReader(content), int64(len(content)), "text/plain"), "key %q", bad)
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	testBackend(t, s)
+
+	t.Run("Short write is rejected", func(t *testing.T) {
+		err := s.Put(context.Background(), "short", bytes.NewReader([]byte("abc")), 10, "text/plain")
+		assert.Error(t, err)
+		exists, err := s.Exists(context.Background(), "short")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+// TestS3Storage runs against a real S3-compatible server, e.g. a local MinIO:
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//	EFFI_TEST_S3_ENDPOINT=localhost:9000 go test ./internal/pkg/storage/
+//
+// Credentials default to MinIO's minioadmin/minioadmin.
+func TestS3Storage(t *testing.T) {
+	endpoint := os.Getenv("EFFI_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("EFFI_TEST_S3_ENDPOINT not set")
+	}
+	cfg := S3Config{
+		Endpoint:        endpoint,
+		Bucket:          envOr("EFFI_TEST_S3_BUCKET", "effiplat-test"),
+		AccessKeyID:     envOr("EFFI_TEST_S3_ACCESS_KEY", "minioadmin"),
+		SecretAccessKey: envOr("EFFI_TEST_S3_SECRET_KEY", "minioadmin"),
+		Prefix:          "storage-test/",
+		CreateBucket:    true,
+	}
+	s, err := NewS3Storage(context.Background(), cfg)
+	require.NoError(t, err)
+	testBackend(t, s)
+}
+
+func TestNew(t *testing.T) {
+	s, err := New(context.Background(), Config{Local: LocalConfig{Dir: t.TempDir()}})
+	require.NoError(t, err)
+	assert.IsType(t, &LocalStorage{}, s)
+
+	_, err = New(context.Background(), Config{Type: "ftp"})
+	assert.Error(t, err)
+	_, err = New(context.Background(), Config{Type: TypeS3})
+	assert.Error(t, err, "s3 needs an endpoint and bucket")
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
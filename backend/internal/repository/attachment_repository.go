@@ -0,0 +1,112 @@
+//go:generate mockgen -destination=mocks/mock_attachment_repository.go -package=mocks EffiPlat/backend/internal/repository AttachmentRepository
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"EffiPlat/backend/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AttachmentRepository defines the interface for attachment metadata. File contents are
+// kept by a storage backend, not in the database.
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *model.Attachment) error
+	// GetByID returns model.ErrAttachmentNotFound if the attachment does not exist.
+	GetByID(ctx context.Context, id uint) (*model.Attachment, error)
+	// FindByOwnerAndHash returns the owner's attachment with the given content hash, or nil.
+	FindByOwnerAndHash(ctx context.Context, ownerType model.AttachmentOwnerType, ownerID uint, sha256 string) (*model.Attachment, error)
+	// ListByOwner returns the owner's attachments, oldest first.
+	ListByOwner(ctx context.Context, ownerType model.AttachmentOwnerType, ownerID uint) ([]*model.Attachment, error)
+	// CountByStorageKey counts the attachments sharing a stored object.
+	CountByStorageKey(ctx context.Context, storageKey string) (int64, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// attachmentRepositoryImpl implements AttachmentRepository.
+type attachmentRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewAttachmentRepository creates a new AttachmentRepository.
+func NewAttachmentRepository(db *gorm.DB, logger *zap.Logger) AttachmentRepository {
+	return &attachmentRepositoryImpl{db: db, logger: logger}
+}
+
+// Create inserts attachment metadata.
+func (r *attachmentRepositoryImpl) Create(ctx context.Context, attachment *model.Attachment) error {
+	r.logger.Debug("Creating attachment", zap.String("ownerType", string(attachment.OwnerType)), zap.Uint("ownerId", attachment.OwnerID))
+	if err := r.db.WithContext(ctx).Create(attachment).Error; err != nil {
+		r.logger.Error("Failed to create attachment", zap.Error(err))
+		return fmt.Errorf("repository.Create: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an attachment's metadata.
+func (r *attachmentRepositoryImpl) GetByID(ctx context.Context, id uint) (*model.Attachment, error) {
+	var attachment model.Attachment
+	if err := r.db.WithContext(ctx).First(&attachment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrAttachmentNotFound
+		}
+		r.logger.Error("Failed to get attachment", zap.Uint("id", id), zap.Error(err))
+		return nil, fmt.Errorf("repository.GetByID: %w", err)
+	}
+	return &attachment, nil
+}
+
+// FindByOwnerAndHash looks for an identical file already attached to the owner.
+func (r *attachmentRepositoryImpl) FindByOwnerAndHash(ctx context.Context, ownerType model.AttachmentOwnerType, ownerID uint, sha256 string) (*model.Attachment, error) {
+	var attachments []*model.Attachment
+	if err := r.db.WithContext(ctx).
+		Where("owner_type = ? AND owner_id = ? AND sha256 = ?", ownerType, ownerID, sha256).
+		Order("id ASC").Limit(1).Find(&attachments).Error; err != nil {
+		r.logger.Error("Failed to find attachment by hash", zap.Error(err))
+		return nil, fmt.Errorf("repository.FindByOwnerAndHash: %w", err)
+	}
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+	return attachments[0], nil
+}
+
+// ListByOwner retrieves the attachments of a bug, business or service.
+func (r *attachmentRepositoryImpl) ListByOwner(ctx context.Context, ownerType model.AttachmentOwnerType, ownerID uint) ([]*model.Attachment, error) {
+	var attachments []*model.Attachment
+	if err := r.db.WithContext(ctx).
+		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("id ASC").Find(&attachments).Error; err != nil {
+		r.logger.Error("Failed to list attachments", zap.String("ownerType", string(ownerType)), zap.Uint("ownerId", ownerID), zap.Error(err))
+		return nil, fmt.Errorf("repository.ListByOwner: %w", err)
+	}
+	return attachments, nil
+}
+
+// CountByStorageKey counts the attachments whose content is the stored object.
+func (r *attachmentRepositoryImpl) CountByStorageKey(ctx context.Context, storageKey string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.Attachment{}).Where("storage_key = ?", storageKey).Count(&count).Error; err != nil {
+		r.logger.Error("Failed to count attachments by storage key", zap.Error(err))
+		return 0, fmt.Errorf("repository.CountByStorageKey: %w", err)
+	}
+	return count, nil
+}
+
+// Delete removes an attachment's metadata. It returns model.ErrAttachmentNotFound if it does not exist.
+func (r *attachmentRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.Attachment{}, id)
+	if result.Error != nil {
+		r.logger.Error("Failed to delete attachment", zap.Uint("id", id), zap.Error(result.Error))
+		return fmt.Errorf("repository.Delete: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrAttachmentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"EffiPlat/backend/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAuditArchiveCorrupt is returned when archived logs do not match their archive record or hash chain.
+	ErrAuditArchiveCorrupt = errors.New("audit log archive is corrupt")
+	// ErrAuditArchiveImported is returned when importing an archive whose logs are already restored.
+	ErrAuditArchiveImported = errors.New("audit log archive is already imported")
+	// ErrAuditArchiveNotImported is returned when unloading an archive that is not imported.
+	ErrAuditArchiveNotImported = errors.New("audit log archive is not imported")
+)
+
+// FindArchivable 实现了AuditLogRepository接口的FindArchivable方法
+func (r *AuditLogRepositoryImpl) FindArchivable(ctx context.Context, cutoff time.Time, limit int) ([]model.AuditLog, error) {
+	db := r.db.WithContext(ctx)
+	var lastToID uint
+	if err := db.Model(&model.AuditLogArchive{}).Select("COALESCE(MAX(to_id), 0)").Scan(&lastToID).Error; err != nil {
+		return nil, err
+	}
+	var logs []model.AuditLog
+	if err := db.Unscoped().Where("id > ?", lastToID).Order("id").Limit(limit).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	// 只归档连续的区间，遇到第一条未过期的日志即停止
+	for i := range logs {
+		if !logs[i].CreatedAt.Before(cutoff) {
+			return logs[:i], nil
+		}
+	}
+	return logs, nil
+}
+
+// PurgeArchived 实现了AuditLogRepository接口的PurgeArchived方法
+func (r *AuditLogRepositoryImpl) PurgeArchived(ctx context.Context, archive *model.AuditLogArchive) (int64, error) {
+	auditChainMu.Lock()
+	defer auditChainMu.Unlock()
+
+	var purged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockAuditChain(tx); err != nil {
+			return err
+		}
+		var overlapping int64
+		if err := tx.Model(&model.AuditLogArchive{}).Where("to_id >= ?", archive.FromID).Count(&overlapping).Error; err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return fmt.Errorf("audit log archive %d-%d overlaps an existing archive", archive.FromID, archive.ToID)
+		}
+		result := tx.Set(auditLogPurgeKey, true).Unscoped().
+			Where("id BETWEEN ? AND ?", archive.FromID, archive.ToID).
+			Delete(&model.AuditLog{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(archive.EntryCount) {
+			return fmt.Errorf("%w: expected to purge %d logs, found %d", ErrAuditArchiveCorrupt, archive.EntryCount, result.RowsAffected)
+		}
+		purged = result.RowsAffected
+		if err := deleteAuditLogChanges(tx, archive.FromID, archive.ToID); err != nil {
+			return err
+		}
+		return tx.Create(archive).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// ListArchives 实现了AuditLogRepository接口的ListArchives方法
+func (r *AuditLogRepositoryImpl) ListArchives(ctx context.Context) ([]model.AuditLogArchive, error) {
+	var archives []model.AuditLogArchive
+	if err := r.db.WithContext(ctx).Order("from_id").Find(&archives).Error; err != nil {
+		return nil, err
+	}
+	return archives, nil
+}
+
+// FindArchiveByID 实现了AuditLogRepository接口的FindArchiveByID方法
+func (r *AuditLogRepositoryImpl) FindArchiveByID(ctx context.Context, id uint) (*model.AuditLogArchive, error) {
+	var archive model.AuditLogArchive
+	if err := r.db.WithContext(ctx).First(&archive, id).Error; err != nil {
+		return nil, err
+	}
+	return &archive, nil
+}
+
+// RestoreArchive 实现了AuditLogRepository接口的RestoreArchive方法
+func (r *AuditLogRepositoryImpl) RestoreArchive(ctx context.Context, archive *model.AuditLogArchive, logs []model.AuditLog) error {
+	if archive.ImportedAt != nil {
+		return ErrAuditArchiveImported
+	}
+	if err := r.checkArchivedLogs(archive, logs); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(logs, 100).Error; err != nil {
+			return err
+		}
+		restored := make([]*model.AuditLog, len(logs))
+		for i := range logs {
+			restored[i] = &logs[i]
+		}
+		if err := createAuditLogChanges(tx, restored); err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := tx.Model(archive).Update("imported_at", now).Error; err != nil {
+			return err
+		}
+		archive.ImportedAt = &now
+		return nil
+	})
+}
+
+// checkArchivedLogs verifies that logs are exactly the chained rows described by archive.
+func (r *AuditLogRepositoryImpl) checkArchivedLogs(archive *model.AuditLogArchive, logs []model.AuditLog) error {
+	if len(logs) != archive.EntryCount {
+		return fmt.Errorf("%w: expected %d logs, found %d", ErrAuditArchiveCorrupt, archive.EntryCount, len(logs))
+	}
+	if len(logs) == 0 {
+		return nil
+	}
+	if logs[0].ID != archive.FromID || logs[len(logs)-1].ID != archive.ToID {
+		return fmt.Errorf("%w: logs %d-%d do not match the archived range %d-%d",
+			ErrAuditArchiveCorrupt, logs[0].ID, logs[len(logs)-1].ID, archive.FromID, archive.ToID)
+	}
+	prevHash := archive.FirstPrevHash
+	chained := prevHash != ""
+	for i := range logs {
+		log := &logs[i]
+		if i > 0 && log.ID <= logs[i-1].ID {
+			return fmt.Errorf("%w: log %d is out of order", ErrAuditArchiveCorrupt, log.ID)
+		}
+		if log.Hash == "" && !chained {
+			continue
+		}
+		chained = true
+		if broken := checkAuditLogLink(r.hashKey, prevHash, log); broken != nil {
+			return fmt.Errorf("%w: log %d: %s", ErrAuditArchiveCorrupt, broken.ID, broken.Reason)
+		}
+		prevHash = log.Hash
+	}
+	if prevHash != archive.LastHash {
+		return fmt.Errorf("%w: last hash does not match the archive record", ErrAuditArchiveCorrupt)
+	}
+	return nil
+}
+
+// UnloadArchive 实现了AuditLogRepository接口的UnloadArchive方法
+func (r *AuditLogRepositoryImpl) UnloadArchive(ctx context.Context, archive *model.AuditLogArchive) (int64, error) {
+	if archive.ImportedAt == nil {
+		return 0, ErrAuditArchiveNotImported
+	}
+	var unloaded int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Set(auditLogPurgeKey, true).Unscoped().
+			Where("id BETWEEN ? AND ?", archive.FromID, archive.ToID).
+			Delete(&model.AuditLog{})
+		if result.Error != nil {
+			return result.Error
+		}
+		unloaded = result.RowsAffected
+		if err := deleteAuditLogChanges(tx, archive.FromID, archive.ToID); err != nil {
+			return err
+		}
+		return tx.Model(archive).Update("imported_at", nil).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	archive.ImportedAt = nil
+	return unloaded, nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupAuditArchiveTestRepo writes six chained audit logs, one a day from 1 May 2024.
+func setupAuditArchiveTestRepo(t *testing.T) (AuditLogRepository, *gorm.DB) {
+	gormDB, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := gormDB.DB()
+		sqlDB.Close()
+	})
+	require.NoError(t, gormDB.AutoMigrate(&model.AuditLog{}, &model.AuditLogArchive{}, &model.AuditLogChange{}, &model.AuditChainLock{}))
+
+	repo := NewAuditLogRepository(gormDB, AuditHashKey("secret"), zap.NewNop())
+	for day := 1; day <= 6; day++ {
+		require.NoError(t, repo.CreateLog(context.Background(), &model.AuditLog{
+			UserID:     1,
+			Username:   "Ann",
+			Action:     "UPDATE",
+			Resource:   "BUG",
+			ResourceID: uint(day),
+			CreatedAt:  time.Date(2024, 5, day, 12, 0, 0, 0, time.UTC),
+		}))
+	}
+	return repo, gormDB
+}
+
+// archiveFor builds the archive record that covers logs.
+func archiveFor(logs []model.AuditLog) *model.AuditLogArchive {
+	first, last := logs[0], logs[len(logs)-1]
+	return &model.AuditLogArchive{
+		ObjectKey:     fmt.Sprintf("audit-logs-%d-%d.jsonl.gz", first.ID, last.ID),
+		FromID:        first.ID,
+		ToID:          last.ID,
+		FromTime:      first.CreatedAt,
+		ToTime:        last.CreatedAt,
+		EntryCount:    len(logs),
+		Checksum:      "checksum",
+		FirstPrevHash: first.PrevHash,
+		LastHash:      last.Hash,
+	}
+}
+
+func assertChainValid(t *testing.T, repo AuditLogRepository) *model.AuditChainVerification {
+	t.Helper()
+	result, err := repo.VerifyChain(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "first broken link: %+v", result.FirstBroken)
+	return result
+}
+
+func TestAuditLogArchive_FindArchivable(t *testing.T) {
+	repo, _ := setupAuditArchiveTestRepo(t)
+	ctx := context.Background()
+	cutoff := time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC)
+
+	logs, err := repo.FindArchivable(ctx, cutoff, 100)
+	require.NoError(t, err)
+	require.Len(t, logs, 3)
+	assert.Equal(t, uint(1), logs[0].ID)
+	assert.Equal(t, uint(3), logs[2].ID)
+
+	logs, err = repo.FindArchivable(ctx, cutoff, 2)
+	require.NoError(t, err)
+	assert.Len(t, logs, 2)
+
+	// 已归档的区间之后重新开始
+	logs, err = repo.FindArchivable(ctx, cutoff, 2)
+	require.NoError(t, err)
+	_, err = repo.PurgeArchived(ctx, archiveFor(logs))
+	require.NoError(t, err)
+	logs, err = repo.FindArchivable(ctx, cutoff, 100)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, uint(3), logs[0].ID)
+}
+
+func TestAuditLogArchive_PurgeKeepsChainVerifiable(t *testing.T) {
+	repo, gormDB := setupAuditArchiveTestRepo(t)
+	ctx := context.Background()
+
+	logs, err := repo.FindArchivable(ctx, time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC), 100)
+	require.NoError(t, err)
+	archive := archiveFor(logs)
+	purged, err := repo.PurgeArchived(ctx, archive)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, purged)
+	assert.NotZero(t, archive.ID)
+
+	var remaining int64
+	require.NoError(t, gormDB.Unscoped().Model(&model.AuditLog{}).Count(&remaining).Error)
+	assert.EqualValues(t, 3, remaining)
+	result := assertChainValid(t, repo)
+	assert.EqualValues(t, 3, result.Checked)
+
+	// 同一区间不能再次归档
+	_, err = repo.PurgeArchived(ctx, archiveFor(logs))
+	assert.Error(t, err)
+
+	// 归档不能掩盖归档区间之外被删除的日志
+	require.NoError(t, gormDB.Set(auditLogPurgeKey, true).Unscoped().Delete(&model.AuditLog{}, 5).Error)
+	result, err = repo.VerifyChain(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	require.NotNil(t, result.FirstBroken)
+	assert.Equal(t, uint(6), result.FirstBroken.ID)
+}
+
+func TestAuditLogArchive_AppendAfterPurgingEverything(t *testing.T) {
+	repo, _ := setupAuditArchiveTestRepo(t)
+	ctx := context.Background()
+
+	logs, err := repo.FindArchivable(ctx, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 100)
+	require.NoError(t, err)
+	require.Len(t, logs, 6)
+	_, err = repo.PurgeArchived(ctx, archiveFor(logs))
+	require.NoError(t, err)
+
+	entry := &model.AuditLog{Action: "LOGIN", Resource: "USER"}
+	require.NoError(t, repo.CreateLog(ctx, entry))
+	assert.Equal(t, logs[5].Hash, entry.PrevHash)
+	assertChainValid(t, repo)
+}
+
+func TestAuditLogArchive_RestoreAndUnload(t *testing.T) {
+	repo, gormDB := setupAuditArchiveTestRepo(t)
+	ctx := context.Background()
+
+	logs, err := repo.FindArchivable(ctx, time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC), 100)
+	require.NoError(t, err)
+	archive := archiveFor(logs)
+	_, err = repo.PurgeArchived(ctx, archive)
+	require.NoError(t, err)
+
+	t.Run("RejectsTamperedLogs", func(t *testing.T) {
+		tampered := append([]model.AuditLog(nil), logs...)
+		tampered[1].Username = "Mallory"
+		assert.ErrorIs(t, repo.RestoreArchive(ctx, archive, tampered), ErrAuditArchiveCorrupt)
+		assert.ErrorIs(t, repo.RestoreArchive(ctx, archive, logs[:2]), ErrAuditArchiveCorrupt)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		require.NoError(t, repo.RestoreArchive(ctx, archive, logs))
+		require.NotNil(t, archive.ImportedAt)
+
+		restored, err := repo.FindLogByID(ctx, logs[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, logs[1].Hash, restored.Hash)
+		result := assertChainValid(t, repo)
+		assert.EqualValues(t, 6, result.Checked)
+
+		assert.ErrorIs(t, repo.RestoreArchive(ctx, archive, logs), ErrAuditArchiveImported)
+
+		// 重新导入的日志不会被再次归档
+		archivable, err := repo.FindArchivable(ctx, time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC), 100)
+		require.NoError(t, err)
+		assert.Empty(t, archivable)
+	})
+
+	t.Run("Unload", func(t *testing.T) {
+		unloaded, err := repo.UnloadArchive(ctx, archive)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, unloaded)
+		assert.Nil(t, archive.ImportedAt)
+
+		var stored model.AuditLogArchive
+		require.NoError(t, gormDB.First(&stored, archive.ID).Error)
+		assert.Nil(t, stored.ImportedAt)
+		assertChainValid(t, repo)
+
+		_, err = repo.UnloadArchive(ctx, archive)
+		assert.ErrorIs(t, err, ErrAuditArchiveNotImported)
+	})
+}
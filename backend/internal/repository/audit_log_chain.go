@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"EffiPlat/backend/internal/model"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAuditLogImmutable is returned when something tries to update or delete audit log rows.
+var ErrAuditLogImmutable = errors.New("audit logs cannot be updated or deleted")
+
+// AuditHashKey keys the audit log hash chain with HMAC-SHA256; empty means plain SHA-256.
+type AuditHashKey []byte
+
+// auditChainMu serializes appends and purges within this process, so that they queue here
+// rather than on the database lock that lockAuditChain takes.
+var auditChainMu sync.Mutex
+
+// auditChainLockName is the audit_chain_locks row that guards the audit_logs hash chain.
+const auditChainLockName = "audit_logs"
+
+// auditVerifyBatchSize is how many rows VerifyChain loads at a time.
+const auditVerifyBatchSize = 1000
+
+// auditLogCanonical is the content covered by a row's hash. Fields are listed explicitly so
+// that adding a column to AuditLog does not change the hashes of existing rows.
+type auditLogCanonical struct {
+	PrevHash   string `json:"prevHash"`
+	UserID     uint   `json:"userId"`
+	Username   string `json:"username"`
+	Action     string `json:"action"`
+	Resource   string `json:"resource"`
+	ResourceID uint   `json:"resourceId"`
+	Details    string `json:"details"`
+	IPAddress  string `json:"ipAddress"`
+	UserAgent  string `json:"userAgent"`
+	Outcome    string `json:"outcome"`
+	Reason     string `json:"reason"`
+	StatusCode int    `json:"statusCode"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+// auditLogHash returns the chain hash of log, linked to prevHash.
+func auditLogHash(key AuditHashKey, prevHash string, log *model.AuditLog) string {
+	content, _ := json.Marshal(auditLogCanonical{
+		PrevHash:   prevHash,
+		UserID:     log.UserID,
+		Username:   log.Username,
+		Action:     log.Action,
+		Resource:   log.Resource,
+		ResourceID: log.ResourceID,
+		Details:    log.Details,
+		IPAddress:  log.IPAddress,
+		UserAgent:  log.UserAgent,
+		Outcome:    string(log.Outcome),
+		Reason:     log.Reason,
+		StatusCode: log.StatusCode,
+		CreatedAt:  log.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if len(key) > 0 {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(content)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// appendChained links logs to the last stored row and inserts them in one transaction.
+func (r *AuditLogRepositoryImpl) appendChained(ctx context.Context, logs []*model.AuditLog) error {
+	auditChainMu.Lock()
+	defer auditChainMu.Unlock()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockAuditChain(tx); err != nil {
+			return err
+		}
+		prevHash, err := auditChainTail(tx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, log := range logs {
+			if log.CreatedAt.IsZero() {
+				log.CreatedAt = now
+			}
+			// 数据库只保证微秒精度，先截断以便读出后能重新算出相同的哈希
+			log.CreatedAt = log.CreatedAt.Truncate(time.Microsecond)
+			if log.Outcome == "" {
+				log.Outcome = model.AuditOutcomeSuccess
+			}
+			log.PrevHash = prevHash
+			log.Hash = auditLogHash(r.hashKey, prevHash, log)
+			prevHash = log.Hash
+		}
+		if err := tx.CreateInBatches(logs, 100).Error; err != nil {
+			return err
+		}
+		return createAuditLogChanges(tx, logs)
+	})
+}
+
+// lockAuditChain locks the hash chain until tx ends, so that another process appending or
+// purging (the API server and cmd/audit share the database) cannot link to the same tail.
+// The lock row is written before anything is read, which on SQLite takes the write lock up
+// front like BEGIN IMMEDIATE; other writers wait for it within the busy timeout.
+func lockAuditChain(tx *gorm.DB) error {
+	lock := func() (int64, error) {
+		result := tx.Model(&model.AuditChainLock{}).Where("name = ?", auditChainLockName).Update("locked_at", time.Now())
+		return result.RowsAffected, result.Error
+	}
+	locked, err := lock()
+	if err != nil || locked > 0 {
+		return err
+	}
+	// 第一次加锁时锁行还不存在
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.AuditChainLock{Name: auditChainLockName, LockedAt: time.Now()}).Error; err != nil {
+		return err
+	}
+	_, err = lock()
+	return err
+}
+
+// auditChainTail returns the hash the next row links to: that of the row with the highest ID,
+// or of the last archive if the rows after it have been purged.
+func auditChainTail(tx *gorm.DB) (string, error) {
+	var last model.AuditLog
+	err := tx.Unscoped().Select("id", "hash").Order("id DESC").Take(&last).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	var archive model.AuditLogArchive
+	err = tx.Select("to_id", "last_hash").Order("to_id DESC").Take(&archive).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return last.Hash, nil
+	case err != nil:
+		return "", err
+	case archive.ToID > last.ID:
+		return archive.LastHash, nil
+	}
+	return last.Hash, nil
+}
+
+// VerifyChain 实现了AuditLogRepository接口的VerifyChain方法
+func (r *AuditLogRepositoryImpl) VerifyChain(ctx context.Context) (*model.AuditChainVerification, error) {
+	result := &model.AuditChainVerification{Valid: true, Keyed: len(r.hashKey) > 0}
+	// 被归档清除的区间由归档记录的LastHash衔接
+	var archives []model.AuditLogArchive
+	if err := r.db.WithContext(ctx).Find(&archives).Error; err != nil {
+		return nil, err
+	}
+	anchors := make(map[string]model.AuditLogArchive, len(archives))
+	for _, archive := range archives {
+		if archive.LastHash != "" {
+			anchors[archive.LastHash] = archive
+		}
+	}
+
+	prevHash := ""
+	chained := false
+	var lastID uint
+	for {
+		var batch []model.AuditLog
+		if err := r.db.WithContext(ctx).Unscoped().
+			Where("id > ?", lastID).
+			Order("id").
+			Limit(auditVerifyBatchSize).
+			Find(&batch).Error; err != nil {
+			return nil, err
+		}
+		for i := range batch {
+			log := &batch[i]
+			prevID := lastID
+			lastID = log.ID
+			if archive, ok := anchors[log.PrevHash]; ok && log.PrevHash != prevHash &&
+				archive.FromID > prevID && archive.ToID < log.ID {
+				prevHash = log.PrevHash // 前面的日志整段归档后被清除
+			}
+			if log.Hash == "" && !chained {
+				result.Unchained++ // 哈希链开始之前的历史日志
+				continue
+			}
+			chained = true
+			result.Checked++
+			result.HeadID, result.HeadHash = log.ID, log.Hash
+			if broken := checkAuditLogLink(r.hashKey, prevHash, log); broken != nil {
+				result.Valid, result.FirstBroken = false, broken
+				result.VerifiedAt = time.Now()
+				return result, nil
+			}
+			prevHash = log.Hash
+		}
+		if len(batch) < auditVerifyBatchSize {
+			break
+		}
+	}
+	result.VerifiedAt = time.Now()
+	return result, nil
+}
+
+// checkAuditLogLink reports how log fails to follow the row whose hash is prevHash, or nil.
+func checkAuditLogLink(key AuditHashKey, prevHash string, log *model.AuditLog) *model.AuditChainBreak {
+	if log.Hash == "" {
+		return &model.AuditChainBreak{ID: log.ID, Reason: model.AuditChainMissingHash, ExpectedPrevHash: prevHash}
+	}
+	if log.PrevHash != prevHash {
+		return &model.AuditChainBreak{ID: log.ID, Reason: model.AuditChainPrevHashMismatch, ExpectedPrevHash: prevHash, ActualPrevHash: log.PrevHash}
+	}
+	if expected := auditLogHash(key, log.PrevHash, log); expected != log.Hash {
+		return &model.AuditChainBreak{ID: log.ID, Reason: model.AuditChainHashMismatch, ExpectedHash: expected, ActualHash: log.Hash}
+	}
+	return nil
+}
+
+// auditLogPurgeKey marks a session allowed to delete archived audit log rows.
+const auditLogPurgeKey = "audit_logs:purge"
+
+// auditLogWriteSQL matches raw statements that would change or remove audit log rows.
+var auditLogWriteSQL = regexp.MustCompile(`(?is)^\s*(update\s+["'\x60]?audit_logs\b|delete\s+from\s+["'\x60]?audit_logs\b)`)
+
+// guardAuditLogs registers callbacks on db that reject updates and deletes of audit_logs,
+// including soft deletes and raw SQL. Only PurgeArchived and UnloadArchive may delete rows.
+// Registering again on the same db is a no-op.
+func guardAuditLogs(db *gorm.DB) {
+	const name = "audit_logs:immutable"
+	if db.Callback().Update().Get(name) != nil {
+		return
+	}
+	reject := func(tx *gorm.DB) {
+		if tx.Statement.Table == "audit_logs" {
+			_ = tx.AddError(ErrAuditLogImmutable)
+		}
+	}
+	_ = db.Callback().Update().Before("gorm:update").Register(name, reject)
+	_ = db.Callback().Delete().Before("gorm:delete").Register(name, func(tx *gorm.DB) {
+		if purge, ok := tx.Get(auditLogPurgeKey); ok && purge == true {
+			return // 已归档日志的清除
+		}
+		reject(tx)
+	})
+	_ = db.Callback().Raw().Before("gorm:raw").Register(name, func(tx *gorm.DB) {
+		if auditLogWriteSQL.MatchString(tx.Statement.SQL.String()) {
+			_ = tx.AddError(ErrAuditLogImmutable)
+		}
+	})
+}
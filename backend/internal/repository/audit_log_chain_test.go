@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupAuditChainTestRepo writes four chained audit logs to an in-memory SQLite database and
+// returns the raw connection too, which bypasses the repository guard to simulate tampering.
+func setupAuditChainTestRepo(t *testing.T, key AuditHashKey) (AuditLogRepository, *gorm.DB, *sql.DB) {
+	gormDB, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, _ := gormDB.DB()
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+	require.NoError(t, gormDB.AutoMigrate(&model.AuditLog{}, &model.AuditLogArchive{}, &model.AuditChainLock{}))
+
+	repo := NewAuditLogRepository(gormDB, key, zap.NewNop())
+	ctx := context.Background()
+	require.NoError(t, repo.CreateLog(ctx, &model.AuditLog{UserID: 1, Username: "Ann", Action: "CREATE", Resource: "BUG", ResourceID: 1, Details: `{"title":"Crash"}`}))
+	require.NoError(t, repo.CreateLogs(ctx, []*model.AuditLog{
+		{UserID: 1, Username: "Ann", Action: "UPDATE", Resource: "BUG", ResourceID: 1, Details: `{"priority":"HIGH"}`},
+		{UserID: 2, Username: "Bob", Action: "DELETE", Resource: "BUG", ResourceID: 1},
+	}))
+	require.NoError(t, repo.CreateLog(ctx, &model.AuditLog{Action: "LOGIN", Resource: "USER", Outcome: model.AuditOutcomeFailure, Reason: "unknown email"}))
+	return repo, gormDB, sqlDB
+}
+
+func TestAuditLogChain_Valid(t *testing.T) {
+	repo, gormDB, _ := setupAuditChainTestRepo(t, nil)
+
+	var logs []model.AuditLog
+	require.NoError(t, gormDB.Order("id").Find(&logs).Error)
+	require.Len(t, logs, 4)
+	assert.Empty(t, logs[0].PrevHash)
+	for i := 1; i < len(logs); i++ {
+		assert.Len(t, logs[i].Hash, 64)
+		assert.Equal(t, logs[i-1].Hash, logs[i].PrevHash)
+	}
+
+	result, err := repo.VerifyChain(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.False(t, result.Keyed)
+	assert.EqualValues(t, 4, result.Checked)
+	assert.Equal(t, logs[3].ID, result.HeadID)
+	assert.Equal(t, logs[3].Hash, result.HeadHash)
+	assert.Nil(t, result.FirstBroken)
+}
+
+func TestAuditLogChain_DetectsTampering(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("EditedContent", func(t *testing.T) {
+		repo, _, sqlDB := setupAuditChainTestRepo(t, nil)
+		_, err := sqlDB.Exec(`UPDATE audit_logs SET username = 'Mallory' WHERE id = 2`)
+		require.NoError(t, err)
+
+		result, err := repo.VerifyChain(ctx)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.FirstBroken)
+		assert.Equal(t, uint(2), result.FirstBroken.ID)
+		assert.Equal(t, model.AuditChainHashMismatch, result.FirstBroken.Reason)
+	})
+
+	t.Run("DeletedRow", func(t *testing.T) {
+		repo, _, sqlDB := setupAuditChainTestRepo(t, nil)
+		_, err := sqlDB.Exec(`DELETE FROM audit_logs WHERE id = 2`)
+		require.NoError(t, err)
+
+		result, err := repo.VerifyChain(ctx)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.FirstBroken)
+		assert.Equal(t, uint(3), result.FirstBroken.ID)
+		assert.Equal(t, model.AuditChainPrevHashMismatch, result.FirstBroken.Reason)
+		assert.EqualValues(t, 2, result.Checked)
+	})
+
+	t.Run("UnhashedRowAfterChainStart", func(t *testing.T) {
+		repo, _, sqlDB := setupAuditChainTestRepo(t, nil)
+		_, err := sqlDB.Exec(`INSERT INTO audit_logs (user_id, username, action, resource, resource_id, outcome, created_at) VALUES (1, 'Ann', 'DELETE', 'BUG', 2, 'SUCCESS', CURRENT_TIMESTAMP)`)
+		require.NoError(t, err)
+
+		result, err := repo.VerifyChain(ctx)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.FirstBroken)
+		assert.Equal(t, model.AuditChainMissingHash, result.FirstBroken.Reason)
+	})
+
+	t.Run("RecomputedWithoutKey", func(t *testing.T) {
+		// 攻击者不知道密钥，按不带密钥的SHA-256重写日志后校验失败
+		keyed, gormDB, sqlDB := setupAuditChainTestRepo(t, AuditHashKey("secret"))
+		var log model.AuditLog
+		require.NoError(t, gormDB.First(&log, 1).Error)
+		log.Username = "Mallory"
+		_, err := sqlDB.Exec(`UPDATE audit_logs SET username = ?, hash = ? WHERE id = 1`, log.Username, auditLogHash(nil, "", &log))
+		require.NoError(t, err)
+
+		result, err := keyed.VerifyChain(ctx)
+		require.NoError(t, err)
+		assert.True(t, result.Keyed)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.FirstBroken)
+		assert.Equal(t, uint(1), result.FirstBroken.ID)
+		assert.Equal(t, model.AuditChainHashMismatch, result.FirstBroken.Reason)
+	})
+}
+
+func TestAuditLogChain_LegacyRowsAreUnchained(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := gormDB.DB()
+		sqlDB.Close()
+	})
+	require.NoError(t, gormDB.AutoMigrate(&model.AuditLog{}, &model.AuditLogArchive{}, &model.AuditChainLock{}))
+	require.NoError(t, gormDB.Create(&model.AuditLog{Action: "CREATE", Resource: "BUG", ResourceID: 1}).Error)
+
+	repo := NewAuditLogRepository(gormDB, nil, zap.NewNop())
+	require.NoError(t, repo.CreateLog(context.Background(), &model.AuditLog{Action: "UPDATE", Resource: "BUG", ResourceID: 1}))
+
+	result, err := repo.VerifyChain(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.EqualValues(t, 1, result.Unchained)
+	assert.EqualValues(t, 1, result.Checked)
+}
+
+func TestAuditLogChain_AppendWaitsForLockHeldByAnotherConnection(t *testing.T) {
+	// 两个独立的连接模拟共享同一数据库文件的API服务和 cmd/audit
+	path := filepath.Join(t.TempDir(), "audit.db")
+	open := func() *gorm.DB {
+		gormDB, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			sqlDB, _ := gormDB.DB()
+			sqlDB.Close()
+		})
+		return gormDB
+	}
+	server, cli := open(), open()
+	require.NoError(t, server.AutoMigrate(&model.AuditLog{}, &model.AuditLogArchive{}, &model.AuditChainLock{}))
+	repo := NewAuditLogRepository(server, nil, zap.NewNop())
+	ctx := context.Background()
+	require.NoError(t, repo.CreateLog(ctx, &model.AuditLog{Action: "CREATE", Resource: "BUG", ResourceID: 1}))
+
+	tx := cli.Begin()
+	require.NoError(t, tx.Error)
+	require.NoError(t, lockAuditChain(tx))
+
+	appended := make(chan error, 1)
+	go func() {
+		appended <- repo.CreateLog(ctx, &model.AuditLog{Action: "UPDATE", Resource: "BUG", ResourceID: 1})
+	}()
+	select {
+	case err := <-appended:
+		t.Fatalf("append finished while another connection held the chain lock: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, tx.Commit().Error)
+	select {
+	case err := <-appended:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("append did not finish after the chain lock was released")
+	}
+
+	result, err := repo.VerifyChain(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.EqualValues(t, 2, result.Checked)
+}
+
+func TestAuditLogRepository_RejectsUpdateAndDelete(t *testing.T) {
+	repo, gormDB, _ := setupAuditChainTestRepo(t, nil)
+
+	assert.ErrorIs(t, gormDB.Model(&model.AuditLog{}).Where("id = ?", 1).Update("username", "Mallory").Error, ErrAuditLogImmutable)
+	assert.ErrorIs(t, gormDB.Save(&model.AuditLog{ID: 1, Action: "CREATE", Resource: "BUG"}).Error, ErrAuditLogImmutable)
+	assert.ErrorIs(t, gormDB.Delete(&model.AuditLog{}, 1).Error, ErrAuditLogImmutable)
+	assert.ErrorIs(t, gormDB.Unscoped().Where("1 = 1").Delete(&model.AuditLog{}).Error, ErrAuditLogImmutable)
+	assert.ErrorIs(t, gormDB.Exec(`UPDATE audit_logs SET username = 'Mallory'`).Error, ErrAuditLogImmutable)
+	assert.ErrorIs(t, gormDB.Exec("delete from `audit_logs` where id = 1").Error, ErrAuditLogImmutable)
+
+	// 其他表不受影响
+	require.NoError(t, gormDB.AutoMigrate(&model.User{}))
+	user := &model.User{Name: "Ann", Email: "ann@example.com", Password: "x", Status: "active"}
+	require.NoError(t, gormDB.Create(user).Error)
+	require.NoError(t, gormDB.Model(user).Update("name", "Anne").Error)
+	require.NoError(t, gormDB.Delete(user).Error)
+
+	result, err := repo.VerifyChain(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.EqualValues(t, 4, result.Checked)
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/utils"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// FindChanges 实现了AuditLogRepository接口的FindChanges方法
+func (r *AuditLogRepositoryImpl) FindChanges(ctx context.Context, params model.AuditLogChangeQueryParams) ([]model.AuditLogChange, int64, error) {
+	var changes []model.AuditLogChange
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&model.AuditLogChange{})
+	if params.Resource != nil && *params.Resource != "" {
+		query = query.Where("resource = ?", strings.ToUpper(*params.Resource))
+	}
+	if params.Field != nil && *params.Field != "" {
+		query = query.Where("LOWER(field) = ?", strings.ToLower(*params.Field))
+	}
+	if params.ResourceID != nil {
+		query = query.Where("resource_id = ?", *params.ResourceID)
+	}
+	if params.UserID != nil {
+		query = query.Where("user_id = ?", *params.UserID)
+	}
+	if params.StartDate != nil && *params.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", *params.StartDate)
+		if err == nil {
+			query = query.Where("created_at >= ?", startDate)
+		} else {
+			r.logger.Warn("Invalid start date format", zap.String("startDate", *params.StartDate), zap.Error(err))
+		}
+	}
+	if params.EndDate != nil && *params.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", *params.EndDate)
+		if err == nil {
+			query = query.Where("created_at <= ?", endDate.Add(24*time.Hour-time.Second))
+		} else {
+			r.logger.Warn("Invalid end date format", zap.String("endDate", *params.EndDate), zap.Error(err))
+		}
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.PageSize
+	if offset < 0 {
+		offset = 0
+	}
+	if err := query.Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(params.PageSize).
+		Find(&changes).Error; err != nil {
+		return nil, 0, err
+	}
+	return changes, count, nil
+}
+
+// createAuditLogChanges saves the field changes recorded in the details of the UPDATE logs,
+// which must already have their IDs.
+func createAuditLogChanges(tx *gorm.DB, logs []*model.AuditLog) error {
+	var changes []model.AuditLogChange
+	for _, log := range logs {
+		changes = append(changes, auditLogChanges(log)...)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(changes, 100).Error
+}
+
+// deleteAuditLogChanges removes the field changes of the logs with IDs from fromID to toID.
+func deleteAuditLogChanges(tx *gorm.DB, fromID, toID uint) error {
+	return tx.Where("audit_log_id BETWEEN ? AND ?", fromID, toID).Delete(&model.AuditLogChange{}).Error
+}
+
+// auditLogChanges returns the rows for the "diff" in the details of an UPDATE log.
+func auditLogChanges(log *model.AuditLog) []model.AuditLogChange {
+	if log.Action != "UPDATE" || !strings.Contains(log.Details, `"diff"`) {
+		return nil
+	}
+	var details struct {
+		Diff []utils.JSONChange `json:"diff"`
+	}
+	if err := json.Unmarshal([]byte(log.Details), &details); err != nil {
+		return nil
+	}
+	changes := make([]model.AuditLogChange, 0, len(details.Diff))
+	for _, diff := range details.Diff {
+		changes = append(changes, model.AuditLogChange{
+			AuditLogID: log.ID,
+			UserID:     log.UserID,
+			Username:   log.Username,
+			Resource:   log.Resource,
+			ResourceID: log.ResourceID,
+			Field:      diff.Path,
+			Op:         string(diff.Op),
+			OldValue:   auditChangeValue(diff.OldValue),
+			NewValue:   auditChangeValue(diff.NewValue),
+			CreatedAt:  log.CreatedAt,
+		})
+	}
+	return changes
+}
+
+// auditChangeValue formats a changed value for the change table: strings as they are,
+// missing values as "" and everything else as JSON.
+func auditChangeValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	default:
+		raw, err := json.Marshal(t)
+		if err != nil {
+			return ""
+		}
+		return string(raw)
+	}
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogChanges(t *testing.T) {
+	repo, gormDB := setupAuditArchiveTestRepo(t)
+	ctx := context.Background()
+
+	ipChange := &model.AuditLog{
+		UserID:     2,
+		Username:   "Bob",
+		Action:     "UPDATE",
+		Resource:   "ASSET",
+		ResourceID: 9,
+		Details:    `{"diff":[{"path":"ipAddress","op":"changed","oldValue":"10.0.0.1","newValue":"10.0.0.2"},{"path":"port","op":"changed","oldValue":80,"newValue":8080}]}`,
+		CreatedAt:  time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, repo.CreateLogs(ctx, []*model.AuditLog{
+		ipChange,
+		{Action: "CREATE", Resource: "ASSET", ResourceID: 10, Details: `{"diff":[{"path":"ipAddress","newValue":"x"}]}`},
+	}))
+
+	t.Run("DerivedFromDetails", func(t *testing.T) {
+		var changes []model.AuditLogChange
+		require.NoError(t, gormDB.Order("id").Find(&changes).Error)
+		require.Len(t, changes, 2)
+		assert.Equal(t, ipChange.ID, changes[0].AuditLogID)
+		assert.Equal(t, "Bob", changes[0].Username)
+		assert.Equal(t, "10.0.0.1", changes[0].OldValue)
+		assert.Equal(t, "8080", changes[1].NewValue)
+		assert.Equal(t, ipChange.CreatedAt, changes[0].CreatedAt.UTC())
+	})
+
+	t.Run("FindByField", func(t *testing.T) {
+		resource, field := "asset", "IPADDRESS"
+		changes, total, err := repo.FindChanges(ctx, model.AuditLogChangeQueryParams{Resource: &resource, Field: &field, Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, total)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "ipAddress", changes[0].Field)
+		assert.Equal(t, uint(9), changes[0].ResourceID)
+
+		start, end := "2024-05-11", "2024-05-31"
+		_, total, err = repo.FindChanges(ctx, model.AuditLogChangeQueryParams{Field: &field, StartDate: &start, EndDate: &end, Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("ArchiveAndRestore", func(t *testing.T) {
+		logs, err := repo.FindArchivable(ctx, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 100)
+		require.NoError(t, err)
+		require.Len(t, logs, 7)
+		archive := archiveFor(logs)
+		_, err = repo.PurgeArchived(ctx, archive)
+		require.NoError(t, err)
+
+		var count int64
+		require.NoError(t, gormDB.Model(&model.AuditLogChange{}).Count(&count).Error)
+		assert.Zero(t, count)
+
+		require.NoError(t, repo.RestoreArchive(ctx, archive, logs))
+		require.NoError(t, gormDB.Model(&model.AuditLogChange{}).Count(&count).Error)
+		assert.EqualValues(t, 2, count)
+
+		_, err = repo.UnloadArchive(ctx, archive)
+		require.NoError(t, err)
+		require.NoError(t, gormDB.Model(&model.AuditLogChange{}).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
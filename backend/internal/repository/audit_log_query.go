@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"EffiPlat/backend/internal/model"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidAuditCursor is returned when a cursor is malformed or was issued for a different sort.
+var ErrInvalidAuditCursor = errors.New("invalid audit log cursor")
+
+// auditLogSortColumns maps the sortBy values of AuditLogQueryParams to columns.
+var auditLogSortColumns = map[string]string{
+	"id":        "id",
+	"createdAt": "created_at",
+	"userId":    "user_id",
+	"username":  "username",
+	"action":    "action",
+	"resource":  "resource",
+	"outcome":   "outcome",
+	"ipAddress": "ip_address",
+}
+
+// likeEscaper escapes the LIKE wildcards in user input; queries use ESCAPE '!'.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// applyAuditLogFilters adds the filters of params to query.
+func (r *AuditLogRepositoryImpl) applyAuditLogFilters(query *gorm.DB, params model.AuditLogQueryParams) *gorm.DB {
+	if params.UserID != nil {
+		query = query.Where("user_id = ?", *params.UserID)
+	}
+	if params.Action != nil && *params.Action != "" {
+		query = query.Where("action = ?", *params.Action)
+	}
+	if params.Resource != nil && *params.Resource != "" {
+		query = query.Where("resource = ?", *params.Resource)
+	}
+	if params.ResourceID != nil {
+		query = query.Where("resource_id = ?", *params.ResourceID)
+	}
+	if params.Outcome != nil && *params.Outcome != "" {
+		query = query.Where("outcome = ?", strings.ToUpper(*params.Outcome))
+	}
+	if params.IPAddress != nil && *params.IPAddress != "" {
+		query = query.Where("ip_address = ?", *params.IPAddress)
+	}
+	if params.Search != nil && *params.Search != "" {
+		query = query.Where("LOWER(details) LIKE ? ESCAPE '!'", "%"+likeEscaper.Replace(strings.ToLower(*params.Search))+"%")
+	}
+
+	// 日期范围筛选
+	if params.StartDate != nil && *params.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", *params.StartDate)
+		if err == nil {
+			query = query.Where("created_at >= ?", startDate)
+		} else {
+			r.logger.Warn("Invalid start date format", zap.String("startDate", *params.StartDate), zap.Error(err))
+		}
+	}
+	if params.EndDate != nil && *params.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", *params.EndDate)
+		if err == nil {
+			// 将结束日期设为当天的最后一刻
+			query = query.Where("created_at <= ?", endDate.Add(24*time.Hour-time.Second))
+		} else {
+			r.logger.Warn("Invalid end date format", zap.String("endDate", *params.EndDate), zap.Error(err))
+		}
+	}
+	return query
+}
+
+// auditLogSort returns the sort column and direction of params, defaulting to created_at DESC.
+func auditLogSort(params model.AuditLogQueryParams) (string, string) {
+	column, ok := auditLogSortColumns[params.SortBy]
+	if !ok {
+		column = "created_at"
+	}
+	if strings.EqualFold(params.Order, "asc") {
+		return column, "ASC"
+	}
+	return column, "DESC"
+}
+
+// orderAuditLogs sorts query by the requested column, breaking ties by ID so that pages are stable.
+func orderAuditLogs(query *gorm.DB, params model.AuditLogQueryParams) *gorm.DB {
+	column, direction := auditLogSort(params)
+	if column == "id" {
+		return query.Order("id " + direction)
+	}
+	return query.Order(column + " " + direction).Order("id " + direction)
+}
+
+// encodeAuditCursor returns the cursor for the page after the log with lastID.
+// The cursor records the sort so that it cannot be reused with another one.
+func encodeAuditCursor(params model.AuditLogQueryParams, lastID uint) string {
+	column, direction := auditLogSort(params)
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s:%d", column, direction, lastID)))
+}
+
+// decodeAuditCursor returns the ID of the last log of the previous page; an empty cursor starts at the beginning.
+func decodeAuditCursor(params model.AuditLogQueryParams, cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidAuditCursor
+	}
+	parts := strings.Split(string(raw), ":")
+	column, direction := auditLogSort(params)
+	if len(parts) != 3 || parts[0] != column || parts[1] != direction {
+		return 0, ErrInvalidAuditCursor
+	}
+	lastID, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil || lastID == 0 {
+		return 0, ErrInvalidAuditCursor
+	}
+	return uint(lastID), nil
+}
+
+// afterAuditLog restricts query to the logs sorted after the log with lastID. The sort value of
+// that log is read in a subquery, so the database compares values in its own representation.
+func afterAuditLog(query *gorm.DB, params model.AuditLogQueryParams, lastID uint) *gorm.DB {
+	column, direction := auditLogSort(params)
+	op := "<"
+	if direction == "ASC" {
+		op = ">"
+	}
+	if column == "id" {
+		return query.Where("id "+op+" ?", lastID)
+	}
+	anchor := fmt.Sprintf("(SELECT %s FROM audit_logs WHERE id = ?)", column)
+	return query.Where(fmt.Sprintf("(%s %s %s OR (%s = %s AND id %s ?))", column, op, anchor, column, anchor, op), lastID, lastID, lastID)
+}
+
+// FindLogPage 实现了AuditLogRepository接口的FindLogPage方法
+func (r *AuditLogRepositoryImpl) FindLogPage(ctx context.Context, params model.AuditLogQueryParams, cursor string) ([]model.AuditLog, string, error) {
+	lastID, err := decodeAuditCursor(params, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	limit := params.PageSize
+	if limit <= 0 {
+		limit = 10
+	}
+	logs, err := r.FindLogsAfter(ctx, params, lastID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(logs) <= limit {
+		return logs, "", nil
+	}
+	logs = logs[:limit]
+	return logs, encodeAuditCursor(params, logs[limit-1].ID), nil
+}
+
+// EachLogBatch 实现了AuditLogRepository接口的EachLogBatch方法
+func (r *AuditLogRepositoryImpl) EachLogBatch(ctx context.Context, params model.AuditLogQueryParams, batchSize int, fn func([]model.AuditLog) error) error {
+	var lastID uint
+	for {
+		logs, err := r.FindLogsAfter(ctx, params, lastID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(logs) == 0 {
+			return nil
+		}
+		if err := fn(logs); err != nil {
+			return err
+		}
+		if len(logs) < batchSize {
+			return nil
+		}
+		lastID = logs[len(logs)-1].ID
+	}
+}
+
+// FindLogsAfter 实现了AuditLogRepository接口的FindLogsAfter方法
+func (r *AuditLogRepositoryImpl) FindLogsAfter(ctx context.Context, params model.AuditLogQueryParams, lastID uint, limit int) ([]model.AuditLog, error) {
+	query := r.applyAuditLogFilters(r.db.WithContext(ctx).Model(&model.AuditLog{}), params)
+	if lastID != 0 {
+		query = afterAuditLog(query, params, lastID)
+	}
+	var logs []model.AuditLog
+	if err := orderAuditLogs(query, params).Limit(limit).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAuditQueryTestRepo writes ten logs: even ones from 10.0.0.1 as Ann, odd ones from 10.0.0.2 as Bob.
+// Logs 4 and 5 share a timestamp to exercise ties.
+func setupAuditQueryTestRepo(t *testing.T) AuditLogRepository {
+	repo, _ := setupAuditArchiveTestRepo(t)
+	base := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	logs := make([]*model.AuditLog, 0, 10)
+	for i := 1; i <= 10; i++ {
+		log := &model.AuditLog{
+			UserID:     2,
+			Username:   "Ann",
+			Action:     "UPDATE",
+			Resource:   "ASSET",
+			ResourceID: uint(i),
+			IPAddress:  "10.0.0.1",
+			Details:    fmt.Sprintf(`{"hostname":"web-%02d"}`, i),
+			CreatedAt:  base.Add(time.Duration(i) * time.Hour),
+		}
+		if i%2 == 1 {
+			log.Username, log.IPAddress = "Bob", "10.0.0.2"
+		}
+		if i == 5 {
+			log.CreatedAt = logs[3].CreatedAt
+		}
+		logs = append(logs, log)
+	}
+	logs[6].Details = `{"note":"100% done_ok"}`
+	require.NoError(t, repo.CreateLogs(context.Background(), logs))
+	return repo
+}
+
+func resourceIDs(logs []model.AuditLog) []uint {
+	ids := make([]uint, len(logs))
+	for i, log := range logs {
+		ids[i] = log.ResourceID
+	}
+	return ids
+}
+
+func TestAuditLogQuery_Filters(t *testing.T) {
+	repo := setupAuditQueryTestRepo(t)
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+	start := "2024-06-01"
+
+	tests := []struct {
+		name   string
+		params model.AuditLogQueryParams
+		want   []uint
+	}{
+		{"IPAddress", model.AuditLogQueryParams{StartDate: &start, IPAddress: str("10.0.0.2"), SortBy: "id", Order: "asc"}, []uint{1, 3, 5, 7, 9}},
+		{"SearchDetails", model.AuditLogQueryParams{StartDate: &start, Search: str("WEB-0")}, []uint{9, 8, 6, 5, 4, 3, 2, 1}},
+		{"SearchEscapesWildcards", model.AuditLogQueryParams{StartDate: &start, Search: str("0% done_")}, []uint{7}},
+		{"SearchUnderscoreIsLiteral", model.AuditLogQueryParams{StartDate: &start, Search: str("web_0")}, nil},
+		{"SortByUsername", model.AuditLogQueryParams{StartDate: &start, SortBy: "username", Order: "asc"}, []uint{2, 4, 6, 8, 10, 1, 3, 5, 7, 9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.Page, tt.params.PageSize = 1, 20
+			logs, total, err := repo.FindLogs(ctx, tt.params)
+			require.NoError(t, err)
+			assert.EqualValues(t, len(tt.want), total)
+			if tt.want == nil {
+				assert.Empty(t, logs)
+				return
+			}
+			assert.Equal(t, tt.want, resourceIDs(logs))
+		})
+	}
+}
+
+func TestAuditLogQuery_CursorPages(t *testing.T) {
+	repo := setupAuditQueryTestRepo(t)
+	ctx := context.Background()
+	start := "2024-06-01"
+
+	for _, order := range []string{"desc", "asc"} {
+		t.Run(order, func(t *testing.T) {
+			params := model.AuditLogQueryParams{StartDate: &start, SortBy: "createdAt", Order: order, PageSize: 3}
+			var seen []uint
+			cursor, pages := "", 0
+			for {
+				logs, next, err := repo.FindLogPage(ctx, params, cursor)
+				require.NoError(t, err)
+				seen = append(seen, resourceIDs(logs)...)
+				pages++
+				if next == "" {
+					break
+				}
+				cursor = next
+			}
+			assert.Equal(t, 4, pages)
+
+			// 与一次性按偏移量查询的顺序相同，时间相同的日志按ID排序
+			params.Page, params.PageSize = 1, 20
+			all, _, err := repo.FindLogs(ctx, params)
+			require.NoError(t, err)
+			assert.Equal(t, resourceIDs(all), seen)
+			assert.Len(t, seen, 10)
+		})
+	}
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		params := model.AuditLogQueryParams{SortBy: "createdAt", Order: "desc", PageSize: 3}
+		_, _, err := repo.FindLogPage(ctx, params, "not-a-cursor")
+		assert.ErrorIs(t, err, ErrInvalidAuditCursor)
+
+		_, next, err := repo.FindLogPage(ctx, params, "")
+		require.NoError(t, err)
+		params.Order = "asc"
+		_, _, err = repo.FindLogPage(ctx, params, next)
+		assert.ErrorIs(t, err, ErrInvalidAuditCursor)
+	})
+}
+
+func TestAuditLogQuery_EachLogBatch(t *testing.T) {
+	repo := setupAuditQueryTestRepo(t)
+	start := "2024-06-01"
+	ip := "10.0.0.1"
+
+	var batches [][]uint
+	err := repo.EachLogBatch(context.Background(), model.AuditLogQueryParams{StartDate: &start, IPAddress: &ip, SortBy: "id", Order: "asc"}, 2, func(logs []model.AuditLog) error {
+		batches = append(batches, resourceIDs(logs))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]uint{{2, 4}, {6, 8}, {10}}, batches)
+}
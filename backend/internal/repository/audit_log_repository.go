@@ -14,30 +14,78 @@ type AuditLogRepository interface {
 	// CreateLog 创建一条新的审计日志记录
 	CreateLog(ctx context.Context, log *model.AuditLog) error
 	
+	// CreateLogs 在一个事务中批量创建审计日志记录，按顺序接入哈希链
+	CreateLogs(ctx context.Context, logs []*model.AuditLog) error
+	
 	// FindLogs 根据查询参数查找审计日志
 	FindLogs(ctx context.Context, params model.AuditLogQueryParams) ([]model.AuditLog, int64, error)
 	
 	// FindLogByID 根据ID查找一条审计日志
 	FindLogByID(ctx context.Context, id uint) (*model.AuditLog, error)
+	
+	// FindLogPage 按游标分页查找审计日志，返回下一页的游标（没有更多记录时为空）；cursor 无效时返回 ErrInvalidAuditCursor
+	FindLogPage(ctx context.Context, params model.AuditLogQueryParams, cursor string) ([]model.AuditLog, string, error)
+	
+	// EachLogBatch 按查询参数的筛选和排序，每次读取batchSize条日志交给fn，用于导出等不宜一次载入内存的场景
+	EachLogBatch(ctx context.Context, params model.AuditLogQueryParams, batchSize int, fn func([]model.AuditLog) error) error
+	
+	// FindLogsAfter 按查询参数的筛选和排序，返回排在ID为lastID的日志之后的至多limit条日志（lastID为0时从头开始）
+	FindLogsAfter(ctx context.Context, params model.AuditLogQueryParams, lastID uint, limit int) ([]model.AuditLog, error)
+	
+	// FindChanges 根据查询参数查找UPDATE日志中的字段变更，按时间倒序
+	FindChanges(ctx context.Context, params model.AuditLogChangeQueryParams) ([]model.AuditLogChange, int64, error)
+	
+	// VerifyChain 按ID顺序校验哈希链，报告第一处断裂
+	VerifyChain(ctx context.Context) (*model.AuditChainVerification, error)
+	
+	// FindArchivable 返回最后一个归档之后、早于cutoff的连续日志（按ID顺序，最多limit条）
+	FindArchivable(ctx context.Context, cutoff time.Time, limit int) ([]model.AuditLog, error)
+	
+	// PurgeArchived 在一个事务中清除归档覆盖的日志并保存归档记录，返回清除的日志数
+	PurgeArchived(ctx context.Context, archive *model.AuditLogArchive) (int64, error)
+	
+	// ListArchives 按日志ID顺序列出所有归档
+	ListArchives(ctx context.Context) ([]model.AuditLogArchive, error)
+	
+	// FindArchiveByID 根据ID查找一个归档
+	FindArchiveByID(ctx context.Context, id uint) (*model.AuditLogArchive, error)
+	
+	// RestoreArchive 校验归档日志的哈希链后将其原样写回audit_logs，供调查使用
+	RestoreArchive(ctx context.Context, archive *model.AuditLogArchive, logs []model.AuditLog) error
+	
+	// UnloadArchive 再次清除重新导入的归档日志，返回清除的日志数
+	UnloadArchive(ctx context.Context, archive *model.AuditLogArchive) (int64, error)
 }
 
-// AuditLogRepositoryImpl 实现了AuditLogRepository接口
+// AuditLogRepositoryImpl 实现了AuditLogRepository接口。
+// 审计日志只能追加：每条日志保存前一条日志的哈希形成哈希链，修改和删除在数据库会话层面被拒绝。
 type AuditLogRepositoryImpl struct {
-	db     *gorm.DB
-	logger *zap.Logger
+	db      *gorm.DB
+	hashKey AuditHashKey
+	logger  *zap.Logger
 }
 
-// NewAuditLogRepository 创建一个新的AuditLogRepository实例
-func NewAuditLogRepository(db *gorm.DB, logger *zap.Logger) AuditLogRepository {
+// NewAuditLogRepository 创建一个新的AuditLogRepository实例；hashKey 为空时哈希链使用不带密钥的SHA-256
+func NewAuditLogRepository(db *gorm.DB, hashKey AuditHashKey, logger *zap.Logger) AuditLogRepository {
+	guardAuditLogs(db)
 	return &AuditLogRepositoryImpl{
-		db:     db,
-		logger: logger,
+		db:      db,
+		hashKey: hashKey,
+		logger:  logger,
 	}
 }
 
 // CreateLog 实现了AuditLogRepository接口的CreateLog方法
 func (r *AuditLogRepositoryImpl) CreateLog(ctx context.Context, log *model.AuditLog) error {
-	return r.db.WithContext(ctx).Create(log).Error
+	return r.appendChained(ctx, []*model.AuditLog{log})
+}
+
+// CreateLogs 实现了AuditLogRepository接口的CreateLogs方法
+func (r *AuditLogRepositoryImpl) CreateLogs(ctx context.Context, logs []*model.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.appendChained(ctx, logs)
 }
 
 // FindLogs 实现了AuditLogRepository接口的FindLogs方法
@@ -45,45 +93,7 @@ func (r *AuditLogRepositoryImpl) FindLogs(ctx context.Context, params model.Audi
 	var logs []model.AuditLog
 	var count int64
 	
-	query := r.db.WithContext(ctx).Model(&model.AuditLog{})
-	
-	// 应用筛选条件
-	if params.UserID != nil {
-		query = query.Where("user_id = ?", *params.UserID)
-	}
-	
-	if params.Action != nil && *params.Action != "" {
-		query = query.Where("action = ?", *params.Action)
-	}
-	
-	if params.Resource != nil && *params.Resource != "" {
-		query = query.Where("resource = ?", *params.Resource)
-	}
-	
-	if params.ResourceID != nil {
-		query = query.Where("resource_id = ?", *params.ResourceID)
-	}
-	
-	// 日期范围筛选
-	if params.StartDate != nil && *params.StartDate != "" {
-		startDate, err := time.Parse("2006-01-02", *params.StartDate)
-		if err == nil {
-			query = query.Where("created_at >= ?", startDate)
-		} else {
-			r.logger.Warn("Invalid start date format", zap.String("startDate", *params.StartDate), zap.Error(err))
-		}
-	}
-	
-	if params.EndDate != nil && *params.EndDate != "" {
-		endDate, err := time.Parse("2006-01-02", *params.EndDate)
-		if err == nil {
-			// 将结束日期设为当天的最后一刻
-			endDate = endDate.Add(24*time.Hour - time.Second)
-			query = query.Where("created_at <= ?", endDate)
-		} else {
-			r.logger.Warn("Invalid end date format", zap.String("endDate", *params.EndDate), zap.Error(err))
-		}
-	}
+	query := r.applyAuditLogFilters(r.db.WithContext(ctx).Model(&model.AuditLog{}), params)
 	
 	// 计算总数
 	if err := query.Count(&count).Error; err != nil {
@@ -97,7 +107,7 @@ func (r *AuditLogRepositoryImpl) FindLogs(ctx context.Context, params model.Audi
 	}
 	
 	// 获取记录
-	if err := query.Order("created_at DESC").
+	if err := orderAuditLogs(query, params).
 		Offset(offset).
 		Limit(params.PageSize).
 		Find(&logs).Error; err != nil {
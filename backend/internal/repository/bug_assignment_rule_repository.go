@@ -0,0 +1,106 @@
+//go:generate mockgen -destination=mocks/mock_bug_assignment_rule_repository.go -package=mocks EffiPlat/backend/internal/repository BugAssignmentRuleRepository
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"EffiPlat/backend/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// BugAssignmentRuleRepository defines the interface for bug assignment rule operations.
+type BugAssignmentRuleRepository interface {
+	Create(ctx context.Context, rule *model.BugAssignmentRule) error
+	GetByID(ctx context.Context, id uint) (*model.BugAssignmentRule, error)
+	// List returns the rules in evaluation order: ascending priority, then ID.
+	List(ctx context.Context, enabledOnly bool) ([]*model.BugAssignmentRule, error)
+	Update(ctx context.Context, rule *model.BugAssignmentRule) error
+	Delete(ctx context.Context, id uint) error
+	// AdvanceCursor records the user a round-robin rule assigned last.
+	AdvanceCursor(ctx context.Context, id uint, userID uint) error
+}
+
+// bugAssignmentRuleRepositoryImpl implements BugAssignmentRuleRepository.
+type bugAssignmentRuleRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewBugAssignmentRuleRepository creates a new BugAssignmentRuleRepository.
+func NewBugAssignmentRuleRepository(db *gorm.DB, logger *zap.Logger) BugAssignmentRuleRepository {
+	return &bugAssignmentRuleRepositoryImpl{db: db, logger: logger}
+}
+
+// Create inserts a new assignment rule.
+func (r *bugAssignmentRuleRepositoryImpl) Create(ctx context.Context, rule *model.BugAssignmentRule) error {
+	r.logger.Debug("Creating bug assignment rule", zap.String("name", rule.Name))
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		r.logger.Error("Failed to create bug assignment rule", zap.Error(err))
+		return fmt.Errorf("repository.Create: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an assignment rule. It returns gorm.ErrRecordNotFound if the rule does not exist.
+func (r *bugAssignmentRuleRepositoryImpl) GetByID(ctx context.Context, id uint) (*model.BugAssignmentRule, error) {
+	var rule model.BugAssignmentRule
+	if err := r.db.WithContext(ctx).First(&rule, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		r.logger.Error("Failed to get bug assignment rule", zap.Uint("id", id), zap.Error(err))
+		return nil, fmt.Errorf("repository.GetByID: %w", err)
+	}
+	return &rule, nil
+}
+
+// List retrieves the assignment rules in evaluation order.
+func (r *bugAssignmentRuleRepositoryImpl) List(ctx context.Context, enabledOnly bool) ([]*model.BugAssignmentRule, error) {
+	var rules []*model.BugAssignmentRule
+	tx := r.db.WithContext(ctx).Model(&model.BugAssignmentRule{})
+	if enabledOnly {
+		tx = tx.Where("enabled = ?", true)
+	}
+	if err := tx.Order("priority ASC, id ASC").Find(&rules).Error; err != nil {
+		r.logger.Error("Failed to list bug assignment rules", zap.Error(err))
+		return nil, fmt.Errorf("repository.List: %w", err)
+	}
+	return rules, nil
+}
+
+// Update saves every field of an existing assignment rule.
+func (r *bugAssignmentRuleRepositoryImpl) Update(ctx context.Context, rule *model.BugAssignmentRule) error {
+	r.logger.Debug("Updating bug assignment rule", zap.Uint("id", rule.ID))
+	if err := r.db.WithContext(ctx).Save(rule).Error; err != nil {
+		r.logger.Error("Failed to update bug assignment rule", zap.Uint("id", rule.ID), zap.Error(err))
+		return fmt.Errorf("repository.Update: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an assignment rule. It returns gorm.ErrRecordNotFound if the rule does not exist.
+func (r *bugAssignmentRuleRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.BugAssignmentRule{}, id)
+	if result.Error != nil {
+		r.logger.Error("Failed to delete bug assignment rule", zap.Uint("id", id), zap.Error(result.Error))
+		return fmt.Errorf("repository.Delete: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// AdvanceCursor moves a round-robin rule's cursor to userID.
+func (r *bugAssignmentRuleRepositoryImpl) AdvanceCursor(ctx context.Context, id uint, userID uint) error {
+	if err := r.db.WithContext(ctx).Model(&model.BugAssignmentRule{}).Where("id = ?", id).
+		Update("last_assigned_user_id", userID).Error; err != nil {
+		r.logger.Error("Failed to advance bug assignment rule cursor", zap.Uint("id", id), zap.Error(err))
+		return fmt.Errorf("repository.AdvanceCursor: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"EffiPlat/backend/internal/model"
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// withCommentUsers preloads the author and mentioned users of comments.
+func withCommentUsers(db *gorm.DB) *gorm.DB {
+	return db.Preload("Author").Preload("Mentions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("user_id ASC")
+	}).Preload("Mentions.User")
+}
+
+// createMentions links comment to each user in mentionIDs.
+func createMentions(tx *gorm.DB, commentID uint, mentionIDs []uint) error {
+	if len(mentionIDs) == 0 {
+		return nil
+	}
+	mentions := make([]model.BugCommentMention, 0, len(mentionIDs))
+	for _, userID := range mentionIDs {
+		mentions = append(mentions, model.BugCommentMention{CommentID: commentID, UserID: userID})
+	}
+	return tx.Omit("User").Create(&mentions).Error
+}
+
+// CreateComment stores a comment together with its mentions.
+func (r *bugRepositoryImpl) CreateComment(ctx context.Context, comment *model.BugComment, mentionIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Author", "Mentions").Create(comment).Error; err != nil {
+			r.logger.Error("GORM: Failed to create bug comment", zap.Uint("bugID", comment.BugID), zap.Error(err))
+			return err
+		}
+		return createMentions(tx, comment.ID, mentionIDs)
+	})
+}
+
+// GetComment retrieves a live comment of a bug, with its author and mentioned users.
+func (r *bugRepositoryImpl) GetComment(ctx context.Context, bugID uint, commentID uint) (*model.BugComment, error) {
+	var comment model.BugComment
+	if err := withCommentUsers(r.db.WithContext(ctx)).Where("bug_id = ?", bugID).First(&comment, commentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBugCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// UpdateComment saves the comment's body and edit counters, stores the replaced body as a
+// revision and replaces its mentions, in one transaction.
+func (r *bugRepositoryImpl) UpdateComment(ctx context.Context, comment *model.BugComment, revision *model.BugCommentRevision, mentionIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.BugComment{}).Where("id = ?", comment.ID).Updates(map[string]interface{}{
+			"body":       comment.Body,
+			"edit_count": comment.EditCount,
+			"edited_at":  comment.EditedAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrBugCommentNotFound
+		}
+
+		revision.CommentID = comment.ID
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&model.BugCommentMention{}).Error; err != nil {
+			return err
+		}
+		return createMentions(tx, comment.ID, mentionIDs)
+	})
+}
+
+// DeleteComment soft-deletes a comment; its revisions and mentions are kept.
+func (r *bugRepositoryImpl) DeleteComment(ctx context.Context, commentID uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.BugComment{}, commentID)
+	if result.Error != nil {
+		r.logger.Error("GORM: Failed to delete bug comment", zap.Uint("commentID", commentID), zap.Error(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBugCommentNotFound
+	}
+	return nil
+}
+
+// ListComments returns the comments of a bug, oldest first. Soft-deleted comments are
+// included only when includeDeleted is set.
+func (r *bugRepositoryImpl) ListComments(ctx context.Context, bugID uint, includeDeleted bool) ([]*model.BugComment, error) {
+	db := withCommentUsers(r.db.WithContext(ctx))
+	if includeDeleted {
+		db = db.Unscoped()
+	}
+	var comments []*model.BugComment
+	if err := db.Where("bug_id = ?", bugID).Order("created_at ASC, id ASC").Find(&comments).Error; err != nil {
+		r.logger.Error("GORM: Failed to list bug comments", zap.Uint("bugID", bugID), zap.Error(err))
+		return nil, err
+	}
+	return comments, nil
+}
+
+// ListCommentRevisions returns the previous bodies of a comment, oldest first.
+func (r *bugRepositoryImpl) ListCommentRevisions(ctx context.Context, commentID uint) ([]*model.BugCommentRevision, error) {
+	var revisions []*model.BugCommentRevision
+	if err := r.db.WithContext(ctx).Where("comment_id = ?", commentID).Order("created_at ASC, id ASC").Find(&revisions).Error; err != nil {
+		r.logger.Error("GORM: Failed to list bug comment revisions", zap.Uint("commentID", commentID), zap.Error(err))
+		return nil, err
+	}
+	return revisions, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"EffiPlat/backend/internal/model"
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// CreateRelation stores a relation. When transition is not nil, bug is saved and the
+// transition recorded in the same transaction, as when a duplicate is closed.
+func (r *bugRepositoryImpl) CreateRelation(ctx context.Context, relation *model.BugRelation, bug *model.Bug, transition *model.BugStatusTransition) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("SourceBug", "TargetBug").Create(relation).Error; err != nil {
+			r.logger.Error("GORM: Failed to create bug relation", zap.Uint("sourceBugID", relation.SourceBugID), zap.Error(err))
+			return err
+		}
+		if transition == nil {
+			return nil
+		}
+		result := saveBug(tx, bug)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrBugNotFound
+		}
+		transition.BugID = bug.ID
+		return tx.Create(transition).Error
+	})
+}
+
+// ListRelations returns the relations a bug is the source or target of, with both bugs
+// preloaded. Relations to deleted bugs are left out.
+func (r *bugRepositoryImpl) ListRelations(ctx context.Context, bugID uint) ([]*model.BugRelation, error) {
+	var relations []*model.BugRelation
+	err := r.db.WithContext(ctx).Preload("SourceBug").Preload("TargetBug").
+		Where("source_bug_id = ? OR target_bug_id = ?", bugID, bugID).
+		Order("created_at ASC, id ASC").Find(&relations).Error
+	if err != nil {
+		r.logger.Error("GORM: Failed to list bug relations", zap.Uint("bugID", bugID), zap.Error(err))
+		return nil, err
+	}
+
+	live := relations[:0]
+	for _, relation := range relations {
+		if relation.SourceBug != nil && relation.TargetBug != nil {
+			live = append(live, relation)
+		}
+	}
+	return live, nil
+}
+
+// ListRelationsFrom returns the relations of a type that start at any of sourceIDs.
+func (r *bugRepositoryImpl) ListRelationsFrom(ctx context.Context, relationType model.BugRelationType, sourceIDs []uint) ([]*model.BugRelation, error) {
+	var relations []*model.BugRelation
+	if len(sourceIDs) == 0 {
+		return relations, nil
+	}
+	if err := r.db.WithContext(ctx).Where("type = ? AND source_bug_id IN ?", relationType, sourceIDs).Order("id ASC").Find(&relations).Error; err != nil {
+		r.logger.Error("GORM: Failed to list bug relations by source", zap.String("type", string(relationType)), zap.Error(err))
+		return nil, err
+	}
+	return relations, nil
+}
+
+// DeleteRelation removes a relation the bug is the source or target of.
+func (r *bugRepositoryImpl) DeleteRelation(ctx context.Context, bugID uint, relationID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND (source_bug_id = ? OR target_bug_id = ?)", relationID, bugID, bugID).
+		Delete(&model.BugRelation{})
+	if result.Error != nil {
+		r.logger.Error("GORM: Failed to delete bug relation", zap.Uint("relationID", relationID), zap.Error(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBugRelationNotFound
+	}
+	return nil
+}
+
+// FindSimilarCandidates returns up to limit bugs whose title contains any of the terms, newest
+// first. Bugs resolved as duplicates are skipped: the bug they duplicate is the better match.
+func (r *bugRepositoryImpl) FindSimilarCandidates(ctx context.Context, terms []string, limit int) ([]*model.Bug, error) {
+	var bugs []*model.Bug
+	if len(terms) == 0 {
+		return bugs, nil
+	}
+
+	conditions := make([]string, len(terms))
+	args := make([]interface{}, len(terms))
+	for i, term := range terms {
+		conditions[i] = "LOWER(title) LIKE ?"
+		args[i] = "%" + strings.ToLower(term) + "%"
+	}
+	err := withLinks(r.db.WithContext(ctx)).
+		Where(strings.Join(conditions, " OR "), args...).
+		Where("resolution IS NULL OR resolution <> ?", model.BugResolutionDuplicate).
+		Order("created_at DESC, id DESC").Limit(limit).Find(&bugs).Error
+	if err != nil {
+		r.logger.Error("GORM: Failed to find similar bug candidates", zap.Error(err))
+		return nil, err
+	}
+	return bugs, nil
+}
@@ -3,8 +3,16 @@ package repository
 import (
 	"EffiPlat/backend/internal/model"
 	"context"
+	"time"
 )
 
+// BugUpdate is one bug of UpdateManyWithHistory: the bug to save and the history to record.
+type BugUpdate struct {
+	Bug        *model.Bug
+	Transition *model.BugStatusTransition // May be nil
+	Changes    []*model.BugFieldChange
+}
+
 // BugRepository defines the interface for bug data operations.
 type BugRepository interface {
 	Create(ctx context.Context, bug *model.Bug) error
@@ -12,9 +20,46 @@ type BugRepository interface {
 	Update(ctx context.Context, bug *model.Bug) error
 	Delete(ctx context.Context, id uint) error
 	List(ctx context.Context, params *model.BugListParams) ([]*model.Bug, int64, error) // Returns bugs and total count
-	
+
 	// Additional query methods
 	CountBugsByAssigneeID(ctx context.Context, assigneeID uint) (int64, error)
 	CountBugsByEnvironmentID(ctx context.Context, environmentID uint) (int64, error)
 	GetBugsByStatus(ctx context.Context, status model.BugStatusType, params *model.BugListParams) ([]*model.Bug, int64, error)
+
+	// Status workflow
+	ListTransitions(ctx context.Context, bugID uint) ([]*model.BugStatusTransition, error) // Oldest first
+
+	// History: saves the bug and records the transition (may be nil) and field changes atomically
+	UpdateWithHistory(ctx context.Context, bug *model.Bug, transition *model.BugStatusTransition, changes []*model.BugFieldChange) error
+	UpdateManyWithHistory(ctx context.Context, updates []BugUpdate) error              // Same for many bugs in one transaction
+	ListFieldChanges(ctx context.Context, bugID uint) ([]*model.BugFieldChange, error) // Oldest first
+
+	// SLA
+	ListSLABreachCandidates(ctx context.Context, now time.Time, limit int) ([]*model.Bug, error) // Running clocks past due that are not flagged yet
+	MarkFirstResponse(ctx context.Context, bugID uint, at time.Time) error                       // Only sets the time if none is recorded
+
+	// Comments
+	CreateComment(ctx context.Context, comment *model.BugComment, mentionIDs []uint) error
+	GetComment(ctx context.Context, bugID uint, commentID uint) (*model.BugComment, error)                                     // Author and mentions preloaded
+	UpdateComment(ctx context.Context, comment *model.BugComment, revision *model.BugCommentRevision, mentionIDs []uint) error // Saves the body, stores the old one and replaces the mentions
+	DeleteComment(ctx context.Context, commentID uint) error                                                                   // Soft delete
+	ListComments(ctx context.Context, bugID uint, includeDeleted bool) ([]*model.BugComment, error)                            // Oldest first
+	ListCommentRevisions(ctx context.Context, commentID uint) ([]*model.BugCommentRevision, error)                             // Oldest first
+
+	// Relations
+	CreateRelation(ctx context.Context, relation *model.BugRelation, bug *model.Bug, transition *model.BugStatusTransition) error // Also saves bug with the transition when it is not nil, atomically
+	ListRelations(ctx context.Context, bugID uint) ([]*model.BugRelation, error)                                                  // Both directions, related bugs preloaded, oldest first
+	ListRelationsFrom(ctx context.Context, relationType model.BugRelationType, sourceIDs []uint) ([]*model.BugRelation, error)
+	DeleteRelation(ctx context.Context, bugID uint, relationID uint) error
+	FindSimilarCandidates(ctx context.Context, terms []string, limit int) ([]*model.Bug, error) // Bugs whose title contains any term, except closed duplicates, newest first
+
+	// Statistics, computed in SQL
+	CountByGroup(ctx context.Context, groupBy model.BugStatsGroupBy, from, to time.Time) ([]model.BugStatsGroup, error)                                         // Bugs created in [from, to), largest group first
+	CountOpenByAge(ctx context.Context, resolvedStatuses []model.BugStatusType, now time.Time) ([]model.BugAgeBucket, error)                                    // Bugs not in a resolved status, by age at now
+	MeanTimeToResolve(ctx context.Context, from, to time.Time) (*float64, int64, error)                                                                         // Mean hours to resolve and count, over bugs resolved in [from, to)
+	CountTrend(ctx context.Context, interval model.BugStatsInterval, resolvedStatuses []model.BugStatusType, from, to time.Time) ([]model.BugTrendPoint, error) // Periods with no bugs are left out
+
+	// Environment snapshots
+	CreateWithSnapshot(ctx context.Context, bug *model.Bug, snapshot *model.BugEnvironmentSnapshot) error // Creates the bug and its snapshot atomically
+	GetSnapshot(ctx context.Context, bugID uint) (*model.BugEnvironmentSnapshot, error)
 }
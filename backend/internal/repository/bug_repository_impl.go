@@ -3,15 +3,24 @@ package repository
 import (
 	"EffiPlat/backend/internal/model" // Corrected import path
 	"context"
+	"database/sql"
 	"errors" // Using standard errors for now
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var ErrBugNotFound = errors.New("bug not found") // Custom error for repository
 
+var ErrBugSnapshotNotFound = errors.New("bug environment snapshot not found")
+
+var ErrBugCommentNotFound = errors.New("bug comment not found")
+
+var ErrBugRelationNotFound = errors.New("bug relation not found")
+
 // bugRepositoryImpl implements the BugRepository interface using GORM.
 type bugRepositoryImpl struct {
 	db     *gorm.DB
@@ -26,15 +35,43 @@ func NewBugRepository(db *gorm.DB, logger *zap.Logger) BugRepository {
 	}
 }
 
-// Create creates a new bug record in the database.
+// withLinks preloads the entities a bug is linked to.
+func withLinks(db *gorm.DB) *gorm.DB {
+	return db.Preload("Environment").Preload("Business").Preload("Service")
+}
+
+// Create creates a new bug record in the database. Linked entities are never written.
 func (r *bugRepositoryImpl) Create(ctx context.Context, bug *model.Bug) error {
-	return r.db.WithContext(ctx).Create(bug).Error
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(bug).Error
+}
+
+// CreateWithSnapshot creates a bug and its environment snapshot in one transaction.
+func (r *bugRepositoryImpl) CreateWithSnapshot(ctx context.Context, bug *model.Bug, snapshot *model.BugEnvironmentSnapshot) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(bug).Error; err != nil {
+			return err
+		}
+		snapshot.BugID = bug.ID
+		return tx.Create(snapshot).Error
+	})
 }
 
-// GetByID retrieves a bug by its ID.
+// GetSnapshot retrieves the environment snapshot taken when a bug was filed.
+func (r *bugRepositoryImpl) GetSnapshot(ctx context.Context, bugID uint) (*model.BugEnvironmentSnapshot, error) {
+	var snapshot model.BugEnvironmentSnapshot
+	if err := r.db.WithContext(ctx).Where("bug_id = ?", bugID).First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBugSnapshotNotFound
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetByID retrieves a bug by its ID, with its linked entities.
 func (r *bugRepositoryImpl) GetByID(ctx context.Context, id uint) (*model.Bug, error) {
 	var bug model.Bug
-	if err := r.db.WithContext(ctx).First(&bug, id).Error; err != nil {
+	if err := withLinks(r.db.WithContext(ctx)).First(&bug, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrBugNotFound // Use custom error from this package or a common one
 		}
@@ -56,8 +93,8 @@ func (r *bugRepositoryImpl) Update(ctx context.Context, bug *model.Bug) error {
 		return ErrBugNotFound
 	}
 
-	// Then proceed with update
-	result := r.db.WithContext(ctx).Model(&model.Bug{}).Where("id = ?", bug.ID).Updates(bug)
+	// Then proceed with update; all columns are written so that cleared links are persisted
+	result := saveBug(r.db.WithContext(ctx), bug)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -65,6 +102,78 @@ func (r *bugRepositoryImpl) Update(ctx context.Context, bug *model.Bug) error {
 	return nil
 }
 
+// saveBug writes every column of bug except the immutable ones, leaving linked entities alone.
+func saveBug(db *gorm.DB, bug *model.Bug) *gorm.DB {
+	return db.Model(&model.Bug{}).Where("id = ?", bug.ID).
+		Select("*").Omit("id", "created_at", "deleted_at", clause.Associations).Updates(bug)
+}
+
+// UpdateWithHistory saves all fields of bug (so a cleared resolution is persisted too) and
+// records the status transition, if any, and the field changes in the same transaction.
+func (r *bugRepositoryImpl) UpdateWithHistory(ctx context.Context, bug *model.Bug, transition *model.BugStatusTransition, changes []*model.BugFieldChange) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.saveWithHistory(tx, bug, transition, changes)
+	})
+}
+
+// UpdateManyWithHistory saves every bug with its history, as UpdateWithHistory does, in a single
+// transaction: if any bug cannot be saved, none is.
+func (r *bugRepositoryImpl) UpdateManyWithHistory(ctx context.Context, updates []BugUpdate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, update := range updates {
+			if err := r.saveWithHistory(tx, update.Bug, update.Transition, update.Changes); err != nil {
+				return fmt.Errorf("bug %d: %w", update.Bug.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *bugRepositoryImpl) saveWithHistory(tx *gorm.DB, bug *model.Bug, transition *model.BugStatusTransition, changes []*model.BugFieldChange) error {
+	result := saveBug(tx, bug)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		r.logger.Debug("Bug not found for update with history", zap.Uint("id", bug.ID))
+		return ErrBugNotFound
+	}
+
+	if transition != nil {
+		transition.BugID = bug.ID
+		if err := tx.Create(transition).Error; err != nil {
+			return err
+		}
+	}
+	for _, change := range changes {
+		change.BugID = bug.ID
+	}
+	if len(changes) > 0 {
+		return tx.Create(&changes).Error
+	}
+	return nil
+}
+
+// ListFieldChanges returns the field change history of a bug, oldest first.
+func (r *bugRepositoryImpl) ListFieldChanges(ctx context.Context, bugID uint) ([]*model.BugFieldChange, error) {
+	var changes []*model.BugFieldChange
+	if err := r.db.WithContext(ctx).Where("bug_id = ?", bugID).Order("created_at ASC, id ASC").Find(&changes).Error; err != nil {
+		r.logger.Error("GORM: Failed to list bug field changes", zap.Uint("bugID", bugID), zap.Error(err))
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ListTransitions returns the status history of a bug, oldest first.
+func (r *bugRepositoryImpl) ListTransitions(ctx context.Context, bugID uint) ([]*model.BugStatusTransition, error) {
+	var transitions []*model.BugStatusTransition
+	if err := r.db.WithContext(ctx).Where("bug_id = ?", bugID).Order("created_at ASC, id ASC").Find(&transitions).Error; err != nil {
+		r.logger.Error("GORM: Failed to list bug status transitions", zap.Uint("bugID", bugID), zap.Error(err))
+		return nil, err
+	}
+	return transitions, nil
+}
+
 // Delete removes a bug record from the database by its ID.
 func (r *bugRepositoryImpl) Delete(ctx context.Context, id uint) error {
 	// First check if the bug exists
@@ -87,6 +196,35 @@ func (r *bugRepositoryImpl) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
+// slaBreachedCondition matches the bugs model.Bug.EvaluateSLA reports as breached at @now.
+// Every term is guarded against NULLs so that the condition can be negated.
+const slaBreachedCondition = `response_breached_at IS NOT NULL OR resolve_breached_at IS NOT NULL` +
+	` OR (response_due_at IS NOT NULL AND first_responded_at IS NULL AND response_due_at < @now)` +
+	` OR (response_due_at IS NOT NULL AND first_responded_at IS NOT NULL AND first_responded_at > response_due_at)` +
+	` OR (resolve_due_at IS NOT NULL AND resolved_at IS NULL AND resolve_due_at < @now)` +
+	` OR (resolve_due_at IS NOT NULL AND resolved_at IS NOT NULL AND resolved_at > resolve_due_at)`
+
+// ListSLABreachCandidates returns bugs with a running SLA clock that is past due but not yet
+// flagged as breached, oldest due first.
+func (r *bugRepositoryImpl) ListSLABreachCandidates(ctx context.Context, now time.Time, limit int) ([]*model.Bug, error) {
+	var bugs []*model.Bug
+	err := r.db.WithContext(ctx).
+		Where("(response_due_at < ? AND first_responded_at IS NULL AND response_breached_at IS NULL) OR (resolve_due_at < ? AND resolved_at IS NULL AND resolve_breached_at IS NULL)", now, now).
+		Order("id ASC").Limit(limit).Find(&bugs).Error
+	if err != nil {
+		r.logger.Error("GORM: Failed to list SLA breach candidates", zap.Error(err))
+		return nil, err
+	}
+	return bugs, nil
+}
+
+// MarkFirstResponse records the first response to a bug unless one is already recorded.
+func (r *bugRepositoryImpl) MarkFirstResponse(ctx context.Context, bugID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.Bug{}).
+		Where("id = ? AND first_responded_at IS NULL", bugID).
+		UpdateColumn("first_responded_at", at).Error
+}
+
 // List retrieves a list of bugs based on the provided parameters, with pagination.
 func (r *bugRepositoryImpl) List(ctx context.Context, params *model.BugListParams) ([]*model.Bug, int64, error) {
 	r.logger.Debug("GORM: Listing bugs", zap.Any("params", params))
@@ -111,6 +249,32 @@ func (r *bugRepositoryImpl) List(ctx context.Context, params *model.BugListParam
 	if params.ReporterID != nil {
 		query = query.Where("reporter_id = ?", *params.ReporterID)
 	}
+	if params.EnvironmentID != nil {
+		query = query.Where("environment_id = ?", *params.EnvironmentID)
+	}
+	if params.BusinessID != nil {
+		query = query.Where("business_id = ?", *params.BusinessID)
+	}
+	if params.ServiceID != nil {
+		query = query.Where("service_id = ?", *params.ServiceID)
+	}
+	if params.ServiceVersion != "" {
+		query = query.Where("service_version = ?", params.ServiceVersion)
+	}
+	if params.Label != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM json_each(bugs.labels) WHERE json_each.value = ?)", params.Label)
+	}
+	if params.SLABreached != nil {
+		breached := fmt.Sprintf("(%s)", slaBreachedCondition)
+		if *params.SLABreached {
+			query = query.Where(breached, sql.Named("now", time.Now()))
+		} else {
+			query = query.Where("NOT "+breached, sql.Named("now", time.Now()))
+		}
+	}
+	if params.SLADueBefore != nil {
+		query = query.Where("((first_responded_at IS NULL AND response_due_at < ?) OR (resolved_at IS NULL AND resolve_due_at < ?))", *params.SLADueBefore, *params.SLADueBefore)
+	}
 
 	// Get total count before pagination
 	if err := query.Count(&totalCount).Error; err != nil {
@@ -120,7 +284,7 @@ func (r *bugRepositoryImpl) List(ctx context.Context, params *model.BugListParam
 
 	// Apply pagination
 	offset := (params.Page - 1) * params.PageSize
-	if err := query.Offset(offset).Limit(params.PageSize).Order("created_at DESC").Find(&bugs).Error; err != nil {
+	if err := withLinks(query).Offset(offset).Limit(params.PageSize).Order("created_at DESC").Find(&bugs).Error; err != nil {
 		r.logger.Error("GORM: Failed to list bugs", zap.Error(err))
 		return nil, 0, err
 	}
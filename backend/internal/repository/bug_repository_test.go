@@ -11,6 +11,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"gorm.io/datatypes"
 	"gorm.io/driver/postgres" // Assuming PostgreSQL, adjust if different
 	"gorm.io/gorm"
 )
@@ -50,15 +51,16 @@ func TestBugRepositoryImpl_Create(t *testing.T) {
 		Description: "A bug for testing",
 		Status:      model.BugStatusOpen,
 		Priority:    model.BugPriorityMedium,
+		Labels:      datatypes.JSONSlice[string]{"checkout"},
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 
 	mock.ExpectBegin()
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`INSERT INTO "bugs" ("title","description","status","priority","reporter_id","assignee_id","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`)).
-		WithArgs(bugToCreate.Title, bugToCreate.Description, bugToCreate.Status, bugToCreate.Priority, bugToCreate.ReporterID, bugToCreate.AssigneeID, bugToCreate.CreatedAt, bugToCreate.UpdatedAt, nil).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		`INSERT INTO "bugs" ("title","description","status","priority","resolution","reporter_id","assignee_id","assignment_rule_id","environment_id","business_id","service_id","service_version","response_due_at","resolve_due_at","first_responded_at","resolved_at","response_breached_at","resolve_breached_at","escalated_at","escalated_to_id","created_at","updated_at","deleted_at","labels") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24) RETURNING "labels","id"`)).
+		WithArgs(bugToCreate.Title, bugToCreate.Description, bugToCreate.Status, bugToCreate.Priority, bugToCreate.Resolution, bugToCreate.ReporterID, bugToCreate.AssigneeID, bugToCreate.AssignmentRuleID, bugToCreate.EnvironmentID, bugToCreate.BusinessID, bugToCreate.ServiceID, bugToCreate.ServiceVersion, bugToCreate.ResponseDueAt, bugToCreate.ResolveDueAt, bugToCreate.FirstRespondedAt, bugToCreate.ResolvedAt, bugToCreate.ResponseBreachedAt, bugToCreate.ResolveBreachedAt, bugToCreate.EscalatedAt, bugToCreate.EscalatedToID, bugToCreate.CreatedAt, bugToCreate.UpdatedAt, nil, `["checkout"]`).
+		WillReturnRows(sqlmock.NewRows([]string{"labels", "id"}).AddRow(`["checkout"]`, 1))
 	mock.ExpectCommit()
 
 	err := repo.Create(ctx, bugToCreate)
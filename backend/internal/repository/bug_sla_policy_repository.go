@@ -0,0 +1,89 @@
+//go:generate mockgen -destination=mocks/mock_bug_sla_policy_repository.go -package=mocks EffiPlat/backend/internal/repository BugSLAPolicyRepository
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"EffiPlat/backend/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BugSLAPolicyRepository defines the interface for bug SLA policy operations.
+type BugSLAPolicyRepository interface {
+	List(ctx context.Context) ([]*model.BugSLAPolicy, error)
+	// GetByPriority returns gorm.ErrRecordNotFound if the priority has no policy.
+	GetByPriority(ctx context.Context, priority model.BugPriorityType) (*model.BugSLAPolicy, error)
+	// Upsert creates the policy of its priority or replaces the existing one.
+	Upsert(ctx context.Context, policy *model.BugSLAPolicy) error
+	DeleteByPriority(ctx context.Context, priority model.BugPriorityType) error
+}
+
+// bugSLAPolicyRepositoryImpl implements BugSLAPolicyRepository.
+type bugSLAPolicyRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewBugSLAPolicyRepository creates a new BugSLAPolicyRepository.
+func NewBugSLAPolicyRepository(db *gorm.DB, logger *zap.Logger) BugSLAPolicyRepository {
+	return &bugSLAPolicyRepositoryImpl{db: db, logger: logger}
+}
+
+// List returns every SLA policy ordered by ID.
+func (r *bugSLAPolicyRepositoryImpl) List(ctx context.Context) ([]*model.BugSLAPolicy, error) {
+	var policies []*model.BugSLAPolicy
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&policies).Error; err != nil {
+		r.logger.Error("Failed to list bug SLA policies", zap.Error(err))
+		return nil, fmt.Errorf("repository.List: %w", err)
+	}
+	return policies, nil
+}
+
+// GetByPriority retrieves the SLA policy of a priority.
+func (r *bugSLAPolicyRepositoryImpl) GetByPriority(ctx context.Context, priority model.BugPriorityType) (*model.BugSLAPolicy, error) {
+	var policy model.BugSLAPolicy
+	if err := r.db.WithContext(ctx).Where("priority = ?", priority).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		r.logger.Error("Failed to get bug SLA policy", zap.String("priority", string(priority)), zap.Error(err))
+		return nil, fmt.Errorf("repository.GetByPriority: %w", err)
+	}
+	return &policy, nil
+}
+
+// Upsert creates or replaces the SLA policy of policy.Priority and reloads it.
+func (r *bugSLAPolicyRepositoryImpl) Upsert(ctx context.Context, policy *model.BugSLAPolicy) error {
+	r.logger.Debug("Upserting bug SLA policy", zap.String("priority", string(policy.Priority)))
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "priority"}},
+		DoUpdates: clause.AssignmentColumns([]string{"first_response_minutes", "resolution_minutes", "enabled", "updated_at"}),
+	}).Create(policy).Error
+	if err != nil {
+		r.logger.Error("Failed to upsert bug SLA policy", zap.String("priority", string(policy.Priority)), zap.Error(err))
+		return fmt.Errorf("repository.Upsert: %w", err)
+	}
+	// The conflict path leaves ID and CreatedAt unset
+	if err := r.db.WithContext(ctx).Where("priority = ?", policy.Priority).First(policy).Error; err != nil {
+		return fmt.Errorf("repository.Upsert: %w", err)
+	}
+	return nil
+}
+
+// DeleteByPriority removes the SLA policy of a priority. It returns gorm.ErrRecordNotFound if there is none.
+func (r *bugSLAPolicyRepositoryImpl) DeleteByPriority(ctx context.Context, priority model.BugPriorityType) error {
+	result := r.db.WithContext(ctx).Where("priority = ?", priority).Delete(&model.BugSLAPolicy{})
+	if result.Error != nil {
+		r.logger.Error("Failed to delete bug SLA policy", zap.String("priority", string(priority)), zap.Error(result.Error))
+		return fmt.Errorf("repository.DeleteByPriority: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"EffiPlat/backend/internal/model"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// bugStatsGrouping is how counts are grouped by one field: the column grouped on and, for
+// linked entities, the join and column that give each group a label.
+type bugStatsGrouping struct {
+	column string
+	join   string
+	label  string
+}
+
+var bugStatsGroupings = map[model.BugStatsGroupBy]bugStatsGrouping{
+	model.BugStatsByStatus:      {column: "b.status"},
+	model.BugStatsByPriority:    {column: "b.priority"},
+	model.BugStatsByAssignee:    {column: "b.assignee_id", join: "LEFT JOIN users AS l ON l.id = b.assignee_id", label: "l.name"},
+	model.BugStatsByEnvironment: {column: "b.environment_id", join: "LEFT JOIN environments AS l ON l.id = b.environment_id", label: "l.name"},
+	model.BugStatsByService:     {column: "b.service_id", join: "LEFT JOIN services AS l ON l.id = b.service_id", label: "l.name"},
+}
+
+// bugStatsNoGroup is the key of the group of bugs not linked to any entity.
+const bugStatsNoGroup = "none"
+
+// bugStatsPeriod returns the SQL expression for the first day (UTC) of the period column falls in.
+func bugStatsPeriod(interval model.BugStatsInterval, column string) string {
+	if interval == model.BugStatsWeekly {
+		return fmt.Sprintf("date(%s, 'weekday 0', '-6 days')", column)
+	}
+	return fmt.Sprintf("date(%s)", column)
+}
+
+// CountByGroup counts the bugs created in [from, to) by the value of one field.
+func (r *bugRepositoryImpl) CountByGroup(ctx context.Context, groupBy model.BugStatsGroupBy, from, to time.Time) ([]model.BugStatsGroup, error) {
+	grouping, ok := bugStatsGroupings[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown bug stats grouping %q", groupBy)
+	}
+	label := "''"
+	if grouping.label != "" {
+		label = grouping.label
+	}
+
+	var rows []struct {
+		GroupKey *string
+		Label    *string
+		BugCount int64
+	}
+	query := r.db.WithContext(ctx).Table("bugs AS b").
+		Select(fmt.Sprintf("CAST(%s AS TEXT) AS group_key, %s AS label, COUNT(*) AS bug_count", grouping.column, label))
+	if grouping.join != "" {
+		query = query.Joins(grouping.join)
+	}
+	err := query.Where("b.deleted_at IS NULL AND b.created_at >= ? AND b.created_at < ?", from, to).
+		Group(grouping.column).Order("bug_count DESC, group_key ASC").Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("GORM: Failed to count bugs by group", zap.String("groupBy", string(groupBy)), zap.Error(err))
+		return nil, err
+	}
+
+	groups := make([]model.BugStatsGroup, len(rows))
+	for i, row := range rows {
+		group := model.BugStatsGroup{Key: bugStatsNoGroup, Count: row.BugCount}
+		if row.GroupKey != nil {
+			group.Key = *row.GroupKey
+		}
+		if row.Label != nil {
+			group.Label = *row.Label
+		}
+		if grouping.label != "" && row.GroupKey != nil {
+			if id, err := strconv.ParseUint(*row.GroupKey, 10, 64); err == nil {
+				id := uint(id)
+				group.ID = &id
+			}
+		}
+		groups[i] = group
+	}
+	return groups, nil
+}
+
+// CountOpenByAge counts the bugs not in any of resolvedStatuses by how many days old they are at now.
+func (r *bugRepositoryImpl) CountOpenByAge(ctx context.Context, resolvedStatuses []model.BugStatusType, now time.Time) ([]model.BugAgeBucket, error) {
+	buckets := model.NewBugAgeBuckets()
+	cases := make([]string, 0, len(buckets))
+	for i, bucket := range buckets {
+		if bucket.MaxDays != nil {
+			cases = append(cases, fmt.Sprintf("WHEN age < %d THEN %d", *bucket.MaxDays, i))
+		}
+	}
+	bucketExpr := fmt.Sprintf("CASE %s ELSE %d END", strings.Join(cases, " "), len(buckets)-1)
+
+	var rows []struct {
+		Bucket   int
+		BugCount int64
+	}
+	ages := r.db.WithContext(ctx).Model(&model.Bug{}).Select("julianday(?) - julianday(created_at) AS age", now)
+	if len(resolvedStatuses) > 0 {
+		ages = ages.Where("status NOT IN ?", resolvedStatuses)
+	}
+	err := r.db.WithContext(ctx).Table("(?) AS a", ages).
+		Select(bucketExpr + " AS bucket, COUNT(*) AS bug_count").
+		Group("bucket").Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("GORM: Failed to count open bugs by age", zap.Error(err))
+		return nil, err
+	}
+	for _, row := range rows {
+		buckets[row.Bucket].Count = row.BugCount
+	}
+	return buckets, nil
+}
+
+// MeanTimeToResolve returns the mean number of hours from creation to resolution of the bugs
+// resolved in [from, to), and how many there were. The mean is nil when there were none.
+func (r *bugRepositoryImpl) MeanTimeToResolve(ctx context.Context, from, to time.Time) (*float64, int64, error) {
+	var row struct {
+		Hours    *float64
+		BugCount int64
+	}
+	err := r.db.WithContext(ctx).Model(&model.Bug{}).
+		Select("AVG((julianday(resolved_at) - julianday(created_at)) * 24) AS hours, COUNT(*) AS bug_count").
+		Where("resolved_at IS NOT NULL AND resolved_at >= ? AND resolved_at < ?", from, to).
+		Scan(&row).Error
+	if err != nil {
+		r.logger.Error("GORM: Failed to compute mean time to resolve", zap.Error(err))
+		return nil, 0, err
+	}
+	return row.Hours, row.BugCount, nil
+}
+
+// CountTrend counts, per period, the bugs created and the bugs moved from an unresolved into a
+// resolved status in [from, to).
+func (r *bugRepositoryImpl) CountTrend(ctx context.Context, interval model.BugStatsInterval, resolvedStatuses []model.BugStatusType, from, to time.Time) ([]model.BugTrendPoint, error) {
+	type periodCount struct {
+		Period   string
+		BugCount int64
+	}
+
+	var created []periodCount
+	err := r.db.WithContext(ctx).Model(&model.Bug{}).
+		Select(bugStatsPeriod(interval, "created_at")+" AS period, COUNT(*) AS bug_count").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("period").Scan(&created).Error
+	if err != nil {
+		r.logger.Error("GORM: Failed to count created bugs per period", zap.Error(err))
+		return nil, err
+	}
+
+	var closed []periodCount
+	if len(resolvedStatuses) > 0 {
+		err = r.db.WithContext(ctx).Table("bug_status_transitions AS t").
+			Select(bugStatsPeriod(interval, "t.created_at")+" AS period, COUNT(*) AS bug_count").
+			Joins("JOIN bugs AS b ON b.id = t.bug_id AND b.deleted_at IS NULL").
+			Where("t.to_status IN ? AND t.from_status NOT IN ?", resolvedStatuses, resolvedStatuses).
+			Where("t.created_at >= ? AND t.created_at < ?", from, to).
+			Group("period").Scan(&closed).Error
+		if err != nil {
+			r.logger.Error("GORM: Failed to count closed bugs per period", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	points := make(map[string]*model.BugTrendPoint)
+	point := func(period string) *model.BugTrendPoint {
+		if points[period] == nil {
+			points[period] = &model.BugTrendPoint{Period: period}
+		}
+		return points[period]
+	}
+	for _, row := range created {
+		point(row.Period).Created = row.BugCount
+	}
+	for _, row := range closed {
+		point(row.Period).Closed = row.BugCount
+	}
+
+	trend := make([]model.BugTrendPoint, 0, len(points))
+	for _, p := range points {
+		trend = append(trend, *p)
+	}
+	sort.Slice(trend, func(i, j int) bool { return trend[i].Period < trend[j].Period })
+	return trend, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupBugStatsTestRepo seeds an in-memory SQLite database, since the statistics rely on
+// SQLite's date functions, with bugs filed in the first week of May 2024.
+func setupBugStatsTestRepo(t *testing.T) (BugRepository, *model.User) {
+	gormDB, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := gormDB.DB()
+		sqlDB.Close()
+	})
+	require.NoError(t, gormDB.AutoMigrate(&model.User{}, &model.Bug{}, &model.BugStatusTransition{}))
+
+	ann := &model.User{Name: "Ann", Email: "ann@example.com", Password: "x", Status: "active"}
+	require.NoError(t, gormDB.Create(ann).Error)
+
+	at := func(day, hour int) time.Time { return time.Date(2024, 5, day, hour, 0, 0, 0, time.UTC) }
+	resolvedAt := at(2, 12)
+	fixed := model.BugResolutionFixed
+	bugs := []*model.Bug{
+		{Title: "Open and assigned", Status: model.BugStatusOpen, Priority: model.BugPriorityHigh, AssigneeID: &ann.ID, CreatedAt: at(1, 10)},
+		{Title: "Fixed in a day", Status: model.BugStatusResolved, Priority: model.BugPriorityLow, Resolution: &fixed, ResolvedAt: &resolvedAt, CreatedAt: at(1, 12)},
+		{Title: "Open, next week", Status: model.BugStatusOpen, Priority: model.BugPriorityHigh, CreatedAt: at(6, 9)},
+		{Title: "Open since April", Status: model.BugStatusOpen, Priority: model.BugPriorityLow, CreatedAt: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "Deleted", Status: model.BugStatusOpen, Priority: model.BugPriorityLow, CreatedAt: at(2, 0)},
+	}
+	for _, bug := range bugs {
+		require.NoError(t, gormDB.Create(bug).Error)
+	}
+	require.NoError(t, gormDB.Delete(bugs[4]).Error)
+	require.NoError(t, gormDB.Create([]*model.BugStatusTransition{
+		{BugID: bugs[1].ID, FromStatus: model.BugStatusOpen, ToStatus: model.BugStatusResolved, CreatedAt: resolvedAt},
+		{BugID: bugs[1].ID, FromStatus: model.BugStatusResolved, ToStatus: model.BugStatusVerified, CreatedAt: at(3, 8)},
+		{BugID: bugs[4].ID, FromStatus: model.BugStatusOpen, ToStatus: model.BugStatusResolved, CreatedAt: at(3, 8)},
+	}).Error)
+
+	return NewBugRepository(gormDB, zap.NewNop()), ann
+}
+
+func TestBugRepositoryImpl_Stats(t *testing.T) {
+	repo, ann := setupBugStatsTestRepo(t)
+	ctx := context.Background()
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC)
+	resolved := model.DefaultBugWorkflow().ResolvedStatuses
+
+	t.Run("CountByGroup", func(t *testing.T) {
+		groups, err := repo.CountByGroup(ctx, model.BugStatsByStatus, from, to)
+		require.NoError(t, err)
+		assert.Equal(t, []model.BugStatsGroup{
+			{Key: "OPEN", Count: 2},
+			{Key: "RESOLVED", Count: 1},
+		}, groups)
+
+		groups, err = repo.CountByGroup(ctx, model.BugStatsByAssignee, from, to)
+		require.NoError(t, err)
+		assert.Equal(t, []model.BugStatsGroup{
+			{Key: "none", Count: 2},
+			{Key: fmt.Sprint(ann.ID), ID: &ann.ID, Label: "Ann", Count: 1},
+		}, groups)
+	})
+
+	t.Run("CountOpenByAge", func(t *testing.T) {
+		buckets, err := repo.CountOpenByAge(ctx, resolved, to)
+		require.NoError(t, err)
+		counts := make(map[string]int64)
+		for _, bucket := range buckets {
+			counts[bucket.Label] = bucket.Count
+		}
+		assert.Equal(t, map[string]int64{"0-1d": 0, "1-7d": 2, "7-30d": 0, "30-90d": 1, "90d+": 0}, counts)
+	})
+
+	t.Run("MeanTimeToResolve", func(t *testing.T) {
+		hours, count, err := repo.MeanTimeToResolve(ctx, from, to)
+		require.NoError(t, err)
+		require.NotNil(t, hours)
+		assert.InDelta(t, 24.0, *hours, 0.001)
+		assert.Equal(t, int64(1), count)
+
+		hours, count, err = repo.MeanTimeToResolve(ctx, to, to.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Nil(t, hours)
+		assert.Zero(t, count)
+	})
+
+	t.Run("CountTrend", func(t *testing.T) {
+		daily, err := repo.CountTrend(ctx, model.BugStatsDaily, resolved, from, to)
+		require.NoError(t, err)
+		assert.Equal(t, []model.BugTrendPoint{
+			{Period: "2024-05-01", Created: 2},
+			{Period: "2024-05-02", Closed: 1},
+			{Period: "2024-05-06", Created: 1},
+		}, daily)
+
+		weekly, err := repo.CountTrend(ctx, model.BugStatsWeekly, resolved, from, to)
+		require.NoError(t, err)
+		assert.Equal(t, []model.BugTrendPoint{
+			{Period: "2024-04-29", Created: 2, Closed: 1},
+			{Period: "2024-05-06", Created: 1},
+		}, weekly)
+	})
+}
@@ -0,0 +1,115 @@
+//go:generate mockgen -destination=mocks/mock_config_revision_repository.go -package=mocks EffiPlat/backend/internal/repository ConfigRevisionRepository
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"EffiPlat/backend/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ConfigRevisionRepository defines the interface for service instance config revisions.
+// Revisions are append-only.
+type ConfigRevisionRepository interface {
+	// Create stores a new revision, assigning the next revision number for the instance.
+	Create(ctx context.Context, revision *model.ConfigRevision) error
+	GetByRevision(ctx context.Context, instanceID uint, revision int) (*model.ConfigRevision, error)
+	GetLatest(ctx context.Context, instanceID uint) (*model.ConfigRevision, error)
+	List(ctx context.Context, instanceID uint, params *model.ConfigRevisionListParams) ([]*model.ConfigRevision, int64, error)
+}
+
+// configRevisionRepositoryImpl implements ConfigRevisionRepository.
+type configRevisionRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewConfigRevisionRepository creates a new ConfigRevisionRepository.
+func NewConfigRevisionRepository(db *gorm.DB, logger *zap.Logger) ConfigRevisionRepository {
+	return &configRevisionRepositoryImpl{db: db, logger: logger}
+}
+
+// Create inserts a revision numbered one above the instance's latest revision.
+// The unique (instance, revision) index rejects concurrent writers racing for the same number.
+func (r *configRevisionRepositoryImpl) Create(ctx context.Context, revision *model.ConfigRevision) error {
+	r.logger.Debug("Creating config revision", zap.Uint("instanceId", revision.ServiceInstanceID))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var maxRevision int
+		if err := tx.Model(&model.ConfigRevision{}).
+			Where("service_instance_id = ?", revision.ServiceInstanceID).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&maxRevision).Error; err != nil {
+			return err
+		}
+		revision.Revision = maxRevision + 1
+		return tx.Create(revision).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to create config revision", zap.Uint("instanceId", revision.ServiceInstanceID), zap.Error(err))
+		return fmt.Errorf("repository.Create: %w", err)
+	}
+	return nil
+}
+
+// GetByRevision retrieves a specific revision of an instance's config.
+func (r *configRevisionRepositoryImpl) GetByRevision(ctx context.Context, instanceID uint, revision int) (*model.ConfigRevision, error) {
+	var rev model.ConfigRevision
+	err := r.db.WithContext(ctx).
+		Where("service_instance_id = ? AND revision = ?", instanceID, revision).
+		First(&rev).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		r.logger.Error("Failed to get config revision", zap.Uint("instanceId", instanceID), zap.Int("revision", revision), zap.Error(err))
+		return nil, fmt.Errorf("repository.GetByRevision: %w", err)
+	}
+	return &rev, nil
+}
+
+// GetLatest retrieves the most recent revision of an instance's config.
+func (r *configRevisionRepositoryImpl) GetLatest(ctx context.Context, instanceID uint) (*model.ConfigRevision, error) {
+	var rev model.ConfigRevision
+	err := r.db.WithContext(ctx).
+		Where("service_instance_id = ?", instanceID).
+		Order("revision DESC").
+		First(&rev).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		r.logger.Error("Failed to get latest config revision", zap.Uint("instanceId", instanceID), zap.Error(err))
+		return nil, fmt.Errorf("repository.GetLatest: %w", err)
+	}
+	return &rev, nil
+}
+
+// List retrieves an instance's config revisions, newest first.
+func (r *configRevisionRepositoryImpl) List(ctx context.Context, instanceID uint, params *model.ConfigRevisionListParams) ([]*model.ConfigRevision, int64, error) {
+	var revisions []*model.ConfigRevision
+	var total int64
+
+	tx := r.db.WithContext(ctx).Model(&model.ConfigRevision{}).Where("service_instance_id = ?", instanceID)
+	if err := tx.Count(&total).Error; err != nil {
+		r.logger.Error("Failed to count config revisions", zap.Error(err))
+		return nil, 0, fmt.Errorf("repository.List.Count: %w", err)
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 {
+		params.PageSize = 20
+	}
+	offset := (params.Page - 1) * params.PageSize
+
+	if err := tx.Order("revision DESC").Limit(params.PageSize).Offset(offset).Find(&revisions).Error; err != nil {
+		r.logger.Error("Failed to list config revisions", zap.Error(err))
+		return nil, 0, fmt.Errorf("repository.List.Find: %w", err)
+	}
+	return revisions, total, nil
+}
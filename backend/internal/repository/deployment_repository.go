@@ -0,0 +1,98 @@
+//go:generate mockgen -destination=mocks/mock_deployment_repository.go -package=mocks EffiPlat/backend/internal/repository DeploymentRepository
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DeploymentRepository defines the interface for deployment record operations.
+// Deployment records are append-only, so no update or delete is offered.
+type DeploymentRepository interface {
+	Create(ctx context.Context, deployment *model.Deployment) error
+	List(ctx context.Context, params *model.DeploymentListParams) ([]*model.Deployment, int64, error)
+}
+
+// deploymentRepositoryImpl implements DeploymentRepository.
+type deploymentRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewDeploymentRepository creates a new DeploymentRepository.
+func NewDeploymentRepository(db *gorm.DB, logger *zap.Logger) DeploymentRepository {
+	return &deploymentRepositoryImpl{db: db, logger: logger}
+}
+
+// Create inserts a new deployment record.
+func (r *deploymentRepositoryImpl) Create(ctx context.Context, deployment *model.Deployment) error {
+	r.logger.Debug("Creating deployment record", zap.Any("deployment", deployment))
+	if err := r.db.WithContext(ctx).Create(deployment).Error; err != nil {
+		r.logger.Error("Failed to create deployment record", zap.Error(err))
+		return fmt.Errorf("repository.Create: %w", err)
+	}
+	return nil
+}
+
+// List retrieves deployment records, newest first, based on the given filters.
+func (r *deploymentRepositoryImpl) List(ctx context.Context, params *model.DeploymentListParams) ([]*model.Deployment, int64, error) {
+	var deployments []*model.Deployment
+	var total int64
+
+	r.logger.Debug("Listing deployments", zap.Any("params", params))
+
+	tx := r.db.WithContext(ctx).Model(&model.Deployment{})
+
+	if params.ServiceInstanceID != nil {
+		tx = tx.Where("service_instance_id = ?", *params.ServiceInstanceID)
+	}
+	if params.ServiceID != nil {
+		tx = tx.Where("service_id = ?", *params.ServiceID)
+	}
+	if params.EnvironmentID != nil {
+		tx = tx.Where("environment_id = ?", *params.EnvironmentID)
+	}
+	if params.Status != "" {
+		tx = tx.Where("status = ?", params.Status)
+	}
+	if params.StartDate != "" {
+		if startDate, err := time.Parse("2006-01-02", params.StartDate); err == nil {
+			tx = tx.Where("created_at >= ?", startDate)
+		} else {
+			r.logger.Warn("Invalid start date format", zap.String("startDate", params.StartDate), zap.Error(err))
+		}
+	}
+	if params.EndDate != "" {
+		if endDate, err := time.Parse("2006-01-02", params.EndDate); err == nil {
+			tx = tx.Where("created_at < ?", endDate.Add(24*time.Hour))
+		} else {
+			r.logger.Warn("Invalid end date format", zap.String("endDate", params.EndDate), zap.Error(err))
+		}
+	}
+
+	if err := tx.Count(&total).Error; err != nil {
+		r.logger.Error("Failed to count deployments", zap.Error(err))
+		return nil, 0, fmt.Errorf("repository.List.Count: %w", err)
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 {
+		params.PageSize = 20
+	}
+	offset := (params.Page - 1) * params.PageSize
+
+	if err := tx.Order("created_at DESC, id DESC").Limit(params.PageSize).Offset(offset).Find(&deployments).Error; err != nil {
+		r.logger.Error("Failed to list deployments", zap.Error(err))
+		return nil, 0, fmt.Errorf("repository.List.Find: %w", err)
+	}
+
+	return deployments, total, nil
+}
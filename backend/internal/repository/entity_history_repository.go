@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// entityHistoryModels maps the resources whose changes are versioned to their model types.
+var entityHistoryModels = map[string]reflect.Type{
+	model.EntityResourceEnvironment:     reflect.TypeOf(model.Environment{}),
+	model.EntityResourceAsset:           reflect.TypeOf(model.Asset{}),
+	model.EntityResourceService:         reflect.TypeOf(model.Service{}),
+	model.EntityResourceServiceInstance: reflect.TypeOf(model.ServiceInstance{}),
+	model.EntityResourceBusiness:        reflect.TypeOf(model.Business{}),
+	model.EntityResourceBug:             reflect.TypeOf(model.Bug{}),
+}
+
+// entityHistoryBeforeKey stores the rows an update or delete is about to change on its statement.
+const entityHistoryBeforeKey = "entity_history:before"
+
+// EntityHistoryRepository defines the interface for the version history of CMDB entities.
+// Versions are written by database callbacks whenever a tracked entity is created, updated
+// or deleted, so every code path that changes an entity is covered.
+type EntityHistoryRepository interface {
+	// List retrieves an entity's versions, newest first.
+	List(ctx context.Context, resource string, id uint, params *model.EntityHistoryListParams) ([]*model.EntityVersion, int64, error)
+	// GetAsOf retrieves the version of an entity that was current at the given time.
+	// It returns gorm.ErrRecordNotFound when the entity did not exist then.
+	GetAsOf(ctx context.Context, resource string, id uint, at time.Time) (*model.EntityVersion, error)
+}
+
+// entityHistoryRepositoryImpl implements EntityHistoryRepository.
+type entityHistoryRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewEntityHistoryRepository creates a new EntityHistoryRepository and registers the
+// callbacks that record entity versions on db.
+func NewEntityHistoryRepository(db *gorm.DB, logger *zap.Logger) EntityHistoryRepository {
+	trackEntityHistory(db)
+	return &entityHistoryRepositoryImpl{db: db, logger: logger}
+}
+
+// List retrieves an entity's versions, newest first.
+func (r *entityHistoryRepositoryImpl) List(ctx context.Context, resource string, id uint, params *model.EntityHistoryListParams) ([]*model.EntityVersion, int64, error) {
+	var versions []*model.EntityVersion
+	var total int64
+
+	tx := r.db.WithContext(ctx).Model(&model.EntityVersion{}).Where("resource = ? AND resource_id = ?", resource, id)
+	if err := tx.Count(&total).Error; err != nil {
+		r.logger.Error("Failed to count entity versions", zap.String("resource", resource), zap.Uint("id", id), zap.Error(err))
+		return nil, 0, fmt.Errorf("repository.List.Count: %w", err)
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 {
+		params.PageSize = 20
+	}
+	offset := (params.Page - 1) * params.PageSize
+
+	if err := tx.Order("version DESC").Limit(params.PageSize).Offset(offset).Find(&versions).Error; err != nil {
+		r.logger.Error("Failed to list entity versions", zap.String("resource", resource), zap.Uint("id", id), zap.Error(err))
+		return nil, 0, fmt.Errorf("repository.List.Find: %w", err)
+	}
+	return versions, total, nil
+}
+
+// GetAsOf retrieves the latest version of an entity created at or before at. An entity that
+// has not changed since history was first kept has no versions; its current row is returned
+// as a baseline (version 0) if it was last updated by then and was not deleted.
+func (r *entityHistoryRepositoryImpl) GetAsOf(ctx context.Context, resource string, id uint, at time.Time) (*model.EntityVersion, error) {
+	modelType, ok := entityHistoryModels[resource]
+	if !ok {
+		return nil, fmt.Errorf("repository.GetAsOf: resource %q has no history", resource)
+	}
+	at = at.Local()
+
+	var version model.EntityVersion
+	err := r.db.WithContext(ctx).
+		Where("resource = ? AND resource_id = ? AND created_at <= ?", resource, id, at).
+		Order("version DESC").
+		First(&version).Error
+	if err == nil {
+		if version.Action == model.EntityVersionDelete {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return &version, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.logger.Error("Failed to get entity version", zap.String("resource", resource), zap.Uint("id", id), zap.Time("asOf", at), zap.Error(err))
+		return nil, fmt.Errorf("repository.GetAsOf: %w", err)
+	}
+
+	// 只有更晚的版本时，实体在at时尚不存在或其状态未被记录
+	var versions int64
+	if err := r.db.WithContext(ctx).Model(&model.EntityVersion{}).Where("resource = ? AND resource_id = ?", resource, id).Count(&versions).Error; err != nil {
+		return nil, fmt.Errorf("repository.GetAsOf.Count: %w", err)
+	}
+	if versions > 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	row := reflect.New(modelType)
+	if err := r.db.WithContext(ctx).Unscoped().First(row.Interface(), id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("repository.GetAsOf.Current: %w", err)
+	}
+	if deletedAt, ok := entityField(row.Elem(), "DeletedAt").(gorm.DeletedAt); ok && deletedAt.Valid && !deletedAt.Time.After(at) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	baseline, err := baselineVersion(resource, id, row)
+	if err != nil {
+		return nil, fmt.Errorf("repository.GetAsOf.Baseline: %w", err)
+	}
+	if baseline.CreatedAt.After(at) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	baseline.Version = 0
+	return baseline, nil
+}
+
+// trackEntityHistory registers callbacks on db that write a version of every tracked entity
+// changed by a create, update or delete, in the same transaction as the change.
+// Registering again on the same db is a no-op.
+func trackEntityHistory(db *gorm.DB) {
+	const name = "entity_history:track"
+	if db.Callback().Create().Get(name) != nil {
+		return
+	}
+	_ = db.Callback().Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").
+		Register(name, recordEntityVersions(model.EntityVersionCreate))
+	_ = db.Callback().Update().After("gorm:setup_reflect_value").Before("gorm:update").
+		Register(name+":before", captureEntityRows)
+	_ = db.Callback().Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").
+		Register(name, recordEntityVersions(model.EntityVersionUpdate))
+	_ = db.Callback().Delete().Before("gorm:delete").
+		Register(name+":before", captureEntityRows)
+	_ = db.Callback().Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").
+		Register(name, recordEntityVersions(model.EntityVersionDelete))
+}
+
+// entityHistoryResource returns the resource name of the statement's model, if it is tracked.
+func entityHistoryResource(stmt *gorm.Statement) (string, bool) {
+	if stmt.Schema == nil {
+		return "", false
+	}
+	for resource, modelType := range entityHistoryModels {
+		if stmt.Schema.ModelType == modelType {
+			return resource, true
+		}
+	}
+	return "", false
+}
+
+// captureEntityRows loads the rows that an update or delete is about to change, so that their
+// IDs are known afterwards and a baseline can be written for entities without history.
+func captureEntityRows(tx *gorm.DB) {
+	if tx.Error != nil || tx.DryRun {
+		return
+	}
+	if _, ok := entityHistoryResource(tx.Statement); !ok {
+		return
+	}
+	stmt := tx.Statement
+
+	var conds []clause.Expression
+	if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok {
+		conds = append(conds, where.Exprs...)
+	}
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array:
+		_, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields)
+		column, values := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+		if len(values) > 0 {
+			conds = append(conds, clause.IN{Column: column, Values: values})
+		}
+	}
+	if len(conds) == 0 {
+		return // 没有条件的更新和删除会被GORM拒绝
+	}
+
+	query := tx.Session(&gorm.Session{NewDB: true}).Model(reflect.New(stmt.Schema.ModelType).Interface())
+	if stmt.Unscoped {
+		query = query.Unscoped()
+	}
+	rows := reflect.New(reflect.SliceOf(reflect.PointerTo(stmt.Schema.ModelType)))
+	if err := query.Clauses(clause.Where{Exprs: conds}).Find(rows.Interface()).Error; err != nil {
+		_ = tx.AddError(fmt.Errorf("entity history: %w", err))
+		return
+	}
+	tx.InstanceSet(entityHistoryBeforeKey, rows.Elem())
+}
+
+// recordEntityVersions returns the callback that writes a version of each entity changed by the statement.
+func recordEntityVersions(action model.EntityVersionAction) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		if tx.Error != nil || tx.DryRun || tx.RowsAffected == 0 {
+			return
+		}
+		resource, ok := entityHistoryResource(tx.Statement)
+		if !ok {
+			return
+		}
+		stmt := tx.Statement
+
+		before := make(map[uint]reflect.Value)
+		var ids []uint
+		if action == model.EntityVersionCreate {
+			ids = entityIDs(stmt, stmt.ReflectValue)
+		} else {
+			captured, ok := tx.InstanceGet(entityHistoryBeforeKey)
+			if !ok {
+				return
+			}
+			rows := captured.(reflect.Value)
+			for i := 0; i < rows.Len(); i++ {
+				id := entityIDs(stmt, rows.Index(i))
+				if len(id) == 1 {
+					ids = append(ids, id[0])
+					before[id[0]] = rows.Index(i)
+				}
+			}
+		}
+		if len(ids) == 0 {
+			return
+		}
+
+		session := tx.Session(&gorm.Session{NewDB: true})
+		rows := reflect.New(reflect.SliceOf(reflect.PointerTo(stmt.Schema.ModelType)))
+		if err := session.Unscoped().Find(rows.Interface(), ids).Error; err != nil {
+			_ = tx.AddError(fmt.Errorf("entity history: %w", err))
+			return
+		}
+		now := tx.NowFunc()
+		for i := 0; i < rows.Elem().Len(); i++ {
+			row := rows.Elem().Index(i)
+			id := entityIDs(stmt, row)[0]
+			if err := appendEntityVersion(session, resource, id, action, row, before[id], now); err != nil {
+				_ = tx.AddError(fmt.Errorf("entity history: %w", err))
+				return
+			}
+		}
+	}
+}
+
+// entityIDs returns the primary keys of the model value or values in v.
+func entityIDs(stmt *gorm.Statement, v reflect.Value) []uint {
+	field := stmt.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return nil
+	}
+	var ids []uint
+	collect := func(elem reflect.Value) {
+		if value, isZero := field.ValueOf(stmt.Context, elem); !isZero {
+			if id, ok := value.(uint); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		collect(v)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			for elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			collect(elem)
+		}
+	}
+	return ids
+}
+
+// appendEntityVersion writes the next version of an entity. The first tracked change of an
+// entity that already existed is preceded by a baseline of its previous state.
+func appendEntityVersion(db *gorm.DB, resource string, id uint, action model.EntityVersionAction, row, before reflect.Value, at time.Time) error {
+	var latest int
+	if err := db.Model(&model.EntityVersion{}).
+		Where("resource = ? AND resource_id = ?", resource, id).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	if latest == 0 && action != model.EntityVersionCreate && before.IsValid() {
+		baseline, err := baselineVersion(resource, id, before)
+		if err != nil {
+			return err
+		}
+		if baseline.CreatedAt.After(at) {
+			baseline.CreatedAt = at
+		}
+		if err := db.Create(baseline).Error; err != nil {
+			return err
+		}
+		latest = baseline.Version
+	}
+
+	snapshot, err := entitySnapshot(row.Interface())
+	if err != nil {
+		return err
+	}
+	version := &model.EntityVersion{
+		Resource:    resource,
+		ResourceID:  id,
+		Version:     latest + 1,
+		Action:      action,
+		Snapshot:    snapshot,
+		ChangedByID: apputils.ActorUserID(db.Statement.Context),
+		ChangedBy:   apputils.ActorName(db.Statement.Context),
+		CreatedAt:   at,
+	}
+	return db.Create(version).Error
+}
+
+// baselineVersion returns the state of an existing row as version 1, dated at its last update.
+func baselineVersion(resource string, id uint, row reflect.Value) (*model.EntityVersion, error) {
+	snapshot, err := entitySnapshot(row.Interface())
+	if err != nil {
+		return nil, err
+	}
+	elem := reflect.Indirect(row)
+	at, _ := entityField(elem, "UpdatedAt").(time.Time)
+	if at.IsZero() {
+		at, _ = entityField(elem, "CreatedAt").(time.Time)
+	}
+	return &model.EntityVersion{
+		Resource:   resource,
+		ResourceID: id,
+		Version:    1,
+		Action:     model.EntityVersionBaseline,
+		Snapshot:   snapshot,
+		CreatedAt:  at.Local(),
+	}, nil
+}
+
+// entitySnapshot returns the JSON of an entity with sensitive fields and encrypted secrets masked.
+func entitySnapshot(entity interface{}) (datatypes.JSON, error) {
+	raw, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	masked, err := json.Marshal(apputils.RedactAuditDetails(doc))
+	if err != nil {
+		return nil, err
+	}
+	return datatypes.JSON(masked), nil
+}
+
+// entityField returns the value of the named field of a model struct, or nil if it has none.
+func entityField(elem reflect.Value, name string) interface{} {
+	field := elem.FieldByName(name)
+	if !field.IsValid() {
+		return nil
+	}
+	return field.Interface()
+}
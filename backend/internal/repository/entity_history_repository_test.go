@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupEntityHistoryTestDB(t *testing.T) *gorm.DB {
+	gormDB, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := gormDB.DB()
+		sqlDB.Close()
+	})
+	require.NoError(t, gormDB.AutoMigrate(&model.EntityVersion{}, &model.Environment{}, &model.Business{}, &model.ServiceInstance{}))
+	return gormDB
+}
+
+func entitySnapshotField(t *testing.T, version *model.EntityVersion, field string) interface{} {
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(version.Snapshot, &doc))
+	return doc[field]
+}
+
+func TestEntityHistory_RecordsVersions(t *testing.T) {
+	db := setupEntityHistoryTestDB(t)
+	historyRepo := NewEntityHistoryRepository(db, zap.NewNop())
+	businessRepo := NewBusinessRepository(db, zap.NewNop())
+	ctx := apputils.WithActor(context.Background(), apputils.Actor{UserID: 7, Username: "ann"})
+
+	business := &model.Business{Name: "Payments", Owner: "team-a"}
+	require.NoError(t, businessRepo.Create(ctx, business))
+	business.Owner = "team-b"
+	require.NoError(t, businessRepo.Update(ctx, business))
+	require.NoError(t, businessRepo.Delete(ctx, business.ID))
+
+	versions, total, err := historyRepo.List(ctx, model.EntityResourceBusiness, business.ID, &model.EntityHistoryListParams{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, total)
+	require.Len(t, versions, 3)
+	assert.Equal(t, []model.EntityVersionAction{model.EntityVersionDelete, model.EntityVersionUpdate, model.EntityVersionCreate},
+		[]model.EntityVersionAction{versions[0].Action, versions[1].Action, versions[2].Action})
+	assert.Equal(t, 3, versions[0].Version)
+	assert.Equal(t, "team-b", entitySnapshotField(t, versions[1], "owner"))
+	assert.Equal(t, "team-a", entitySnapshotField(t, versions[2], "owner"))
+	assert.Equal(t, "active", entitySnapshotField(t, versions[2], "status"), "snapshot is reloaded with database defaults")
+	require.NotNil(t, versions[1].ChangedByID)
+	assert.EqualValues(t, 7, *versions[1].ChangedByID)
+	assert.Equal(t, "ann", versions[1].ChangedBy)
+}
+
+func TestEntityHistory_BaselineAndAsOf(t *testing.T) {
+	db := setupEntityHistoryTestDB(t)
+	ctx := context.Background()
+	created := time.Now().Add(-48 * time.Hour)
+
+	// 在记录历史之前已经存在的环境
+	env := &model.Environment{Name: "Staging", Slug: "staging", Config: datatypes.JSONMap{"apiToken": "abc"}, CreatedAt: created, UpdatedAt: created}
+	require.NoError(t, db.Create(env).Error)
+
+	historyRepo := NewEntityHistoryRepository(db, zap.NewNop())
+	envRepo := NewGormEnvironmentRepository(db, zap.NewNop())
+
+	t.Run("UnchangedEntityUsesCurrentRow", func(t *testing.T) {
+		version, err := historyRepo.GetAsOf(ctx, model.EntityResourceEnvironment, env.ID, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, model.EntityVersionBaseline, version.Action)
+		assert.Equal(t, 0, version.Version)
+		assert.Equal(t, apputils.SecretMask, entitySnapshotField(t, version, "config").(map[string]interface{})["apiToken"])
+
+		_, err = historyRepo.GetAsOf(ctx, model.EntityResourceEnvironment, env.ID, created.Add(-time.Hour))
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	beforeUpdate := time.Now()
+	env.Description = "pre-production"
+	_, err := envRepo.Update(ctx, env)
+	require.NoError(t, err)
+
+	versions, _, err := historyRepo.List(ctx, model.EntityResourceEnvironment, env.ID, &model.EntityHistoryListParams{})
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, model.EntityVersionBaseline, versions[1].Action)
+	assert.WithinDuration(t, created, versions[1].CreatedAt, time.Second)
+
+	t.Run("AsOfBeforeAndAfterUpdate", func(t *testing.T) {
+		version, err := historyRepo.GetAsOf(ctx, model.EntityResourceEnvironment, env.ID, beforeUpdate)
+		require.NoError(t, err)
+		assert.Equal(t, 1, version.Version)
+		assert.Equal(t, "", entitySnapshotField(t, version, "description"))
+
+		version, err = historyRepo.GetAsOf(ctx, model.EntityResourceEnvironment, env.ID, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, 2, version.Version)
+		assert.Equal(t, "pre-production", entitySnapshotField(t, version, "description"))
+	})
+
+	t.Run("AsOfAfterDelete", func(t *testing.T) {
+		require.NoError(t, envRepo.Delete(ctx, env.ID))
+		_, err := historyRepo.GetAsOf(ctx, model.EntityResourceEnvironment, env.ID, time.Now())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		version, err := historyRepo.GetAsOf(ctx, model.EntityResourceEnvironment, env.ID, beforeUpdate)
+		require.NoError(t, err)
+		assert.Equal(t, 1, version.Version)
+	})
+}
+
+func TestEntityHistory_UpdateWithoutTransaction(t *testing.T) {
+	db := setupEntityHistoryTestDB(t)
+	historyRepo := NewEntityHistoryRepository(db, zap.NewNop())
+	instanceRepo := NewServiceInstanceRepository(db, zap.NewNop())
+	ctx := context.Background()
+
+	instance := &model.ServiceInstance{ServiceID: 1, EnvironmentID: 1, Version: "1.0.0", Status: model.ServiceInstanceStatusRunning}
+	require.NoError(t, instanceRepo.Create(ctx, instance))
+	instance.Version = "1.1.0"
+	require.NoError(t, instanceRepo.Update(ctx, instance))
+
+	version, err := historyRepo.GetAsOf(ctx, model.EntityResourceServiceInstance, instance.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, version.Version)
+	assert.Equal(t, "1.1.0", entitySnapshotField(t, version, "version"))
+
+	// 写入版本本身不会再产生版本
+	var count int64
+	require.NoError(t, db.Model(&model.EntityVersion{}).Count(&count).Error)
+	assert.EqualValues(t, 2, count)
+}
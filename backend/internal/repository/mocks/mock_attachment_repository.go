@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: EffiPlat/backend/internal/repository (interfaces: AttachmentRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_attachment_repository.go -package=mocks EffiPlat/backend/internal/repository AttachmentRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "EffiPlat/backend/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAttachmentRepository is a mock of AttachmentRepository interface.
+type MockAttachmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAttachmentRepositoryMockRecorder is the mock recorder for MockAttachmentRepository.
+type MockAttachmentRepositoryMockRecorder struct {
+	mock *MockAttachmentRepository
+}
+
+// NewMockAttachmentRepository creates a new mock instance.
+func NewMockAttachmentRepository(ctrl *gomock.Controller) *MockAttachmentRepository {
+	mock := &MockAttachmentRepository{ctrl: ctrl}
+	mock.recorder = &MockAttachmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentRepository) EXPECT() *MockAttachmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountByStorageKey mocks base method.
+func (m *MockAttachmentRepository) CountByStorageKey(ctx context.Context, storageKey string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByStorageKey", ctx, storageKey)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByStorageKey indicates an expected call of CountByStorageKey.
+func (mr *MockAttachmentRepositoryMockRecorder) CountByStorageKey(ctx, storageKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByStorageKey", reflect.TypeOf((*MockAttachmentRepository)(nil).CountByStorageKey), ctx, storageKey)
+}
+
+// Create mocks base method.
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *model.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAttachmentRepositoryMockRecorder) Create(ctx, attachment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAttachmentRepository)(nil).Create), ctx, attachment)
+}
+
+// Delete mocks base method.
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAttachmentRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAttachmentRepository)(nil).Delete), ctx, id)
+}
+
+// FindByOwnerAndHash mocks base method.
+func (m *MockAttachmentRepository) FindByOwnerAndHash(ctx context.Context, ownerType model.AttachmentOwnerType, ownerID uint, sha256 string) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOwnerAndHash", ctx, ownerType, ownerID, sha256)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOwnerAndHash indicates an expected call of FindByOwnerAndHash.
+func (mr *MockAttachmentRepositoryMockRecorder) FindByOwnerAndHash(ctx, ownerType, ownerID, sha256 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOwnerAndHash", reflect.TypeOf((*MockAttachmentRepository)(nil).FindByOwnerAndHash), ctx, ownerType, ownerID, sha256)
+}
+
+// GetByID mocks base method.
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, id uint) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAttachmentRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAttachmentRepository)(nil).GetByID), ctx, id)
+}
+
+// ListByOwner mocks base method.
+func (m *MockAttachmentRepository) ListByOwner(ctx context.Context, ownerType model.AttachmentOwnerType, ownerID uint) ([]*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByOwner", ctx, ownerType, ownerID)
+	ret0, _ := ret[0].([]*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByOwner indicates an expected call of ListByOwner.
+func (mr *MockAttachmentRepositoryMockRecorder) ListByOwner(ctx, ownerType, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByOwner", reflect.TypeOf((*MockAttachmentRepository)(nil).ListByOwner), ctx, ownerType, ownerID)
+}
@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: EffiPlat/backend/internal/repository (interfaces: BugAssignmentRuleRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_bug_assignment_rule_repository.go -package=mocks EffiPlat/backend/internal/repository BugAssignmentRuleRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "EffiPlat/backend/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBugAssignmentRuleRepository is a mock of BugAssignmentRuleRepository interface.
+type MockBugAssignmentRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBugAssignmentRuleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockBugAssignmentRuleRepositoryMockRecorder is the mock recorder for MockBugAssignmentRuleRepository.
+type MockBugAssignmentRuleRepositoryMockRecorder struct {
+	mock *MockBugAssignmentRuleRepository
+}
+
+// NewMockBugAssignmentRuleRepository creates a new mock instance.
+func NewMockBugAssignmentRuleRepository(ctrl *gomock.Controller) *MockBugAssignmentRuleRepository {
+	mock := &MockBugAssignmentRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockBugAssignmentRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBugAssignmentRuleRepository) EXPECT() *MockBugAssignmentRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AdvanceCursor mocks base method.
+func (m *MockBugAssignmentRuleRepository) AdvanceCursor(ctx context.Context, id, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceCursor", ctx, id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdvanceCursor indicates an expected call of AdvanceCursor.
+func (mr *MockBugAssignmentRuleRepositoryMockRecorder) AdvanceCursor(ctx, id, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceCursor", reflect.TypeOf((*MockBugAssignmentRuleRepository)(nil).AdvanceCursor), ctx, id, userID)
+}
+
+// Create mocks base method.
+func (m *MockBugAssignmentRuleRepository) Create(ctx context.Context, rule *model.BugAssignmentRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBugAssignmentRuleRepositoryMockRecorder) Create(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBugAssignmentRuleRepository)(nil).Create), ctx, rule)
+}
+
+// Delete mocks base method.
+func (m *MockBugAssignmentRuleRepository) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBugAssignmentRuleRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBugAssignmentRuleRepository)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockBugAssignmentRuleRepository) GetByID(ctx context.Context, id uint) (*model.BugAssignmentRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*model.BugAssignmentRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockBugAssignmentRuleRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBugAssignmentRuleRepository)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockBugAssignmentRuleRepository) List(ctx context.Context, enabledOnly bool) ([]*model.BugAssignmentRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, enabledOnly)
+	ret0, _ := ret[0].([]*model.BugAssignmentRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBugAssignmentRuleRepositoryMockRecorder) List(ctx, enabledOnly any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBugAssignmentRuleRepository)(nil).List), ctx, enabledOnly)
+}
+
+// Update mocks base method.
+func (m *MockBugAssignmentRuleRepository) Update(ctx context.Context, rule *model.BugAssignmentRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBugAssignmentRuleRepositoryMockRecorder) Update(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBugAssignmentRuleRepository)(nil).Update), ctx, rule)
+}
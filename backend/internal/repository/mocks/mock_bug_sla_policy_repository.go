@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: EffiPlat/backend/internal/repository (interfaces: BugSLAPolicyRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_bug_sla_policy_repository.go -package=mocks EffiPlat/backend/internal/repository BugSLAPolicyRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "EffiPlat/backend/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBugSLAPolicyRepository is a mock of BugSLAPolicyRepository interface.
+type MockBugSLAPolicyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBugSLAPolicyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockBugSLAPolicyRepositoryMockRecorder is the mock recorder for MockBugSLAPolicyRepository.
+type MockBugSLAPolicyRepositoryMockRecorder struct {
+	mock *MockBugSLAPolicyRepository
+}
+
+// NewMockBugSLAPolicyRepository creates a new mock instance.
+func NewMockBugSLAPolicyRepository(ctrl *gomock.Controller) *MockBugSLAPolicyRepository {
+	mock := &MockBugSLAPolicyRepository{ctrl: ctrl}
+	mock.recorder = &MockBugSLAPolicyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBugSLAPolicyRepository) EXPECT() *MockBugSLAPolicyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteByPriority mocks base method.
+func (m *MockBugSLAPolicyRepository) DeleteByPriority(ctx context.Context, priority model.BugPriorityType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByPriority", ctx, priority)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByPriority indicates an expected call of DeleteByPriority.
+func (mr *MockBugSLAPolicyRepositoryMockRecorder) DeleteByPriority(ctx, priority any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPriority", reflect.TypeOf((*MockBugSLAPolicyRepository)(nil).DeleteByPriority), ctx, priority)
+}
+
+// GetByPriority mocks base method.
+func (m *MockBugSLAPolicyRepository) GetByPriority(ctx context.Context, priority model.BugPriorityType) (*model.BugSLAPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPriority", ctx, priority)
+	ret0, _ := ret[0].(*model.BugSLAPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPriority indicates an expected call of GetByPriority.
+func (mr *MockBugSLAPolicyRepositoryMockRecorder) GetByPriority(ctx, priority any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPriority", reflect.TypeOf((*MockBugSLAPolicyRepository)(nil).GetByPriority), ctx, priority)
+}
+
+// List mocks base method.
+func (m *MockBugSLAPolicyRepository) List(ctx context.Context) ([]*model.BugSLAPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]*model.BugSLAPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBugSLAPolicyRepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBugSLAPolicyRepository)(nil).List), ctx)
+}
+
+// Upsert mocks base method.
+func (m *MockBugSLAPolicyRepository) Upsert(ctx context.Context, policy *model.BugSLAPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockBugSLAPolicyRepositoryMockRecorder) Upsert(ctx, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockBugSLAPolicyRepository)(nil).Upsert), ctx, policy)
+}
@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: EffiPlat/backend/internal/repository (interfaces: BusinessRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_business_repository.go -package=mocks EffiPlat/backend/internal/repository BusinessRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "EffiPlat/backend/internal/model"
+	repository "EffiPlat/backend/internal/repository"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBusinessRepository is a mock of BusinessRepository interface.
+type MockBusinessRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBusinessRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockBusinessRepositoryMockRecorder is the mock recorder for MockBusinessRepository.
+type MockBusinessRepositoryMockRecorder struct {
+	mock *MockBusinessRepository
+}
+
+// NewMockBusinessRepository creates a new mock instance.
+func NewMockBusinessRepository(ctrl *gomock.Controller) *MockBusinessRepository {
+	mock := &MockBusinessRepository{ctrl: ctrl}
+	mock.recorder = &MockBusinessRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBusinessRepository) EXPECT() *MockBusinessRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CheckExists mocks base method.
+func (m *MockBusinessRepository) CheckExists(ctx context.Context, name string, excludeID uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckExists", ctx, name, excludeID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckExists indicates an expected call of CheckExists.
+func (mr *MockBusinessRepositoryMockRecorder) CheckExists(ctx, name, excludeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckExists", reflect.TypeOf((*MockBusinessRepository)(nil).CheckExists), ctx, name, excludeID)
+}
+
+// Create mocks base method.
+func (m *MockBusinessRepository) Create(ctx context.Context, business *model.Business) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, business)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBusinessRepositoryMockRecorder) Create(ctx, business any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBusinessRepository)(nil).Create), ctx, business)
+}
+
+// Delete mocks base method.
+func (m *MockBusinessRepository) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBusinessRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBusinessRepository)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockBusinessRepository) GetByID(ctx context.Context, id uint) (*model.Business, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*model.Business)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockBusinessRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBusinessRepository)(nil).GetByID), ctx, id)
+}
+
+// GetByName mocks base method.
+func (m *MockBusinessRepository) GetByName(ctx context.Context, name string) (*model.Business, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByName", ctx, name)
+	ret0, _ := ret[0].(*model.Business)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByName indicates an expected call of GetByName.
+func (mr *MockBusinessRepositoryMockRecorder) GetByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockBusinessRepository)(nil).GetByName), ctx, name)
+}
+
+// List mocks base method.
+func (m *MockBusinessRepository) List(ctx context.Context, params *repository.ListBusinessesParams) ([]model.Business, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, params)
+	ret0, _ := ret[0].([]model.Business)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockBusinessRepositoryMockRecorder) List(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBusinessRepository)(nil).List), ctx, params)
+}
+
+// Update mocks base method.
+func (m *MockBusinessRepository) Update(ctx context.Context, business *model.Business) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, business)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBusinessRepositoryMockRecorder) Update(ctx, business any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBusinessRepository)(nil).Update), ctx, business)
+}
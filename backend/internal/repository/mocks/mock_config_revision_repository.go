@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: EffiPlat/backend/internal/repository (interfaces: ConfigRevisionRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_config_revision_repository.go -package=mocks EffiPlat/backend/internal/repository ConfigRevisionRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "EffiPlat/backend/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConfigRevisionRepository is a mock of ConfigRevisionRepository interface.
+type MockConfigRevisionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigRevisionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockConfigRevisionRepositoryMockRecorder is the mock recorder for MockConfigRevisionRepository.
+type MockConfigRevisionRepositoryMockRecorder struct {
+	mock *MockConfigRevisionRepository
+}
+
+// NewMockConfigRevisionRepository creates a new mock instance.
+func NewMockConfigRevisionRepository(ctrl *gomock.Controller) *MockConfigRevisionRepository {
+	mock := &MockConfigRevisionRepository{ctrl: ctrl}
+	mock.recorder = &MockConfigRevisionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigRevisionRepository) EXPECT() *MockConfigRevisionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockConfigRevisionRepository) Create(ctx context.Context, revision *model.ConfigRevision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, revision)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockConfigRevisionRepositoryMockRecorder) Create(ctx, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockConfigRevisionRepository)(nil).Create), ctx, revision)
+}
+
+// GetByRevision mocks base method.
+func (m *MockConfigRevisionRepository) GetByRevision(ctx context.Context, instanceID uint, revision int) (*model.ConfigRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByRevision", ctx, instanceID, revision)
+	ret0, _ := ret[0].(*model.ConfigRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByRevision indicates an expected call of GetByRevision.
+func (mr *MockConfigRevisionRepositoryMockRecorder) GetByRevision(ctx, instanceID, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByRevision", reflect.TypeOf((*MockConfigRevisionRepository)(nil).GetByRevision), ctx, instanceID, revision)
+}
+
+// GetLatest mocks base method.
+func (m *MockConfigRevisionRepository) GetLatest(ctx context.Context, instanceID uint) (*model.ConfigRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatest", ctx, instanceID)
+	ret0, _ := ret[0].(*model.ConfigRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatest indicates an expected call of GetLatest.
+func (mr *MockConfigRevisionRepositoryMockRecorder) GetLatest(ctx, instanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatest", reflect.TypeOf((*MockConfigRevisionRepository)(nil).GetLatest), ctx, instanceID)
+}
+
+// List mocks base method.
+func (m *MockConfigRevisionRepository) List(ctx context.Context, instanceID uint, params *model.ConfigRevisionListParams) ([]*model.ConfigRevision, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, instanceID, params)
+	ret0, _ := ret[0].([]*model.ConfigRevision)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockConfigRevisionRepositoryMockRecorder) List(ctx, instanceID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockConfigRevisionRepository)(nil).List), ctx, instanceID, params)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: EffiPlat/backend/internal/repository (interfaces: DeploymentRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_deployment_repository.go -package=mocks EffiPlat/backend/internal/repository DeploymentRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "EffiPlat/backend/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeploymentRepository is a mock of DeploymentRepository interface.
+type MockDeploymentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeploymentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDeploymentRepositoryMockRecorder is the mock recorder for MockDeploymentRepository.
+type MockDeploymentRepositoryMockRecorder struct {
+	mock *MockDeploymentRepository
+}
+
+// NewMockDeploymentRepository creates a new mock instance.
+func NewMockDeploymentRepository(ctrl *gomock.Controller) *MockDeploymentRepository {
+	mock := &MockDeploymentRepository{ctrl: ctrl}
+	mock.recorder = &MockDeploymentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeploymentRepository) EXPECT() *MockDeploymentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDeploymentRepository) Create(ctx context.Context, deployment *model.Deployment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, deployment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeploymentRepositoryMockRecorder) Create(ctx, deployment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeploymentRepository)(nil).Create), ctx, deployment)
+}
+
+// List mocks base method.
+func (m *MockDeploymentRepository) List(ctx context.Context, params *model.DeploymentListParams) ([]*model.Deployment, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, params)
+	ret0, _ := ret[0].([]*model.Deployment)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockDeploymentRepositoryMockRecorder) List(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDeploymentRepository)(nil).List), ctx, params)
+}
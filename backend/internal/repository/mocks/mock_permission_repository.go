@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: EffiPlat/backend/internal/repository (interfaces: PermissionRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_permission_repository.go -package=mocks EffiPlat/backend/internal/repository PermissionRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "EffiPlat/backend/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPermissionRepository is a mock of PermissionRepository interface.
+type MockPermissionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPermissionRepositoryMockRecorder is the mock recorder for MockPermissionRepository.
+type MockPermissionRepositoryMockRecorder struct {
+	mock *MockPermissionRepository
+}
+
+// NewMockPermissionRepository creates a new mock instance.
+func NewMockPermissionRepository(ctrl *gomock.Controller) *MockPermissionRepository {
+	mock := &MockPermissionRepository{ctrl: ctrl}
+	mock.recorder = &MockPermissionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermissionRepository) EXPECT() *MockPermissionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreatePermission mocks base method.
+func (m *MockPermissionRepository) CreatePermission(ctx context.Context, permission *model.Permission) (*model.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePermission", ctx, permission)
+	ret0, _ := ret[0].(*model.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePermission indicates an expected call of CreatePermission.
+func (mr *MockPermissionRepositoryMockRecorder) CreatePermission(ctx, permission any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePermission", reflect.TypeOf((*MockPermissionRepository)(nil).CreatePermission), ctx, permission)
+}
+
+// DeletePermission mocks base method.
+func (m *MockPermissionRepository) DeletePermission(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePermission", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePermission indicates an expected call of DeletePermission.
+func (mr *MockPermissionRepositoryMockRecorder) DeletePermission(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePermission", reflect.TypeOf((*MockPermissionRepository)(nil).DeletePermission), ctx, id)
+}
+
+// GetPermissionByID mocks base method.
+func (m *MockPermissionRepository) GetPermissionByID(ctx context.Context, id uint) (*model.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissionByID", ctx, id)
+	ret0, _ := ret[0].(*model.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissionByID indicates an expected call of GetPermissionByID.
+func (mr *MockPermissionRepositoryMockRecorder) GetPermissionByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissionByID", reflect.TypeOf((*MockPermissionRepository)(nil).GetPermissionByID), ctx, id)
+}
+
+// GetPermissionsByIDs mocks base method.
+func (m *MockPermissionRepository) GetPermissionsByIDs(ctx context.Context, ids []uint) ([]model.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissionsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]model.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissionsByIDs indicates an expected call of GetPermissionsByIDs.
+func (mr *MockPermissionRepositoryMockRecorder) GetPermissionsByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissionsByIDs", reflect.TypeOf((*MockPermissionRepository)(nil).GetPermissionsByIDs), ctx, ids)
+}
+
+// ListPermissions mocks base method.
+func (m *MockPermissionRepository) ListPermissions(ctx context.Context, params model.PermissionListParams) ([]model.Permission, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPermissions", ctx, params)
+	ret0, _ := ret[0].([]model.Permission)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPermissions indicates an expected call of ListPermissions.
+func (mr *MockPermissionRepositoryMockRecorder) ListPermissions(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPermissions", reflect.TypeOf((*MockPermissionRepository)(nil).ListPermissions), ctx, params)
+}
+
+// UpdatePermission mocks base method.
+func (m *MockPermissionRepository) UpdatePermission(ctx context.Context, id uint, permission *model.Permission) (*model.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePermission", ctx, id, permission)
+	ret0, _ := ret[0].(*model.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePermission indicates an expected call of UpdatePermission.
+func (mr *MockPermissionRepositoryMockRecorder) UpdatePermission(ctx, id, permission any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePermission", reflect.TypeOf((*MockPermissionRepository)(nil).UpdatePermission), ctx, id, permission)
+}
+
+// UserHasPermission mocks base method.
+func (m *MockPermissionRepository) UserHasPermission(ctx context.Context, userID uint, name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserHasPermission", ctx, userID, name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserHasPermission indicates an expected call of UserHasPermission.
+func (mr *MockPermissionRepositoryMockRecorder) UserHasPermission(ctx, userID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserHasPermission", reflect.TypeOf((*MockPermissionRepository)(nil).UserHasPermission), ctx, userID, name)
+}
@@ -130,6 +130,50 @@ func (mr *MockResponsibilityGroupRepositoryMockRecorder) List(ctx, params any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockResponsibilityGroupRepository)(nil).List), ctx, params)
 }
 
+// ListMembers mocks base method.
+func (m *MockResponsibilityGroupRepository) ListMembers(ctx context.Context, groupID uint) ([]model.ResponsibilityGroupMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, groupID)
+	ret0, _ := ret[0].([]model.ResponsibilityGroupMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockResponsibilityGroupRepositoryMockRecorder) ListMembers(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockResponsibilityGroupRepository)(nil).ListMembers), ctx, groupID)
+}
+
+// ListMembershipsByUser mocks base method.
+func (m *MockResponsibilityGroupRepository) ListMembershipsByUser(ctx context.Context, userID uint) ([]model.ResponsibilityGroupMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembershipsByUser", ctx, userID)
+	ret0, _ := ret[0].([]model.ResponsibilityGroupMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembershipsByUser indicates an expected call of ListMembershipsByUser.
+func (mr *MockResponsibilityGroupRepositoryMockRecorder) ListMembershipsByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembershipsByUser", reflect.TypeOf((*MockResponsibilityGroupRepository)(nil).ListMembershipsByUser), ctx, userID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockResponsibilityGroupRepository) RemoveMember(ctx context.Context, groupID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, groupID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockResponsibilityGroupRepositoryMockRecorder) RemoveMember(ctx, groupID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockResponsibilityGroupRepository)(nil).RemoveMember), ctx, groupID, userID)
+}
+
 // RemoveResponsibilityFromGroup mocks base method.
 func (m *MockResponsibilityGroupRepository) RemoveResponsibilityFromGroup(ctx context.Context, groupID, responsibilityID uint) error {
 	m.ctrl.T.Helper()
@@ -158,6 +202,20 @@ func (mr *MockResponsibilityGroupRepositoryMockRecorder) ReplaceResponsibilities
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceResponsibilitiesForGroup", reflect.TypeOf((*MockResponsibilityGroupRepository)(nil).ReplaceResponsibilitiesForGroup), ctx, groupID, responsibilityIDs)
 }
 
+// SetMember mocks base method.
+func (m *MockResponsibilityGroupRepository) SetMember(ctx context.Context, member *model.ResponsibilityGroupMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMember", ctx, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMember indicates an expected call of SetMember.
+func (mr *MockResponsibilityGroupRepositoryMockRecorder) SetMember(ctx, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMember", reflect.TypeOf((*MockResponsibilityGroupRepository)(nil).SetMember), ctx, member)
+}
+
 // Update mocks base method.
 func (m *MockResponsibilityGroupRepository) Update(ctx context.Context, group *model.ResponsibilityGroup, responsibilityIDs *[]uint) (*model.ResponsibilityGroup, error) {
 	m.ctrl.T.Helper()
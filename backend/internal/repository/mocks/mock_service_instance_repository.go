@@ -116,6 +116,21 @@ func (mr *MockServiceInstanceRepositoryMockRecorder) List(ctx, params any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockServiceInstanceRepository)(nil).List), ctx, params)
 }
 
+// ListByEnvironmentID mocks base method.
+func (m *MockServiceInstanceRepository) ListByEnvironmentID(ctx context.Context, environmentID uint) ([]*model.ServiceInstance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByEnvironmentID", ctx, environmentID)
+	ret0, _ := ret[0].([]*model.ServiceInstance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByEnvironmentID indicates an expected call of ListByEnvironmentID.
+func (mr *MockServiceInstanceRepositoryMockRecorder) ListByEnvironmentID(ctx, environmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByEnvironmentID", reflect.TypeOf((*MockServiceInstanceRepository)(nil).ListByEnvironmentID), ctx, environmentID)
+}
+
 // Update mocks base method.
 func (m *MockServiceInstanceRepository) Update(ctx context.Context, instance *model.ServiceInstance) error {
 	m.ctrl.T.Helper()
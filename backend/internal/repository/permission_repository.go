@@ -19,6 +19,8 @@ type PermissionRepository interface {
 	GetPermissionsByIDs(ctx context.Context, ids []uint) ([]model.Permission, error)
 	UpdatePermission(ctx context.Context, id uint, permission *model.Permission) (*model.Permission, error)
 	DeletePermission(ctx context.Context, id uint) error
+	// UserHasPermission reports whether any of the user's roles grants the named permission.
+	UserHasPermission(ctx context.Context, userID uint, name string) (bool, error)
 	// TODO: Add methods for associating/disassociating permissions with roles if needed at repository level
 }
 
@@ -96,6 +98,20 @@ func (r *PermissionRepositoryImpl) GetPermissionsByIDs(ctx context.Context, ids
 	return permissions, nil
 }
 
+func (r *PermissionRepositoryImpl) UserHasPermission(ctx context.Context, userID uint, name string) (bool, error) {
+	r.logger.Debug("PermissionRepository: UserHasPermission", zap.Uint("userID", userID), zap.String("permission", name))
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN user_roles ON user_roles.role_id = role_permissions.role_id").
+		Where("user_roles.user_id = ? AND permissions.name = ?", userID, name).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check user permission: %w", err)
+	}
+	return count > 0, nil
+}
+
 func (r *PermissionRepositoryImpl) UpdatePermission(ctx context.Context, id uint, permission *model.Permission) (*model.Permission, error) {
 	r.logger.Info("PermissionRepository: UpdatePermission", zap.Uint("id", id), zap.String("newName", permission.Name))
 	var existing model.Permission
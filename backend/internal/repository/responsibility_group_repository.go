@@ -22,6 +22,12 @@ type ResponsibilityGroupRepository interface {
 	ReplaceResponsibilitiesForGroup(ctx context.Context, groupID uint, responsibilityIDs []uint) error // Atomically replace all responsibilities for a group
 	GetResponsibilitiesForGroup(ctx context.Context, groupID uint) ([]model.Responsibility, error)
 	// RemoveAllResponsibilitiesFromGroup(ctx context.Context, groupID uint) error // Useful for updates
+
+	// Methods for managing the users who belong to a group
+	ListMembers(ctx context.Context, groupID uint) ([]model.ResponsibilityGroupMember, error) // Ordered by user ID, users preloaded
+	SetMember(ctx context.Context, member *model.ResponsibilityGroupMember) error             // Adds or updates; a primary (or backup) member demotes the others
+	RemoveMember(ctx context.Context, groupID uint, userID uint) error
+	ListMembershipsByUser(ctx context.Context, userID uint) ([]model.ResponsibilityGroupMember, error) // Ordered by group ID
 }
 
 /*
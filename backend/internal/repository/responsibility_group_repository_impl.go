@@ -199,6 +199,12 @@ func (r *gormResponsibilityGroupRepository) Delete(ctx context.Context, id uint)
 			return err
 		}
 
+		// Remove the group's members
+		if err := tx.Where("group_id = ?", id).Delete(&model.ResponsibilityGroupMember{}).Error; err != nil {
+			r.logger.Error("GORM: Failed to remove members of group", zap.Uint("id", id), zap.Error(err))
+			return err
+		}
+
 		// Delete the group itself
 		if err := tx.Delete(&model.ResponsibilityGroup{}, id).Error; err != nil {
 			r.logger.Error("GORM: Failed to delete responsibility group after clearing associations", zap.Uint("id", id), zap.Error(err))
@@ -291,3 +297,71 @@ func (r *gormResponsibilityGroupRepository) ReplaceResponsibilitiesForGroup(ctx
 		return nil
 	})
 }
+
+func (r *gormResponsibilityGroupRepository) ListMembers(ctx context.Context, groupID uint) ([]model.ResponsibilityGroupMember, error) {
+	r.logger.Debug("GORM: Listing members of group", zap.Uint("groupID", groupID))
+	var members []model.ResponsibilityGroupMember
+	if err := r.db.WithContext(ctx).Preload("User").Where("group_id = ?", groupID).Order("user_id ASC").Find(&members).Error; err != nil {
+		r.logger.Error("GORM: Failed to list members of group", zap.Uint("groupID", groupID), zap.Error(err))
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *gormResponsibilityGroupRepository) SetMember(ctx context.Context, member *model.ResponsibilityGroupMember) error {
+	r.logger.Debug("GORM: Setting group member", zap.Uint("groupID", member.GroupID), zap.Uint("userID", member.UserID), zap.Bool("isPrimary", member.IsPrimary), zap.Bool("isBackup", member.IsBackup))
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The user must exist; the caller has already checked the group.
+		if err := tx.Select("id").First(&model.User{}, member.UserID).Error; err != nil {
+			return err
+		}
+		if member.IsPrimary {
+			if err := tx.Model(&model.ResponsibilityGroupMember{}).
+				Where("group_id = ? AND user_id <> ?", member.GroupID, member.UserID).
+				Update("is_primary", false).Error; err != nil {
+				return err
+			}
+		}
+		if member.IsBackup {
+			if err := tx.Model(&model.ResponsibilityGroupMember{}).
+				Where("group_id = ? AND user_id <> ?", member.GroupID, member.UserID).
+				Update("is_backup", false).Error; err != nil {
+				return err
+			}
+		}
+		var count int64
+		existing := tx.Model(&model.ResponsibilityGroupMember{}).Where("group_id = ? AND user_id = ?", member.GroupID, member.UserID)
+		if err := existing.Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return tx.Omit("User").Create(member).Error
+		}
+		return tx.Model(&model.ResponsibilityGroupMember{}).
+			Where("group_id = ? AND user_id = ?", member.GroupID, member.UserID).
+			Updates(map[string]interface{}{"is_primary": member.IsPrimary, "is_backup": member.IsBackup}).Error
+	})
+}
+
+func (r *gormResponsibilityGroupRepository) RemoveMember(ctx context.Context, groupID uint, userID uint) error {
+	r.logger.Debug("GORM: Removing group member", zap.Uint("groupID", groupID), zap.Uint("userID", userID))
+	result := r.db.WithContext(ctx).Where("group_id = ? AND user_id = ?", groupID, userID).Delete(&model.ResponsibilityGroupMember{})
+	if result.Error != nil {
+		r.logger.Error("GORM: Failed to remove group member", zap.Error(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *gormResponsibilityGroupRepository) ListMembershipsByUser(ctx context.Context, userID uint) ([]model.ResponsibilityGroupMember, error) {
+	r.logger.Debug("GORM: Listing group memberships of user", zap.Uint("userID", userID))
+	var members []model.ResponsibilityGroupMember
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("group_id ASC").Find(&members).Error; err != nil {
+		r.logger.Error("GORM: Failed to list group memberships of user", zap.Uint("userID", userID), zap.Error(err))
+		return nil, err
+	}
+	return members, nil
+}
@@ -37,6 +37,8 @@ type ServiceInstanceRepository interface {
 	Delete(ctx context.Context, id uint) error
 	// CheckExists checks if a service instance with the given serviceId, environmentId, and version already exists.
 	CheckExists(ctx context.Context, serviceID, environmentID uint, version string, excludeID uint) (bool, error)
+	// ListByEnvironmentID returns every service instance in an environment, ordered by ID.
+	ListByEnvironmentID(ctx context.Context, environmentID uint) ([]*model.ServiceInstance, error)
 }
 
 // serviceInstanceRepositoryImpl implements ServiceInstanceRepository.
@@ -75,6 +77,17 @@ func (r *serviceInstanceRepositoryImpl) GetByID(ctx context.Context, id uint) (*
 	return &instance, nil
 }
 
+// ListByEnvironmentID returns every service instance in an environment, ordered by ID.
+func (r *serviceInstanceRepositoryImpl) ListByEnvironmentID(ctx context.Context, environmentID uint) ([]*model.ServiceInstance, error) {
+	var instances []*model.ServiceInstance
+	r.logger.Debug("Listing service instances by environment", zap.Uint("environmentID", environmentID))
+	if err := r.db.WithContext(ctx).Where("environment_id = ?", environmentID).Order("id ASC").Find(&instances).Error; err != nil {
+		r.logger.Error("Failed to list service instances by environment", zap.Uint("environmentID", environmentID), zap.Error(err))
+		return nil, fmt.Errorf("repository.ListByEnvironmentID: %w", err)
+	}
+	return instances, nil
+}
+
 // List retrieves a list of service instances based on parameters.
 func (r *serviceInstanceRepositoryImpl) List(ctx context.Context, params *ListServiceInstancesParams) ([]*model.ServiceInstance, int64, error) {
 	var instances []*model.ServiceInstance
@@ -1,3 +1,4 @@
+//go:generate mockgen -destination=mocks/mock_user_repository.go -package=mocks EffiPlat/backend/internal/repository UserRepository
 package repository
 
 import (
@@ -5,6 +6,7 @@ import (
 	"EffiPlat/backend/internal/utils"
 	"context"
 	"errors"
+	"strings"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -28,6 +30,9 @@ type UserRepository interface {
 	FindRoleByID(ctx context.Context, id uint) (*model.Role, error)
 	AssignRolesToUser(ctx context.Context, userID uint, roleIDs []uint) error
 	RemoveRolesFromUser(ctx context.Context, userID uint, roleIDs []uint) error
+	// FindByMentionHandles returns the users whose email, or the part of it before "@",
+	// equals one of handles (case-insensitive).
+	FindByMentionHandles(ctx context.Context, handles []string) ([]model.User, error)
 }
 
 // UserRepositoryImpl implements the UserRepository interface.
@@ -115,6 +120,36 @@ func (r *UserRepositoryImpl) FindByID(ctx context.Context, id uint) (*model.User
 	return &user, nil
 }
 
+// FindByMentionHandles retrieves the users an @mention may refer to.
+func (r *UserRepositoryImpl) FindByMentionHandles(ctx context.Context, handles []string) ([]model.User, error) {
+	if len(handles) == 0 {
+		return nil, nil
+	}
+	wanted := make(map[string]bool, len(handles))
+	cond := r.db.WithContext(ctx)
+	for _, handle := range handles {
+		handle = strings.ToLower(handle)
+		wanted[handle] = true
+		cond = cond.Or("LOWER(email) = ?", handle).Or("LOWER(email) LIKE ?", handle+"@%")
+	}
+	var candidates []model.User
+	if err := r.db.WithContext(ctx).Where(cond).Order("id ASC").Find(&candidates).Error; err != nil {
+		r.logger.Error("Failed to find users by mention handles", zap.Strings("handles", handles), zap.Error(err))
+		return nil, err
+	}
+
+	// LIKE treats "_" as a wildcard, so keep only exact matches
+	users := make([]model.User, 0, len(candidates))
+	for _, user := range candidates {
+		email := strings.ToLower(user.Email)
+		local, _, _ := strings.Cut(email, "@")
+		if wanted[email] || wanted[local] {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
 // Create inserts a new user record into the database.
 // It handles assigning roles within a transaction.
 func (r *UserRepositoryImpl) Create(ctx context.Context, user *model.User, roleIDs []uint) (*model.User, error) {
@@ -9,6 +9,14 @@ import (
 func auditLogRoutes(rg *gin.RouterGroup, auditLogHdlr *handler.AuditLogHandler) {
 	{
 		rg.GET("", auditLogHdlr.GetLogs)            // GET /api/v1/audit-logs
+		rg.GET("/pipeline", auditLogHdlr.GetPipelineStats) // GET /api/v1/audit-logs/pipeline
+		rg.GET("/changes", auditLogHdlr.GetChanges)         // GET /api/v1/audit-logs/changes
+		rg.GET("/export", auditLogHdlr.ExportLogs)          // GET /api/v1/audit-logs/export
+		rg.GET("/stream", auditLogHdlr.StreamLogs)          // GET /api/v1/audit-logs/stream (Server-Sent Events)
+		rg.GET("/verify", auditLogHdlr.VerifyChain)         // GET /api/v1/audit-logs/verify
+		rg.GET("/archives", auditLogHdlr.ListArchives)                   // GET /api/v1/audit-logs/archives
+		rg.POST("/archives/:id/import", auditLogHdlr.ImportArchive)      // POST /api/v1/audit-logs/archives/{id}/import
+		rg.DELETE("/archives/:id/import", auditLogHdlr.UnloadArchive)    // DELETE /api/v1/audit-logs/archives/{id}/import
 		rg.GET("/:id", auditLogHdlr.GetLogByID)     // GET /api/v1/audit-logs/{id}
 	}
 }
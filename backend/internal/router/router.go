@@ -3,6 +3,7 @@ package router
 import (
 	"EffiPlat/backend/internal/handler" // Unified import path for all handlers
 	"EffiPlat/backend/internal/middleware"           // Corrected import path
+	"EffiPlat/backend/internal/model"
 	"EffiPlat/backend/internal/service"              // 导入service包用于审计日志服务
 	
 	"go.uber.org/zap" // 导入zap日志库
@@ -40,8 +41,15 @@ func SetupRouter(
 	serviceInstanceHandler *handler.ServiceInstanceHandler,
 	businessHandler *handler.BusinessHandler,
 	bugHandler *handler.BugHandler,
+	bugAssignmentHandler *handler.BugAssignmentHandler,
+	bugSLAHandler *handler.BugSLAHandler,
+	attachmentHandler *handler.AttachmentHandler,
+	deploymentHandler *handler.DeploymentHandler,
+	configRevisionHandler *handler.ConfigRevisionHandler,
+	entityHistoryHandler *handler.EntityHistoryHandler,
 	auditLogHandler *handler.AuditLogHandler, // 添加审计日志处理器
 	auditLogService service.AuditLogService, // 添加审计日志服务（用于中间件）
+	readAuditor *middleware.ReadAuditor, // 读取审计策略（用于中间件）
 	jwtKey []byte,
 ) *gin.Engine {
 	r := gin.Default()
@@ -61,6 +69,9 @@ func SetupRouter(
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// 客户端信息供服务层的审计日志使用（如登录）
+	r.Use(middleware.ClientInfoMiddleware())
+
 	// Logger middleware (Gin's default logger is quite good)
 	// For custom logging, you can add r.Use(middleware.LoggingMiddleware(logger)) here if you have one
 
@@ -90,8 +101,9 @@ func SetupRouter(
 	jwtMiddleware := middleware.JWTAuthMiddleware(jwtKey)
 	// 创建一个简单的zap logger用于审计中间件
 	logger, _ := zap.NewProduction()
-	auditLogMiddleware := middleware.AuditLogMiddleware(auditLogService, logger)
-	apiV1Authenticated.Use(jwtMiddleware, auditLogMiddleware)
+	auditLogMiddleware := middleware.AuditLogMiddleware(auditLogService, readAuditor, logger)
+	// 审计中间件在JWT之前，令牌无效而被拒绝的请求也会记录
+	apiV1Authenticated.Use(auditLogMiddleware, jwtMiddleware)
 	{
 		// Authenticated Auth routes (me, logout)
 		authAuth := apiV1Authenticated.Group("/auth")
@@ -127,31 +139,62 @@ func SetupRouter(
 		responsibilityGroupRoutes(apiV1Authenticated.Group("/responsibility-groups"), responsibilityGroupHandler)
 
 		// Environment routes
-		environmentRoutes(apiV1Authenticated.Group("/environments"), environmentHandler)
+		environmentRg := apiV1Authenticated.Group("/environments")
+		entityHistoryRoutes(environmentRg, entityHistoryHandler, model.EntityResourceEnvironment, "id")
+		environmentRoutes(environmentRg, environmentHandler)
+		environmentRg.GET("/:id/deployments", deploymentHandler.ListEnvironmentDeployments) // GET /api/v1/environments/{id}/deployments
 
 		// Asset routes
-		assetRoutes(apiV1Authenticated.Group("/assets"), assetHandler)
+		assetRg := apiV1Authenticated.Group("/assets")
+		entityHistoryRoutes(assetRg, entityHistoryHandler, model.EntityResourceAsset, "id")
+		assetRoutes(assetRg, assetHandler)
 
 		// ServiceType and Service routes
 		serviceTypeRoutes(apiV1Authenticated.Group("/service-types"), serviceHandler)
-		serviceRoutes(apiV1Authenticated.Group("/services"), serviceHandler)
+		serviceRg := apiV1Authenticated.Group("/services")
+		entityHistoryRoutes(serviceRg, entityHistoryHandler, model.EntityResourceService, "id")
+		serviceRoutes(serviceRg, serviceHandler)
+		ownerAttachmentRoutes(serviceRg, attachmentHandler, model.AttachmentOwnerService, "id")
 
 		// Service Instance routes
 		serviceInstanceGroup := apiV1Authenticated.Group("/service-instances")
+		entityHistoryRoutes(serviceInstanceGroup, entityHistoryHandler, model.EntityResourceServiceInstance, "instanceId")
 		{
 			serviceInstanceGroup.POST("", serviceInstanceHandler.CreateServiceInstance)
 			serviceInstanceGroup.GET("", serviceInstanceHandler.ListServiceInstances)
 			serviceInstanceGroup.GET("/:instanceId", serviceInstanceHandler.GetServiceInstance)
 			serviceInstanceGroup.PUT("/:instanceId", serviceInstanceHandler.UpdateServiceInstance)
 			serviceInstanceGroup.DELETE("/:instanceId", serviceInstanceHandler.DeleteServiceInstance)
+
+			// Deployment history
+			serviceInstanceGroup.POST("/:instanceId/deployments", deploymentHandler.ReportDeployment)
+			serviceInstanceGroup.GET("/:instanceId/deployments", deploymentHandler.ListInstanceDeployments)
+
+			// Versioned configuration
+			serviceInstanceGroup.GET("/:instanceId/config/revisions", configRevisionHandler.ListRevisions)
+			serviceInstanceGroup.GET("/:instanceId/config/revisions/:revision", configRevisionHandler.GetRevision)
+			serviceInstanceGroup.GET("/:instanceId/config/diff", configRevisionHandler.DiffRevisions)
+			serviceInstanceGroup.POST("/:instanceId/config/rollback/:revision", configRevisionHandler.Rollback)
+			serviceInstanceGroup.POST("/:instanceId/config/secrets/reveal", serviceInstanceHandler.RevealConfigSecrets)
+			serviceInstanceGroup.GET("/:instanceId/config/effective", serviceInstanceHandler.GetEffectiveConfig)
 		}
 
 		// Business routes
-		businessRoutes(apiV1Authenticated.Group("/businesses"), businessHandler)
+		businessRg := apiV1Authenticated.Group("/businesses")
+		entityHistoryRoutes(businessRg, entityHistoryHandler, model.EntityResourceBusiness, "businessId")
+		businessRoutes(businessRg, businessHandler)
+		ownerAttachmentRoutes(businessRg, attachmentHandler, model.AttachmentOwnerBusiness, "businessId")
 
 		// Bug routes
 		bugRg := apiV1Authenticated.Group("/bugs")
+		entityHistoryRoutes(bugRg, entityHistoryHandler, model.EntityResourceBug, "id")
 		bugRoutes(bugRg, bugHandler)
+		ownerAttachmentRoutes(bugRg, attachmentHandler, model.AttachmentOwnerBug, "id")
+		bugAssignmentRoutes(apiV1Authenticated.Group("/bug-assignment-rules"), bugAssignmentHandler)
+		bugSLARoutes(apiV1Authenticated.Group("/bug-sla"), bugSLAHandler)
+
+		// Attachment routes
+		attachmentRoutes(apiV1Authenticated.Group("/attachments"), attachmentHandler)
 
 		// Audit Log routes
 		auditLogRg := apiV1Authenticated.Group("/audit-logs")
@@ -251,6 +294,11 @@ func responsibilityGroupRoutes(rg *gin.RouterGroup, hdlr *handler.Responsibility
 		// Routes for managing responsibilities within a group
 		rg.POST("/:groupId/responsibilities/:responsibilityId", hdlr.AddResponsibilityToGroup)        // POST /api/v1/responsibility-groups/{groupId}/responsibilities/{responsibilityId}
 		rg.DELETE("/:groupId/responsibilities/:responsibilityId", hdlr.RemoveResponsibilityFromGroup) // DELETE /api/v1/responsibility-groups/{groupId}/responsibilities/{responsibilityId}
+
+		// Routes for managing the users who belong to a group
+		rg.GET("/:groupId/members", hdlr.ListGroupMembers)             // GET /api/v1/responsibility-groups/{groupId}/members
+		rg.PUT("/:groupId/members/:userId", hdlr.SetGroupMember)       // PUT /api/v1/responsibility-groups/{groupId}/members/{userId}
+		rg.DELETE("/:groupId/members/:userId", hdlr.RemoveGroupMember) // DELETE /api/v1/responsibility-groups/{groupId}/members/{userId}
 	}
 }
 
@@ -296,6 +344,8 @@ func serviceRoutes(rg *gin.RouterGroup, hdlr *handler.ServiceHandler) {
 		rg.GET("/:id", hdlr.GetServiceByID)   // GET /api/v1/services/{id}
 		rg.PUT("/:id", hdlr.UpdateService)    // PUT /api/v1/services/{id}
 		rg.DELETE("/:id", hdlr.DeleteService) // DELETE /api/v1/services/{id}
+
+		rg.POST("/:id/config-schema/validate", hdlr.ValidateServiceConfig) // POST /api/v1/services/{id}/config-schema/validate
 	}
 }
 
@@ -313,11 +363,73 @@ func businessRoutes(rg *gin.RouterGroup, businessHdlr *handler.BusinessHandler)
 // bugRoutes 注册bug管理相关的路由
 func bugRoutes(rg *gin.RouterGroup, bugHdlr *handler.BugHandler) {
 	{
-		rg.POST("", bugHdlr.CreateBug)       // POST /api/v1/bugs
-		rg.GET("", bugHdlr.ListBugs)         // GET /api/v1/bugs
-		rg.GET("/:id", bugHdlr.GetBugByID)   // GET /api/v1/bugs/{id}
-		rg.PUT("/:id", bugHdlr.UpdateBug)    // PUT /api/v1/bugs/{id}
-		rg.DELETE("/:id", bugHdlr.DeleteBug) // DELETE /api/v1/bugs/{id}
+		rg.POST("", bugHdlr.CreateBug)                                                // POST /api/v1/bugs
+		rg.GET("", bugHdlr.ListBugs)                                                  // GET /api/v1/bugs
+		rg.GET("/workflow", bugHdlr.GetBugWorkflow)                                   // GET /api/v1/bugs/workflow
+		rg.GET("/stats", bugHdlr.GetBugStats)                                         // GET /api/v1/bugs/stats
+		rg.POST("/similar", bugHdlr.FindSimilarBugs)                                  // POST /api/v1/bugs/similar
+		rg.POST("/bulk", bugHdlr.BulkUpdateBugs)                                      // POST /api/v1/bugs/bulk
+		rg.GET("/:id", bugHdlr.GetBugByID)                                            // GET /api/v1/bugs/{id}
+		rg.PUT("/:id", bugHdlr.UpdateBug)                                             // PUT /api/v1/bugs/{id}
+		rg.DELETE("/:id", bugHdlr.DeleteBug)                                          // DELETE /api/v1/bugs/{id}
+		rg.GET("/:id/transitions", bugHdlr.ListBugTransitions)                        // GET /api/v1/bugs/{id}/transitions
+		rg.GET("/:id/snapshot", bugHdlr.GetBugSnapshot)                               // GET /api/v1/bugs/{id}/snapshot
+		rg.GET("/:id/snapshot/diff", bugHdlr.DiffBugSnapshot)                         // GET /api/v1/bugs/{id}/snapshot/diff
+		rg.POST("/:id/comments", bugHdlr.AddBugComment)                               // POST /api/v1/bugs/{id}/comments
+		rg.GET("/:id/comments", bugHdlr.ListBugComments)                              // GET /api/v1/bugs/{id}/comments
+		rg.PUT("/:id/comments/:commentId", bugHdlr.UpdateBugComment)                  // PUT /api/v1/bugs/{id}/comments/{commentId}
+		rg.DELETE("/:id/comments/:commentId", bugHdlr.DeleteBugComment)               // DELETE /api/v1/bugs/{id}/comments/{commentId}
+		rg.GET("/:id/comments/:commentId/revisions", bugHdlr.ListBugCommentRevisions) // GET /api/v1/bugs/{id}/comments/{commentId}/revisions
+		rg.GET("/:id/activity", bugHdlr.GetBugActivity)                               // GET /api/v1/bugs/{id}/activity
+		rg.POST("/:id/relations", bugHdlr.AddBugRelation)                             // POST /api/v1/bugs/{id}/relations
+		rg.GET("/:id/relations", bugHdlr.ListBugRelations)                            // GET /api/v1/bugs/{id}/relations
+		rg.DELETE("/:id/relations/:relationId", bugHdlr.DeleteBugRelation)            // DELETE /api/v1/bugs/{id}/relations/{relationId}
+	}
+}
+
+
+// entityHistoryRoutes 注册CMDB实体的历史版本路由，并使 GET /{id}?asOf= 返回实体在该时间点的状态。
+// 中间件只作用于之后注册的路由，因此须在实体自身的路由之前调用。
+func entityHistoryRoutes(rg *gin.RouterGroup, hdlr *handler.EntityHistoryHandler, resource, idParam string) {
+	rg.Use(hdlr.AsOf(resource, idParam, rg.BasePath()+"/:"+idParam))
+	rg.GET("/:"+idParam+"/history", hdlr.ListVersions(resource, idParam)) // GET /api/v1/{resources}/{id}/history
+}
+
+// ownerAttachmentRoutes 注册某类记录（bug、业务、服务）下的附件路由
+func ownerAttachmentRoutes(rg *gin.RouterGroup, hdlr *handler.AttachmentHandler, ownerType model.AttachmentOwnerType, idParam string) {
+	rg.POST("/:"+idParam+"/attachments", hdlr.UploadAttachment(ownerType, idParam)) // POST /api/v1/{owners}/{id}/attachments
+	rg.GET("/:"+idParam+"/attachments", hdlr.ListAttachments(ownerType, idParam))   // GET /api/v1/{owners}/{id}/attachments
+}
+
+// attachmentRoutes 注册附件相关的路由
+func attachmentRoutes(rg *gin.RouterGroup, hdlr *handler.AttachmentHandler) {
+	{
+		rg.GET("/:id", hdlr.GetAttachment)               // GET /api/v1/attachments/{id}
+		rg.GET("/:id/download", hdlr.DownloadAttachment) // GET /api/v1/attachments/{id}/download
+		rg.DELETE("/:id", hdlr.DeleteAttachment)         // DELETE /api/v1/attachments/{id}
+	}
+}
+
+// bugAssignmentRoutes 注册Bug自动分配规则相关的路由
+func bugAssignmentRoutes(rg *gin.RouterGroup, hdlr *handler.BugAssignmentHandler) {
+	{
+		rg.POST("", hdlr.CreateRule)       // POST /api/v1/bug-assignment-rules
+		rg.GET("", hdlr.ListRules)         // GET /api/v1/bug-assignment-rules
+		rg.POST("/dry-run", hdlr.DryRun)   // POST /api/v1/bug-assignment-rules/dry-run
+		rg.GET("/:id", hdlr.GetRule)       // GET /api/v1/bug-assignment-rules/{id}
+		rg.PUT("/:id", hdlr.UpdateRule)    // PUT /api/v1/bug-assignment-rules/{id}
+		rg.DELETE("/:id", hdlr.DeleteRule) // DELETE /api/v1/bug-assignment-rules/{id}
+	}
+}
+
+
+// bugSLARoutes 注册Bug SLA策略相关的路由
+func bugSLARoutes(rg *gin.RouterGroup, hdlr *handler.BugSLAHandler) {
+	{
+		rg.GET("/policies", hdlr.ListPolicies)              // GET /api/v1/bug-sla/policies
+		rg.PUT("/policies/:priority", hdlr.UpsertPolicy)    // PUT /api/v1/bug-sla/policies/{priority}
+		rg.DELETE("/policies/:priority", hdlr.DeletePolicy) // DELETE /api/v1/bug-sla/policies/{priority}
+		rg.POST("/check", hdlr.CheckBreaches)               // POST /api/v1/bug-sla/check
 	}
 }
 
@@ -0,0 +1,125 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	uploaderToken := GetAuthTokenForTest(t, app.Router, app.DB)
+	otherToken := GetAuthTokenForTest(t, app.Router, app.DB)
+
+	upload := func(token, path, fileName string, content []byte) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		require.NoError(t, mw.WriteField("note", "ignored"))
+		part, err := mw.CreateFormFile("file", fileName)
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+		req, _ := http.NewRequest(http.MethodPost, path, &buf)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder, v interface{}) {
+		resp := model.SuccessResponse{Data: v}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+
+	bug := model.Bug{Title: "Crash on save", Status: model.BugStatusOpen, Priority: model.BugPriorityHigh}
+	require.NoError(t, app.DB.Create(&bug).Error)
+	business := model.Business{Name: fmt.Sprintf("attach-biz-%d", time.Now().UnixNano())}
+	require.NoError(t, app.DB.Create(&business).Error)
+	bugAttachments := fmt.Sprintf("/api/v1/bugs/%d/attachments", bug.ID)
+
+	logContent := []byte(fmt.Sprintf("panic: nil map at %d\ngoroutine 1 [running]\n", time.Now().UnixNano()))
+	w := upload(uploaderToken, bugAttachments, "crash.log", logContent)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var attachment model.Attachment
+	decode(w, &attachment)
+	assert.Equal(t, "crash.log", attachment.FileName)
+	assert.Equal(t, "text/plain", attachment.ContentType)
+	assert.Equal(t, int64(len(logContent)), attachment.Size)
+	attachmentPath := fmt.Sprintf("/api/v1/attachments/%d", attachment.ID)
+
+	t.Run("Same file again is not stored twice", func(t *testing.T) {
+		w := upload(uploaderToken, bugAttachments, "crash-copy.log", logContent)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var again model.Attachment
+		decode(w, &again)
+		assert.Equal(t, attachment.ID, again.ID)
+
+		w = DoRequestForTest(t, app.Router, uploaderToken, http.MethodGet, bugAttachments, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var list []model.Attachment
+		decode(w, &list)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("Business attachments use the business route", func(t *testing.T) {
+		w := upload(uploaderToken, fmt.Sprintf("/api/v1/businesses/%d/attachments", business.ID), "design.txt", []byte("# Design\n"))
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var designDoc model.Attachment
+		decode(w, &designDoc)
+		assert.Equal(t, model.AttachmentOwnerBusiness, designDoc.OwnerType)
+		assert.Equal(t, business.ID, designDoc.OwnerID)
+	})
+
+	t.Run("Limits are enforced", func(t *testing.T) {
+		w := upload(uploaderToken, bugAttachments, "tool.exe", []byte("MZ\x90\x00\x03\x00\x00\x00binary"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code, w.Body.String())
+
+		w = upload(uploaderToken, bugAttachments, "huge.log", []byte(strings.Repeat("x", 64<<10+1)))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+
+		w = DoRequestForTest(t, app.Router, uploaderToken, http.MethodPost, bugAttachments, map[string]interface{}{"file": "x"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+		w = upload(uploaderToken, "/api/v1/bugs/999999/attachments", "crash.log", logContent)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+
+	t.Run("Download is permission-checked", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, uploaderToken, http.MethodGet, attachmentPath+"/download", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, logContent, w.Body.Bytes())
+		assert.Equal(t, `attachment; filename=crash.log`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+
+		w = DoRequestForTest(t, app.Router, otherToken, http.MethodGet, attachmentPath+"/download", nil)
+		assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+		grantPermissionForTest(t, app, otherToken, model.PermissionDownloadBugAttachments)
+		w = DoRequestForTest(t, app.Router, otherToken, http.MethodGet, attachmentPath+"/download", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, logContent, w.Body.Bytes())
+	})
+
+	t.Run("Only the uploader may delete", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, otherToken, http.MethodDelete, attachmentPath, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+		w = DoRequestForTest(t, app.Router, uploaderToken, http.MethodDelete, attachmentPath, nil)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+		w = DoRequestForTest(t, app.Router, uploaderToken, http.MethodGet, attachmentPath, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+}
@@ -0,0 +1,42 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditArchiveRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+
+	t.Run("ListArchives", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs/archives", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data []model.AuditLogArchive `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.NotNil(t, resp.Data)
+	})
+
+	t.Run("ImportUnknownArchive", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/audit-logs/archives/999999/import", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+
+	t.Run("UnloadUnknownArchive", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodDelete, "/api/v1/audit-logs/archives/999999/import", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+
+	t.Run("InvalidArchiveID", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/audit-logs/archives/abc/import", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+}
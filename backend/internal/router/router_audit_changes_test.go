@@ -0,0 +1,63 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditChangesRoute(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	suffix := time.Now().UnixNano()
+
+	w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{"title": fmt.Sprintf("Diffed bug %d", suffix), "priority": "LOW"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var bug model.BugResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+
+	w = DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("/api/v1/bugs/%d", bug.ID), map[string]interface{}{"priority": "HIGH"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, app.AuditLogService.Flush(context.Background()))
+
+	t.Run("UpdateLogHasDiff", func(t *testing.T) {
+		var logs []model.AuditLog
+		require.NoError(t, app.DB.Where("resource = ? AND resource_id = ? AND action = ?", "BUG", bug.ID, "UPDATE").Find(&logs).Error)
+		require.Len(t, logs, 1)
+		assert.Contains(t, logs[0].Details, `"diff":[`)
+		assert.Contains(t, logs[0].Details, `{"path":"priority","op":"changed","oldValue":"LOW","newValue":"HIGH"}`)
+	})
+
+	t.Run("QueryByField", func(t *testing.T) {
+		today := time.Now().Format("2006-01-02")
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/audit-logs/changes?resource=bug&field=Priority&resourceId=%d&startDate=%s", bug.ID, today), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				Items []model.AuditLogChange `json:"items"`
+				Total int64                  `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.EqualValues(t, 1, resp.Data.Total)
+		require.Len(t, resp.Data.Items, 1)
+		change := resp.Data.Items[0]
+		assert.Equal(t, "priority", change.Field)
+		assert.Equal(t, "LOW", change.OldValue)
+		assert.Equal(t, "HIGH", change.NewValue)
+		assert.NotZero(t, change.AuditLogID)
+	})
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs/changes?resourceId=abc", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+}
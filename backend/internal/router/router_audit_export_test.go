@@ -0,0 +1,112 @@
+package router
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditExportRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	marker := fmt.Sprintf("export-marker-%d", time.Now().UnixNano())
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		app.AuditLogService.Record(ctx, &model.AuditLog{
+			Action:    "UPDATE",
+			Resource:  "ASSET",
+			IPAddress: "192.0.2.10",
+			Details:   fmt.Sprintf(`{"note":"%s","n":%d}`, marker, i),
+		})
+	}
+	require.NoError(t, app.AuditLogService.Flush(ctx))
+
+	filter := "q=" + url.QueryEscape(marker) + "&ipAddress=192.0.2.10"
+
+	t.Run("CursorPages", func(t *testing.T) {
+		var seen []uint
+		cursor := ""
+		for pages := 0; pages < 10; pages++ {
+			w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs?"+filter+"&pageSize=2&sortBy=id&order=asc&cursor="+url.QueryEscape(cursor), nil)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var resp struct {
+				Data model.AuditLogCursorPage `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			for _, item := range resp.Data.Items {
+				seen = append(seen, item.ID)
+			}
+			if resp.Data.NextCursor == "" {
+				break
+			}
+			cursor = resp.Data.NextCursor
+		}
+		require.Len(t, seen, 5)
+		assert.IsIncreasing(t, seen)
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs?cursor=bogus", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	t.Run("InvalidSort", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs?sortBy=details", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	t.Run("ExportCSV", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs/export?"+filter, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), `attachment; filename="audit-logs-`)
+
+		records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 6)
+		assert.Equal(t, "id", records[0][0])
+		assert.Contains(t, records[1][12], marker)
+	})
+
+	t.Run("ExportJSONL", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs/export?format=jsonl&"+filter, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		scanner := bufio.NewScanner(strings.NewReader(w.Body.String()))
+		var lines int
+		for scanner.Scan() {
+			var log model.AuditLog
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &log))
+			assert.Equal(t, "192.0.2.10", log.IPAddress)
+			lines++
+		}
+		assert.Equal(t, 5, lines)
+	})
+
+	t.Run("ExportIsAudited", func(t *testing.T) {
+		require.NoError(t, app.AuditLogService.Flush(ctx))
+		var logs []model.AuditLog
+		require.NoError(t, app.DB.Where("action = ? AND resource = ? AND details LIKE ?", "EXPORT", "AUDIT_LOG", "%"+marker+"%").Find(&logs).Error)
+		require.Len(t, logs, 2)
+		assert.Contains(t, logs[0].Details, `"exported":5`)
+	})
+
+	t.Run("ExportUnknownFormat", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs/export?format=xlsx", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+}
@@ -1,6 +1,7 @@
 package router_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,9 +10,11 @@ import (
 	"time"
 
 	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/repository"
 	"EffiPlat/backend/internal/router"
 	"EffiPlat/backend/internal/utils"
 	
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 
 	"github.com/stretchr/testify/assert"
@@ -55,6 +58,7 @@ func TestAuditLogRoutes(t *testing.T) {
 
 // createTestAuditLogs 创建测试审计日志记录
 func createTestAuditLogs(t *testing.T, db *gorm.DB, userID uint) {
+	auditLogRepo := repository.NewAuditLogRepository(db, nil, zap.NewNop())
 	// 创建5条审计日志记录
 	for i := 1; i <= 5; i++ {
 		var action string
@@ -90,7 +94,8 @@ func createTestAuditLogs(t *testing.T, db *gorm.DB, userID uint) {
 			CreatedAt:  time.Now().Add(-time.Duration(i) * time.Hour), // 不同时间创建
 		}
 
-		err := db.Create(&auditLog).Error
+		// 经存储库写入，使测试数据接入哈希链
+		err := auditLogRepo.CreateLog(context.Background(), &auditLog)
 		require.NoError(t, err, "Failed to create test audit log")
 	}
 }
@@ -0,0 +1,116 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditOutcomeRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	suffix := time.Now().UnixNano()
+	email := fmt.Sprintf("audit_outcome_%d@example.com", suffix)
+	user, err := CreateTestUser(app.DB, email, "password123")
+	require.NoError(t, err)
+
+	login := func(email, password string) *httptest.ResponseRecorder {
+		return DoRequestForTest(t, app.Router, "", http.MethodPost, "/api/v1/auth/login", map[string]string{"email": email, "password": password})
+	}
+	findLogs := func(query string, args ...interface{}) []model.AuditLog {
+		require.NoError(t, app.AuditLogService.Flush(context.Background()))
+		var logs []model.AuditLog
+		require.NoError(t, app.DB.Where(query, args...).Order("id").Find(&logs).Error)
+		return logs
+	}
+
+	t.Run("FailedLogins", func(t *testing.T) {
+		unknown := fmt.Sprintf("nobody_%d@example.com", suffix)
+		require.Equal(t, http.StatusUnauthorized, login(unknown, "password123").Code)
+		require.Equal(t, http.StatusUnauthorized, login(email, "wrong-password").Code)
+
+		logs := findLogs("action = ? AND username = ?", "LOGIN", unknown)
+		require.Len(t, logs, 1)
+		assert.Equal(t, model.AuditOutcomeFailure, logs[0].Outcome)
+		assert.Equal(t, "unknown email", logs[0].Reason)
+		assert.Zero(t, logs[0].UserID)
+		assert.Contains(t, logs[0].Details, unknown)
+
+		logs = findLogs("action = ? AND user_id = ? AND outcome = ?", "LOGIN", user.ID, model.AuditOutcomeFailure)
+		require.Len(t, logs, 1)
+		assert.Equal(t, "invalid password", logs[0].Reason)
+		assert.Equal(t, "USER", logs[0].Resource)
+		assert.Equal(t, user.ID, logs[0].ResourceID)
+	})
+
+	var token string
+	t.Run("LoginAndLogout", func(t *testing.T) {
+		w := login(email, "password123")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				Token string `json:"token"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		token = resp.Data.Token
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/auth/logout", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		logs := findLogs("user_id = ? AND action IN ? AND outcome = ?", user.ID, []string{"LOGIN", "LOGOUT"}, model.AuditOutcomeSuccess)
+		require.Len(t, logs, 2)
+		assert.Equal(t, "LOGIN", logs[0].Action)
+		assert.Equal(t, "Test User", logs[0].Username)
+		assert.Equal(t, "LOGOUT", logs[1].Action)
+		assert.Equal(t, user.ID, logs[1].ResourceID)
+	})
+
+	t.Run("DeniedRequestWithInvalidToken", func(t *testing.T) {
+		bugID := uint(suffix % 1_000_000_000)
+		w := DoRequestForTest(t, app.Router, "not-a-token", http.MethodDelete, fmt.Sprintf("/api/v1/bugs/%d", bugID), nil)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+
+		logs := findLogs("resource = ? AND resource_id = ? AND action = ?", "BUG", bugID, "DELETE")
+		require.Len(t, logs, 1)
+		assert.Equal(t, model.AuditOutcomeDenied, logs[0].Outcome)
+		assert.Equal(t, "invalid token", logs[0].Reason)
+		assert.Equal(t, http.StatusUnauthorized, logs[0].StatusCode)
+		assert.Zero(t, logs[0].UserID)
+	})
+
+	t.Run("FailedRequest", func(t *testing.T) {
+		require.NotEmpty(t, token)
+		bugID := uint(suffix%1_000_000_000) + 1
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("/api/v1/bugs/%d", bugID), map[string]string{"title": "Missing"})
+		require.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+		logs := findLogs("resource = ? AND resource_id = ? AND action = ?", "BUG", bugID, "UPDATE")
+		require.Len(t, logs, 1)
+		assert.Equal(t, model.AuditOutcomeFailure, logs[0].Outcome)
+		assert.Equal(t, "Bug not found.", logs[0].Reason)
+		assert.Equal(t, http.StatusNotFound, logs[0].StatusCode)
+		assert.Equal(t, user.ID, logs[0].UserID)
+	})
+
+	t.Run("FilterByOutcome", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/audit-logs?outcome=denied&resource=BUG&resourceId=%d", uint(suffix%1_000_000_000)), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				Items []model.AuditLogResponse `json:"items"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data.Items, 1)
+		assert.Equal(t, model.AuditOutcomeDenied, resp.Data.Items[0].Outcome)
+	})
+}
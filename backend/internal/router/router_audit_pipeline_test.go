@@ -0,0 +1,79 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditPipelineRoute(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	suffix := time.Now().UnixNano()
+
+	w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/auth/me", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var me struct {
+		Data struct {
+			ID    uint   `json:"id"`
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &me))
+
+	w = DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{"title": fmt.Sprintf("Audited bug %d", suffix), "priority": "LOW"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var bug model.BugResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+
+	w = DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs/bulk", map[string]interface{}{"ids": []uint{bug.ID}, "update": map[string]interface{}{"priority": "HIGH"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	t.Run("ActionIsAttributedToTheCaller", func(t *testing.T) {
+		require.NoError(t, app.AuditLogService.Flush(context.Background()))
+
+		var logs []model.AuditLog
+		require.NoError(t, app.DB.Where("resource = ? AND resource_id = ? AND action = ?", "BUG", bug.ID, "UPDATE").Find(&logs).Error)
+		require.Len(t, logs, 1)
+		assert.Equal(t, me.Data.ID, logs[0].UserID)
+		assert.Equal(t, me.Data.Name, logs[0].Username)
+		assert.Contains(t, logs[0].Details, `"bulk":true`)
+	})
+
+	t.Run("PipelineStats", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs/pipeline", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data model.AuditPipelineStats `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Positive(t, resp.Data.Written)
+		assert.Positive(t, resp.Data.QueueCapacity)
+		assert.Zero(t, resp.Data.Dropped)
+		assert.Zero(t, resp.Data.WriteFailures)
+	})
+
+	t.Run("VerifyChain", func(t *testing.T) {
+		require.NoError(t, app.AuditLogService.Flush(context.Background()))
+
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs/verify", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data model.AuditChainVerification `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.True(t, resp.Data.Valid, w.Body.String())
+		assert.Nil(t, resp.Data.FirstBroken)
+		assert.Positive(t, resp.Data.Checked)
+		assert.Len(t, resp.Data.HeadHash, 64)
+	})
+}
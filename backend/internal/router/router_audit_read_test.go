@@ -0,0 +1,89 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAuditPolicy(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	suffix := time.Now().UnixNano()
+
+	readLogs := func(path string) []model.AuditLog {
+		app.ReadAuditor.Flush()
+		require.NoError(t, app.AuditLogService.Flush(context.Background()))
+		var logs []model.AuditLog
+		require.NoError(t, app.DB.Where("action = ? AND details LIKE ?", "READ", `%"requestPath":"`+path+`"%`).Order("id").Find(&logs).Error)
+		return logs
+	}
+
+	env := model.Environment{Name: fmt.Sprintf("Read audit %d", suffix), Slug: fmt.Sprintf("read-audit-%d", suffix)}
+	require.NoError(t, app.DB.Create(&env).Error)
+
+	t.Run("SensitiveReadsAreAggregated", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/environments/%d", env.ID)
+		for i := 0; i < 3; i++ {
+			require.Equal(t, http.StatusOK, DoRequestForTest(t, app.Router, token, http.MethodGet, path, nil).Code)
+		}
+
+		logs := readLogs(path)
+		require.Len(t, logs, 2, "the first read is logged, the repeats are summarised")
+		for _, log := range logs {
+			assert.Equal(t, "ENVIRONMENT", log.Resource)
+			assert.Equal(t, env.ID, log.ResourceID)
+			assert.Contains(t, log.Details, `"fields":["config"]`)
+		}
+		assert.NotContains(t, logs[0].Details, "aggregated")
+		var summary map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(logs[1].Details), &summary))
+		assert.Equal(t, true, summary["aggregated"])
+		assert.EqualValues(t, 2, summary["repeatedReads"])
+		assert.NotEmpty(t, logs[1].Username)
+	})
+
+	t.Run("OrdinaryReadsAreNotLogged", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/bugs/workflow", nil).Code)
+		require.Equal(t, http.StatusNotFound, DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/bugs/999999", nil).Code)
+		assert.Empty(t, readLogs("/api/v1/bugs/workflow"))
+		assert.Empty(t, readLogs("/api/v1/bugs/999999"))
+	})
+
+	t.Run("AuditLogReadsAreLogged", func(t *testing.T) {
+		query := fmt.Sprintf("read-audit-%d", suffix)
+		require.Equal(t, http.StatusOK, DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs?q="+query, nil).Code)
+		require.Equal(t, http.StatusOK, DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/audit-logs/pipeline", nil).Code)
+
+		var logs []model.AuditLog
+		for _, log := range readLogs("/api/v1/audit-logs") {
+			if strings.Contains(log.Details, query) {
+				logs = append(logs, log)
+			}
+		}
+		require.Len(t, logs, 1)
+		assert.Equal(t, "AUDIT_LOG", logs[0].Resource)
+		assert.Empty(t, readLogs("/api/v1/audit-logs/pipeline"))
+	})
+
+	t.Run("DeniedReadsAreLogged", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, "invalid", http.MethodGet, "/api/v1/bugs/workflow", nil)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+
+		require.NoError(t, app.AuditLogService.Flush(context.Background()))
+		var count int64
+		require.NoError(t, app.DB.Model(&model.AuditLog{}).
+			Where("action = ? AND outcome = ? AND details LIKE ?", "READ", model.AuditOutcomeDenied, `%"/api/v1/bugs/workflow"%`).
+			Count(&count).Error)
+		assert.Positive(t, count)
+	})
+}
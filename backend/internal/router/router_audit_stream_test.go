@@ -0,0 +1,144 @@
+package router
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sseEvent is one event read from a Server-Sent Events response.
+type sseEvent struct {
+	ID   string
+	Name string
+	Data string
+}
+
+// readSSEEvents parses events from body until it is closed; comment lines are skipped.
+func readSSEEvents(body *bufio.Reader, events chan<- sseEvent) {
+	defer close(events)
+	var event sseEvent
+	for {
+		line, err := body.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "":
+			if event.Data != "" {
+				events <- event
+			}
+			event = sseEvent{}
+		case strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "id: "):
+			event.ID = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.Name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.Data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestAuditLogStreamRoute(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	server := httptest.NewServer(app.Router)
+	t.Cleanup(server.Close)
+
+	resource := fmt.Sprintf("STREAM_%d", time.Now().UnixNano())
+	openStream := func(lastEventID string) (*http.Response, <-chan sseEvent, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/audit-logs/stream?resource="+resource, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		events := make(chan sseEvent)
+		go readSSEEvents(bufio.NewReader(resp.Body), events)
+		return resp, events, func() {
+			cancel()
+			resp.Body.Close()
+		}
+	}
+	nextEvent := func(events <-chan sseEvent) sseEvent {
+		select {
+		case event, ok := <-events:
+			require.True(t, ok, "stream closed")
+			return event
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for audit event")
+			return sseEvent{}
+		}
+	}
+	record := func(res string, resourceID uint) {
+		app.AuditLogService.Record(context.Background(), &model.AuditLog{UserID: 1, Username: "ops", Action: "UPDATE", Resource: res, ResourceID: resourceID})
+		require.NoError(t, app.AuditLogService.Flush(context.Background()))
+	}
+
+	t.Run("RequiresPermission", func(t *testing.T) {
+		resp, _, closeStream := openStream("")
+		defer closeStream()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	grantPermissionForTest(t, app, token, model.PermissionStreamAuditLogs)
+
+	var firstID string
+	t.Run("PushesMatchingEntries", func(t *testing.T) {
+		record(resource, 1) // 打开之前写入的日志不会推送
+		resp, events, closeStream := openStream("")
+		defer closeStream()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		record(resource, 2)
+		record("OTHER_RESOURCE", 3)
+		record(resource, 4)
+
+		for _, want := range []uint{2, 4} {
+			event := nextEvent(events)
+			assert.Equal(t, "audit", event.Name)
+			var entry model.AuditLogResponse
+			require.NoError(t, json.Unmarshal([]byte(event.Data), &entry))
+			assert.Equal(t, resource, entry.Resource)
+			assert.Equal(t, want, entry.ResourceID)
+			assert.Equal(t, strconv.FormatUint(uint64(entry.ID), 10), event.ID)
+			if firstID == "" {
+				firstID = event.ID
+			}
+		}
+	})
+
+	t.Run("ResumesFromLastEventID", func(t *testing.T) {
+		require.NotEmpty(t, firstID)
+		resp, events, closeStream := openStream(firstID)
+		defer closeStream()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var entry model.AuditLogResponse
+		require.NoError(t, json.Unmarshal([]byte(nextEvent(events).Data), &entry))
+		assert.EqualValues(t, 4, entry.ResourceID, "entries after Last-Event-ID are replayed from the database")
+	})
+
+	t.Run("InvalidLastEventID", func(t *testing.T) {
+		resp, _, closeStream := openStream("abc")
+		defer closeStream()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
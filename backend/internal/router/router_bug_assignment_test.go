@@ -0,0 +1,135 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugAssignmentRuleRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	_, instance := seedDeploymentFixtures(t, app)
+	suffix := time.Now().UnixNano()
+
+	group := &model.ResponsibilityGroup{Name: fmt.Sprintf("assign-group-%d", suffix)}
+	require.NoError(t, app.DB.Create(group).Error)
+	var users []*model.User
+	for i, status := range []string{"active", "inactive", "active"} {
+		user := &model.User{Name: fmt.Sprintf("Assignee %d", i), Email: fmt.Sprintf("assignee-%d-%d@example.com", i, suffix), Password: "x", Status: status}
+		require.NoError(t, app.DB.Create(user).Error)
+		users = append(users, user)
+	}
+
+	membersPath := fmt.Sprintf("/api/v1/responsibility-groups/%d/members", group.ID)
+	for i, user := range users {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("%s/%d", membersPath, user.ID), map[string]interface{}{"isPrimary": i == 0})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	t.Run("Members list the users with their primary flag", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, membersPath, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data []model.ResponsibilityGroupMember `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 3)
+		assert.True(t, resp.Data[0].IsPrimary)
+		assert.Equal(t, users[1].Status, resp.Data[1].User.Status)
+	})
+
+	t.Run("Unknown user cannot join", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, membersPath+"/999999", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+
+	t.Run("Rule without a match is rejected", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bug-assignment-rules", map[string]interface{}{
+			"name":                  "catch-all",
+			"responsibilityGroupId": group.ID,
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bug-assignment-rules", map[string]interface{}{
+		"name":                  "checkout service",
+		"matchServiceId":        instance.ServiceID,
+		"responsibilityGroupId": group.ID,
+		"strategy":              "round_robin",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data model.BugAssignmentRule `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	rule := created.Data
+
+	t.Run("Dry run explains the decision without assigning", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bug-assignment-rules/dry-run", map[string]interface{}{"serviceId": instance.ServiceID})
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var resp struct {
+				Data service.BugAssignmentDryRunResultDTO `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.NotNil(t, resp.Data.Decision)
+			assert.Equal(t, rule.ID, resp.Data.Decision.RuleID)
+			assert.Equal(t, users[0].ID, resp.Data.Decision.AssigneeID)
+		}
+	})
+
+	t.Run("New bugs rotate through active members", func(t *testing.T) {
+		for _, want := range []uint{users[0].ID, users[2].ID, users[0].ID} {
+			w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{
+				"title":     "Cart total is wrong",
+				"priority":  "HIGH",
+				"serviceId": instance.ServiceID,
+			})
+			require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+			var bug model.BugResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+			require.NotNil(t, bug.AssigneeID)
+			assert.Equal(t, want, *bug.AssigneeID)
+			require.NotNil(t, bug.AssignmentRuleID)
+			assert.Equal(t, rule.ID, *bug.AssignmentRuleID)
+		}
+	})
+
+	t.Run("Disabled rule no longer fires", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("/api/v1/bug-assignment-rules/%d", rule.ID), map[string]interface{}{
+			"name":                  rule.Name,
+			"enabled":               false,
+			"matchServiceId":        instance.ServiceID,
+			"responsibilityGroupId": group.ID,
+			"strategy":              "round_robin",
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{
+			"title":     "Cart total is wrong",
+			"priority":  "HIGH",
+			"serviceId": instance.ServiceID,
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var bug model.BugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+		assert.Nil(t, bug.AssigneeID)
+	})
+
+	t.Run("Delete removes the rule", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/bug-assignment-rules/%d", rule.ID)
+		w := DoRequestForTest(t, app.Router, token, http.MethodDelete, path, nil)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, path, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+}
@@ -0,0 +1,117 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugBulkRoute(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	suffix := time.Now().UnixNano()
+	label := fmt.Sprintf("triage-%d", suffix)
+
+	createBug := func(title string, labels ...string) model.BugResponse {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{"title": title, "priority": "LOW", "labels": labels})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var bug model.BugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+		return bug
+	}
+	getBug := func(id uint) model.BugResponse {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/bugs/%d", id), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var bug model.BugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+		return bug
+	}
+	bulk := func(body map[string]interface{}) (*httptest.ResponseRecorder, model.BulkBugUpdateResponse) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs/bulk", body)
+		var resp model.BulkBugUpdateResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w, resp
+	}
+
+	first := createBug(fmt.Sprintf("Bulk first %d", suffix), label, " "+label)
+	second := createBug(fmt.Sprintf("Bulk second %d", suffix), label)
+	other := createBug(fmt.Sprintf("Bulk other %d", suffix))
+	assert.Equal(t, []string{label}, first.Labels, "labels are trimmed and deduplicated")
+	assert.Equal(t, []string{}, other.Labels)
+
+	t.Run("Update by filter", func(t *testing.T) {
+		w, resp := bulk(map[string]interface{}{
+			"filter": map[string]interface{}{"label": label},
+			"update": map[string]interface{}{"priority": "URGENT", "status": "IN_PROGRESS", "addLabels": []string{"sprint-1"}},
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, 2, resp.Matched)
+		assert.Equal(t, 2, resp.Updated)
+
+		bug := getBug(second.ID)
+		assert.Equal(t, model.BugPriorityUrgent, bug.Priority)
+		assert.Equal(t, model.BugStatusInProgress, bug.Status)
+		assert.Equal(t, []string{"sprint-1", label}, bug.Labels)
+		assert.Equal(t, model.BugPriorityLow, getBug(other.ID).Priority, "bugs outside the filter are left alone")
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/bugs?label="+label, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var list struct {
+			Data struct {
+				Total int64 `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		assert.Equal(t, int64(2), list.Data.Total)
+	})
+
+	t.Run("Atomic update rolls back when a bug cannot move", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("/api/v1/bugs/%d", first.ID), map[string]interface{}{"status": "RESOLVED", "resolution": "FIXED", "comment": "Fixed"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w, resp := bulk(map[string]interface{}{
+			"ids":    []uint{other.ID, first.ID},
+			"update": map[string]interface{}{"status": "IN_PROGRESS", "removeLabels": []string{label}},
+			"atomic": true,
+		})
+		require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+		var conflict struct {
+			Data model.BulkBugUpdateResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conflict))
+		assert.Equal(t, 1, conflict.Data.Failed)
+		assert.Equal(t, model.BugStatusOpen, getBug(other.ID).Status)
+		assert.Contains(t, getBug(first.ID).Labels, label)
+
+		// Without atomic, the bug that can move does
+		w, resp = bulk(map[string]interface{}{
+			"ids":    []uint{other.ID, first.ID, 999999},
+			"update": map[string]interface{}{"status": "IN_PROGRESS", "removeLabels": []string{label}},
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, 1, resp.Updated)
+		assert.Equal(t, 2, resp.Failed)
+		assert.Equal(t, model.BulkBugFailed, resp.Results[1].Status)
+		assert.Equal(t, model.BugStatusInProgress, getBug(other.ID).Status)
+		assert.Equal(t, model.BugStatusResolved, getBug(first.ID).Status)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		w, _ := bulk(map[string]interface{}{"ids": []uint{first.ID}, "update": map[string]interface{}{}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w, _ = bulk(map[string]interface{}{"update": map[string]interface{}{"priority": "HIGH"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w, _ = bulk(map[string]interface{}{"ids": []uint{first.ID}, "update": map[string]interface{}{"priority": "SOMEDAY"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,106 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugCommentRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	authorToken := GetAuthTokenForTest(t, app.Router, app.DB)
+	otherToken := GetAuthTokenForTest(t, app.Router, app.DB)
+
+	handle := fmt.Sprintf("mentioned_%d", time.Now().UnixNano())
+	mentioned := model.User{Name: "Mentioned User", Email: handle + "@example.com", Password: "x", Status: "active"}
+	require.NoError(t, app.DB.Create(&mentioned).Error)
+
+	w := DoRequestForTest(t, app.Router, authorToken, http.MethodPost, "/api/v1/bugs", map[string]interface{}{
+		"title":    "Checkout times out",
+		"priority": "MEDIUM",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var bug model.BugResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+	bugPath := fmt.Sprintf("/api/v1/bugs/%d", bug.ID)
+
+	w = DoRequestForTest(t, app.Router, authorToken, http.MethodPost, bugPath+"/comments", map[string]interface{}{
+		"body": fmt.Sprintf("@%s can you take a look? `@%s` in code is ignored", handle, "nobody"),
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var comment model.BugCommentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &comment))
+	require.Len(t, comment.Mentions, 1)
+	assert.Equal(t, mentioned.ID, comment.Mentions[0].ID)
+	require.NotNil(t, comment.Author)
+	commentPath := fmt.Sprintf("%s/comments/%d", bugPath, comment.ID)
+
+	t.Run("Empty body is rejected", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, authorToken, http.MethodPost, bugPath+"/comments", map[string]interface{}{"body": ""})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	t.Run("Comment on a missing bug", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, authorToken, http.MethodPost, "/api/v1/bugs/999999/comments", map[string]interface{}{"body": "hello"})
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+
+	t.Run("Only the author may edit", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, otherToken, http.MethodPut, commentPath, map[string]interface{}{"body": "hijacked"})
+		assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+		w = DoRequestForTest(t, app.Router, otherToken, http.MethodDelete, commentPath, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	})
+
+	t.Run("Edit keeps a revision", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, authorToken, http.MethodPut, commentPath, map[string]interface{}{"body": "never mind, found it"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var edited model.BugCommentResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &edited))
+		assert.Equal(t, 1, edited.EditCount)
+		assert.NotNil(t, edited.EditedAt)
+		assert.Empty(t, edited.Mentions, "mentions follow the new body")
+
+		w = DoRequestForTest(t, app.Router, authorToken, http.MethodGet, commentPath+"/revisions", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var revisions []model.BugCommentRevision
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+		require.Len(t, revisions, 1)
+		assert.Contains(t, revisions[0].Body, "can you take a look?")
+	})
+
+	t.Run("Activity interleaves comments, field changes and transitions", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, authorToken, http.MethodPut, bugPath, map[string]interface{}{"priority": "HIGH", "status": "IN_PROGRESS"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = DoRequestForTest(t, app.Router, authorToken, http.MethodDelete, commentPath, nil)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+		w = DoRequestForTest(t, app.Router, authorToken, http.MethodGet, bugPath+"/comments", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var comments []model.BugCommentResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &comments))
+		assert.Empty(t, comments)
+
+		w = DoRequestForTest(t, app.Router, authorToken, http.MethodGet, bugPath+"/activity", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var feed []model.BugActivityItem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &feed))
+		require.Len(t, feed, 3)
+		assert.Equal(t, model.BugActivityComment, feed[0].Type)
+		assert.True(t, feed[0].Comment.Deleted)
+		assert.Empty(t, feed[0].Comment.Body)
+		assert.Equal(t, model.BugActivityFieldChange, feed[1].Type)
+		assert.Equal(t, "priority", feed[1].FieldChange.Field)
+		assert.Equal(t, "HIGH", feed[1].FieldChange.NewValue)
+		assert.Equal(t, model.BugActivityStatusTransition, feed[2].Type)
+		assert.Equal(t, model.BugStatusInProgress, feed[2].Transition.ToStatus)
+	})
+}
@@ -0,0 +1,93 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugContextLinkRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	env, instance := seedDeploymentFixtures(t, app)
+	business := &model.Business{Name: fmt.Sprintf("bug-biz-%d", time.Now().UnixNano()), Status: model.BusinessStatusActive}
+	require.NoError(t, app.DB.Create(business).Error)
+
+	w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{
+		"title":          "Checkout times out",
+		"priority":       "URGENT",
+		"environmentId":  env.ID,
+		"businessId":     business.ID,
+		"serviceId":      instance.ServiceID,
+		"serviceVersion": instance.Version,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created model.BugResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.Environment)
+	assert.Equal(t, env.Slug, created.Environment.Slug)
+	require.NotNil(t, created.Business)
+	assert.Equal(t, business.Name, created.Business.Name)
+	require.NotNil(t, created.Service)
+	assert.Equal(t, instance.Version, created.Service.Version)
+	bugPath := fmt.Sprintf("/api/v1/bugs/%d", created.ID)
+
+	t.Run("Reads embed the linked entities", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, bugPath, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp model.BugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, created.Environment, resp.Environment)
+		assert.Equal(t, created.Service, resp.Service)
+	})
+
+	t.Run("List filters by links", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/bugs?environmentId=%d&serviceVersion=%s", env.ID, instance.Version), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				Items []model.BugResponse `json:"items"`
+				Total int64               `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, int64(1), resp.Data.Total)
+		assert.Equal(t, created.ID, resp.Data.Items[0].ID)
+		assert.NotNil(t, resp.Data.Items[0].Business)
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/bugs?businessId=%d&serviceVersion=0.0.0", business.ID), nil)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(0), resp.Data.Total)
+	})
+
+	t.Run("Undeployed version is rejected", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, bugPath, map[string]interface{}{"serviceVersion": "9.9.9"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	t.Run("Unlinking clears the link", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, bugPath, map[string]interface{}{"businessId": 0})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var bug model.Bug
+		require.NoError(t, app.DB.First(&bug, created.ID).Error)
+		assert.Nil(t, bug.BusinessID)
+		assert.NotNil(t, bug.EnvironmentID)
+	})
+
+	t.Run("Unknown environment is rejected", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{
+			"title":         "Checkout times out",
+			"priority":      "URGENT",
+			"environmentId": 999999,
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+}
@@ -0,0 +1,138 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugRelationRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	suffix := time.Now().UnixNano()
+
+	createBug := func(title string) model.BugResponse {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{"title": title, "priority": "HIGH"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var bug model.BugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+		return bug
+	}
+	relate := func(source uint, relationType model.BugRelationType, target uint) *httptest.ResponseRecorder {
+		return DoRequestForTest(t, app.Router, token, http.MethodPost, fmt.Sprintf("/api/v1/bugs/%d/relations", source), map[string]interface{}{
+			"type":        relationType,
+			"targetBugId": target,
+		})
+	}
+
+	original := createBug(fmt.Sprintf("Invoice export hangs %d", suffix))
+	first := createBug(fmt.Sprintf("Invoice export stuck %d", suffix))
+	second := createBug(fmt.Sprintf("Invoice export spinner %d", suffix))
+
+	t.Run("Similar bugs are found before filing", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs/similar", map[string]interface{}{"title": fmt.Sprintf("invoice EXPORT hangs forever %d", suffix)})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var similar []model.BugSimilarityResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &similar))
+		require.NotEmpty(t, similar)
+		assert.Equal(t, original.ID, similar[0].Bug.ID)
+		assert.Equal(t, 0.8, similar[0].Score)
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{
+			"title":           fmt.Sprintf("Invoice export hangs forever %d", suffix),
+			"priority":        "HIGH",
+			"checkDuplicates": true,
+		})
+		require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+		var conflict struct {
+			Data []model.BugSimilarityResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conflict))
+		require.NotEmpty(t, conflict.Data)
+		assert.Equal(t, original.ID, conflict.Data[0].Bug.ID)
+	})
+
+	t.Run("Duplicates are closed and chains point at the original", func(t *testing.T) {
+		w := relate(first.ID, model.BugRelationDuplicates, original.ID)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		w = relate(second.ID, model.BugRelationDuplicates, first.ID)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var relation model.BugRelationResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &relation))
+		assert.Equal(t, original.ID, relation.Bug.ID)
+		assert.Equal(t, model.BugRelationOutward, relation.Direction)
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/bugs/%d", second.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var bug model.BugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+		assert.Equal(t, model.BugStatusClosed, bug.Status)
+		assert.Equal(t, model.BugResolutionDuplicate, *bug.Resolution)
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/bugs/%d/transitions", second.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var transitions []model.BugStatusTransition
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &transitions))
+		require.Len(t, transitions, 1)
+		assert.Equal(t, fmt.Sprintf("Duplicate of #%d", original.ID), transitions[0].Comment)
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/bugs/%d/relations", original.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var relations []model.BugRelationResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &relations))
+		require.Len(t, relations, 2)
+		for _, r := range relations {
+			assert.Equal(t, model.BugRelationInward, r.Direction)
+			assert.Equal(t, model.BugRelationDuplicates, r.Type)
+		}
+
+		assert.Equal(t, http.StatusConflict, relate(original.ID, model.BugRelationDuplicates, second.ID).Code, "the original cannot duplicate its own duplicate")
+		assert.Equal(t, http.StatusConflict, relate(first.ID, model.BugRelationDuplicates, second.ID).Code, "a bug duplicates one bug")
+	})
+
+	t.Run("Closed duplicates are not offered as similar bugs", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs/similar", map[string]interface{}{"title": fmt.Sprintf("Invoice export stuck %d", suffix)})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var similar []model.BugSimilarityResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &similar))
+		for _, s := range similar {
+			assert.NotEqual(t, first.ID, s.Bug.ID)
+		}
+	})
+
+	t.Run("Blocks cannot form a cycle", func(t *testing.T) {
+		a := createBug(fmt.Sprintf("Schema migration %d", suffix))
+		b := createBug(fmt.Sprintf("Reporting backfill %d", suffix))
+		c := createBug(fmt.Sprintf("Dashboard rollout %d", suffix))
+
+		require.Equal(t, http.StatusCreated, relate(a.ID, model.BugRelationBlocks, b.ID).Code)
+		require.Equal(t, http.StatusCreated, relate(b.ID, model.BugRelationBlocks, c.ID).Code)
+		assert.Equal(t, http.StatusConflict, relate(c.ID, model.BugRelationBlocks, a.ID).Code)
+		assert.Equal(t, http.StatusConflict, relate(a.ID, model.BugRelationBlocks, b.ID).Code, "relation exists already")
+		assert.Equal(t, http.StatusBadRequest, relate(a.ID, model.BugRelationBlocks, a.ID).Code)
+		assert.Equal(t, http.StatusNotFound, relate(a.ID, model.BugRelationBlocks, 999999).Code)
+
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/bugs/%d/relations", b.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var relations []model.BugRelationResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &relations))
+		require.Len(t, relations, 2)
+
+		// Removing a link allows the reverse direction
+		w = DoRequestForTest(t, app.Router, token, http.MethodDelete, fmt.Sprintf("/api/v1/bugs/%d/relations/%d", b.ID, relations[1].ID), nil)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+		assert.Equal(t, http.StatusCreated, relate(c.ID, model.BugRelationBlocks, a.ID).Code)
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodDelete, fmt.Sprintf("/api/v1/bugs/%d/relations/%d", b.ID, relations[1].ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+}
@@ -0,0 +1,158 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugSLARoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	suffix := time.Now().UnixNano()
+
+	group := &model.ResponsibilityGroup{Name: fmt.Sprintf("sla-group-%d", suffix)}
+	require.NoError(t, app.DB.Create(group).Error)
+	assignee := &model.User{Name: "SLA Assignee", Email: fmt.Sprintf("sla-assignee-%d@example.com", suffix), Password: "x", Status: "active"}
+	backup := &model.User{Name: "SLA Backup", Email: fmt.Sprintf("sla-backup-%d@example.com", suffix), Password: "x", Status: "active"}
+	require.NoError(t, app.DB.Create(assignee).Error)
+	require.NoError(t, app.DB.Create(backup).Error)
+
+	createBug := func(title string) model.BugResponse {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{
+			"title":      title,
+			"priority":   "URGENT",
+			"assigneeId": assignee.ID,
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var bug model.BugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+		return bug
+	}
+	listBugs := func(query string) []model.BugResponse {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/bugs?title=%d&%s", suffix, query), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				Items []model.BugResponse `json:"items"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.Items
+	}
+
+	membersPath := fmt.Sprintf("/api/v1/responsibility-groups/%d/members", group.ID)
+	w := DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("%s/%d", membersPath, assignee.ID), map[string]interface{}{"isPrimary": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("%s/%d", membersPath, backup.ID), map[string]interface{}{"isBackup": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	t.Run("A member cannot be primary and backup", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("%s/%d", membersPath, backup.ID), map[string]interface{}{"isPrimary": true, "isBackup": true})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	t.Run("Unknown priority is rejected", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, "/api/v1/bug-sla/policies/CRITICAL", map[string]interface{}{"resolutionMinutes": 60})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	w = DoRequestForTest(t, app.Router, token, http.MethodPut, "/api/v1/bug-sla/policies/urgent", map[string]interface{}{"firstResponseMinutes": 30, "resolutionMinutes": 240})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var saved struct {
+		Data model.BugSLAPolicy `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &saved))
+	assert.Equal(t, model.BugPriorityUrgent, saved.Data.Priority)
+	assert.True(t, saved.Data.Enabled)
+
+	overdue := createBug(fmt.Sprintf("Checkout is down %d", suffix))
+	onTime := createBug(fmt.Sprintf("Refunds are slow %d", suffix))
+	require.NotNil(t, overdue.SLA)
+	assert.Equal(t, overdue.CreatedAt.Add(30*time.Minute), *overdue.SLA.ResponseDueAt)
+	assert.False(t, overdue.SLA.Breached)
+
+	// Pretend the first bug was filed two hours ago
+	filed := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, app.DB.Model(&model.Bug{}).Where("id = ?", overdue.ID).Updates(map[string]interface{}{
+		"created_at":      filed,
+		"response_due_at": filed.Add(30 * time.Minute),
+		"resolve_due_at":  filed.Add(4 * time.Hour),
+	}).Error)
+
+	t.Run("List filters by SLA state", func(t *testing.T) {
+		breached := listBugs("slaBreached=true")
+		require.Len(t, breached, 1)
+		assert.Equal(t, overdue.ID, breached[0].ID)
+		assert.True(t, breached[0].SLA.ResponseBreached)
+
+		healthy := listBugs("slaBreached=false")
+		require.Len(t, healthy, 1)
+		assert.Equal(t, onTime.ID, healthy[0].ID)
+
+		dueSoon := listBugs("slaDueBefore=" + url.QueryEscape(time.Now().Add(time.Hour).UTC().Format(time.RFC3339)))
+		assert.Len(t, dueSoon, 2)
+		dueNow := listBugs("slaDueBefore=" + url.QueryEscape(time.Now().UTC().Format(time.RFC3339)))
+		require.Len(t, dueNow, 1)
+		assert.Equal(t, overdue.ID, dueNow[0].ID)
+	})
+
+	t.Run("Check flags the breach and escalates to the backup once", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bug-sla/check", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data service.BugSLACheckResultDTO `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Contains(t, resp.Data.Breached, overdue.ID)
+		assert.NotContains(t, resp.Data.Breached, onTime.ID)
+		assert.Contains(t, resp.Data.Escalated, service.BugSLAEscalationDTO{BugID: overdue.ID, FromAssigneeID: &assignee.ID, ToAssigneeID: backup.ID})
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/bugs/%d", overdue.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var bug model.BugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+		assert.Equal(t, backup.ID, *bug.AssigneeID)
+		require.NotNil(t, bug.SLA)
+		assert.True(t, bug.SLA.ResponseBreached)
+		assert.False(t, bug.SLA.ResolveBreached)
+		assert.Equal(t, backup.ID, *bug.SLA.EscalatedToID)
+		assert.NotNil(t, bug.SLA.EscalatedAt)
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bug-sla/check", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.NotContains(t, resp.Data.Breached, overdue.ID, "a flagged clock is not flagged again")
+	})
+
+	t.Run("Comment by someone else counts as the first response", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, fmt.Sprintf("/api/v1/bugs/%d/comments", onTime.ID), map[string]interface{}{"body": "Looking into it"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/bugs/%d", onTime.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var bug model.BugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+		require.NotNil(t, bug.SLA)
+		assert.NotNil(t, bug.SLA.FirstRespondedAt)
+	})
+
+	t.Run("Delete removes the policy", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodDelete, "/api/v1/bug-sla/policies/URGENT", nil)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+		w = DoRequestForTest(t, app.Router, token, http.MethodDelete, "/api/v1/bug-sla/policies/URGENT", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+		bug := createBug(fmt.Sprintf("No policy any more %d", suffix))
+		assert.Nil(t, bug.SLA)
+	})
+}
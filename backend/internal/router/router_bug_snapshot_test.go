@@ -0,0 +1,96 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugEnvironmentSnapshotRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	env, seeded := seedDeploymentFixtures(t, app)
+
+	instancePayload := map[string]interface{}{
+		"serviceId":     seeded.ServiceID,
+		"environmentId": env.ID,
+		"version":       "5.0.0",
+		"status":        "running",
+		"config":        map[string]interface{}{"apiKey": "secret://k3y-v4lue"},
+	}
+	w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/service-instances", instancePayload)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var instance struct {
+		Data struct {
+			ID uint `json:"id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &instance))
+
+	w = DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{
+		"title":         "Webhook signature mismatch",
+		"priority":      "HIGH",
+		"environmentId": env.ID,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var bug model.BugResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+	bugPath := fmt.Sprintf("/api/v1/bugs/%d", bug.ID)
+
+	t.Run("Snapshot lists every instance with masked config", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, bugPath+"/snapshot", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.NotContains(t, w.Body.String(), "k3y-v4lue")
+		assert.NotContains(t, w.Body.String(), "enc:v1:")
+
+		var snapshot model.BugEnvironmentSnapshot
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+		assert.Equal(t, env.ID, snapshot.EnvironmentID)
+		require.Len(t, snapshot.Instances, 2)
+		assert.Equal(t, seeded.ID, snapshot.Instances[0].ServiceInstanceID)
+		assert.Equal(t, instance.Data.ID, snapshot.Instances[1].ServiceInstanceID)
+		assert.Equal(t, utils.SecretMask, snapshot.Instances[1].Config["apiKey"])
+	})
+
+	t.Run("Diff shows what changed since", func(t *testing.T) {
+		instancePayload["version"] = "5.1.0"
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("/api/v1/service-instances/%d", instance.Data.ID), instancePayload)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, bugPath+"/snapshot/diff", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var diff struct {
+			Added   []model.BugSnapshotInstance `json:"added"`
+			Removed []model.BugSnapshotInstance `json:"removed"`
+			Changed []struct {
+				ServiceInstanceID uint               `json:"serviceInstanceId"`
+				Changes           []utils.JSONChange `json:"changes"`
+			} `json:"changed"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+		assert.Empty(t, diff.Added)
+		assert.Empty(t, diff.Removed)
+		require.Len(t, diff.Changed, 1)
+		assert.Equal(t, instance.Data.ID, diff.Changed[0].ServiceInstanceID)
+		require.Len(t, diff.Changed[0].Changes, 1)
+		assert.Equal(t, "version", diff.Changed[0].Changes[0].Path)
+		assert.Equal(t, "5.1.0", diff.Changed[0].Changes[0].NewValue)
+	})
+
+	t.Run("Bug without environment has no snapshot", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{"title": "Typo on landing page", "priority": "LOW"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var other model.BugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &other))
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/bugs/%d/snapshot", other.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+}
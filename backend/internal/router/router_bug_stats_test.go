@@ -0,0 +1,50 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugStatsRoute(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	suffix := time.Now().UnixNano()
+
+	w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{"title": fmt.Sprintf("Stats bug %d", suffix), "priority": "URGENT"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	t.Run("Grouped counts and a continuous trend", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/bugs/stats?groupBy=priority&interval=week", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var stats model.BugStatsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Equal(t, model.BugStatsByPriority, stats.GroupBy)
+		assert.GreaterOrEqual(t, stats.Total, int64(1))
+		assert.GreaterOrEqual(t, countGroup(stats.Groups, string(model.BugPriorityUrgent)), int64(1))
+		assert.Len(t, stats.OpenAge, 5)
+		assert.GreaterOrEqual(t, len(stats.Trend), 5, "30 days span at least five weeks")
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/bugs/stats?groupBy=reporter", nil).Code)
+		assert.Equal(t, http.StatusBadRequest, DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/bugs/stats?from=yesterday", nil).Code)
+		assert.Equal(t, http.StatusBadRequest, DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/bugs/stats?from=2024-05-02T00:00:00Z&to=2024-05-01T00:00:00Z", nil).Code)
+	})
+}
+
+func countGroup(groups []model.BugStatsGroup, key string) int64 {
+	for _, group := range groups {
+		if group.Key == key {
+			return group.Count
+		}
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugWorkflowRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+
+	w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/bugs", map[string]interface{}{
+		"title":    "Login page crashes",
+		"priority": "HIGH",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created model.BugResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, model.BugStatusOpen, created.Status)
+	bugPath := fmt.Sprintf("/api/v1/bugs/%d", created.ID)
+
+	t.Run("Illegal transition is rejected", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, bugPath, map[string]interface{}{"status": "CLOSED", "resolution": "FIXED", "comment": "done"})
+		assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "cannot move bug from OPEN to CLOSED")
+	})
+
+	t.Run("Resolving without a resolution is rejected", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, bugPath, map[string]interface{}{"status": "RESOLVED", "comment": "done"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	t.Run("Full lifecycle is recorded", func(t *testing.T) {
+		steps := []map[string]interface{}{
+			{"status": "IN_PROGRESS"},
+			{"status": "RESOLVED", "resolution": "FIXED", "comment": "fixed in 1.2.0"},
+			{"status": "VERIFIED"},
+			{"status": "CLOSED", "comment": "confirmed in production"},
+			{"status": "REOPENED"},
+		}
+		for _, step := range steps {
+			w := DoRequestForTest(t, app.Router, token, http.MethodPut, bugPath, step)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var bug model.Bug
+		require.NoError(t, app.DB.First(&bug, created.ID).Error)
+		assert.Equal(t, model.BugStatusReopened, bug.Status)
+		assert.Nil(t, bug.Resolution, "reopening clears the resolution")
+
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, bugPath+"/transitions", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var transitions []model.BugStatusTransition
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &transitions))
+		require.Len(t, transitions, len(steps))
+		assert.Equal(t, model.BugStatusOpen, transitions[0].FromStatus)
+		assert.Equal(t, model.BugStatusReopened, transitions[4].ToStatus)
+		assert.Equal(t, "fixed in 1.2.0", transitions[1].Comment)
+		assert.Equal(t, "Test User", transitions[1].Actor)
+		assert.NotNil(t, transitions[1].ActorID)
+	})
+
+	t.Run("Workflow is exposed", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/bugs/workflow", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var workflow model.BugWorkflow
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &workflow))
+		assert.Equal(t, model.BugStatusOpen, workflow.InitialStatus)
+		assert.Contains(t, workflow.Transitions[model.BugStatusResolved], model.BugStatusVerified)
+	})
+
+	t.Run("Unknown bug returns 404", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/bugs/999999/transitions", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
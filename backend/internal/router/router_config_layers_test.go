@@ -0,0 +1,90 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceInstanceEffectiveConfigRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	env, seeded := seedDeploymentFixtures(t, app)
+
+	w := DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("/api/v1/services/%d", seeded.ServiceID), map[string]interface{}{
+		"defaultConfig": map[string]interface{}{"replicas": 1, "logLevel": "info", "db": map[string]interface{}{"host": "db", "pool": 5}},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("/api/v1/environments/%d", env.ID), map[string]interface{}{
+		"config": map[string]interface{}{"replicas": 3, "db": map[string]interface{}{"host": "prod-db"}},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/service-instances", map[string]interface{}{
+		"serviceId":     seeded.ServiceID,
+		"environmentId": env.ID,
+		"version":       "4.0.0",
+		"status":        "running",
+		"config":        map[string]interface{}{"replicas": 3, "db": map[string]interface{}{"host": "prod-db", "pool": 20}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data struct {
+			ID     uint                   `json:"id"`
+			Config map[string]interface{} `json:"config"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, map[string]interface{}{"db": map[string]interface{}{"pool": float64(20)}}, created.Data.Config)
+
+	t.Run("Effective config merges all layers", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/service-instances/%d/config/effective", created.Data.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				Config     map[string]interface{} `json:"config"`
+				Provenance map[string]string      `json:"provenance"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, map[string]interface{}{
+			"replicas": float64(3),
+			"logLevel": "info",
+			"db":       map[string]interface{}{"host": "prod-db", "pool": float64(20)},
+		}, resp.Data.Config)
+		assert.Equal(t, map[string]string{
+			"replicas": "environment",
+			"logLevel": "service",
+			"db.host":  "environment",
+			"db.pool":  "instance",
+		}, resp.Data.Provenance)
+	})
+
+	t.Run("Default layers reject secrets", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("/api/v1/environments/%d", env.ID), map[string]interface{}{
+			"config": map[string]interface{}{"token": "secret://abc"},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("/api/v1/environments/%d", env.ID), map[string]interface{}{
+			"config": map[string]interface{}{"replicas": []interface{}{map[string]interface{}{"token": "secret://abc"}}},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+		var reloaded model.Environment
+		require.NoError(t, app.DB.First(&reloaded, env.ID).Error)
+		assert.NotContains(t, reloaded.Config, "token")
+	})
+
+	t.Run("Unknown instance returns 404", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/service-instances/999999/config/effective", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
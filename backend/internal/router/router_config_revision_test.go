@@ -0,0 +1,130 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRevisionRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	_, seeded := seedDeploymentFixtures(t, app)
+
+	// Create the instance through the API so that revision 1 is recorded.
+	w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/service-instances", map[string]interface{}{
+		"serviceId":     seeded.ServiceID,
+		"environmentId": seeded.EnvironmentID,
+		"version":       "2.0.0",
+		"status":        "running",
+		"config":        map[string]interface{}{"replicas": 1, "logLevel": "info"},
+		"configComment": "initial config",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data struct {
+			ID uint `json:"id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	instancePath := fmt.Sprintf("/api/v1/service-instances/%d", created.Data.ID)
+
+	update := func(config map[string]interface{}, comment string) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, instancePath, map[string]interface{}{
+			"serviceId":     seeded.ServiceID,
+			"environmentId": seeded.EnvironmentID,
+			"version":       "2.0.0",
+			"status":        "running",
+			"config":        config,
+			"configComment": comment,
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+	update(map[string]interface{}{"replicas": 3, "logLevel": "info"}, "scale out")
+	update(map[string]interface{}{"replicas": 3, "logLevel": "info"}, "no-op") // unchanged config, no revision
+
+	t.Run("List revisions", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, instancePath+"/config/revisions", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				Items []model.ConfigRevision `json:"items"`
+				Total int64                  `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(2), resp.Data.Total)
+		require.Len(t, resp.Data.Items, 2)
+		assert.Equal(t, 2, resp.Data.Items[0].Revision)
+		assert.Equal(t, "scale out", resp.Data.Items[0].Comment)
+		assert.Equal(t, "Test User", resp.Data.Items[0].Author)
+	})
+
+	t.Run("Diff revisions", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, instancePath+"/config/diff?from=1&to=2", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				Changes []utils.JSONChange `json:"changes"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data.Changes, 1)
+		assert.Equal(t, "replicas", resp.Data.Changes[0].Path)
+		assert.Equal(t, utils.JSONChangeChanged, resp.Data.Changes[0].Op)
+	})
+
+	t.Run("Rollback creates a new revision", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, instancePath+"/config/rollback/1", nil)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp struct {
+			Data model.ConfigRevision `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 3, resp.Data.Revision)
+		require.NotNil(t, resp.Data.RolledBackFrom)
+		assert.Equal(t, 1, *resp.Data.RolledBackFrom)
+
+		var instance model.ServiceInstance
+		require.NoError(t, app.DB.First(&instance, created.Data.ID).Error)
+		assert.Equal(t, json.Number("1"), instance.Config["replicas"])
+	})
+
+	t.Run("Unknown revision returns 404", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, instancePath+"/config/rollback/99", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, instancePath+"/config/diff?from=1&to=99", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Rollback validates against the current schema", func(t *testing.T) {
+		// The schema is attached after revision 2 was saved and no longer allows its replicas.
+		schema := `{"type": "object", "properties": {"replicas": {"type": "integer", "maximum": 2}}}`
+		require.NoError(t, app.DB.Model(&model.Service{}).Where("id = ?", seeded.ServiceID).Update("config_schema", schema).Error)
+
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, instancePath+"/config/rollback/2", nil)
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		var errResp struct {
+			Data []utils.FieldError `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+		assert.Equal(t, []utils.FieldError{{Field: "replicas", Message: "must be <= 2"}}, errResp.Data)
+
+		var instance model.ServiceInstance
+		require.NoError(t, app.DB.First(&instance, created.Data.ID).Error)
+		assert.Equal(t, json.Number("1"), instance.Config["replicas"])
+	})
+
+	t.Run("Invalid revision number returns 400", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, instancePath+"/config/diff?from=abc&to=1", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,118 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceConfigSchemaRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	env, seeded := seedDeploymentFixtures(t, app)
+
+	var seededService model.Service
+	require.NoError(t, app.DB.First(&seededService, seeded.ServiceID).Error)
+
+	schema := json.RawMessage(`{
+		"type": "object",
+		"required": ["port"],
+		"additionalProperties": false,
+		"properties": {
+			"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+			"logLevel": {"enum": ["debug", "info"]}
+		}
+	}`)
+
+	t.Run("Invalid schema is rejected", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/services", map[string]interface{}{
+			"name":          fmt.Sprintf("schema-bad-%d", time.Now().UnixNano()),
+			"serviceTypeId": seededService.ServiceTypeID,
+			"configSchema":  json.RawMessage(`{"type": "dictionary"}`),
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/services", map[string]interface{}{
+		"name":          fmt.Sprintf("schema-svc-%d", time.Now().UnixNano()),
+		"serviceTypeId": seededService.ServiceTypeID,
+		"configSchema":  schema,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data model.ServiceResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotEmpty(t, created.Data.ConfigSchema)
+	serviceID := created.Data.ID
+
+	t.Run("Offline validation returns field errors", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, fmt.Sprintf("/api/v1/services/%d/config-schema/validate", serviceID), map[string]interface{}{
+			"prot":     8080,
+			"logLevel": "trace",
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				Valid  bool               `json:"valid"`
+				Errors []utils.FieldError `json:"errors"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.False(t, resp.Data.Valid)
+		assert.Equal(t, []utils.FieldError{
+			{Field: "logLevel", Message: `must be one of ["debug","info"]`},
+			{Field: "port", Message: "is required"},
+			{Field: "prot", Message: "unknown property"},
+		}, resp.Data.Errors)
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodPost, fmt.Sprintf("/api/v1/services/%d/config-schema/validate", serviceID), map[string]interface{}{"port": 8080})
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.True(t, resp.Data.Valid)
+	})
+
+	t.Run("Instance create and update enforce the schema", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"serviceId":     serviceID,
+			"environmentId": env.ID,
+			"version":       "1.0.0",
+			"status":        "running",
+			"config":        map[string]interface{}{"port": 70000},
+		}
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/service-instances", payload)
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		var errResp struct {
+			Data []utils.FieldError `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+		assert.Equal(t, []utils.FieldError{{Field: "port", Message: "must be <= 65535"}}, errResp.Data)
+
+		payload["config"] = map[string]interface{}{"port": 8080}
+		w = DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/service-instances", payload)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var createdInstance struct {
+			Data struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createdInstance))
+
+		payload["config"] = map[string]interface{}{"port": 8080, "extra": true}
+		w = DoRequestForTest(t, app.Router, token, http.MethodPut, fmt.Sprintf("/api/v1/service-instances/%d", createdInstance.Data.ID), payload)
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	t.Run("Unknown service returns 404", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/services/999999/config-schema/validate", map[string]interface{}{})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceInstanceConfigSecretRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	env, seeded := seedDeploymentFixtures(t, app)
+
+	payload := map[string]interface{}{
+		"serviceId":     seeded.ServiceID,
+		"environmentId": env.ID,
+		"version":       "3.0.0",
+		"status":        "running",
+		"config": map[string]interface{}{
+			"replicas": 2,
+			"database": map[string]interface{}{"password": "secret://p@ssw0rd"},
+		},
+	}
+	w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/service-instances", payload)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "p@ssw0rd")
+	var created struct {
+		Data struct {
+			ID     uint                   `json:"id"`
+			Config map[string]interface{} `json:"config"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, map[string]interface{}{"password": utils.SecretMask}, created.Data.Config["database"])
+	instancePath := fmt.Sprintf("/api/v1/service-instances/%d", created.Data.ID)
+
+	t.Run("Secret is stored encrypted", func(t *testing.T) {
+		var instance model.ServiceInstance
+		require.NoError(t, app.DB.First(&instance, created.Data.ID).Error)
+		password, ok := utils.LookupJSONPath(instance.Config, "database.password")
+		require.True(t, ok)
+		assert.True(t, utils.IsEncryptedSecret(password))
+	})
+
+	t.Run("Reads and revisions are masked", func(t *testing.T) {
+		for _, path := range []string{instancePath, instancePath + "/config/revisions", instancePath + "/config/revisions/1"} {
+			w := DoRequestForTest(t, app.Router, token, http.MethodGet, path, nil)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.NotContains(t, w.Body.String(), "p@ssw0rd", path)
+			assert.NotContains(t, w.Body.String(), "enc:v1:", path)
+			assert.Contains(t, w.Body.String(), utils.SecretMask, path)
+		}
+	})
+
+	t.Run("Round-tripping the masked config keeps the secret", func(t *testing.T) {
+		payload["config"] = created.Data.Config
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, instancePath, payload)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, instancePath+"/config/revisions", nil)
+		var resp struct {
+			Data struct {
+				Total int64 `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(1), resp.Data.Total)
+	})
+
+	t.Run("Reveal is forbidden without permission", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, instancePath+"/config/secrets/reveal", nil)
+		assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	})
+
+	t.Run("Reveal returns plaintext with permission", func(t *testing.T) {
+		grantPermissionForTest(t, app, token, model.PermissionRevealConfigSecrets)
+
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, instancePath+"/config/secrets/reveal", map[string]interface{}{"keys": []string{"database.password"}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				Secrets map[string]string `json:"secrets"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, map[string]string{"database.password": "p@ssw0rd"}, resp.Data.Secrets)
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodPost, instancePath+"/config/secrets/reveal", map[string]interface{}{"keys": []string{"replicas"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+}
@@ -0,0 +1,114 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"EffiPlat/backend/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedDeploymentFixtures creates an environment, a service and one running instance of it.
+func TestDeploymentRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	env, instance := seedDeploymentFixtures(t, app)
+
+	instancePath := fmt.Sprintf("/api/v1/service-instances/%d", instance.ID)
+
+	t.Run("CI reports a successful deployment", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, instancePath+"/deployments", map[string]interface{}{
+			"version":     "1.1.0",
+			"status":      "success",
+			"triggeredBy": "jenkins#42",
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var resp struct {
+			Data model.Deployment `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "1.1.0", resp.Data.Version)
+		assert.Equal(t, "1.0.0", resp.Data.PreviousVersion)
+		assert.Equal(t, model.DeploymentSourceCI, resp.Data.Source)
+		assert.Equal(t, "jenkins#42", resp.Data.TriggeredBy)
+		assert.NotNil(t, resp.Data.TriggeredByID)
+
+		var updated model.ServiceInstance
+		require.NoError(t, app.DB.First(&updated, instance.ID).Error)
+		assert.Equal(t, "1.1.0", updated.Version)
+		assert.NotNil(t, updated.DeployedAt)
+	})
+
+	t.Run("Failed deployment keeps the current version", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, instancePath+"/deployments", map[string]interface{}{
+			"version": "1.2.0",
+			"status":  "failed",
+			"notes":   "health check timed out",
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var updated model.ServiceInstance
+		require.NoError(t, app.DB.First(&updated, instance.ID).Error)
+		assert.Equal(t, "1.1.0", updated.Version)
+		assert.Equal(t, model.ServiceInstanceStatusError, updated.Status)
+	})
+
+	t.Run("Version change through update is recorded", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPut, instancePath, map[string]interface{}{
+			"serviceId":     instance.ServiceID,
+			"environmentId": instance.EnvironmentID,
+			"version":       "1.3.0",
+			"status":        "running",
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+
+	t.Run("Environment timeline lists newest first", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/environments/%d/deployments", env.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp struct {
+			Data struct {
+				Items []model.Deployment `json:"items"`
+				Total int64              `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(3), resp.Data.Total)
+		require.Len(t, resp.Data.Items, 3)
+		assert.Equal(t, "1.3.0", resp.Data.Items[0].Version)
+		assert.Equal(t, model.DeploymentSourceManual, resp.Data.Items[0].Source)
+		assert.Equal(t, model.DeploymentStatusFailed, resp.Data.Items[1].Status)
+	})
+
+	t.Run("Instance history supports status filter", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, instancePath+"/deployments?status=success", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp struct {
+			Data struct {
+				Total int64 `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(2), resp.Data.Total)
+	})
+
+	t.Run("Unknown instance and environment return 404", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/service-instances/999999/deployments", map[string]interface{}{"version": "1.0.0", "status": "success"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, "/api/v1/environments/999999/deployments", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Invalid status is rejected", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodPost, instancePath+"/deployments", map[string]interface{}{"version": "2.0.0", "status": "exploded"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
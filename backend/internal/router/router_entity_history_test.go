@@ -0,0 +1,104 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityHistoryRoutes(t *testing.T) {
+	app := SetupTestApp(t)
+	token := GetAuthTokenForTest(t, app.Router, app.DB)
+	suffix := time.Now().UnixNano()
+
+	asOf := func(at time.Time) string {
+		return "?asOf=" + url.QueryEscape(at.Format(time.RFC3339Nano))
+	}
+
+	beforeCreate := time.Now().Add(-time.Second)
+	w := DoRequestForTest(t, app.Router, token, http.MethodPost, "/api/v1/businesses", map[string]interface{}{"name": fmt.Sprintf("History %d", suffix), "owner": "first@example.com"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data struct {
+			ID uint `json:"id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	path := fmt.Sprintf("/api/v1/businesses/%d", created.Data.ID)
+
+	afterCreate := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	w = DoRequestForTest(t, app.Router, token, http.MethodPut, path, map[string]interface{}{"owner": "second@example.com"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	t.Run("History", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, path+"/history", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data service.ListEntityVersionsResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.EqualValues(t, 2, resp.Data.Total)
+		require.Len(t, resp.Data.Items, 2)
+		assert.Equal(t, model.EntityVersionUpdate, resp.Data.Items[0].Action)
+		assert.Equal(t, model.EntityResourceBusiness, resp.Data.Items[0].Resource)
+		assert.NotNil(t, resp.Data.Items[0].ChangedByID)
+		assert.NotEmpty(t, resp.Data.Items[0].ChangedBy)
+		assert.JSONEq(t, `"second@example.com"`, string(jsonField(t, resp.Data.Items[0].Snapshot, "owner")))
+	})
+
+	t.Run("AsOf", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, path+asOf(afterCreate), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data model.EntityVersion `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.Data.Version)
+		assert.JSONEq(t, `"first@example.com"`, string(jsonField(t, resp.Data.Snapshot, "owner")))
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, path+asOf(beforeCreate), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+
+	t.Run("AsOfAfterDelete", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, DoRequestForTest(t, app.Router, token, http.MethodGet, path, nil).Code)
+		w := DoRequestForTest(t, app.Router, token, http.MethodDelete, path, nil)
+		require.True(t, w.Code == http.StatusOK || w.Code == http.StatusNoContent, w.Body.String())
+
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, path+asOf(time.Now()), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+		w = DoRequestForTest(t, app.Router, token, http.MethodGet, path+asOf(afterCreate), nil)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+
+	t.Run("InvalidAsOf", func(t *testing.T) {
+		w := DoRequestForTest(t, app.Router, token, http.MethodGet, path+"?asOf=yesterday", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	t.Run("EveryEntityHasHistory", func(t *testing.T) {
+		for _, resources := range []string{"environments", "assets", "services", "service-instances", "businesses", "bugs"} {
+			w := DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/%s/999999/history", resources), nil)
+			assert.Equal(t, http.StatusNotFound, w.Code, "%s: %s", resources, w.Body.String())
+			w = DoRequestForTest(t, app.Router, token, http.MethodGet, fmt.Sprintf("/api/v1/%s/999999%s", resources, asOf(time.Now())), nil)
+			assert.Equal(t, http.StatusNotFound, w.Code, "%s: %s", resources, w.Body.String())
+		}
+	})
+}
+
+// jsonField returns the raw JSON of a top-level field of doc.
+func jsonField(t *testing.T, doc []byte, field string) json.RawMessage {
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(doc, &fields))
+	return fields[field]
+}
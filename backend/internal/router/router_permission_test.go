@@ -108,7 +108,7 @@ func TestPermissionManagementRoutes(t *testing.T) {
 				Items    []model.Permission `json:"items"`
 				Total    int64               `json:"total"`
 				Page     int                 `json:"page"`
-				pageSize int                 `json:"pageSize"` // Note: JSON tag should be "pageSize"
+				PageSize int                 `json:"pageSize"`
 			} `json:"data"`
 		}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
@@ -2,24 +2,30 @@ package router
 
 import (
 	"EffiPlat/backend/internal/handler"
+	"EffiPlat/backend/internal/middleware"
 	"EffiPlat/backend/internal/model"
 	pkgmodel "EffiPlat/backend/internal/model"
 	"EffiPlat/backend/internal/pkg/config"
 	pkgdb "EffiPlat/backend/internal/pkg/database"
 	"EffiPlat/backend/internal/pkg/logger"
+	"EffiPlat/backend/internal/pkg/storage"
 	"EffiPlat/backend/internal/repository"
 	"EffiPlat/backend/internal/service"
+	"EffiPlat/backend/internal/utils"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -48,8 +54,14 @@ type TestAppComponents struct {
 	ServiceInstanceHandler     *handler.ServiceInstanceHandler
 	BusinessHandler            *handler.BusinessHandler
 	BugHandler                 *handler.BugHandler
+	AttachmentHandler          *handler.AttachmentHandler
+	DeploymentHandler          *handler.DeploymentHandler
+	ConfigRevisionHandler      *handler.ConfigRevisionHandler
+	EntityHistoryHandler       *handler.EntityHistoryHandler
 	AuditLogHandler            *handler.AuditLogHandler   // 新增审计日志处理器
 	AuditLogService            service.AuditLogService   // 新增审计日志服务
+	AuditArchiveService        service.AuditArchiveService
+	ReadAuditor                *middleware.ReadAuditor
 	JWTKey                     []byte
 }
 
@@ -69,6 +81,11 @@ func SetupTestApp(t *testing.T) TestAppComponents {
 
 	db, err := pkgdb.NewConnection(cfg.Database, appLogger)
 	assert.NoError(t, err)
+	// The audit pipeline writes in the background. Connections sharing an in-memory cache fail
+	// with "database table is locked" instead of waiting, so use a single connection.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
 	// err = pkgdb.AutoMigrate(db, appLogger) // Ensure all tables including new ones are migrated
 	// Directly migrate all necessary models for tests, including new ones
 	err = db.AutoMigrate(
@@ -84,6 +101,24 @@ func SetupTestApp(t *testing.T) TestAppComponents {
 		&model.ServiceInstance{}, // Changed to model.ServiceInstance
 		&model.Business{},        // Changed to model.Business
 		&model.AuditLog{},        // Added AuditLog model for migration
+		&model.AuditLogArchive{},
+		&model.AuditLogChange{},
+		&model.AuditChainLock{},
+		&model.Deployment{},
+		&model.ConfigRevision{},
+		&model.EntityVersion{},
+		&model.Bug{},
+		&model.BugStatusTransition{},
+		&model.BugEnvironmentSnapshot{},
+		&model.ResponsibilityGroupMember{},
+		&model.BugAssignmentRule{},
+		&model.BugSLAPolicy{},
+		&model.BugComment{},
+		&model.BugRelation{},
+		&model.BugCommentMention{},
+		&model.BugCommentRevision{},
+		&model.BugFieldChange{},
+		&model.Attachment{},
 	)
 	assert.NoError(t, err, "AutoMigrate should not fail")
 
@@ -99,15 +134,27 @@ func SetupTestApp(t *testing.T) TestAppComponents {
 	serviceTypeRepo := repository.NewGormServiceTypeRepository(db)                // Added ServiceTypeRepository
 	serviceInstanceRepo := repository.NewServiceInstanceRepository(db, appLogger) // Added
 	bugRepo := repository.NewBugRepository(db, appLogger) // Added BugRepository
-	auditLogRepo := repository.NewAuditLogRepository(db, appLogger) // 审计日志存储库
+	auditLogRepo := repository.NewAuditLogRepository(db, nil, appLogger) // 审计日志存储库
 	businessRepo := repository.NewBusinessRepository(db, appLogger)               // Added
+	deploymentRepo := repository.NewDeploymentRepository(db, appLogger)
+	configRevisionRepo := repository.NewConfigRevisionRepository(db, appLogger)
+	entityHistoryRepo := repository.NewEntityHistoryRepository(db, appLogger)
+	bugAssignmentRuleRepo := repository.NewBugAssignmentRuleRepository(db, appLogger)
+	attachmentRepo := repository.NewAttachmentRepository(db, appLogger)
+	bugSLAPolicyRepo := repository.NewBugSLAPolicyRepository(db, appLogger)
 
 	// Initialize services
 	jwtKey := []byte(os.Getenv("JWT_SECRET_TEST"))
 	if len(jwtKey) == 0 {
 		jwtKey = []byte("test_secret_key_for_router_tests_effiplat")
 	}
-	authService := service.NewAuthService(userRepo, jwtKey, appLogger)
+	auditLogPipeline := service.NewAuditPipeline(auditLogRepo, service.AuditPipelineConfig{
+		FlushInterval: 20 * time.Millisecond,
+		SpillFile:     filepath.Join(t.TempDir(), "audit-spill.jsonl"),
+	}, appLogger)
+	t.Cleanup(func() { _ = auditLogPipeline.Close(context.Background()) })
+	auditLogService := service.NewAuditLogService(auditLogRepo, auditLogPipeline, permRepo, appLogger) // 审计日志服务
+	authService := service.NewAuthService(userRepo, jwtKey, auditLogService, appLogger)
 	userService := service.NewUserService(userRepo, roleRepo, appLogger)
 	roleService := service.NewRoleService(roleRepo, appLogger)
 	permissionService := service.NewPermissionService(permRepo, roleRepo, appLogger)
@@ -116,10 +163,20 @@ func SetupTestApp(t *testing.T) TestAppComponents {
 	environmentService := service.NewEnvironmentService(environmentRepo, appLogger)
 	assetService := service.NewAssetService(assetRepo, environmentRepo, appLogger)
 	serviceService := service.NewServiceService(serviceRepo, serviceTypeRepo, appLogger)                                      // Renamed serviceSvc to serviceService and added logger
-	serviceInstanceService := service.NewServiceInstanceService(serviceInstanceRepo, serviceRepo, environmentRepo, appLogger) // Added
+	secretCipher, err := utils.NewSecretCipher([]byte("test_config_secret_key_for_router_tests"))
+	require.NoError(t, err)
+	serviceInstanceService := service.NewServiceInstanceService(serviceInstanceRepo, serviceRepo, environmentRepo, deploymentRepo, configRevisionRepo, permRepo, secretCipher, appLogger) // Added
 	businessService := service.NewBusinessService(businessRepo, appLogger)                                                    // Added
-	bugService := service.NewBugService(bugRepo)
-	auditLogService := service.NewAuditLogService(auditLogRepo, appLogger) // 审计日志服务
+	bugAssignmentService := service.NewBugAssignmentService(bugAssignmentRuleRepo, responsibilityGroupRepo, environmentRepo, serviceRepo, businessRepo, appLogger)
+	bugSLAService := service.NewBugSLAService(bugSLAPolicyRepo, bugRepo, bugAssignmentRuleRepo, responsibilityGroupRepo, appLogger)
+	bugService := service.NewBugService(bugRepo, environmentRepo, serviceRepo, serviceInstanceRepo, businessRepo, userRepo, model.DefaultBugWorkflow(), bugAssignmentService, bugSLAService)
+	deploymentService := service.NewDeploymentService(deploymentRepo, serviceInstanceRepo, environmentRepo, appLogger)
+	configRevisionService := service.NewConfigRevisionService(configRevisionRepo, serviceInstanceRepo, serviceRepo, environmentRepo, secretCipher, appLogger)
+	entityHistoryService := service.NewEntityHistoryService(entityHistoryRepo, appLogger)
+	attachmentStore, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	attachmentLimits := service.AttachmentLimits{MaxSize: 64 << 10, AllowedTypes: service.DefaultAttachmentLimits.AllowedTypes}
+	attachmentService := service.NewAttachmentService(attachmentRepo, bugRepo, businessRepo, serviceRepo, permRepo, attachmentStore, attachmentLimits, appLogger)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
@@ -133,8 +190,18 @@ func SetupTestApp(t *testing.T) TestAppComponents {
 	serviceHandler := handler.NewServiceHandler(serviceService, auditLogService, appLogger)                     // Use handler.NewServiceHandler
 	serviceInstanceHandler := handler.NewServiceInstanceHandler(serviceInstanceService, auditLogService, appLogger) // Added
 	businessHandler := handler.NewBusinessHandler(businessService, appLogger)                      // Added
-	bugHandler := handler.NewBugHandler(bugService) // Added BugHandler
-	auditLogHandler := handler.NewAuditLogHandler(auditLogService, appLogger) // 审计日志处理器
+	bugHandler := handler.NewBugHandler(bugService, auditLogService) // Added BugHandler
+	bugAssignmentHandler := handler.NewBugAssignmentHandler(bugAssignmentService, auditLogService, appLogger)
+	bugSLAHandler := handler.NewBugSLAHandler(bugSLAService, auditLogService, appLogger)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService, auditLogService, appLogger)
+	deploymentHandler := handler.NewDeploymentHandler(deploymentService, auditLogService, appLogger)
+	configRevisionHandler := handler.NewConfigRevisionHandler(configRevisionService, auditLogService, appLogger)
+	entityHistoryHandler := handler.NewEntityHistoryHandler(entityHistoryService, appLogger)
+	auditArchiveStore, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	auditArchiveService := service.NewAuditArchiveService(auditLogRepo, auditArchiveStore, auditLogService, service.AuditRetentionPolicy{Retention: 180 * 24 * time.Hour}, appLogger)
+	auditLogHandler := handler.NewAuditLogHandler(auditLogService, auditArchiveService, appLogger) // 审计日志处理器
+	readAuditor := middleware.NewReadAuditor(auditLogService, model.ReadAuditPolicy{Window: middleware.DefaultReadAuditWindow}, appLogger)
 
 	routerInstance := SetupRouter(
 		authHandler,
@@ -149,8 +216,15 @@ func SetupTestApp(t *testing.T) TestAppComponents {
 		serviceInstanceHandler, // Pass the new handler
 		businessHandler,        // Pass the new handler
 		bugHandler,             // Pass the new handler
+		bugAssignmentHandler,
+		bugSLAHandler,
+		attachmentHandler,
+		deploymentHandler,
+		configRevisionHandler,
+		entityHistoryHandler,
 		auditLogHandler,
 		auditLogService,
+		readAuditor,
 		jwtKey,
 	)
 
@@ -170,8 +244,14 @@ func SetupTestApp(t *testing.T) TestAppComponents {
 		ServiceInstanceHandler:     serviceInstanceHandler, // Added
 		BusinessHandler:            businessHandler,        // Added
 		BugHandler:                 bugHandler,             // Added
+		AttachmentHandler:          attachmentHandler,
+		DeploymentHandler:          deploymentHandler,
+		ConfigRevisionHandler:      configRevisionHandler,
+		EntityHistoryHandler:       entityHistoryHandler,
 		AuditLogHandler:            auditLogHandler,
 		AuditLogService:            auditLogService,
+		ReadAuditor:                readAuditor,
+		AuditArchiveService:        auditArchiveService,
 		JWTKey:                     jwtKey,
 	}
 }
@@ -321,3 +401,53 @@ func CreateTestResponsibility(t *testing.T, router http.Handler, token string, r
 	assert.NotZero(t, createdResp.Data.ID)
 	return createdResp.Data
 }
+
+// DoRequestForTest sends a request to r and returns the recorded response. A non-nil body is
+// encoded as JSON, and a non-empty token is sent as a Bearer token.
+func DoRequestForTest(t *testing.T, r *gin.Engine, token, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req, _ := http.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// seedDeploymentFixtures creates an environment, service and running service instance with unique names.
+func seedDeploymentFixtures(t *testing.T, app TestAppComponents) (*model.Environment, *model.ServiceInstance) {
+	suffix := time.Now().UnixNano()
+
+	env := &model.Environment{Name: fmt.Sprintf("deploy-env-%d", suffix), Slug: fmt.Sprintf("deploy-env-%d", suffix)}
+	require.NoError(t, app.DB.Create(env).Error)
+
+	serviceType := &model.ServiceType{Name: fmt.Sprintf("deploy-type-%d", suffix)}
+	require.NoError(t, app.DB.Create(serviceType).Error)
+
+	svc := &model.Service{Name: fmt.Sprintf("deploy-svc-%d", suffix), ServiceTypeID: serviceType.ID, Status: model.ServiceStatusActive}
+	require.NoError(t, app.DB.Create(svc).Error)
+
+	instance := &model.ServiceInstance{ServiceID: svc.ID, EnvironmentID: env.ID, Version: "1.0.0", Status: model.ServiceInstanceStatusRunning}
+	require.NoError(t, app.DB.Create(instance).Error)
+
+	return env, instance
+}
+
+// grantPermissionForTest gives the user behind token a fresh role holding the named permission.
+func grantPermissionForTest(t *testing.T, app TestAppComponents, token, permissionName string) {
+	claims := &model.Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return app.JWTKey, nil })
+	require.NoError(t, err)
+
+	permission := model.Permission{Name: permissionName, Resource: "test", Action: permissionName}
+	require.NoError(t, app.DB.Where(model.Permission{Name: permissionName}).FirstOrCreate(&permission).Error)
+	role := model.Role{Name: fmt.Sprintf("perm-role-%d", time.Now().UnixNano())}
+	require.NoError(t, app.DB.Create(&role).Error)
+	require.NoError(t, app.DB.Create(&model.RolePermission{RoleID: role.ID, PermissionID: permission.ID}).Error)
+	require.NoError(t, app.DB.Create(&model.UserRole{UserID: claims.UserID, RoleID: role.ID}).Error)
+}
@@ -28,6 +28,41 @@ func SeedRoles(db *gorm.DB) error {
 	return nil
 }
 
+// SeedPermissions creates permissions that are checked by the application and grants them to the admin role.
+func SeedPermissions(db *gorm.DB) error {
+	fmt.Println("Seeding permissions...")
+
+	if err := SeedRoles(db); err != nil {
+		return err
+	}
+
+	var adminRole model.Role
+	if err := db.Where("name = ?", "admin").First(&adminRole).Error; err != nil {
+		return fmt.Errorf("failed to find admin role: %w", err)
+	}
+
+	permissions := []model.Permission{
+		{Name: model.PermissionRevealConfigSecrets, Description: "Reveal secret values in service instance configs", Resource: "service_instance", Action: "reveal_secrets"},
+		{Name: model.PermissionDownloadBugAttachments, Description: "Download files attached to bugs", Resource: "bug", Action: "download_attachments"},
+		{Name: model.PermissionDownloadBusinessAttachments, Description: "Download files attached to businesses", Resource: "business", Action: "download_attachments"},
+		{Name: model.PermissionDownloadServiceAttachments, Description: "Download files attached to services", Resource: "service", Action: "download_attachments"},
+		{Name: model.PermissionDeleteAttachments, Description: "Delete attachments uploaded by other users", Resource: "attachment", Action: "delete"},
+		{Name: model.PermissionStreamAuditLogs, Description: "Watch new audit log entries as they are written", Resource: "audit_log", Action: "stream"},
+	}
+
+	for _, permission := range permissions {
+		if err := db.FirstOrCreate(&permission, model.Permission{Name: permission.Name}).Error; err != nil {
+			return fmt.Errorf("failed to seed permission %s: %w", permission.Name, err)
+		}
+		if err := db.Model(&adminRole).Association("Permissions").Append(&permission); err != nil {
+			return fmt.Errorf("failed to grant permission %s to admin role: %w", permission.Name, err)
+		}
+	}
+
+	fmt.Println("Permission seeding complete.")
+	return nil
+}
+
 // SeedUsers creates some sample users.
 func SeedUsers(db *gorm.DB) error {
 	fmt.Println("Seeding users...")
@@ -84,6 +119,10 @@ func SeedAll(db *gorm.DB) error {
 		return err
 	}
 
+	if err := SeedPermissions(db); err != nil {
+		return err
+	}
+
 	// Add calls to other seeders here later, e.g.:
 	// if err := SeedEnvironments(db); err != nil {
 	//     return err
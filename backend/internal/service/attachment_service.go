@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"unicode"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/pkg/storage"
+	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AttachmentLimits bounds what can be uploaded.
+type AttachmentLimits struct {
+	MaxSize int64 // Bytes
+	// AllowedTypes lists the accepted MIME types, detected from the content rather than
+	// trusted from the client. "image/*" accepts a whole family.
+	AllowedTypes []string
+}
+
+// DefaultAttachmentLimits accepts logs, screenshots, PDFs and archives (which includes
+// Office documents) up to 20 MiB.
+var DefaultAttachmentLimits = AttachmentLimits{
+	MaxSize:      20 << 20,
+	AllowedTypes: []string{"image/*", "text/*", "application/pdf", "application/zip", "application/x-gzip"},
+}
+
+// Allows reports whether contentType is one of the allowed types.
+func (l AttachmentLimits) Allows(contentType string) bool {
+	for _, allowed := range l.AllowedTypes {
+		if family, ok := strings.CutSuffix(allowed, "/*"); ok {
+			if strings.HasPrefix(contentType, family+"/") {
+				return true
+			}
+		} else if contentType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// AttachmentService defines the interface for attachments on bugs, businesses and services.
+type AttachmentService interface {
+	// Upload stores the content read from r as an attachment of the owner. Uploading a
+	// file the owner already has returns the existing attachment and created == false.
+	Upload(ctx context.Context, ownerType model.AttachmentOwnerType, ownerID uint, fileName string, r io.Reader) (attachment *model.Attachment, created bool, err error)
+	List(ctx context.Context, ownerType model.AttachmentOwnerType, ownerID uint) ([]*model.Attachment, error)
+	Get(ctx context.Context, id uint) (*model.Attachment, error)
+	// Open checks that the actor may download the attachment and opens its content.
+	// The caller must close the reader.
+	Open(ctx context.Context, id uint) (*model.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, id uint) (*model.Attachment, error)
+	Limits() AttachmentLimits
+}
+
+// attachmentServiceImpl implements AttachmentService.
+type attachmentServiceImpl struct {
+	repo         repository.AttachmentRepository
+	bugRepo      repository.BugRepository
+	businessRepo repository.BusinessRepository
+	serviceRepo  repository.ServiceRepository
+	permRepo     repository.PermissionRepository
+	store        storage.Storage
+	limits       AttachmentLimits
+	logger       *zap.Logger
+}
+
+// NewAttachmentService creates a new AttachmentService.
+func NewAttachmentService(
+	repo repository.AttachmentRepository,
+	bugRepo repository.BugRepository,
+	businessRepo repository.BusinessRepository,
+	serviceRepo repository.ServiceRepository,
+	permRepo repository.PermissionRepository,
+	store storage.Storage,
+	limits AttachmentLimits,
+	logger *zap.Logger,
+) AttachmentService {
+	return &attachmentServiceImpl{
+		repo:         repo,
+		bugRepo:      bugRepo,
+		businessRepo: businessRepo,
+		serviceRepo:  serviceRepo,
+		permRepo:     permRepo,
+		store:        store,
+		limits:       limits,
+		logger:       logger,
+	}
+}
+
+func (s *attachmentServiceImpl) Limits() AttachmentLimits {
+	return s.limits
+}
+
+// checkOwner makes sure the record attachments are added to or listed for exists.
+func (s *attachmentServiceImpl) checkOwner(ctx context.Context, ownerType model.AttachmentOwnerType, ownerID uint) error {
+	var err error
+	switch ownerType {
+	case model.AttachmentOwnerBug:
+		_, err = s.bugRepo.GetByID(ctx, ownerID)
+	case model.AttachmentOwnerBusiness:
+		_, err = s.businessRepo.GetByID(ctx, ownerID)
+	case model.AttachmentOwnerService:
+		_, err = s.serviceRepo.GetByID(ctx, ownerID)
+	default:
+		return fmt.Errorf("%w: invalid attachment owner type '%s'", apputils.ErrBadRequest, ownerType)
+	}
+	if errors.Is(err, repository.ErrBugNotFound) || errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, model.ErrServiceNotFound) {
+		return fmt.Errorf("%w: %s %d", apputils.ErrNotFound, ownerType, ownerID)
+	}
+	return err
+}
+
+// Upload spools the content to a temporary file while hashing it, so the size limit is
+// enforced and the type detected before anything reaches the storage backend. Content is
+// stored once per SHA-256, however many attachments share it.
+func (s *attachmentServiceImpl) Upload(ctx context.Context, ownerType model.AttachmentOwnerType, ownerID uint, fileName string, r io.Reader) (*model.Attachment, bool, error) {
+	fileName = sanitizeFileName(fileName)
+	if fileName == "" {
+		return nil, false, fmt.Errorf("%w: file name is required", apputils.ErrBadRequest)
+	}
+	if err := s.checkOwner(ctx, ownerType, ownerID); err != nil {
+		return nil, false, err
+	}
+
+	tmp, err := os.CreateTemp("", "effiplat-attachment-*")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to spool attachment: %w", err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, s.limits.MaxSize+1))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if size > s.limits.MaxSize {
+		return nil, false, fmt.Errorf("%w: the limit is %d bytes", model.ErrAttachmentTooLarge, s.limits.MaxSize)
+	}
+	if size == 0 {
+		return nil, false, fmt.Errorf("%w: file is empty", apputils.ErrBadRequest)
+	}
+
+	head := make([]byte, 512)
+	n, err := tmp.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, false, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if !s.limits.Allows(contentType) {
+		return nil, false, fmt.Errorf("%w: %s", model.ErrAttachmentTypeNotAllowed, contentType)
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	existing, err := s.repo.FindByOwnerAndHash(ctx, ownerType, ownerID, sum)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, false, nil
+	}
+
+	key := "sha256/" + sum[:2] + "/" + sum
+	stored, err := s.store.Exists(ctx, key)
+	if err != nil {
+		s.logger.Error("Failed to check stored attachment", zap.String("key", key), zap.Error(err))
+		return nil, false, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if !stored {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, false, fmt.Errorf("failed to store attachment: %w", err)
+		}
+		if err := s.store.Put(ctx, key, tmp, size, contentType); err != nil {
+			s.logger.Error("Failed to store attachment", zap.String("key", key), zap.Error(err))
+			return nil, false, fmt.Errorf("failed to store attachment: %w", err)
+		}
+	}
+
+	attachment := &model.Attachment{
+		OwnerType:    ownerType,
+		OwnerID:      ownerID,
+		FileName:     fileName,
+		ContentType:  contentType,
+		Size:         size,
+		SHA256:       sum,
+		StorageKey:   key,
+		UploadedByID: apputils.ActorUserID(ctx),
+	}
+	if err := s.repo.Create(ctx, attachment); err != nil {
+		return nil, false, err
+	}
+	return attachment, true, nil
+}
+
+// List returns the attachments of a bug, business or service.
+func (s *attachmentServiceImpl) List(ctx context.Context, ownerType model.AttachmentOwnerType, ownerID uint) ([]*model.Attachment, error) {
+	if err := s.checkOwner(ctx, ownerType, ownerID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByOwner(ctx, ownerType, ownerID)
+}
+
+// Get returns an attachment's metadata.
+func (s *attachmentServiceImpl) Get(ctx context.Context, id uint) (*model.Attachment, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Open requires the owner type's download permission unless the actor uploaded the file.
+func (s *attachmentServiceImpl) Open(ctx context.Context, id uint) (*model.Attachment, io.ReadCloser, error) {
+	attachment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.authorize(ctx, attachment, attachment.OwnerType.DownloadPermission()); err != nil {
+		return nil, nil, err
+	}
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		s.logger.Error("Failed to open stored attachment", zap.Uint("id", id), zap.String("key", attachment.StorageKey), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to open attachment content: %w", err)
+	}
+	return attachment, content, nil
+}
+
+// Delete removes an attachment, and its stored content once no attachment shares it.
+// Only the uploader or a holder of model.PermissionDeleteAttachments may delete it.
+func (s *attachmentServiceImpl) Delete(ctx context.Context, id uint) (*model.Attachment, error) {
+	attachment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, attachment, model.PermissionDeleteAttachments); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+
+	remaining, err := s.repo.CountByStorageKey(ctx, attachment.StorageKey)
+	if err != nil {
+		s.logger.Warn("Failed to count attachments sharing content, keeping it", zap.String("key", attachment.StorageKey), zap.Error(err))
+		return attachment, nil
+	}
+	if remaining == 0 {
+		if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+			s.logger.Warn("Failed to delete stored attachment content", zap.String("key", attachment.StorageKey), zap.Error(err))
+		}
+	}
+	return attachment, nil
+}
+
+// authorize lets the uploader through and otherwise requires the permission.
+func (s *attachmentServiceImpl) authorize(ctx context.Context, attachment *model.Attachment, permission string) error {
+	userID := apputils.ActorUserID(ctx)
+	if userID == nil {
+		return apputils.ErrUnauthorized
+	}
+	if attachment.UploadedByID != nil && *attachment.UploadedByID == *userID {
+		return nil
+	}
+	allowed, err := s.permRepo.UserHasPermission(ctx, *userID, permission)
+	if err != nil {
+		s.logger.Error("Failed to check attachment permission", zap.Uint("userId", *userID), zap.Error(err))
+		return fmt.Errorf("failed to check permission: %w", err)
+	}
+	if !allowed {
+		return fmt.Errorf("%w: missing permission '%s'", apputils.ErrForbidden, permission)
+	}
+	return nil
+}
+
+// sanitizeFileName keeps the base name of an uploaded file, without control characters.
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "." || name == "/" {
+		return ""
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[len(runes)-255:])
+	}
+	return name
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/pkg/storage"
+	mock_repository "EffiPlat/backend/internal/repository/mocks"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestAttachmentLimits_Allows(t *testing.T) {
+	limits := AttachmentLimits{AllowedTypes: []string{"image/*", "application/pdf"}}
+	assert.True(t, limits.Allows("image/png"))
+	assert.True(t, limits.Allows("application/pdf"))
+	assert.False(t, limits.Allows("application/zip"))
+	assert.False(t, limits.Allows("imagex/png"))
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	assert.Equal(t, "shadow", sanitizeFileName("../../etc/shadow"))
+	assert.Equal(t, "report.pdf", sanitizeFileName(`C:\Users\me\report.pdf`))
+	assert.Equal(t, "log.txt", sanitizeFileName("log\x00.txt\n"))
+	assert.Equal(t, "", sanitizeFileName("  "))
+	assert.Equal(t, "", sanitizeFileName("/"))
+}
+
+func TestAttachmentService(t *testing.T) {
+	uploaderCtx := apputils.WithActor(context.Background(), apputils.Actor{UserID: 7, Username: "alice"})
+	otherCtx := apputils.WithActor(context.Background(), apputils.Actor{UserID: 8, Username: "bob"})
+	uploader := uint(7)
+
+	type fixture struct {
+		svc          AttachmentService
+		repo         *mock_repository.MockAttachmentRepository
+		bugRepo      *MockBugRepository
+		businessRepo *mock_repository.MockBusinessRepository
+		permRepo     *mock_repository.MockPermissionRepository
+		store        storage.Storage
+	}
+	setup := func(t *testing.T) fixture {
+		ctrl := gomock.NewController(t)
+		store, err := storage.NewLocalStorage(t.TempDir())
+		require.NoError(t, err)
+		f := fixture{
+			repo:         mock_repository.NewMockAttachmentRepository(ctrl),
+			bugRepo:      new(MockBugRepository),
+			businessRepo: mock_repository.NewMockBusinessRepository(ctrl),
+			permRepo:     mock_repository.NewMockPermissionRepository(ctrl),
+			store:        store,
+		}
+		limits := AttachmentLimits{MaxSize: 1024, AllowedTypes: []string{"image/*", "text/*"}}
+		f.svc = NewAttachmentService(f.repo, f.bugRepo, f.businessRepo, nil, f.permRepo, store, limits, zap.NewNop())
+		return f
+	}
+
+	t.Run("Upload stores the content once per hash", func(t *testing.T) {
+		f := setup(t)
+		content := append(append([]byte{}, pngHeader...), "screenshot"...)
+		sum := sha256.Sum256(content)
+		hash := hex.EncodeToString(sum[:])
+		f.bugRepo.On("GetByID", uploaderCtx, uint(1)).Return(&model.Bug{ID: 1}, nil).Twice()
+		f.repo.EXPECT().FindByOwnerAndHash(uploaderCtx, model.AttachmentOwnerBug, uint(1), hash).Return(nil, nil)
+		f.repo.EXPECT().Create(uploaderCtx, gomock.Any()).DoAndReturn(func(_ context.Context, a *model.Attachment) error {
+			a.ID = 10
+			return nil
+		})
+
+		attachment, created, err := f.svc.Upload(uploaderCtx, model.AttachmentOwnerBug, 1, "../shot.png", bytes.NewReader(content))
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "shot.png", attachment.FileName)
+		assert.Equal(t, "image/png", attachment.ContentType)
+		assert.Equal(t, int64(len(content)), attachment.Size)
+		assert.Equal(t, hash, attachment.SHA256)
+		assert.Equal(t, "sha256/"+hash[:2]+"/"+hash, attachment.StorageKey)
+		assert.Equal(t, uploader, *attachment.UploadedByID)
+		exists, err := f.store.Exists(context.Background(), attachment.StorageKey)
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		// The same file again is answered with the existing attachment.
+		f.repo.EXPECT().FindByOwnerAndHash(uploaderCtx, model.AttachmentOwnerBug, uint(1), hash).Return(attachment, nil)
+		again, created, err := f.svc.Upload(uploaderCtx, model.AttachmentOwnerBug, 1, "copy.png", bytes.NewReader(content))
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, attachment.ID, again.ID)
+	})
+
+	t.Run("Upload limits", func(t *testing.T) {
+		f := setup(t)
+		f.bugRepo.On("GetByID", uploaderCtx, uint(1)).Return(&model.Bug{ID: 1}, nil)
+
+		_, _, err := f.svc.Upload(uploaderCtx, model.AttachmentOwnerBug, 1, "big.txt", strings.NewReader(strings.Repeat("x", 1025)))
+		assert.ErrorIs(t, err, model.ErrAttachmentTooLarge)
+
+		_, _, err = f.svc.Upload(uploaderCtx, model.AttachmentOwnerBug, 1, "doc.pdf", strings.NewReader("%PDF-1.4 spoofed as text"))
+		assert.ErrorIs(t, err, model.ErrAttachmentTypeNotAllowed)
+
+		_, _, err = f.svc.Upload(uploaderCtx, model.AttachmentOwnerBug, 1, "empty.txt", strings.NewReader(""))
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+	})
+
+	t.Run("Upload to a missing owner", func(t *testing.T) {
+		f := setup(t)
+		f.businessRepo.EXPECT().GetByID(uploaderCtx, uint(99)).Return(nil, gorm.ErrRecordNotFound)
+		_, _, err := f.svc.Upload(uploaderCtx, model.AttachmentOwnerBusiness, 99, "design.txt", strings.NewReader("spec"))
+		assert.ErrorIs(t, err, apputils.ErrNotFound)
+
+		_, _, err = f.svc.Upload(uploaderCtx, model.AttachmentOwnerType("asset"), 1, "a.txt", strings.NewReader("x"))
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+	})
+
+	t.Run("Download requires the permission unless you uploaded it", func(t *testing.T) {
+		f := setup(t)
+		require.NoError(t, f.store.Put(context.Background(), "sha256/ab/abc", strings.NewReader("log line"), 8, "text/plain"))
+		attachment := &model.Attachment{ID: 10, OwnerType: model.AttachmentOwnerBug, OwnerID: 1, StorageKey: "sha256/ab/abc", UploadedByID: &uploader}
+		f.repo.EXPECT().GetByID(gomock.Any(), uint(10)).Return(attachment, nil).Times(3)
+
+		_, content, err := f.svc.Open(uploaderCtx, 10)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(content)
+		content.Close()
+		assert.Equal(t, "log line", string(data))
+
+		f.permRepo.EXPECT().UserHasPermission(otherCtx, uint(8), model.PermissionDownloadBugAttachments).Return(false, nil)
+		_, _, err = f.svc.Open(otherCtx, 10)
+		assert.ErrorIs(t, err, apputils.ErrForbidden)
+
+		f.permRepo.EXPECT().UserHasPermission(otherCtx, uint(8), model.PermissionDownloadBugAttachments).Return(true, nil)
+		_, content, err = f.svc.Open(otherCtx, 10)
+		require.NoError(t, err)
+		content.Close()
+	})
+
+	t.Run("Delete keeps content still shared by another attachment", func(t *testing.T) {
+		f := setup(t)
+		require.NoError(t, f.store.Put(context.Background(), "sha256/ab/abc", strings.NewReader("log line"), 8, "text/plain"))
+		attachment := &model.Attachment{ID: 10, StorageKey: "sha256/ab/abc", UploadedByID: &uploader}
+		f.repo.EXPECT().GetByID(uploaderCtx, uint(10)).Return(attachment, nil)
+		f.repo.EXPECT().Delete(uploaderCtx, uint(10)).Return(nil)
+		f.repo.EXPECT().CountByStorageKey(uploaderCtx, "sha256/ab/abc").Return(int64(1), nil)
+		_, err := f.svc.Delete(uploaderCtx, 10)
+		require.NoError(t, err)
+		exists, _ := f.store.Exists(context.Background(), "sha256/ab/abc")
+		assert.True(t, exists)
+
+		attachment = &model.Attachment{ID: 11, StorageKey: "sha256/ab/abc", UploadedByID: &uploader}
+		f.permRepo.EXPECT().UserHasPermission(otherCtx, uint(8), model.PermissionDeleteAttachments).Return(true, nil)
+		f.repo.EXPECT().GetByID(otherCtx, uint(11)).Return(attachment, nil)
+		f.repo.EXPECT().Delete(otherCtx, uint(11)).Return(nil)
+		f.repo.EXPECT().CountByStorageKey(otherCtx, "sha256/ab/abc").Return(int64(0), nil)
+		_, err = f.svc.Delete(otherCtx, 11)
+		require.NoError(t, err)
+		exists, _ = f.store.Exists(context.Background(), "sha256/ab/abc")
+		assert.False(t, exists)
+	})
+
+	t.Run("Delete of a missing attachment", func(t *testing.T) {
+		f := setup(t)
+		f.repo.EXPECT().GetByID(uploaderCtx, uint(404)).Return(nil, model.ErrAttachmentNotFound)
+		_, err := f.svc.Delete(uploaderCtx, 404)
+		assert.True(t, errors.Is(err, model.ErrAttachmentNotFound))
+	})
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/pkg/storage"
+	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AuditRetentionPolicy 配置审计日志的保留期限和归档
+type AuditRetentionPolicy struct {
+	Retention            time.Duration // 早于该时长的日志被归档并清除；为0时永久保留
+	MaxEntriesPerArchive int           // 每个归档文件最多包含的日志数
+}
+
+// defaultAuditArchiveEntries 是未配置时每个归档文件的日志数
+const defaultAuditArchiveEntries = 50000
+
+// AuditArchiveService 将过期的审计日志导出为压缩的JSON Lines归档文件并清除，
+// 需要调查时可以把归档重新导入audit_logs。
+type AuditArchiveService interface {
+	// ArchiveExpired 导出早于保留期限的日志，回读校验每个归档文件后清除已归档的日志
+	ArchiveExpired(ctx context.Context, now time.Time) (*model.AuditArchiveRunResult, error)
+
+	// ListArchives 列出所有归档
+	ListArchives(ctx context.Context) ([]model.AuditLogArchive, error)
+
+	// ImportArchive 校验归档文件的校验和与哈希链后，将其中的日志写回audit_logs
+	ImportArchive(ctx context.Context, id uint) (*model.AuditLogArchive, error)
+
+	// UnloadArchive 清除ImportArchive写回的日志，归档文件保持不变
+	UnloadArchive(ctx context.Context, id uint) (*model.AuditLogArchive, int64, error)
+
+	// Run 每隔interval调用一次ArchiveExpired，启动时先执行一次，直到ctx结束
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// auditArchiveServiceImpl 实现了AuditArchiveService接口
+type auditArchiveServiceImpl struct {
+	repo         repository.AuditLogRepository
+	store        storage.Storage
+	auditService AuditLogService
+	policy       AuditRetentionPolicy
+	logger       *zap.Logger
+	mu           sync.Mutex // 同一进程内的归档依次进行
+}
+
+// NewAuditArchiveService 创建一个新的AuditArchiveService实例
+func NewAuditArchiveService(repo repository.AuditLogRepository, store storage.Storage, auditService AuditLogService, policy AuditRetentionPolicy, logger *zap.Logger) AuditArchiveService {
+	if policy.MaxEntriesPerArchive <= 0 {
+		policy.MaxEntriesPerArchive = defaultAuditArchiveEntries
+	}
+	return &auditArchiveServiceImpl{
+		repo:         repo,
+		store:        store,
+		auditService: auditService,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// ArchiveExpired 实现了AuditArchiveService接口的ArchiveExpired方法
+func (s *auditArchiveServiceImpl) ArchiveExpired(ctx context.Context, now time.Time) (*model.AuditArchiveRunResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &model.AuditArchiveRunResult{Archives: []*model.AuditLogArchive{}}
+	if s.policy.Retention <= 0 {
+		return result, nil
+	}
+	result.Cutoff = now.Add(-s.policy.Retention)
+	for {
+		logs, err := s.repo.FindArchivable(ctx, result.Cutoff, s.policy.MaxEntriesPerArchive)
+		if err != nil {
+			return result, err
+		}
+		if len(logs) == 0 {
+			break
+		}
+		archive, err := s.archive(ctx, logs)
+		if err != nil {
+			return result, err
+		}
+		result.Archives = append(result.Archives, archive)
+		result.Purged += int64(archive.EntryCount)
+		if len(logs) < s.policy.MaxEntriesPerArchive {
+			break
+		}
+	}
+
+	if len(result.Archives) > 0 {
+		ids := make([]uint, 0, len(result.Archives))
+		for _, archive := range result.Archives {
+			ids = append(ids, archive.ID)
+		}
+		details, _ := json.Marshal(map[string]interface{}{"archiveIds": ids, "purged": result.Purged, "cutoff": result.Cutoff})
+		s.auditService.Record(ctx, &model.AuditLog{
+			Username: "system",
+			Action:   string(apputils.AuditActionArchive),
+			Resource: "AUDIT_LOG",
+			Details:  string(details),
+		})
+	}
+	return result, nil
+}
+
+// archive 将一段连续的日志写入存储，回读校验通过后清除这些日志
+func (s *auditArchiveServiceImpl) archive(ctx context.Context, logs []model.AuditLog) (*model.AuditLogArchive, error) {
+	first, last := &logs[0], &logs[len(logs)-1]
+	archive := &model.AuditLogArchive{
+		ObjectKey:     fmt.Sprintf("%s/audit-logs-%d-%d-%d.jsonl.gz", first.CreatedAt.UTC().Format("2006/01"), first.ID, last.ID, time.Now().UnixNano()),
+		FromID:        first.ID,
+		ToID:          last.ID,
+		FromTime:      first.CreatedAt,
+		ToTime:        last.CreatedAt,
+		EntryCount:    len(logs),
+		FirstPrevHash: first.PrevHash,
+		LastHash:      last.Hash,
+	}
+	if err := s.upload(ctx, archive, logs); err != nil {
+		return nil, err
+	}
+	if err := s.verifyUpload(ctx, archive, logs); err != nil {
+		s.removeObjects(archive)
+		return nil, err
+	}
+	if _, err := s.repo.PurgeArchived(ctx, archive); err != nil {
+		s.removeObjects(archive)
+		return nil, err
+	}
+	s.logger.Info("Archived and purged audit logs",
+		zap.Uint("archiveId", archive.ID),
+		zap.String("objectKey", archive.ObjectKey),
+		zap.Uint("fromId", archive.FromID),
+		zap.Uint("toId", archive.ToID),
+		zap.Int("entries", archive.EntryCount))
+	return archive, nil
+}
+
+// upload 把日志写成gzip压缩的JSON Lines，连同sha256sum格式的校验和文件一起存入存储
+func (s *auditArchiveServiceImpl) upload(ctx context.Context, archive *model.AuditLogArchive, logs []model.AuditLog) error {
+	tmp, err := os.CreateTemp("", "audit-archive-*.jsonl.gz")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hasher := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(tmp, hasher))
+	enc := json.NewEncoder(gz)
+	for i := range logs {
+		if err := enc.Encode(&logs[i]); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	archive.SizeBytes = size
+	archive.Checksum = hex.EncodeToString(hasher.Sum(nil))
+
+	if err := s.store.Put(ctx, archive.ObjectKey, tmp, size, "application/gzip"); err != nil {
+		return fmt.Errorf("failed to store audit log archive: %w", err)
+	}
+	sum := fmt.Sprintf("%s  %s\n", archive.Checksum, path.Base(archive.ObjectKey))
+	if err := s.store.Put(ctx, archive.ObjectKey+".sha256", strings.NewReader(sum), int64(len(sum)), "text/plain"); err != nil {
+		s.removeObjects(archive)
+		return fmt.Errorf("failed to store audit log archive checksum: %w", err)
+	}
+	return nil
+}
+
+// verifyUpload 回读归档文件，确认其中的日志与要清除的日志完全一致
+func (s *auditArchiveServiceImpl) verifyUpload(ctx context.Context, archive *model.AuditLogArchive, logs []model.AuditLog) error {
+	stored, err := s.readArchive(ctx, archive)
+	if err != nil {
+		return err
+	}
+	if len(stored) != len(logs) {
+		return fmt.Errorf("%w: wrote %d logs, read back %d", repository.ErrAuditArchiveCorrupt, len(logs), len(stored))
+	}
+	for i := range logs {
+		if stored[i].ID != logs[i].ID || stored[i].Hash != logs[i].Hash || stored[i].Details != logs[i].Details {
+			return fmt.Errorf("%w: log %d differs after read back", repository.ErrAuditArchiveCorrupt, logs[i].ID)
+		}
+	}
+	return nil
+}
+
+// readArchive 读取归档文件并核对校验和
+func (s *auditArchiveServiceImpl) readArchive(ctx context.Context, archive *model.AuditLogArchive) ([]model.AuditLog, error) {
+	r, err := s.store.Get(ctx, archive.ObjectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log archive %s: %w", archive.ObjectKey, err)
+	}
+	defer r.Close()
+
+	hasher := sha256.New()
+	tee := io.TeeReader(r, hasher)
+	gz, err := gzip.NewReader(tee)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", repository.ErrAuditArchiveCorrupt, err)
+	}
+	var logs []model.AuditLog
+	dec := json.NewDecoder(gz)
+	for {
+		var log model.AuditLog
+		if err := dec.Decode(&log); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("%w: %v", repository.ErrAuditArchiveCorrupt, err)
+		}
+		logs = append(logs, log)
+	}
+	if _, err := io.Copy(io.Discard, tee); err != nil {
+		return nil, err
+	}
+	if checksum := hex.EncodeToString(hasher.Sum(nil)); checksum != archive.Checksum {
+		return nil, fmt.Errorf("%w: checksum %s does not match %s", repository.ErrAuditArchiveCorrupt, checksum, archive.Checksum)
+	}
+	return logs, nil
+}
+
+// removeObjects 删除未能完成归档的文件
+func (s *auditArchiveServiceImpl) removeObjects(archive *model.AuditLogArchive) {
+	for _, key := range []string{archive.ObjectKey, archive.ObjectKey + ".sha256"} {
+		if err := s.store.Delete(context.Background(), key); err != nil {
+			s.logger.Warn("Failed to remove incomplete audit log archive", zap.String("objectKey", key), zap.Error(err))
+		}
+	}
+}
+
+// ListArchives 实现了AuditArchiveService接口的ListArchives方法
+func (s *auditArchiveServiceImpl) ListArchives(ctx context.Context) ([]model.AuditLogArchive, error) {
+	return s.repo.ListArchives(ctx)
+}
+
+// findArchive 查找归档，不存在时返回apputils.ErrNotFound
+func (s *auditArchiveServiceImpl) findArchive(ctx context.Context, id uint) (*model.AuditLogArchive, error) {
+	archive, err := s.repo.FindArchiveByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("audit log archive %d: %w", id, apputils.ErrNotFound)
+	}
+	return archive, err
+}
+
+// ImportArchive 实现了AuditArchiveService接口的ImportArchive方法
+func (s *auditArchiveServiceImpl) ImportArchive(ctx context.Context, id uint) (*model.AuditLogArchive, error) {
+	archive, err := s.findArchive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if archive.ImportedAt != nil {
+		return nil, repository.ErrAuditArchiveImported
+	}
+	logs, err := s.readArchive(ctx, archive)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RestoreArchive(ctx, archive, logs); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// UnloadArchive 实现了AuditArchiveService接口的UnloadArchive方法
+func (s *auditArchiveServiceImpl) UnloadArchive(ctx context.Context, id uint) (*model.AuditLogArchive, int64, error) {
+	archive, err := s.findArchive(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	unloaded, err := s.repo.UnloadArchive(ctx, archive)
+	if err != nil {
+		return nil, 0, err
+	}
+	return archive, unloaded, nil
+}
+
+// Run 实现了AuditArchiveService接口的Run方法
+func (s *auditArchiveServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	s.logger.Info("Audit log archiver started", zap.Duration("interval", interval), zap.Duration("retention", s.policy.Retention))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	now := time.Now()
+	for {
+		result, err := s.ArchiveExpired(ctx, now)
+		if err != nil {
+			s.logger.Error("Audit log archiving failed", zap.Error(err))
+		} else if len(result.Archives) > 0 {
+			s.logger.Info("Audit log archiving finished", zap.Int("archives", len(result.Archives)), zap.Int64("purged", result.Purged))
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Audit log archiver stopped")
+			return
+		case now = <-ticker.C:
+		}
+	}
+}
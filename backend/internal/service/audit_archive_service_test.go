@@ -0,0 +1,240 @@
+package service
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/pkg/storage"
+	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeAuditArchiveRepository keeps audit logs and archives in memory.
+type fakeAuditArchiveRepository struct {
+	repository.AuditLogRepository
+	logs     []model.AuditLog
+	archives []*model.AuditLogArchive
+	restored []model.AuditLog
+	purgeErr error
+}
+
+func (r *fakeAuditArchiveRepository) FindArchivable(ctx context.Context, cutoff time.Time, limit int) ([]model.AuditLog, error) {
+	var logs []model.AuditLog
+	for _, log := range r.logs {
+		if len(logs) == limit || !log.CreatedAt.Before(cutoff) {
+			break
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+func (r *fakeAuditArchiveRepository) PurgeArchived(ctx context.Context, archive *model.AuditLogArchive) (int64, error) {
+	if r.purgeErr != nil {
+		return 0, r.purgeErr
+	}
+	archive.ID = uint(len(r.archives) + 1)
+	r.archives = append(r.archives, archive)
+	r.logs = r.logs[archive.EntryCount:]
+	return int64(archive.EntryCount), nil
+}
+
+func (r *fakeAuditArchiveRepository) FindArchiveByID(ctx context.Context, id uint) (*model.AuditLogArchive, error) {
+	for _, archive := range r.archives {
+		if archive.ID == id {
+			return archive, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAuditArchiveRepository) RestoreArchive(ctx context.Context, archive *model.AuditLogArchive, logs []model.AuditLog) error {
+	now := time.Now()
+	archive.ImportedAt = &now
+	r.restored = append(r.restored, logs...)
+	return nil
+}
+
+// recordingAuditLogService keeps the entries passed to Record.
+type recordingAuditLogService struct {
+	AuditLogService
+	recorded []*model.AuditLog
+}
+
+func (s *recordingAuditLogService) Record(ctx context.Context, entry *model.AuditLog) {
+	s.recorded = append(s.recorded, entry)
+}
+
+func setupAuditArchiveService(t *testing.T, maxEntries int) (*auditArchiveServiceImpl, *fakeAuditArchiveRepository, storage.Storage, *recordingAuditLogService) {
+	repo := &fakeAuditArchiveRepository{}
+	for day := 1; day <= 5; day++ {
+		repo.logs = append(repo.logs, model.AuditLog{
+			ID:        uint(day),
+			UserID:    1,
+			Username:  "alice",
+			Action:    "UPDATE",
+			Resource:  "BUG",
+			Details:   fmt.Sprintf(`{"day":%d}`, day),
+			Hash:      fmt.Sprintf("hash-%d", day),
+			PrevHash:  fmt.Sprintf("hash-%d", day-1),
+			CreatedAt: time.Date(2024, 5, day, 12, 0, 0, 0, time.UTC),
+		})
+	}
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	audit := &recordingAuditLogService{}
+	svc := NewAuditArchiveService(repo, store, audit, AuditRetentionPolicy{Retention: 24 * time.Hour, MaxEntriesPerArchive: maxEntries}, zap.NewNop())
+	return svc.(*auditArchiveServiceImpl), repo, store, audit
+}
+
+func readStoredObject(t *testing.T, store storage.Storage, key string) []byte {
+	t.Helper()
+	r, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return content
+}
+
+func TestAuditArchiveService_ArchiveExpired(t *testing.T) {
+	svc, repo, store, audit := setupAuditArchiveService(t, 2)
+	now := time.Date(2024, 5, 4, 18, 0, 0, 0, time.UTC) // 5月3日12点之前的日志过期
+
+	result, err := svc.ArchiveExpired(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-24*time.Hour), result.Cutoff)
+	assert.EqualValues(t, 3, result.Purged)
+	require.Len(t, result.Archives, 2)
+	assert.Len(t, repo.logs, 2)
+
+	first := result.Archives[0]
+	assert.Equal(t, uint(1), first.FromID)
+	assert.Equal(t, uint(2), first.ToID)
+	assert.Equal(t, 2, first.EntryCount)
+	assert.Equal(t, "hash-0", first.FirstPrevHash)
+	assert.Equal(t, "hash-2", first.LastHash)
+	assert.True(t, strings.HasPrefix(first.ObjectKey, "2024/05/audit-logs-1-2-"), first.ObjectKey)
+	assert.Equal(t, uint(3), result.Archives[1].FromID)
+
+	t.Run("ArchiveFileIsCompressedJSONLines", func(t *testing.T) {
+		content := readStoredObject(t, store, first.ObjectKey)
+		assert.EqualValues(t, len(content), first.SizeBytes)
+		gz, err := gzip.NewReader(strings.NewReader(string(content)))
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(gz)
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[1], `"hash":"hash-2"`)
+	})
+
+	t.Run("ChecksumFile", func(t *testing.T) {
+		sum := string(readStoredObject(t, store, first.ObjectKey+".sha256"))
+		assert.Equal(t, first.Checksum+"  "+path.Base(first.ObjectKey)+"\n", sum)
+		assert.Len(t, first.Checksum, 64)
+	})
+
+	t.Run("RunIsAudited", func(t *testing.T) {
+		require.Len(t, audit.recorded, 1)
+		assert.Equal(t, string(apputils.AuditActionArchive), audit.recorded[0].Action)
+		assert.Equal(t, "AUDIT_LOG", audit.recorded[0].Resource)
+		assert.Contains(t, audit.recorded[0].Details, `"purged":3`)
+	})
+
+	t.Run("NothingLeftToArchive", func(t *testing.T) {
+		result, err := svc.ArchiveExpired(context.Background(), now)
+		require.NoError(t, err)
+		assert.Empty(t, result.Archives)
+		assert.Len(t, audit.recorded, 1)
+	})
+}
+
+func TestAuditArchiveService_RetentionDisabled(t *testing.T) {
+	svc, repo, _, _ := setupAuditArchiveService(t, 0)
+	svc.policy.Retention = 0
+
+	result, err := svc.ArchiveExpired(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, result.Archives)
+	assert.Len(t, repo.logs, 5)
+}
+
+func TestAuditArchiveService_PurgeFailureRemovesFiles(t *testing.T) {
+	svc, repo, _, audit := setupAuditArchiveService(t, 0)
+	repo.purgeErr = errors.New("database is locked")
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+	svc.store = store
+
+	result, err := svc.ArchiveExpired(context.Background(), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, repo.purgeErr)
+	assert.Empty(t, result.Archives)
+	assert.Len(t, repo.logs, 5)
+	assert.Empty(t, audit.recorded)
+
+	var files []string
+	require.NoError(t, filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, p)
+		}
+		return err
+	}))
+	assert.Empty(t, files)
+}
+
+func TestAuditArchiveService_ImportArchive(t *testing.T) {
+	svc, repo, store, _ := setupAuditArchiveService(t, 0)
+	ctx := context.Background()
+	result, err := svc.ArchiveExpired(ctx, time.Date(2024, 5, 4, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, result.Archives, 1)
+	archive := result.Archives[0]
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := svc.ImportArchive(ctx, 99)
+		assert.ErrorIs(t, err, apputils.ErrNotFound)
+	})
+
+	t.Run("RestoresArchivedLogs", func(t *testing.T) {
+		imported, err := svc.ImportArchive(ctx, archive.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, imported.ImportedAt)
+		require.Len(t, repo.restored, 3)
+		assert.Equal(t, uint(1), repo.restored[0].ID)
+		assert.Equal(t, "hash-3", repo.restored[2].Hash)
+		assert.Equal(t, `{"day":2}`, repo.restored[1].Details)
+
+		_, err = svc.ImportArchive(ctx, archive.ID)
+		assert.ErrorIs(t, err, repository.ErrAuditArchiveImported)
+	})
+
+	t.Run("RejectsModifiedFile", func(t *testing.T) {
+		archive.ImportedAt = nil
+		content := readStoredObject(t, store, archive.ObjectKey)
+		content[len(content)-1] ^= 0xff
+		require.NoError(t, store.Put(ctx, archive.ObjectKey, strings.NewReader(string(content)), int64(len(content)), "application/gzip"))
+
+		_, err := svc.ImportArchive(ctx, archive.ID)
+		assert.ErrorIs(t, err, repository.ErrAuditArchiveCorrupt)
+	})
+}
@@ -0,0 +1,497 @@
+package service
+
+import (
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/pkg/auditsink"
+	"EffiPlat/backend/internal/repository"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// auditWriteTimeout 限制一次批量写入数据库的时长，超时的批次落盘等待重放
+const auditWriteTimeout = 10 * time.Second
+
+var (
+	errAuditQueueFull      = errors.New("audit log queue is full")
+	errAuditPipelineClosed = errors.New("audit log pipeline is closed")
+	errAuditPipelineAbort  = errors.New("audit log pipeline shutdown timed out")
+)
+
+// AuditPipelineConfig 配置审计日志的写入管道
+type AuditPipelineConfig struct {
+	QueueSize      int           // 队列容量
+	BatchSize      int           // 每批写入的最大条数
+	FlushInterval  time.Duration // 不满一批时最长等待多久写入
+	EnqueueTimeout time.Duration // 队列已满时写入方最多等待多久，超时后日志直接落盘
+	// SpillFile 是无法写入数据库的日志的落盘文件（JSON Lines），会定期重放；
+	// 为空时这些日志只记录到错误日志中并计为丢失
+	SpillFile      string
+	ReplayInterval time.Duration // 重放落盘文件的间隔
+	// Sinks 在日志写入数据库（包括重放）后转发到外部系统，如SIEM的syslog；
+	// 转发在后台进行，不影响写入。管道关闭时一并关闭
+	Sinks []*auditsink.Forwarder
+}
+
+// DefaultAuditPipelineConfig 是审计日志写入管道的默认配置，未设置的字段取这里的值
+var DefaultAuditPipelineConfig = AuditPipelineConfig{
+	QueueSize:      1024,
+	BatchSize:      100,
+	FlushInterval:  time.Second,
+	EnqueueTimeout: 50 * time.Millisecond,
+	ReplayInterval: time.Minute,
+}
+
+func (c AuditPipelineConfig) withDefaults() AuditPipelineConfig {
+	d := DefaultAuditPipelineConfig
+	if c.QueueSize <= 0 {
+		c.QueueSize = d.QueueSize
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = d.BatchSize
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = d.FlushInterval
+	}
+	if c.EnqueueTimeout <= 0 {
+		c.EnqueueTimeout = d.EnqueueTimeout
+	}
+	if c.ReplayInterval <= 0 {
+		c.ReplayInterval = d.ReplayInterval
+	}
+	return c
+}
+
+// auditQueueItem 是队列中的一条日志，或者一个刷新标记
+type auditQueueItem struct {
+	entry   *model.AuditLog
+	flushed chan struct{} // 刷新标记：之前入队的日志处理完后关闭
+}
+
+// AuditPipeline 通过有界队列批量写入审计日志。
+// 写入失败、队列持续已满或停机超时的日志会追加到落盘文件，之后定期重放，
+// 因此数据库暂时不可用时日志不会丢失。
+type AuditPipeline struct {
+	repo   repository.AuditLogRepository
+	cfg    AuditPipelineConfig
+	logger *zap.Logger
+
+	queue  chan auditQueueItem
+	mu     sync.RWMutex // 保护 closed，避免向已关闭的队列发送
+	closed bool
+
+	stop       chan struct{} // 关闭后停止定期重放
+	abort      chan struct{} // 停机超时后关闭，剩余日志直接落盘
+	done       chan struct{} // 写入协程退出后关闭
+	replayDone chan struct{} // 重放协程退出后关闭
+	closeOnce  sync.Once
+	abortOnce  sync.Once
+
+	spillMu  sync.Mutex // 保护落盘文件
+	replayMu sync.Mutex // 同一时间只进行一次重放
+
+	subscribersMu sync.Mutex
+	subscribers   map[chan struct{}]struct{} // 日志写入数据库后通知的订阅者，见 Subscribe
+
+	enqueued, written, batches, blocked   atomic.Int64
+	writeFailures, spilled, replayed      atomic.Int64
+	dropped, missingActor, lastFlushNanos atomic.Int64
+	lastError                             atomic.Value // string
+}
+
+// NewAuditPipeline 创建审计日志写入管道并启动后台写入与重放
+func NewAuditPipeline(repo repository.AuditLogRepository, cfg AuditPipelineConfig, logger *zap.Logger) *AuditPipeline {
+	cfg = cfg.withDefaults()
+	p := &AuditPipeline{
+		repo:       repo,
+		cfg:        cfg,
+		logger:     logger,
+		queue:      make(chan auditQueueItem, cfg.QueueSize),
+		stop:       make(chan struct{}),
+		abort:      make(chan struct{}),
+		done:       make(chan struct{}),
+		replayDone: make(chan struct{}),
+
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+	go p.run()
+	go p.replayLoop()
+	return p
+}
+
+// Enqueue 将一条日志放入队列。队列已满时最多等待 EnqueueTimeout，仍无空位则直接落盘，
+// 以免拖慢业务请求。
+func (p *AuditPipeline) Enqueue(entry *model.AuditLog) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.spill([]*model.AuditLog{entry}, errAuditPipelineClosed)
+		return
+	}
+
+	item := auditQueueItem{entry: entry}
+	select {
+	case p.queue <- item:
+		p.enqueued.Add(1)
+		return
+	default:
+	}
+
+	// 队列已满：记录背压并短暂等待
+	p.blocked.Add(1)
+	timer := time.NewTimer(p.cfg.EnqueueTimeout)
+	defer timer.Stop()
+	select {
+	case p.queue <- item:
+		p.enqueued.Add(1)
+	case <-timer.C:
+		p.spill([]*model.AuditLog{entry}, errAuditQueueFull)
+	}
+}
+
+// Flush 等待调用前入队的日志全部处理完（写入数据库或落盘）
+func (p *AuditPipeline) Flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return nil
+	}
+	select {
+	case p.queue <- auditQueueItem{flushed: flushed}:
+	case <-ctx.Done():
+		p.mu.RUnlock()
+		return ctx.Err()
+	}
+	p.mu.RUnlock()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close 停止接收日志并写完队列中剩余的日志。ctx 结束时尚未写入的日志会落盘，
+// 下次启动时重放，此时返回 ctx 的错误。
+func (p *AuditPipeline) Close(ctx context.Context) error {
+	p.closeOnce.Do(func() {
+		close(p.stop)
+		p.mu.Lock()
+		p.closed = true
+		close(p.queue)
+		p.mu.Unlock()
+	})
+
+	var err error
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		err = ctx.Err()
+		p.abortOnce.Do(func() { close(p.abort) })
+		<-p.done
+	}
+	select {
+	case <-p.replayDone:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	// 数据库写入结束后再关闭转发，未转发完的日志写入死信文件
+	for _, sink := range p.cfg.Sinks {
+		if sinkErr := sink.Close(ctx); sinkErr != nil && err == nil {
+			err = sinkErr
+		}
+	}
+	return err
+}
+
+// Stats 返回管道的运行指标
+func (p *AuditPipeline) Stats() model.AuditPipelineStats {
+	stats := model.AuditPipelineStats{
+		QueueLength:     len(p.queue),
+		QueueCapacity:   cap(p.queue),
+		Enqueued:        p.enqueued.Load(),
+		Written:         p.written.Load(),
+		Batches:         p.batches.Load(),
+		BlockedEnqueues: p.blocked.Load(),
+		WriteFailures:   p.writeFailures.Load(),
+		Spilled:         p.spilled.Load(),
+		Replayed:        p.replayed.Load(),
+		Dropped:         p.dropped.Load(),
+		MissingActor:    p.missingActor.Load(),
+		SpillFile:       p.cfg.SpillFile,
+	}
+	if lastError, ok := p.lastError.Load().(string); ok {
+		stats.LastError = lastError
+	}
+	if nanos := p.lastFlushNanos.Load(); nanos > 0 {
+		lastFlushAt := time.Unix(0, nanos)
+		stats.LastFlushAt = &lastFlushAt
+	}
+	for _, sink := range p.cfg.Sinks {
+		stats.Sinks = append(stats.Sinks, sink.Stats())
+	}
+	if p.cfg.SpillFile != "" {
+		for _, path := range []string{p.cfg.SpillFile, p.replayFile()} {
+			if info, err := os.Stat(path); err == nil {
+				stats.SpillBytes += info.Size()
+			}
+		}
+	}
+	return stats
+}
+
+// countMissingActor 记录一次因缺少已认证用户而未能记录的操作
+func (p *AuditPipeline) countMissingActor() {
+	p.missingActor.Add(1)
+}
+
+// run 从队列中取出日志，攒够一批或到达刷新间隔时写入
+func (p *AuditPipeline) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*model.AuditLog, 0, p.cfg.BatchSize)
+	for {
+		select {
+		case item, ok := <-p.queue:
+			if !ok {
+				p.write(batch)
+				return
+			}
+			if item.flushed != nil {
+				batch = p.write(batch)
+				close(item.flushed)
+				continue
+			}
+			batch = append(batch, item.entry)
+			if len(batch) >= p.cfg.BatchSize {
+				batch = p.write(batch)
+			}
+		case <-ticker.C:
+			batch = p.write(batch)
+		}
+	}
+}
+
+// write 写入一批日志，失败时落盘；返回清空后的批次以便复用
+func (p *AuditPipeline) write(batch []*model.AuditLog) []*model.AuditLog {
+	if len(batch) == 0 {
+		return batch
+	}
+	select {
+	case <-p.abort:
+		p.spill(batch, errAuditPipelineAbort)
+		return batch[:0]
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), auditWriteTimeout)
+	err := p.repo.CreateLogs(ctx, batch)
+	cancel()
+	if err != nil {
+		p.writeFailures.Add(1)
+		p.spill(batch, err)
+		return batch[:0]
+	}
+	p.written.Add(int64(len(batch)))
+	p.batches.Add(1)
+	p.lastFlushNanos.Store(time.Now().UnixNano())
+	p.forward(batch)
+	p.notifySubscribers()
+	return batch[:0]
+}
+
+// forward 把已写入数据库的一批日志交给各外部接收端
+func (p *AuditPipeline) forward(batch []*model.AuditLog) {
+	for _, sink := range p.cfg.Sinks {
+		sink.Enqueue(batch)
+	}
+}
+
+// spill 将无法写入数据库的日志追加到落盘文件；没有落盘文件或落盘失败时记录错误日志
+func (p *AuditPipeline) spill(entries []*model.AuditLog, cause error) {
+	p.lastError.Store(cause.Error())
+	if p.cfg.SpillFile != "" {
+		err := p.appendSpill(entries)
+		if err == nil {
+			p.spilled.Add(int64(len(entries)))
+			p.logger.Warn("Audit logs spilled to file for replay",
+				zap.Error(cause),
+				zap.Int("count", len(entries)),
+				zap.String("file", p.cfg.SpillFile))
+			return
+		}
+		p.logger.Error("Failed to spill audit logs", zap.Error(err), zap.String("file", p.cfg.SpillFile))
+	}
+
+	p.dropped.Add(int64(len(entries)))
+	for _, entry := range entries {
+		p.logger.Error("Audit log lost",
+			zap.Error(cause),
+			zap.Uint("userID", entry.UserID),
+			zap.String("action", entry.Action),
+			zap.String("resource", entry.Resource),
+			zap.Uint("resourceID", entry.ResourceID),
+			zap.String("details", entry.Details))
+	}
+}
+
+func (p *AuditPipeline) appendSpill(entries []*model.AuditLog) error {
+	p.spillMu.Lock()
+	defer p.spillMu.Unlock()
+	return writeAuditLogFile(p.cfg.SpillFile, entries, os.O_APPEND)
+}
+
+// writeAuditLogFile 以 JSON Lines 写入日志并同步到磁盘；flag 为 os.O_APPEND 或 os.O_TRUNC
+func writeAuditLogFile(path string, entries []*model.AuditLog, flag int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|flag, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (p *AuditPipeline) replayFile() string {
+	return p.cfg.SpillFile + ".replay"
+}
+
+// replayLoop 启动时及之后每隔 ReplayInterval 重放一次落盘文件
+func (p *AuditPipeline) replayLoop() {
+	defer close(p.replayDone)
+	if p.cfg.SpillFile == "" {
+		return
+	}
+	ticker := time.NewTicker(p.cfg.ReplayInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := p.Replay(context.Background()); err != nil {
+			p.logger.Warn("Failed to replay spilled audit logs", zap.Error(err), zap.String("file", p.cfg.SpillFile))
+		}
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Replay 将落盘文件中的日志写入数据库，返回写入的条数。
+// 文件先改名再读取，重放期间新落盘的日志写入新文件；写入失败的日志保留到下次重放。
+func (p *AuditPipeline) Replay(ctx context.Context) (int, error) {
+	if p.cfg.SpillFile == "" {
+		return 0, nil
+	}
+	p.replayMu.Lock()
+	defer p.replayMu.Unlock()
+
+	// 上次重放中断时留下的文件优先处理
+	replayFile := p.replayFile()
+	if _, err := os.Stat(replayFile); errors.Is(err, os.ErrNotExist) {
+		p.spillMu.Lock()
+		err = os.Rename(p.cfg.SpillFile, replayFile)
+		p.spillMu.Unlock()
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	} else if err != nil {
+		return 0, err
+	}
+
+	entries, err := p.readReplayFile(replayFile)
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for len(entries) > 0 {
+		n := min(len(entries), p.cfg.BatchSize)
+		batch := entries[:n]
+		for _, entry := range batch {
+			entry.ID = 0 // 由数据库重新分配
+		}
+		writeCtx, cancel := context.WithTimeout(ctx, auditWriteTimeout)
+		err := p.repo.CreateLogs(writeCtx, batch)
+		cancel()
+		if err != nil {
+			p.lastError.Store(err.Error())
+			// 只保留尚未写入的日志，避免下次重放时重复写入
+			if keepErr := writeAuditLogFile(replayFile, entries, os.O_TRUNC); keepErr != nil {
+				p.logger.Error("Failed to keep unreplayed audit logs", zap.Error(keepErr), zap.String("file", replayFile))
+			}
+			return replayed, err
+		}
+		replayed += n
+		p.replayed.Add(int64(n))
+		p.forward(batch)
+		p.notifySubscribers()
+		entries = entries[n:]
+	}
+	if replayed > 0 {
+		p.lastFlushNanos.Store(time.Now().UnixNano())
+		p.logger.Info("Replayed spilled audit logs", zap.Int("count", replayed))
+	}
+	return replayed, os.Remove(replayFile)
+}
+
+// readReplayFile 读取落盘文件，无法解析的行记录错误后跳过
+func (p *AuditPipeline) readReplayFile(path string) ([]*model.AuditLog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []*model.AuditLog
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry model.AuditLog
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			p.dropped.Add(1)
+			p.logger.Error("Skipping corrupt spilled audit log",
+				zap.Error(err),
+				zap.String("file", path),
+				zap.Int("line", line),
+				zap.ByteString("content", scanner.Bytes()))
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/pkg/auditsink"
+	"EffiPlat/backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeAuditLogRepository records batches in memory and fails while err is set.
+type fakeAuditLogRepository struct {
+	repository.AuditLogRepository
+	mu      sync.Mutex
+	err     error
+	block   chan struct{} // CreateLogs waits on it when set
+	logs    []*model.AuditLog
+	batches []int
+}
+
+func (r *fakeAuditLogRepository) CreateLogs(ctx context.Context, logs []*model.AuditLog) error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	for _, log := range logs {
+		saved := *log
+		r.logs = append(r.logs, &saved)
+	}
+	r.batches = append(r.batches, len(logs))
+	return nil
+}
+
+func (r *fakeAuditLogRepository) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+func (r *fakeAuditLogRepository) saved() ([]*model.AuditLog, []int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*model.AuditLog(nil), r.logs...), append([]int(nil), r.batches...)
+}
+
+func testAuditLog(i int) *model.AuditLog {
+	return &model.AuditLog{UserID: 1, Username: "alice", Action: "UPDATE", Resource: "BUG", ResourceID: uint(i), Details: fmt.Sprintf(`{"n":%d}`, i)}
+}
+
+func TestAuditPipeline_BatchesAndFlush(t *testing.T) {
+	repo := &fakeAuditLogRepository{}
+	p := NewAuditPipeline(repo, AuditPipelineConfig{BatchSize: 3, FlushInterval: time.Hour}, zap.NewNop())
+	defer p.Close(context.Background())
+
+	for i := 1; i <= 7; i++ {
+		p.Enqueue(testAuditLog(i))
+	}
+	require.NoError(t, p.Flush(context.Background()))
+
+	logs, batches := repo.saved()
+	assert.Equal(t, []int{3, 3, 1}, batches)
+	require.Len(t, logs, 7)
+	for i, log := range logs {
+		assert.Equal(t, uint(i+1), log.ResourceID, "entries keep their order")
+	}
+
+	stats := p.Stats()
+	assert.Equal(t, int64(7), stats.Enqueued)
+	assert.Equal(t, int64(7), stats.Written)
+	assert.Equal(t, int64(3), stats.Batches)
+	assert.NotNil(t, stats.LastFlushAt)
+}
+
+func TestAuditPipeline_SpillsFailedWritesAndReplays(t *testing.T) {
+	repo := &fakeAuditLogRepository{err: errors.New("database is locked")}
+	spillFile := filepath.Join(t.TempDir(), "audit-spill.jsonl")
+	p := NewAuditPipeline(repo, AuditPipelineConfig{SpillFile: spillFile, ReplayInterval: time.Hour}, zap.NewNop())
+	defer p.Close(context.Background())
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 1; i <= 3; i++ {
+		log := testAuditLog(i)
+		log.CreatedAt = createdAt
+		p.Enqueue(log)
+	}
+	require.NoError(t, p.Flush(context.Background()))
+
+	stats := p.Stats()
+	assert.Equal(t, int64(1), stats.WriteFailures)
+	assert.Equal(t, int64(3), stats.Spilled)
+	assert.Equal(t, "database is locked", stats.LastError)
+	assert.Positive(t, stats.SpillBytes)
+
+	// 数据库仍不可用时重放失败，日志留在文件中
+	n, err := p.Replay(context.Background())
+	assert.Error(t, err)
+	assert.Zero(t, n)
+
+	repo.setErr(nil)
+	n, err = p.Replay(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	logs, _ := repo.saved()
+	require.Len(t, logs, 3)
+	assert.Equal(t, "alice", logs[0].Username)
+	assert.True(t, createdAt.Equal(logs[0].CreatedAt), "replayed entries keep the time of the action")
+	assert.Equal(t, int64(3), p.Stats().Replayed)
+	assert.Zero(t, p.Stats().SpillBytes)
+
+	// 文件已清空，再次重放不会重复写入
+	n, err = p.Replay(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestAuditPipeline_ReplaySkipsCorruptLines(t *testing.T) {
+	repo := &fakeAuditLogRepository{}
+	spillFile := filepath.Join(t.TempDir(), "audit-spill.jsonl")
+	require.NoError(t, writeAuditLogFile(spillFile, []*model.AuditLog{testAuditLog(1)}, os.O_APPEND))
+	f, err := os.OpenFile(spillFile, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString("{not json\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.NoError(t, writeAuditLogFile(spillFile, []*model.AuditLog{testAuditLog(2)}, os.O_APPEND))
+
+	p := NewAuditPipeline(repo, AuditPipelineConfig{SpillFile: spillFile, ReplayInterval: time.Hour}, zap.NewNop())
+	require.NoError(t, p.Close(context.Background()))
+
+	logs, _ := repo.saved()
+	require.Len(t, logs, 2, "the startup replay writes the readable entries")
+	assert.Equal(t, int64(1), p.Stats().Dropped)
+	_, err = os.Stat(spillFile)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestAuditPipeline_FullQueueSpills(t *testing.T) {
+	repo := &fakeAuditLogRepository{block: make(chan struct{})}
+	spillFile := filepath.Join(t.TempDir(), "audit-spill.jsonl")
+	p := NewAuditPipeline(repo, AuditPipelineConfig{QueueSize: 1, BatchSize: 1, EnqueueTimeout: time.Millisecond, SpillFile: spillFile, ReplayInterval: time.Hour}, zap.NewNop())
+
+	// 第一条日志占住写入协程，第二条填满队列，第三条等待超时后落盘
+	p.Enqueue(testAuditLog(1))
+	require.Eventually(t, func() bool { return len(p.queue) == 0 }, time.Second, time.Millisecond)
+	p.Enqueue(testAuditLog(2))
+	p.Enqueue(testAuditLog(3))
+
+	stats := p.Stats()
+	assert.Equal(t, int64(2), stats.Enqueued)
+	assert.Equal(t, int64(1), stats.BlockedEnqueues)
+	assert.Equal(t, int64(1), stats.Spilled)
+	assert.Equal(t, 1, stats.QueueLength)
+	assert.Equal(t, errAuditQueueFull.Error(), stats.LastError)
+
+	close(repo.block)
+	require.NoError(t, p.Close(context.Background()))
+	logs, _ := repo.saved()
+	assert.Len(t, logs, 2)
+}
+
+func TestAuditPipeline_CloseWritesQueuedEntries(t *testing.T) {
+	repo := &fakeAuditLogRepository{}
+	p := NewAuditPipeline(repo, AuditPipelineConfig{BatchSize: 100, FlushInterval: time.Hour}, zap.NewNop())
+	for i := 1; i <= 5; i++ {
+		p.Enqueue(testAuditLog(i))
+	}
+	require.NoError(t, p.Close(context.Background()))
+
+	logs, _ := repo.saved()
+	assert.Len(t, logs, 5)
+
+	// 关闭后的日志没有落盘文件时计为丢失
+	p.Enqueue(testAuditLog(6))
+	assert.Equal(t, int64(1), p.Stats().Dropped)
+	assert.NoError(t, p.Flush(context.Background()))
+}
+
+// recordingSink 记录转发到外部接收端的日志
+type recordingSink struct {
+	mu      sync.Mutex
+	entries []*model.AuditLog
+}
+
+func (s *recordingSink) Send(ctx context.Context, entries []*model.AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func TestAuditPipeline_ForwardsCommittedEntriesToSinks(t *testing.T) {
+	repo := &fakeAuditLogRepository{err: errors.New("database is locked")}
+	sink := &recordingSink{}
+	forwarder := auditsink.NewForwarderWithSink(auditsink.Config{Name: "siem", Type: auditsink.TypeSyslog}, sink, zap.NewNop())
+	spillFile := filepath.Join(t.TempDir(), "audit-spill.jsonl")
+	p := NewAuditPipeline(repo, AuditPipelineConfig{SpillFile: spillFile, ReplayInterval: time.Hour, Sinks: []*auditsink.Forwarder{forwarder}}, zap.NewNop())
+
+	// 写入失败落盘的日志不转发，重放成功后才转发
+	p.Enqueue(testAuditLog(1))
+	require.NoError(t, p.Flush(context.Background()))
+	repo.setErr(nil)
+	_, err := p.Replay(context.Background())
+	require.NoError(t, err)
+	p.Enqueue(testAuditLog(2))
+	require.NoError(t, p.Close(context.Background()))
+
+	require.Len(t, sink.entries, 2)
+	assert.Equal(t, uint(1), sink.entries[0].ResourceID)
+	assert.Equal(t, uint(2), sink.entries[1].ResourceID)
+
+	stats := p.Stats()
+	require.Len(t, stats.Sinks, 1)
+	assert.Equal(t, "siem", stats.Sinks[0].Name)
+	assert.Equal(t, int64(2), stats.Sinks[0].Delivered)
+}
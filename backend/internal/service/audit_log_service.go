@@ -1,13 +1,17 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"EffiPlat/backend/internal/model"
 	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
 	"encoding/json"
 	"github.com/gin-gonic/gin"
+	"io"
 	"go.uber.org/zap"
 	"strings"
+	"time"
 )
 
 //go:generate mockgen -source=audit_log_service.go -destination=mocks/mock_audit_log_service.go -package=mocks AuditLogService
@@ -23,21 +27,57 @@ type AuditLogService interface {
 	// FindLogByID 根据ID查找一条审计日志
 	FindLogByID(ctx context.Context, id uint) (*model.AuditLog, error)
 	
-	// LogUserAction 记录用户操作，从Gin上下文中获取用户信息
+	// LogUserAction 记录用户操作，操作人取自JWT中间件校验过的令牌；日志经写入管道异步保存
 	LogUserAction(c *gin.Context, action, resource string, resourceID uint, details interface{}) error
+	
+	// LogFailedAction 记录失败或被拒绝的操作及原因；请求未认证时操作人记为匿名（用户ID为0）
+	LogFailedAction(c *gin.Context, action, resource string, resourceID uint, outcome model.AuditOutcome, reason string, details interface{}) error
+	
+	// Record 记录一条审计日志，供没有Gin上下文的服务层使用（如登录）。
+	// 未设置的操作人、IP地址和用户代理取自ctx中的已认证用户和客户端信息
+	Record(ctx context.Context, entry *model.AuditLog)
+	
+	// PipelineStats 返回审计日志写入管道的运行指标
+	PipelineStats() model.AuditPipelineStats
+	
+	// FindLogPage 按游标分页查找审计日志，返回下一页的游标（没有更多记录时为空）
+	FindLogPage(ctx context.Context, params model.AuditLogQueryParams, cursor string) ([]model.AuditLog, string, error)
+	
+	// ExportLogs 按查询参数的筛选和排序把审计日志分批写入w（CSV或JSON Lines），返回导出的日志数
+	ExportLogs(ctx context.Context, params model.AuditLogQueryParams, format AuditExportFormat, w io.Writer) (int64, error)
+	
+	// FindChanges 查询UPDATE日志中的字段变更，如某资源某字段在一段时间内的所有修改
+	FindChanges(ctx context.Context, params model.AuditLogChangeQueryParams) ([]model.AuditLogChange, int64, error)
+	
+	// OpenStream 打开审计日志的实时流，推送之后写入且符合筛选条件的日志；
+	// lastEventID不为空时从该ID之后的日志续传。需要model.PermissionStreamAuditLogs权限
+	OpenStream(ctx context.Context, params model.AuditLogQueryParams, lastEventID *uint) (*AuditLogStream, error)
+	
+	// VerifyChain 校验审计日志哈希链，报告第一处被篡改或缺失的日志
+	VerifyChain(ctx context.Context) (*model.AuditChainVerification, error)
+	
+	// Flush 等待已记录的日志全部写入数据库（或落盘）
+	Flush(ctx context.Context) error
+	
+	// Close 停止接收日志并写完队列中剩余的日志，用于优雅停机
+	Close(ctx context.Context) error
 }
 
 // AuditLogServiceImpl 实现了AuditLogService接口
 type AuditLogServiceImpl struct {
-	repo   repository.AuditLogRepository
-	logger *zap.Logger
+	repo     repository.AuditLogRepository
+	pipeline *AuditPipeline
+	permRepo repository.PermissionRepository
+	logger   *zap.Logger
 }
 
 // NewAuditLogService 创建一个新的AuditLogService实例
-func NewAuditLogService(repo repository.AuditLogRepository, logger *zap.Logger) AuditLogService {
+func NewAuditLogService(repo repository.AuditLogRepository, pipeline *AuditPipeline, permRepo repository.PermissionRepository, logger *zap.Logger) AuditLogService {
 	return &AuditLogServiceImpl{
-		repo:   repo,
-		logger: logger,
+		repo:     repo,
+		pipeline: pipeline,
+		permRepo: permRepo,
+		logger:   logger,
 	}
 }
 
@@ -56,69 +96,186 @@ func (s *AuditLogServiceImpl) FindLogByID(ctx context.Context, id uint) (*model.
 	return s.repo.FindLogByID(ctx, id)
 }
 
+// FindLogPage 实现了AuditLogService接口的FindLogPage方法
+func (s *AuditLogServiceImpl) FindLogPage(ctx context.Context, params model.AuditLogQueryParams, cursor string) ([]model.AuditLog, string, error) {
+	return s.repo.FindLogPage(ctx, params, cursor)
+}
+
+// FindChanges 实现了AuditLogService接口的FindChanges方法
+func (s *AuditLogServiceImpl) FindChanges(ctx context.Context, params model.AuditLogChangeQueryParams) ([]model.AuditLogChange, int64, error) {
+	return s.repo.FindChanges(ctx, params)
+}
+
 // LogUserAction 实现了AuditLogService接口的LogUserAction方法
 func (s *AuditLogServiceImpl) LogUserAction(c *gin.Context, action, resource string, resourceID uint, details interface{}) error {
-	// 从上下文中获取用户信息
-	userID, exists := c.Get("userID")
-	if !exists {
-		s.logger.Warn("User ID not found in context when logging action",
+	// 操作人只取自JWT中间件校验过的令牌
+	actor, ok := auditActor(c)
+	if !ok {
+		s.pipeline.countMissingActor()
+		s.logger.Warn("No authenticated user in context when logging action",
 			zap.String("action", action),
 			zap.String("resource", resource),
-			zap.Uint("resourceID", resourceID))
+			zap.Uint("resourceID", resourceID),
+			zap.String("path", c.Request.URL.Path))
 		return nil // 不阻止主要操作，即使审计日志记录失败
 	}
 	
-	username, _ := c.Get("username")
-	usernameStr, ok := username.(string)
-	if !ok {
-		usernameStr = "unknown"
+	s.pipeline.Enqueue(s.newRequestLog(c, actor, action, resource, resourceID, model.AuditOutcomeSuccess, "", details))
+	return nil
+}
+
+// LogFailedAction 实现了AuditLogService接口的LogFailedAction方法
+func (s *AuditLogServiceImpl) LogFailedAction(c *gin.Context, action, resource string, resourceID uint, outcome model.AuditOutcome, reason string, details interface{}) error {
+	// 未认证的请求同样需要记录，此时操作人为空
+	actor, _ := auditActor(c)
+	s.pipeline.Enqueue(s.newRequestLog(c, actor, action, resource, resourceID, outcome, reason, details))
+	return nil
+}
+
+// Record 实现了AuditLogService接口的Record方法
+func (s *AuditLogServiceImpl) Record(ctx context.Context, entry *model.AuditLog) {
+	if entry.UserID == 0 {
+		if actor, ok := apputils.ActorFromContext(ctx); ok {
+			entry.UserID = actor.UserID
+			if entry.Username == "" {
+				entry.Username = actorDisplayName(actor)
+			}
+		}
 	}
-	
-	// 将详情转换为JSON字符串
-	var detailsJSON string
-	if details != nil {
-		detailsBytes, err := json.Marshal(details)
-		if err != nil {
-			s.logger.Warn("Failed to marshal details to JSON",
-				zap.Error(err),
-				zap.String("action", action),
-				zap.String("resource", resource))
-			detailsJSON = "{}"
-		} else {
-			detailsJSON = string(detailsBytes)
+	if info, ok := apputils.ClientInfoFromContext(ctx); ok {
+		if entry.IPAddress == "" {
+			entry.IPAddress = info.IPAddress
+		}
+		if entry.UserAgent == "" {
+			entry.UserAgent = info.UserAgent
 		}
+	}
+	entry.Action = strings.ToUpper(entry.Action)
+	entry.Resource = strings.ToUpper(entry.Resource)
+	if entry.Outcome == "" {
+		entry.Outcome = model.AuditOutcomeSuccess
+	}
+	if entry.Details == "" {
+		entry.Details = "{}"
 	} else {
-		detailsJSON = "{}"
+		entry.Details = s.normalizeDetails(entry.Action, []byte(entry.Details))
 	}
-	
-	// 获取IP地址
-	ipAddress := c.ClientIP()
-	
-	// 获取用户代理
-	userAgent := c.Request.UserAgent()
-	
-	// 创建审计日志记录
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	s.pipeline.Enqueue(entry)
+}
+
+// newRequestLog 根据HTTP请求创建审计日志记录；时间取操作发生时，落盘重放后也保持不变
+func (s *AuditLogServiceImpl) newRequestLog(c *gin.Context, actor apputils.Actor, action, resource string, resourceID uint, outcome model.AuditOutcome, reason string, details interface{}) *model.AuditLog {
 	log := &model.AuditLog{
-		UserID:     userID.(uint),
-		Username:   usernameStr,
+		UserID:     actor.UserID,
+		Username:   actorDisplayName(actor),
 		Action:     strings.ToUpper(action),
 		Resource:   strings.ToUpper(resource),
 		ResourceID: resourceID,
-		Details:    detailsJSON,
-		IPAddress:  ipAddress,
-		UserAgent:  userAgent,
+		Details:    s.marshalDetails(action, resource, details),
+		IPAddress:  c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		Outcome:    outcome,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
 	}
-	
-	// 异步保存日志，不阻塞主要操作
-	go func(logEntry *model.AuditLog) {
-		if err := s.repo.CreateLog(context.Background(), logEntry); err != nil {
-			s.logger.Error("Failed to save audit log",
-				zap.Error(err),
-				zap.String("action", logEntry.Action),
-				zap.String("resource", logEntry.Resource),
-				zap.Uint("userID", logEntry.UserID))
+	if c.Writer.Written() {
+		log.StatusCode = c.Writer.Status()
+	}
+	return log
+}
+
+// marshalDetails 将详情转换为JSON字符串
+func (s *AuditLogServiceImpl) marshalDetails(action, resource string, details interface{}) string {
+	if details == nil {
+		return "{}"
+	}
+	detailsBytes, err := json.Marshal(details)
+	if err != nil {
+		s.logger.Warn("Failed to marshal details to JSON",
+			zap.Error(err),
+			zap.String("action", action),
+			zap.String("resource", resource))
+		return "{}"
+	}
+	return s.normalizeDetails(action, detailsBytes)
+}
+
+// normalizeDetails 屏蔽详情中的敏感字段；UPDATE操作的详情中加入字段级变更"diff"。
+// 无法解析为JSON的详情原样返回
+func (s *AuditLogServiceImpl) normalizeDetails(action string, raw []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return string(raw)
+	}
+	var diff []apputils.JSONChange
+	obj, isObj := doc.(map[string]interface{})
+	if isObj && strings.EqualFold(action, string(apputils.AuditActionUpdate)) {
+		if _, exists := obj["diff"]; !exists {
+			diff = apputils.AuditUpdateDiff(obj)
 		}
-	}(log)
-	
-	return nil
+	}
+	doc = apputils.RedactAuditDetails(doc)
+	if len(diff) > 0 {
+		doc.(map[string]interface{})["diff"] = diff
+	}
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return string(raw)
+	}
+	return string(normalized)
+}
+
+// PipelineStats 实现了AuditLogService接口的PipelineStats方法
+func (s *AuditLogServiceImpl) PipelineStats() model.AuditPipelineStats {
+	return s.pipeline.Stats()
+}
+
+// VerifyChain 实现了AuditLogService接口的VerifyChain方法
+func (s *AuditLogServiceImpl) VerifyChain(ctx context.Context) (*model.AuditChainVerification, error) {
+	result, err := s.repo.VerifyChain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Valid {
+		s.logger.Error("Audit log hash chain is broken",
+			zap.Uint("id", result.FirstBroken.ID),
+			zap.String("reason", result.FirstBroken.Reason))
+	}
+	return result, nil
+}
+
+// Flush 实现了AuditLogService接口的Flush方法
+func (s *AuditLogServiceImpl) Flush(ctx context.Context) error {
+	return s.pipeline.Flush(ctx)
+}
+
+// Close 实现了AuditLogService接口的Close方法
+func (s *AuditLogServiceImpl) Close(ctx context.Context) error {
+	return s.pipeline.Close(ctx)
+}
+
+// auditActor 返回JWT中间件放入请求上下文的已认证用户
+func auditActor(c *gin.Context) (apputils.Actor, bool) {
+	if actor, ok := apputils.ActorFromContext(c.Request.Context()); ok && actor.UserID != 0 {
+		return actor, true
+	}
+	if value, exists := c.Get("user"); exists {
+		if claims, ok := value.(*model.Claims); ok && claims.UserID != 0 {
+			return apputils.Actor{UserID: claims.UserID, Username: claims.Name, Email: claims.Email}, true
+		}
+	}
+	return apputils.Actor{}, false
+}
+
+// actorDisplayName 返回操作人的名称，没有名称时使用邮箱
+func actorDisplayName(actor apputils.Actor) string {
+	if actor.Username != "" {
+		return actor.Username
+	}
+	return actor.Email
 }
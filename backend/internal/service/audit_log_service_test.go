@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func decodeAuditDetails(t *testing.T, details string) (map[string]interface{}, []apputils.JSONChange) {
+	t.Helper()
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(details), &doc))
+	var withDiff struct {
+		Diff []apputils.JSONChange `json:"diff"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(details), &withDiff))
+	return doc, withDiff.Diff
+}
+
+func TestAuditLogService_MarshalDetails(t *testing.T) {
+	svc := &AuditLogServiceImpl{logger: zap.NewNop()}
+
+	type asset struct {
+		ID        uint   `json:"id"`
+		IPAddress string `json:"ipAddress"`
+		Status    string `json:"status"`
+		Owner     struct {
+			Name         string `json:"name"`
+			PasswordHash string `json:"passwordHash"`
+		} `json:"owner"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+
+	t.Run("UpdateWithSnapshots", func(t *testing.T) {
+		before := asset{ID: 7, IPAddress: "10.0.0.1", Status: "ACTIVE", UpdatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+		before.Owner.Name, before.Owner.PasswordHash = "ann", "hash-1"
+		after := before
+		after.IPAddress = "10.0.0.2"
+		after.Owner.PasswordHash = "hash-2"
+		after.UpdatedAt = time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+		details := svc.marshalDetails("update", "ASSET", map[string]interface{}{"before": before, "after": after})
+		doc, diff := decodeAuditDetails(t, details)
+
+		require.Len(t, diff, 2)
+		assert.Equal(t, "ipAddress", diff[0].Path)
+		assert.Equal(t, "10.0.0.1", diff[0].OldValue)
+		assert.Equal(t, "10.0.0.2", diff[0].NewValue)
+		// 敏感字段仍记为变更，但不保存值
+		assert.Equal(t, "owner.passwordHash", diff[1].Path)
+		assert.Equal(t, apputils.SecretMask, diff[1].OldValue)
+		assert.Equal(t, apputils.SecretMask, diff[1].NewValue)
+		assert.NotContains(t, details, "hash-1")
+		assert.NotContains(t, details, "hash-2")
+		assert.Equal(t, "10.0.0.1", doc["before"].(map[string]interface{})["ipAddress"])
+	})
+
+	t.Run("UpdateWithChangeList", func(t *testing.T) {
+		details := svc.marshalDetails("UPDATE", "BUG", map[string]interface{}{
+			"bulk": true,
+			"changes": []map[string]interface{}{
+				{"bugId": 3, "field": "priority", "oldValue": "LOW", "newValue": "HIGH"},
+			},
+		})
+		_, diff := decodeAuditDetails(t, details)
+		require.Len(t, diff, 1)
+		assert.Equal(t, "priority", diff[0].Path)
+		assert.Equal(t, "LOW", diff[0].OldValue)
+		assert.Equal(t, "HIGH", diff[0].NewValue)
+	})
+
+	t.Run("UpdateWithoutChanges", func(t *testing.T) {
+		details := svc.marshalDetails("UPDATE", "USER_ROLES", map[string]interface{}{"roleIDs": []uint{1, 2}})
+		assert.JSONEq(t, `{"roleIDs":[1,2]}`, details)
+	})
+
+	t.Run("OtherActionsAreRedactedWithoutDiff", func(t *testing.T) {
+		details := svc.marshalDetails("CREATE", "USER", map[string]interface{}{
+			"email":    "ann@example.com",
+			"password": "s3cr3t",
+			"apiKey":   "abc",
+			"id":       uint64(1) << 60,
+		})
+		assert.JSONEq(t, `{"email":"ann@example.com","password":"******","apiKey":"******","id":1152921504606846976}`, details)
+	})
+
+	t.Run("InvalidJSONIsKept", func(t *testing.T) {
+		assert.Equal(t, "not json", svc.normalizeDetails("UPDATE", []byte("not json")))
+	})
+}
+
+// batchAuditLogRepository serves logs to EachLogBatch in fixed-size batches.
+type batchAuditLogRepository struct {
+	repository.AuditLogRepository
+	logs    []model.AuditLog
+	batches int
+}
+
+func (r *batchAuditLogRepository) EachLogBatch(ctx context.Context, params model.AuditLogQueryParams, batchSize int, fn func([]model.AuditLog) error) error {
+	for start := 0; start < len(r.logs); start += batchSize {
+		end := min(start+batchSize, len(r.logs))
+		r.batches++
+		if err := fn(r.logs[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushRecorder counts the flushes of an export.
+type flushRecorder struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (w *flushRecorder) Flush() { w.flushes++ }
+
+func TestAuditLogService_ExportLogs(t *testing.T) {
+	repo := &batchAuditLogRepository{}
+	for i := 1; i <= auditExportBatchSize+1; i++ {
+		repo.logs = append(repo.logs, model.AuditLog{
+			ID:        uint(i),
+			Username:  "ann",
+			Action:    "UPDATE",
+			Resource:  "ASSET",
+			Outcome:   model.AuditOutcomeSuccess,
+			Details:   `{"a":1}`,
+			Hash:      fmt.Sprintf("hash-%d", i),
+			CreatedAt: time.Date(2024, 6, 1, 0, 0, i, 0, time.UTC),
+		})
+	}
+	repo.logs[1].Username = "=HYPERLINK(\"http://evil\")"
+	svc := &AuditLogServiceImpl{repo: repo, logger: zap.NewNop()}
+	ctx := context.Background()
+
+	t.Run("CSV", func(t *testing.T) {
+		var out flushRecorder
+		exported, err := svc.ExportLogs(ctx, model.AuditLogQueryParams{}, AuditExportCSV, &out)
+		require.NoError(t, err)
+		assert.EqualValues(t, auditExportBatchSize+1, exported)
+		assert.Equal(t, 3, out.flushes) // 每批一次，结束时一次
+
+		records, err := csv.NewReader(&out).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, auditExportBatchSize+2)
+		assert.Equal(t, auditExportCSVHeader, records[0])
+		assert.Equal(t, []string{"1", "2024-06-01T00:00:01Z", "0", "ann", "UPDATE", "ASSET", "0", "SUCCESS", "0", "", "", "", `{"a":1}`, "", "hash-1"}, records[1])
+		assert.Equal(t, `'=HYPERLINK("http://evil")`, records[2][3])
+	})
+
+	t.Run("JSONL", func(t *testing.T) {
+		var out flushRecorder
+		exported, err := svc.ExportLogs(ctx, model.AuditLogQueryParams{}, AuditExportJSONL, &out)
+		require.NoError(t, err)
+		assert.EqualValues(t, auditExportBatchSize+1, exported)
+
+		scanner := bufio.NewScanner(&out)
+		var lines int
+		for scanner.Scan() {
+			var log model.AuditLog
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &log))
+			lines++
+			assert.Equal(t, uint(lines), log.ID)
+		}
+		assert.Equal(t, auditExportBatchSize+1, lines)
+	})
+
+	t.Run("UnknownFormat", func(t *testing.T) {
+		_, err := ParseAuditExportFormat("xlsx")
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+		format, err := ParseAuditExportFormat("")
+		require.NoError(t, err)
+		assert.Equal(t, AuditExportCSV, format)
+	})
+}
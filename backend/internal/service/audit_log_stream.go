@@ -0,0 +1,128 @@
+package service
+
+import (
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// auditStreamBatchSize 是实时流每次从数据库读取的最大日志条数
+const auditStreamBatchSize = 100
+
+// Subscribe 订阅审计日志的写入：每当一批日志写入数据库（包括落盘日志重放）后，
+// 返回的通道收到一个通知。通知会合并，订阅者据此从数据库读取新日志，而不是从通道取日志。
+// 调用返回的函数取消订阅
+func (p *AuditPipeline) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	p.subscribersMu.Lock()
+	p.subscribers[ch] = struct{}{}
+	p.subscribersMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			p.subscribersMu.Lock()
+			delete(p.subscribers, ch)
+			p.subscribersMu.Unlock()
+		})
+	}
+}
+
+// notifySubscribers 通知所有订阅者有新日志写入，不会因订阅者未及时读取而阻塞
+func (p *AuditPipeline) notifySubscribers() {
+	p.subscribersMu.Lock()
+	defer p.subscribersMu.Unlock()
+	for ch := range p.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default: // 已有未读取的通知
+		}
+	}
+}
+
+// AuditLogStream 是一个打开的审计日志实时流，按ID升序返回符合筛选条件的新日志
+type AuditLogStream struct {
+	repo        repository.AuditLogRepository
+	params      model.AuditLogQueryParams
+	lastID      uint
+	updates     <-chan struct{}
+	unsubscribe func()
+}
+
+// Updates 返回有新日志写入时收到通知的通道；收到通知后调用Next读取
+func (s *AuditLogStream) Updates() <-chan struct{} {
+	return s.updates
+}
+
+// LastID 返回已读取的最后一条日志的ID
+func (s *AuditLogStream) LastID() uint {
+	return s.lastID
+}
+
+// Next 返回上次读取之后写入的至多auditStreamBatchSize条日志，没有新日志时返回空
+func (s *AuditLogStream) Next(ctx context.Context) ([]model.AuditLog, error) {
+	logs, err := s.repo.FindLogsAfter(ctx, s.params, s.lastID, auditStreamBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) > 0 {
+		s.lastID = logs[len(logs)-1].ID
+	}
+	return logs, nil
+}
+
+// Full 报告一批日志是否已达到单次读取的上限，此时应立即再次调用Next
+func (s *AuditLogStream) Full(logs []model.AuditLog) bool {
+	return len(logs) >= auditStreamBatchSize
+}
+
+// Close 关闭实时流，不再接收写入通知
+func (s *AuditLogStream) Close() {
+	s.unsubscribe()
+}
+
+// OpenStream 实现了AuditLogService接口的OpenStream方法
+func (s *AuditLogServiceImpl) OpenStream(ctx context.Context, params model.AuditLogQueryParams, lastEventID *uint) (*AuditLogStream, error) {
+	userID := apputils.ActorUserID(ctx)
+	if userID == nil {
+		return nil, apputils.ErrUnauthorized
+	}
+	allowed, err := s.permRepo.UserHasPermission(ctx, *userID, model.PermissionStreamAuditLogs)
+	if err != nil {
+		s.logger.Error("Failed to check audit stream permission", zap.Uint("userId", *userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to check permission: %w", err)
+	}
+	if !allowed {
+		s.logger.Warn("User is not allowed to stream audit logs", zap.Uint("userId", *userID))
+		return nil, fmt.Errorf("%w: missing permission '%s'", apputils.ErrForbidden, model.PermissionStreamAuditLogs)
+	}
+
+	// 实时流总是按ID升序推送；ID按提交顺序分配，读过的ID之前不会再出现新日志
+	params.SortBy = "id"
+	params.Order = "asc"
+
+	// 先订阅再确定起点，避免遗漏两者之间写入的日志
+	updates, unsubscribe := s.pipeline.Subscribe()
+	stream := &AuditLogStream{repo: s.repo, params: params, updates: updates, unsubscribe: unsubscribe}
+	if lastEventID != nil {
+		stream.lastID = *lastEventID
+		return stream, nil
+	}
+
+	// 没有续传位置时只推送之后写入的日志
+	latest, err := s.repo.FindLogsAfter(ctx, model.AuditLogQueryParams{SortBy: "id", Order: "desc"}, 0, 1)
+	if err != nil {
+		unsubscribe()
+		s.logger.Error("Failed to find latest audit log", zap.Error(err))
+		return nil, fmt.Errorf("failed to open audit log stream: %w", err)
+	}
+	if len(latest) > 0 {
+		stream.lastID = latest[0].ID
+	}
+	return stream, nil
+}
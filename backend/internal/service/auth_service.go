@@ -5,6 +5,7 @@ import (
 	"EffiPlat/backend/internal/repository"
 	"EffiPlat/backend/internal/utils"
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -15,15 +16,18 @@ import (
 )
 
 type AuthService struct {
-	userRepo repository.UserRepository
-	jwtKey   []byte
-	logger   *zap.Logger
+	userRepo     repository.UserRepository
+	jwtKey       []byte
+	auditService AuditLogService
+	logger       *zap.Logger
 }
 
-func NewAuthService(userRepo repository.UserRepository, jwtKey []byte, logger *zap.Logger) *AuthService {
-	return &AuthService{userRepo: userRepo, jwtKey: jwtKey, logger: logger}
+func NewAuthService(userRepo repository.UserRepository, jwtKey []byte, auditService AuditLogService, logger *zap.Logger) *AuthService {
+	return &AuthService{userRepo: userRepo, jwtKey: jwtKey, auditService: auditService, logger: logger}
 }
 
+// Login checks the credentials and issues a token. Every attempt is audited with the email
+// it was made with; failed attempts record why they failed.
 func (s *AuthService) Login(ctx context.Context, email, password string) (*model.LoginResponse, error) {
 	s.logger.Info("Login attempt", zap.String("email", email))
 
@@ -31,13 +35,16 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*model
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			s.logger.Warn("User not found by email", zap.String("email", email), zap.Error(err))
+			s.auditLogin(ctx, email, nil, "unknown email")
 			return nil, utils.ErrInvalidCredentials
 		}
 		s.logger.Error("Error fetching user by email", zap.String("email", email), zap.Error(err))
+		s.auditLogin(ctx, email, nil, "user lookup failed: "+err.Error())
 		return nil, err
 	}
 	if user == nil {
 		s.logger.Warn("User object is nil after FindByEmail (no error)", zap.String("email", email))
+		s.auditLogin(ctx, email, nil, "unknown email")
 		return nil, utils.ErrInvalidCredentials
 	}
 
@@ -45,6 +52,7 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*model
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
 		s.logger.Warn("Password comparison failed", zap.Uint("userID", user.ID), zap.String("email", email), zap.Error(err))
+		s.auditLogin(ctx, email, user, "invalid password")
 		return nil, utils.ErrInvalidCredentials
 	}
 
@@ -62,13 +70,58 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*model
 	tokenString, err := token.SignedString(s.jwtKey)
 	if err != nil {
 		s.logger.Error("Failed to sign JWT token", zap.Uint("userID", user.ID), zap.Error(err))
+		s.auditLogin(ctx, email, user, "failed to sign token: "+err.Error())
 		return nil, err
 	}
 
 	s.logger.Info("Login successful, token generated", zap.Uint("userID", user.ID), zap.String("email", email))
+	s.auditLogin(ctx, email, user, "")
 
 	return &model.LoginResponse{
 		Token: tokenString,
 		User:  user,
 	}, nil
 }
+
+// Logout audits the logout of the user in ctx. Tokens are stateless, so the client discarding
+// its token is what ends the session.
+func (s *AuthService) Logout(ctx context.Context) {
+	actor, ok := utils.ActorFromContext(ctx)
+	if !ok {
+		return
+	}
+	s.logger.Info("Logout", zap.Uint("userID", actor.UserID))
+	if s.auditService != nil {
+		s.auditService.Record(ctx, &model.AuditLog{
+			Action:     string(utils.AuditActionLogout),
+			Resource:   "USER",
+			ResourceID: actor.UserID,
+		})
+	}
+}
+
+// auditLogin records a login attempt; an empty reason means it succeeded. user is nil when
+// no account matches the email.
+func (s *AuthService) auditLogin(ctx context.Context, email string, user *model.User, reason string) {
+	if s.auditService == nil {
+		return
+	}
+	details, _ := json.Marshal(map[string]string{"email": email})
+	entry := &model.AuditLog{
+		Username: email,
+		Action:   string(utils.AuditActionLogin),
+		Resource: "USER",
+		Details:  string(details),
+		Outcome:  model.AuditOutcomeSuccess,
+	}
+	if user != nil {
+		entry.UserID, entry.ResourceID = user.ID, user.ID
+		if user.Name != "" {
+			entry.Username = user.Name
+		}
+	}
+	if reason != "" {
+		entry.Outcome, entry.Reason = model.AuditOutcomeFailure, reason
+	}
+	s.auditService.Record(ctx, entry)
+}
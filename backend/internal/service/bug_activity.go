@@ -0,0 +1,104 @@
+package service
+
+import (
+	"EffiPlat/backend/internal/model"
+	apputils "EffiPlat/backend/internal/utils"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// bugFieldChanges lists the fields that differ between before and after. The status, and the
+// resolution when it moved with the status, are left to the status transition.
+func bugFieldChanges(ctx context.Context, before, after *model.Bug, transitioned bool) []*model.BugFieldChange {
+	var changes []*model.BugFieldChange
+	record := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, &model.BugFieldChange{
+				BugID:    after.ID,
+				Field:    field,
+				OldValue: oldValue,
+				NewValue: newValue,
+				ActorID:  apputils.ActorUserID(ctx),
+				Actor:    apputils.ActorName(ctx),
+			})
+		}
+	}
+
+	record("title", before.Title, after.Title)
+	record("description", before.Description, after.Description)
+	record("priority", string(before.Priority), string(after.Priority))
+	record("assigneeId", formatOptionalID(before.AssigneeID), formatOptionalID(after.AssigneeID))
+	if !transitioned {
+		record("resolution", formatResolution(before.Resolution), formatResolution(after.Resolution))
+	}
+	record("environmentId", formatOptionalID(before.EnvironmentID), formatOptionalID(after.EnvironmentID))
+	record("businessId", formatOptionalID(before.BusinessID), formatOptionalID(after.BusinessID))
+	record("serviceId", formatOptionalID(before.ServiceID), formatOptionalID(after.ServiceID))
+	record("serviceVersion", before.ServiceVersion, after.ServiceVersion)
+	record("labels", strings.Join(before.Labels, ", "), strings.Join(after.Labels, ", "))
+	return changes
+}
+
+func formatOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+func formatResolution(resolution *model.BugResolutionType) string {
+	if resolution == nil {
+		return ""
+	}
+	return string(*resolution)
+}
+
+// activityOrder breaks ties between entries recorded at the same instant: an update's field
+// changes come before its transition, and both before a comment.
+var activityOrder = map[model.BugActivityType]int{
+	model.BugActivityFieldChange:      0,
+	model.BugActivityStatusTransition: 1,
+	model.BugActivityComment:          2,
+}
+
+// GetBugActivity returns the bug's comments, field changes and status transitions as one
+// feed, oldest first. Deleted comments stay in the feed without their body.
+func (s *bugServiceImpl) GetBugActivity(ctx context.Context, bugID uint) ([]model.BugActivityItem, error) {
+	if _, err := s.bugRepo.GetByID(ctx, bugID); err != nil {
+		return nil, err
+	}
+	comments, err := s.bugRepo.ListComments(ctx, bugID, true)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.bugRepo.ListFieldChanges(ctx, bugID)
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := s.bugRepo.ListTransitions(ctx, bugID)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := make([]model.BugActivityItem, 0, len(comments)+len(changes)+len(transitions))
+	for _, comment := range comments {
+		resp := comment.ToBugCommentResponse()
+		feed = append(feed, model.BugActivityItem{Type: model.BugActivityComment, At: comment.CreatedAt, ActorID: comment.AuthorID, Comment: &resp})
+	}
+	for _, change := range changes {
+		feed = append(feed, model.BugActivityItem{Type: model.BugActivityFieldChange, At: change.CreatedAt, ActorID: change.ActorID, FieldChange: change})
+	}
+	for _, transition := range transitions {
+		feed = append(feed, model.BugActivityItem{Type: model.BugActivityStatusTransition, At: transition.CreatedAt, ActorID: transition.ActorID, Transition: transition})
+	}
+
+	sort.SliceStable(feed, func(i, j int) bool {
+		if !feed[i].At.Equal(feed[j].At) {
+			return feed[i].At.Before(feed[j].At)
+		}
+		return activityOrder[feed[i].Type] < activityOrder[feed[j].Type]
+	})
+	return feed, nil
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// userStatusActive is the only user status eligible for automatic assignment.
+const userStatusActive = "active"
+
+// BugAssignmentRuleInputDTO is the payload for creating or replacing an assignment rule.
+type BugAssignmentRuleInputDTO struct {
+	Name                  string `json:"name" binding:"required,min=1,max=100"`
+	Description           string `json:"description" binding:"omitempty,max=255"`
+	Priority              *int   `json:"priority"` // Lower runs first, defaults to 100
+	Enabled               *bool  `json:"enabled"`  // Defaults to true
+	MatchServiceID        *uint  `json:"matchServiceId" binding:"omitempty,gt=0"`
+	MatchBusinessID       *uint  `json:"matchBusinessId" binding:"omitempty,gt=0"`
+	MatchEnvironmentID    *uint  `json:"matchEnvironmentId" binding:"omitempty,gt=0"`
+	ResponsibilityGroupID uint   `json:"responsibilityGroupId" binding:"required,gt=0"`
+	Strategy              string `json:"strategy" binding:"omitempty,oneof=primary round_robin"` // Defaults to primary
+}
+
+// BugAssignmentDryRunInputDTO describes a hypothetical bug to evaluate the rules against.
+type BugAssignmentDryRunInputDTO struct {
+	ServiceID     *uint `json:"serviceId"`
+	BusinessID    *uint `json:"businessId"`
+	EnvironmentID *uint `json:"environmentId"`
+}
+
+// BugAssignmentDecision records which rule assigned a bug and to whom.
+type BugAssignmentDecision struct {
+	RuleID                uint                            `json:"ruleId"`
+	RuleName              string                          `json:"ruleName"`
+	ResponsibilityGroupID uint                            `json:"responsibilityGroupId"`
+	Strategy              model.BugAssignmentStrategyType `json:"strategy"`
+	AssigneeID            uint                            `json:"assigneeId"`
+}
+
+// BugAssignmentRuleEvaluationDTO explains the outcome of one rule during a dry run.
+type BugAssignmentRuleEvaluationDTO struct {
+	RuleID   uint   `json:"ruleId"`
+	RuleName string `json:"ruleName"`
+	Matched  bool   `json:"matched"`
+	Reason   string `json:"reason"`
+}
+
+// BugAssignmentDryRunResultDTO is the result of evaluating the rules without assigning anything.
+type BugAssignmentDryRunResultDTO struct {
+	Decision    *BugAssignmentDecision           `json:"decision"` // nil when no rule fired
+	Evaluations []BugAssignmentRuleEvaluationDTO `json:"evaluations"`
+}
+
+// BugAssigner picks an assignee for a new bug.
+type BugAssigner interface {
+	// Assign returns the decision of the first rule that fires for bug, or nil if none does.
+	// It does not modify bug; round-robin rules advance their cursor.
+	Assign(ctx context.Context, bug *model.Bug) (*BugAssignmentDecision, error)
+}
+
+// BugAssignmentService manages bug assignment rules and evaluates them.
+type BugAssignmentService interface {
+	BugAssigner
+	CreateRule(ctx context.Context, input *BugAssignmentRuleInputDTO) (*model.BugAssignmentRule, error)
+	GetRule(ctx context.Context, id uint) (*model.BugAssignmentRule, error)
+	ListRules(ctx context.Context) ([]*model.BugAssignmentRule, error)
+	UpdateRule(ctx context.Context, id uint, input *BugAssignmentRuleInputDTO) (*model.BugAssignmentRule, error)
+	DeleteRule(ctx context.Context, id uint) error
+	DryRun(ctx context.Context, input *BugAssignmentDryRunInputDTO) (*BugAssignmentDryRunResultDTO, error)
+}
+
+// bugAssignmentServiceImpl implements BugAssignmentService.
+type bugAssignmentServiceImpl struct {
+	ruleRepo     repository.BugAssignmentRuleRepository
+	groupRepo    repository.ResponsibilityGroupRepository
+	envRepo      repository.EnvironmentRepository
+	serviceRepo  repository.ServiceRepository
+	businessRepo repository.BusinessRepository
+	logger       *zap.Logger
+}
+
+// NewBugAssignmentService creates a new BugAssignmentService.
+func NewBugAssignmentService(
+	ruleRepo repository.BugAssignmentRuleRepository,
+	groupRepo repository.ResponsibilityGroupRepository,
+	envRepo repository.EnvironmentRepository,
+	serviceRepo repository.ServiceRepository,
+	businessRepo repository.BusinessRepository,
+	logger *zap.Logger,
+) BugAssignmentService {
+	return &bugAssignmentServiceImpl{
+		ruleRepo:     ruleRepo,
+		groupRepo:    groupRepo,
+		envRepo:      envRepo,
+		serviceRepo:  serviceRepo,
+		businessRepo: businessRepo,
+		logger:       logger,
+	}
+}
+
+// CreateRule validates and stores a new assignment rule.
+func (s *bugAssignmentServiceImpl) CreateRule(ctx context.Context, input *BugAssignmentRuleInputDTO) (*model.BugAssignmentRule, error) {
+	rule := &model.BugAssignmentRule{Priority: 100, Enabled: true}
+	if err := s.applyRuleInput(ctx, rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Bug assignment rule created", zap.Uint("id", rule.ID), zap.String("name", rule.Name))
+	return rule, nil
+}
+
+// GetRule returns an assignment rule by ID.
+func (s *bugAssignmentServiceImpl) GetRule(ctx context.Context, id uint) (*model.BugAssignmentRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: bug assignment rule %d", apputils.ErrNotFound, id)
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// ListRules returns every assignment rule in evaluation order.
+func (s *bugAssignmentServiceImpl) ListRules(ctx context.Context) ([]*model.BugAssignmentRule, error) {
+	return s.ruleRepo.List(ctx, false)
+}
+
+// UpdateRule replaces an assignment rule. Moving the rule to another group resets its round-robin cursor.
+func (s *bugAssignmentServiceImpl) UpdateRule(ctx context.Context, id uint, input *BugAssignmentRuleInputDTO) (*model.BugAssignmentRule, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previousGroupID := rule.ResponsibilityGroupID
+	if err := s.applyRuleInput(ctx, rule, input); err != nil {
+		return nil, err
+	}
+	if rule.ResponsibilityGroupID != previousGroupID {
+		rule.LastAssignedUserID = nil
+	}
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Bug assignment rule updated", zap.Uint("id", rule.ID))
+	return rule, nil
+}
+
+// DeleteRule removes an assignment rule. Bugs it assigned keep their AssignmentRuleID.
+func (s *bugAssignmentServiceImpl) DeleteRule(ctx context.Context, id uint) error {
+	if err := s.ruleRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: bug assignment rule %d", apputils.ErrNotFound, id)
+		}
+		return err
+	}
+	s.logger.Info("Bug assignment rule deleted", zap.Uint("id", id))
+	return nil
+}
+
+// DryRun evaluates the enabled rules against the given links and explains each outcome.
+// Nothing is assigned and no round-robin cursor moves.
+func (s *bugAssignmentServiceImpl) DryRun(ctx context.Context, input *BugAssignmentDryRunInputDTO) (*BugAssignmentDryRunResultDTO, error) {
+	result := &BugAssignmentDryRunResultDTO{Evaluations: []BugAssignmentRuleEvaluationDTO{}}
+	decision, err := s.evaluate(ctx, input.ServiceID, input.BusinessID, input.EnvironmentID, &result.Evaluations)
+	if err != nil {
+		return nil, err
+	}
+	result.Decision = decision
+	return result, nil
+}
+
+// Assign implements BugAssigner.
+func (s *bugAssignmentServiceImpl) Assign(ctx context.Context, bug *model.Bug) (*BugAssignmentDecision, error) {
+	decision, err := s.evaluate(ctx, bug.ServiceID, bug.BusinessID, bug.EnvironmentID, nil)
+	if err != nil || decision == nil {
+		return nil, err
+	}
+	if decision.Strategy == model.BugAssignmentStrategyRoundRobin {
+		if err := s.ruleRepo.AdvanceCursor(ctx, decision.RuleID, decision.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
+	s.logger.Info("Bug assigned by rule", zap.Uint("ruleId", decision.RuleID), zap.Uint("assigneeId", decision.AssigneeID))
+	return decision, nil
+}
+
+// evaluate runs the enabled rules in order and returns the first decision. A matching rule
+// whose group has no active member falls through to the next rule. When trace is non-nil
+// every evaluated rule is appended to it.
+func (s *bugAssignmentServiceImpl) evaluate(ctx context.Context, serviceID, businessID, environmentID *uint, trace *[]BugAssignmentRuleEvaluationDTO) (*BugAssignmentDecision, error) {
+	rules, err := s.ruleRepo.List(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	explain := func(rule *model.BugAssignmentRule, matched bool, reason string) {
+		if trace != nil {
+			*trace = append(*trace, BugAssignmentRuleEvaluationDTO{RuleID: rule.ID, RuleName: rule.Name, Matched: matched, Reason: reason})
+		}
+	}
+
+	for _, rule := range rules {
+		if !rule.Matches(serviceID, businessID, environmentID) {
+			explain(rule, false, "links do not match")
+			continue
+		}
+		members, err := s.groupRepo.ListMembers(ctx, rule.ResponsibilityGroupID)
+		if err != nil {
+			return nil, err
+		}
+		assignee := pickAssignee(rule, members)
+		if assignee == 0 {
+			explain(rule, false, fmt.Sprintf("responsibility group %d has no active member", rule.ResponsibilityGroupID))
+			continue
+		}
+		explain(rule, true, fmt.Sprintf("assigned user %d by %s", assignee, rule.Strategy))
+		return &BugAssignmentDecision{
+			RuleID:                rule.ID,
+			RuleName:              rule.Name,
+			ResponsibilityGroupID: rule.ResponsibilityGroupID,
+			Strategy:              rule.Strategy,
+			AssigneeID:            assignee,
+		}, nil
+	}
+	return nil, nil
+}
+
+// pickAssignee chooses a member according to the rule's strategy, or returns 0 if no member
+// is active. members are ordered by user ID.
+func pickAssignee(rule *model.BugAssignmentRule, members []model.ResponsibilityGroupMember) uint {
+	var active []uint
+	var primary uint
+	for _, member := range members {
+		if member.User == nil || member.User.Status != userStatusActive {
+			continue
+		}
+		active = append(active, member.UserID)
+		if member.IsPrimary {
+			primary = member.UserID
+		}
+	}
+	if len(active) == 0 {
+		return 0
+	}
+
+	switch rule.Strategy {
+	case model.BugAssignmentStrategyRoundRobin:
+		if rule.LastAssignedUserID != nil {
+			for _, id := range active {
+				if id > *rule.LastAssignedUserID {
+					return id
+				}
+			}
+		}
+		return active[0]
+	default:
+		if primary != 0 {
+			return primary
+		}
+		return active[0]
+	}
+}
+
+// applyRuleInput validates input and copies it onto rule.
+func (s *bugAssignmentServiceImpl) applyRuleInput(ctx context.Context, rule *model.BugAssignmentRule, input *BugAssignmentRuleInputDTO) error {
+	if input.MatchServiceID == nil && input.MatchBusinessID == nil && input.MatchEnvironmentID == nil {
+		return fmt.Errorf("%w: a rule must match a service, business or environment", apputils.ErrBadRequest)
+	}
+	strategy := model.BugAssignmentStrategyPrimary
+	if input.Strategy != "" {
+		strategy = model.BugAssignmentStrategyType(input.Strategy)
+	}
+	if !strategy.IsValid() {
+		return fmt.Errorf("%w: invalid assignment strategy '%s'", apputils.ErrBadRequest, input.Strategy)
+	}
+
+	if _, err := s.groupRepo.GetByID(ctx, input.ResponsibilityGroupID); err != nil {
+		return linkLookupError("responsibility group", input.ResponsibilityGroupID, err)
+	}
+	if input.MatchServiceID != nil {
+		if _, err := s.serviceRepo.GetByID(ctx, *input.MatchServiceID); err != nil {
+			return linkLookupError("service", *input.MatchServiceID, err)
+		}
+	}
+	if input.MatchBusinessID != nil {
+		if _, err := s.businessRepo.GetByID(ctx, *input.MatchBusinessID); err != nil {
+			return linkLookupError("business", *input.MatchBusinessID, err)
+		}
+	}
+	if input.MatchEnvironmentID != nil {
+		if _, err := s.envRepo.GetByID(ctx, *input.MatchEnvironmentID); err != nil {
+			return linkLookupError("environment", *input.MatchEnvironmentID, err)
+		}
+	}
+
+	rule.Name = input.Name
+	rule.Description = input.Description
+	if input.Priority != nil {
+		rule.Priority = *input.Priority
+	}
+	if input.Enabled != nil {
+		rule.Enabled = *input.Enabled
+	}
+	rule.MatchServiceID = input.MatchServiceID
+	rule.MatchBusinessID = input.MatchBusinessID
+	rule.MatchEnvironmentID = input.MatchEnvironmentID
+	rule.ResponsibilityGroupID = input.ResponsibilityGroupID
+	rule.Strategy = strategy
+	return nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"EffiPlat/backend/internal/model"
+	mock_repository "EffiPlat/backend/internal/repository/mocks"
+	"EffiPlat/backend/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func groupMember(userID uint, status string, primary bool) model.ResponsibilityGroupMember {
+	return model.ResponsibilityGroupMember{UserID: userID, IsPrimary: primary, User: &model.User{ID: userID, Status: status}}
+}
+
+func TestBugAssignmentService_Assign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruleRepo := mock_repository.NewMockBugAssignmentRuleRepository(ctrl)
+	groupRepo := mock_repository.NewMockResponsibilityGroupRepository(ctrl)
+	svc := NewBugAssignmentService(ruleRepo, groupRepo, nil, nil, nil, zap.NewNop())
+	ctx := context.Background()
+
+	serviceID, envID := uint(7), uint(3)
+	otherServiceID := uint(8)
+	bug := &model.Bug{ServiceID: &serviceID, EnvironmentID: &envID}
+
+	t.Run("First matching rule with an active primary wins", func(t *testing.T) {
+		ruleRepo.EXPECT().List(ctx, true).Return([]*model.BugAssignmentRule{
+			{ID: 1, Name: "other service", MatchServiceID: &otherServiceID, ResponsibilityGroupID: 10, Strategy: model.BugAssignmentStrategyPrimary},
+			{ID: 2, Name: "payments", MatchServiceID: &serviceID, MatchEnvironmentID: &envID, ResponsibilityGroupID: 20, Strategy: model.BugAssignmentStrategyPrimary},
+		}, nil)
+		groupRepo.EXPECT().ListMembers(ctx, uint(20)).Return([]model.ResponsibilityGroupMember{
+			groupMember(4, "active", false),
+			groupMember(5, "active", true),
+		}, nil)
+
+		decision, err := svc.Assign(ctx, bug)
+		require.NoError(t, err)
+		require.NotNil(t, decision)
+		assert.Equal(t, uint(2), decision.RuleID)
+		assert.Equal(t, uint(5), decision.AssigneeID)
+	})
+
+	t.Run("Inactive primary falls back to the first active member", func(t *testing.T) {
+		ruleRepo.EXPECT().List(ctx, true).Return([]*model.BugAssignmentRule{
+			{ID: 2, MatchServiceID: &serviceID, ResponsibilityGroupID: 20, Strategy: model.BugAssignmentStrategyPrimary},
+		}, nil)
+		groupRepo.EXPECT().ListMembers(ctx, uint(20)).Return([]model.ResponsibilityGroupMember{
+			groupMember(4, "inactive", false),
+			groupMember(5, "inactive", true),
+			groupMember(6, "active", false),
+		}, nil)
+
+		decision, err := svc.Assign(ctx, bug)
+		require.NoError(t, err)
+		assert.Equal(t, uint(6), decision.AssigneeID)
+	})
+
+	t.Run("Round robin skips inactive members and advances the cursor", func(t *testing.T) {
+		last := uint(4)
+		ruleRepo.EXPECT().List(ctx, true).Return([]*model.BugAssignmentRule{
+			{ID: 3, MatchServiceID: &serviceID, ResponsibilityGroupID: 30, Strategy: model.BugAssignmentStrategyRoundRobin, LastAssignedUserID: &last},
+		}, nil)
+		groupRepo.EXPECT().ListMembers(ctx, uint(30)).Return([]model.ResponsibilityGroupMember{
+			groupMember(4, "active", false),
+			groupMember(5, "inactive", false),
+			groupMember(6, "active", false),
+		}, nil)
+		ruleRepo.EXPECT().AdvanceCursor(ctx, uint(3), uint(6)).Return(nil)
+
+		decision, err := svc.Assign(ctx, bug)
+		require.NoError(t, err)
+		assert.Equal(t, uint(6), decision.AssigneeID)
+	})
+
+	t.Run("Round robin wraps around", func(t *testing.T) {
+		last := uint(6)
+		ruleRepo.EXPECT().List(ctx, true).Return([]*model.BugAssignmentRule{
+			{ID: 3, MatchServiceID: &serviceID, ResponsibilityGroupID: 30, Strategy: model.BugAssignmentStrategyRoundRobin, LastAssignedUserID: &last},
+		}, nil)
+		groupRepo.EXPECT().ListMembers(ctx, uint(30)).Return([]model.ResponsibilityGroupMember{
+			groupMember(4, "active", false),
+			groupMember(6, "active", false),
+		}, nil)
+		ruleRepo.EXPECT().AdvanceCursor(ctx, uint(3), uint(4)).Return(nil)
+
+		decision, err := svc.Assign(ctx, bug)
+		require.NoError(t, err)
+		assert.Equal(t, uint(4), decision.AssigneeID)
+	})
+
+	t.Run("Group without active members falls through", func(t *testing.T) {
+		ruleRepo.EXPECT().List(ctx, true).Return([]*model.BugAssignmentRule{
+			{ID: 2, MatchServiceID: &serviceID, ResponsibilityGroupID: 20, Strategy: model.BugAssignmentStrategyPrimary},
+		}, nil)
+		groupRepo.EXPECT().ListMembers(ctx, uint(20)).Return([]model.ResponsibilityGroupMember{groupMember(4, "inactive", true)}, nil)
+
+		decision, err := svc.Assign(ctx, bug)
+		assert.NoError(t, err)
+		assert.Nil(t, decision)
+	})
+}
+
+func TestBugAssignmentService_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruleRepo := mock_repository.NewMockBugAssignmentRuleRepository(ctrl)
+	groupRepo := mock_repository.NewMockResponsibilityGroupRepository(ctrl)
+	svc := NewBugAssignmentService(ruleRepo, groupRepo, nil, nil, nil, zap.NewNop())
+	ctx := context.Background()
+
+	businessID := uint(9)
+	ruleRepo.EXPECT().List(ctx, true).Return([]*model.BugAssignmentRule{
+		{ID: 1, Name: "by business", MatchBusinessID: &businessID, ResponsibilityGroupID: 10, Strategy: model.BugAssignmentStrategyRoundRobin},
+	}, nil)
+	groupRepo.EXPECT().ListMembers(ctx, uint(10)).Return([]model.ResponsibilityGroupMember{groupMember(2, "active", false)}, nil)
+	// No AdvanceCursor call is expected: a dry run has no side effects.
+
+	result, err := svc.DryRun(ctx, &BugAssignmentDryRunInputDTO{BusinessID: &businessID})
+	require.NoError(t, err)
+	require.NotNil(t, result.Decision)
+	assert.Equal(t, uint(2), result.Decision.AssigneeID)
+	require.Len(t, result.Evaluations, 1)
+	assert.True(t, result.Evaluations[0].Matched)
+}
+
+func TestBugAssignmentService_CreateRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruleRepo := mock_repository.NewMockBugAssignmentRuleRepository(ctrl)
+	groupRepo := mock_repository.NewMockResponsibilityGroupRepository(ctrl)
+	svc := NewBugAssignmentService(ruleRepo, groupRepo, nil, nil, nil, zap.NewNop())
+	ctx := context.Background()
+
+	t.Run("Rule must match something", func(t *testing.T) {
+		_, err := svc.CreateRule(ctx, &BugAssignmentRuleInputDTO{Name: "catch-all", ResponsibilityGroupID: 1})
+		assert.True(t, errors.Is(err, utils.ErrBadRequest))
+	})
+
+	t.Run("Unknown group is rejected", func(t *testing.T) {
+		envID := uint(3)
+		groupRepo.EXPECT().GetByID(ctx, uint(99)).Return(nil, gorm.ErrRecordNotFound)
+		_, err := svc.CreateRule(ctx, &BugAssignmentRuleInputDTO{Name: "prod", MatchEnvironmentID: &envID, ResponsibilityGroupID: 99})
+		assert.True(t, errors.Is(err, utils.ErrBadRequest))
+	})
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// bugMaxLabels is how many labels a bug may carry, as validated on create and update.
+const bugMaxLabels = 20
+
+// BulkBugUpdateError is returned by BulkUpdateBugs when an atomic update is not applied because
+// some bugs cannot be updated; Response tells which and why.
+type BulkBugUpdateError struct {
+	Response *model.BulkBugUpdateResponse
+}
+
+func (e *BulkBugUpdateError) Error() string {
+	return fmt.Sprintf("%s: %d of %d bugs cannot be updated, none was changed", model.ErrBulkBugUpdateFailed, e.Response.Failed, e.Response.Matched)
+}
+
+func (e *BulkBugUpdateError) Unwrap() error {
+	return model.ErrBulkBugUpdateFailed
+}
+
+// BulkUpdateBugs applies the same changes to the bugs picked by ID or by filter. Each bug goes
+// through the same checks as UpdateBug, so status changes follow the workflow.
+func (s *bugServiceImpl) BulkUpdateBugs(ctx context.Context, req *model.BulkBugUpdateRequest) (*model.BulkBugUpdateResponse, error) {
+	if (len(req.IDs) > 0) == (req.Filter != nil) {
+		return nil, fmt.Errorf("%w: give either ids or a filter", apputils.ErrBadRequest)
+	}
+	if req.Update.IsEmpty() {
+		return nil, fmt.Errorf("%w: no changes to apply", apputils.ErrBadRequest)
+	}
+
+	resp := &model.BulkBugUpdateResponse{Atomic: req.Atomic}
+	bugs, err := s.bulkBugTargets(ctx, req, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先在内存中逐个校验并应用变更，再统一保存
+	pending := make(map[uint]repository.BugUpdate)
+	var updates []repository.BugUpdate
+	for i := range resp.Results {
+		result := &resp.Results[i]
+		bug := bugs[result.ID]
+		if bug == nil {
+			continue // Not found
+		}
+		transition, changes, err := s.applyBugUpdate(ctx, bug, bulkBugUpdateFor(bug, &req.Update))
+		if err == nil && len(bug.Labels) > bugMaxLabels {
+			err = fmt.Errorf("%w: a bug carries at most %d labels", apputils.ErrBadRequest, bugMaxLabels)
+		}
+		switch {
+		case err != nil:
+			result.Status, result.Error = model.BulkBugFailed, err.Error()
+		case transition == nil && len(changes) == 0:
+			result.Status = model.BulkBugUnchanged
+		default:
+			result.Changes, result.Transition = changes, transition
+			update := repository.BugUpdate{Bug: bug, Transition: transition, Changes: changes}
+			pending[bug.ID] = update
+			updates = append(updates, update)
+		}
+	}
+
+	if req.Atomic {
+		if countBulkBugResults(resp.Results, model.BulkBugFailed) > 0 {
+			for i := range resp.Results {
+				if _, ok := pending[resp.Results[i].ID]; ok {
+					resp.Results[i].Status = model.BulkBugSkipped
+				}
+			}
+			summarizeBulkBugUpdate(resp)
+			return nil, &BulkBugUpdateError{Response: resp}
+		}
+		if len(updates) > 0 {
+			if err := s.bugRepo.UpdateManyWithHistory(ctx, updates); err != nil {
+				return nil, err
+			}
+		}
+		for i := range resp.Results {
+			if _, ok := pending[resp.Results[i].ID]; ok {
+				resp.Results[i].Status = model.BulkBugUpdated
+			}
+		}
+	} else {
+		for i := range resp.Results {
+			result := &resp.Results[i]
+			update, ok := pending[result.ID]
+			if !ok {
+				continue
+			}
+			if err := s.bugRepo.UpdateWithHistory(ctx, update.Bug, update.Transition, update.Changes); err != nil {
+				result.Status, result.Error = model.BulkBugFailed, err.Error()
+				result.Changes, result.Transition = nil, nil
+				continue
+			}
+			result.Status = model.BulkBugUpdated
+		}
+	}
+
+	for i := range resp.Results {
+		result := &resp.Results[i]
+		if result.Status == model.BulkBugUpdated || result.Status == model.BulkBugUnchanged {
+			bugResponse := bugs[result.ID].ToBugResponse()
+			result.Bug = &bugResponse
+		}
+	}
+	summarizeBulkBugUpdate(resp)
+	return resp, nil
+}
+
+// bulkBugTargets loads the bugs of a bulk update and adds a result for each to resp, in request
+// order for IDs and newest first for a filter. Unknown IDs get a failed result.
+func (s *bugServiceImpl) bulkBugTargets(ctx context.Context, req *model.BulkBugUpdateRequest, resp *model.BulkBugUpdateResponse) (map[uint]*model.Bug, error) {
+	bugs := make(map[uint]*model.Bug)
+	if req.Filter != nil {
+		params := *req.Filter
+		params.Page, params.PageSize = 1, model.BulkBugUpdateMaxBugs
+		found, total, err := s.bugRepo.List(ctx, &params)
+		if err != nil {
+			return nil, err
+		}
+		if total > model.BulkBugUpdateMaxBugs {
+			return nil, fmt.Errorf("%w: the filter matches %d bugs, at most %d can be updated at once", apputils.ErrBadRequest, total, model.BulkBugUpdateMaxBugs)
+		}
+		for _, bug := range found {
+			bugs[bug.ID] = bug
+			resp.Results = append(resp.Results, model.BulkBugUpdateResult{ID: bug.ID})
+		}
+		return bugs, nil
+	}
+
+	for _, id := range req.IDs {
+		if _, seen := bugs[id]; seen {
+			continue
+		}
+		bug, err := s.bugRepo.GetByID(ctx, id)
+		if errors.Is(err, repository.ErrBugNotFound) {
+			bugs[id] = nil
+			resp.Results = append(resp.Results, model.BulkBugUpdateResult{ID: id, Status: model.BulkBugFailed, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		bugs[id] = bug
+		resp.Results = append(resp.Results, model.BulkBugUpdateResult{ID: id})
+	}
+	return bugs, nil
+}
+
+// bulkBugUpdateFor turns the changes of a bulk update into the update of one bug.
+func bulkBugUpdateFor(bug *model.Bug, changes *model.BulkBugChanges) *model.UpdateBugRequest {
+	req := &model.UpdateBugRequest{
+		Status:     changes.Status,
+		Resolution: changes.Resolution,
+		Comment:    changes.Comment,
+		Priority:   changes.Priority,
+		AssigneeID: changes.AssigneeID,
+	}
+	if len(changes.AddLabels) > 0 || len(changes.RemoveLabels) > 0 {
+		removed := make(map[string]bool, len(changes.RemoveLabels))
+		for _, label := range normalizeBugLabels(changes.RemoveLabels) {
+			removed[label] = true
+		}
+		var labels []string
+		for _, label := range append(append([]string{}, bug.Labels...), normalizeBugLabels(changes.AddLabels)...) {
+			if !removed[label] {
+				labels = append(labels, label)
+			}
+		}
+		req.Labels = &labels
+	}
+	return req
+}
+
+func countBulkBugResults(results []model.BulkBugUpdateResult, status model.BulkBugResultStatus) int {
+	count := 0
+	for _, result := range results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func summarizeBulkBugUpdate(resp *model.BulkBugUpdateResponse) {
+	resp.Matched = len(resp.Results)
+	resp.Updated = countBulkBugResults(resp.Results, model.BulkBugUpdated)
+	resp.Unchanged = countBulkBugResults(resp.Results, model.BulkBugUnchanged)
+	resp.Failed = countBulkBugResults(resp.Results, model.BulkBugFailed)
+}
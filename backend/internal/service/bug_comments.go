@@ -0,0 +1,191 @@
+package service
+
+import (
+	"EffiPlat/backend/internal/model"
+	apputils "EffiPlat/backend/internal/utils"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// mentionPattern matches "@handle" or "@name@example.com" not preceded by a word
+	// character, so e-mail addresses written in the text are not taken as mentions.
+	mentionPattern    = regexp.MustCompile(`(^|[^A-Za-z0-9_.@])@([A-Za-z0-9._+-]+(?:@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+)?)`)
+	fencedCodePattern = regexp.MustCompile("(?s)```.*?(?:```|$)")
+	inlineCodePattern = regexp.MustCompile("`[^`\n]*`")
+)
+
+// parseMentionHandles returns the distinct, lower-cased handles @mentioned in a Markdown
+// body, in order of appearance. Mentions inside code spans and code blocks are ignored.
+func parseMentionHandles(body string) []string {
+	text := fencedCodePattern.ReplaceAllString(body, " ")
+	text = inlineCodePattern.ReplaceAllString(text, " ")
+
+	var handles []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		handle := strings.ToLower(strings.TrimRight(match[2], ".-"))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+	}
+	return handles
+}
+
+// resolveMentions maps the handles mentioned in body to user IDs. A handle is either a full
+// e-mail address or the part before "@"; handles that match no user, or more than one, are skipped.
+func (s *bugServiceImpl) resolveMentions(ctx context.Context, body string) ([]uint, error) {
+	handles := parseMentionHandles(body)
+	if len(handles) == 0 || s.userRepo == nil {
+		return nil, nil
+	}
+	users, err := s.userRepo.FindByMentionHandles(ctx, handles)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make(map[string][]uint)
+	for _, user := range users {
+		email := strings.ToLower(user.Email)
+		local, _, _ := strings.Cut(email, "@")
+		matches[email] = append(matches[email], user.ID)
+		if local != email {
+			matches[local] = append(matches[local], user.ID)
+		}
+	}
+
+	var ids []uint
+	seen := make(map[uint]bool)
+	for _, handle := range handles {
+		if candidates := matches[handle]; len(candidates) == 1 && !seen[candidates[0]] {
+			seen[candidates[0]] = true
+			ids = append(ids, candidates[0])
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// AddBugComment adds a comment by the current actor to a bug.
+func (s *bugServiceImpl) AddBugComment(ctx context.Context, bugID uint, req *model.CreateBugCommentRequest) (*model.BugCommentResponse, error) {
+	bug, err := s.bugRepo.GetByID(ctx, bugID)
+	if err != nil {
+		return nil, err
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, fmt.Errorf("%w: comment body cannot be empty", apputils.ErrBadRequest)
+	}
+	mentionIDs, err := s.resolveMentions(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &model.BugComment{BugID: bugID, AuthorID: apputils.ActorUserID(ctx), Body: body}
+	if err := s.bugRepo.CreateComment(ctx, comment, mentionIDs); err != nil {
+		return nil, err
+	}
+	// 报告人以外的用户首次评论即视为首次响应
+	if bug.FirstRespondedAt == nil && comment.AuthorID != nil && (bug.ReporterID == nil || *bug.ReporterID != *comment.AuthorID) {
+		if err := s.bugRepo.MarkFirstResponse(ctx, bugID, comment.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	return s.getBugComment(ctx, bugID, comment.ID)
+}
+
+// ListBugComments returns the live comments of a bug, oldest first.
+func (s *bugServiceImpl) ListBugComments(ctx context.Context, bugID uint) ([]model.BugCommentResponse, error) {
+	if _, err := s.bugRepo.GetByID(ctx, bugID); err != nil {
+		return nil, err
+	}
+	comments, err := s.bugRepo.ListComments(ctx, bugID, false)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]model.BugCommentResponse, len(comments))
+	for i, comment := range comments {
+		responses[i] = comment.ToBugCommentResponse()
+	}
+	return responses, nil
+}
+
+// UpdateBugComment replaces the body of a comment, keeping the previous body as a revision.
+// Only the author may edit a comment.
+func (s *bugServiceImpl) UpdateBugComment(ctx context.Context, bugID uint, commentID uint, req *model.UpdateBugCommentRequest) (*model.BugCommentResponse, error) {
+	comment, err := s.bugRepo.GetComment(ctx, bugID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCommentAuthor(ctx, comment); err != nil {
+		return nil, err
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, fmt.Errorf("%w: comment body cannot be empty", apputils.ErrBadRequest)
+	}
+	if body == comment.Body {
+		resp := comment.ToBugCommentResponse()
+		return &resp, nil
+	}
+	mentionIDs, err := s.resolveMentions(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &model.BugCommentRevision{Body: comment.Body, EditorID: apputils.ActorUserID(ctx)}
+	now := time.Now()
+	comment.Body = body
+	comment.EditCount++
+	comment.EditedAt = &now
+	if err := s.bugRepo.UpdateComment(ctx, comment, revision, mentionIDs); err != nil {
+		return nil, err
+	}
+	return s.getBugComment(ctx, bugID, commentID)
+}
+
+// DeleteBugComment soft-deletes a comment. Only the author may delete a comment.
+func (s *bugServiceImpl) DeleteBugComment(ctx context.Context, bugID uint, commentID uint) error {
+	comment, err := s.bugRepo.GetComment(ctx, bugID, commentID)
+	if err != nil {
+		return err
+	}
+	if err := checkCommentAuthor(ctx, comment); err != nil {
+		return err
+	}
+	return s.bugRepo.DeleteComment(ctx, commentID)
+}
+
+// ListBugCommentRevisions returns the previous bodies of a comment, oldest first.
+func (s *bugServiceImpl) ListBugCommentRevisions(ctx context.Context, bugID uint, commentID uint) ([]*model.BugCommentRevision, error) {
+	if _, err := s.bugRepo.GetComment(ctx, bugID, commentID); err != nil {
+		return nil, err
+	}
+	return s.bugRepo.ListCommentRevisions(ctx, commentID)
+}
+
+func (s *bugServiceImpl) getBugComment(ctx context.Context, bugID uint, commentID uint) (*model.BugCommentResponse, error) {
+	comment, err := s.bugRepo.GetComment(ctx, bugID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	resp := comment.ToBugCommentResponse()
+	return &resp, nil
+}
+
+// checkCommentAuthor rejects changes to a comment by anyone but its author.
+func checkCommentAuthor(ctx context.Context, comment *model.BugComment) error {
+	if comment.AuthorID == nil {
+		return nil
+	}
+	if actor := apputils.ActorUserID(ctx); actor == nil || *actor != *comment.AuthorID {
+		return fmt.Errorf("%w: only the author can change comment %d", apputils.ErrForbidden, comment.ID)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"EffiPlat/backend/internal/model"
+	apputils "EffiPlat/backend/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// bugLinks are the context links requested for a bug. A nil field leaves the link unchanged,
+// an ID of 0 (or an empty version) removes it.
+type bugLinks struct {
+	EnvironmentID  *uint
+	BusinessID     *uint
+	ServiceID      *uint
+	ServiceVersion *string
+}
+
+// applyBugLinks validates the requested links against the existing environments, businesses,
+// services and service instances, then sets them on bug together with the loaded entities.
+func (s *bugServiceImpl) applyBugLinks(ctx context.Context, bug *model.Bug, links bugLinks) error {
+	versionChanged := false
+
+	if id, changed := linkChange(bug.EnvironmentID, links.EnvironmentID); changed {
+		bug.EnvironmentID, bug.Environment, versionChanged = nil, nil, true
+		if id != 0 {
+			env, err := s.envRepo.GetByID(ctx, id)
+			if err != nil {
+				return linkLookupError("environment", id, err)
+			}
+			bug.EnvironmentID, bug.Environment = &env.ID, env
+		}
+	}
+
+	if id, changed := linkChange(bug.BusinessID, links.BusinessID); changed {
+		bug.BusinessID, bug.Business = nil, nil
+		if id != 0 {
+			business, err := s.businessRepo.GetByID(ctx, id)
+			if err != nil {
+				return linkLookupError("business", id, err)
+			}
+			bug.BusinessID, bug.Business = &business.ID, business
+		}
+	}
+
+	if id, changed := linkChange(bug.ServiceID, links.ServiceID); changed {
+		bug.ServiceID, bug.Service, versionChanged = nil, nil, true
+		if id != 0 {
+			svc, err := s.serviceRepo.GetByID(ctx, id)
+			if err != nil {
+				return linkLookupError("service", id, err)
+			}
+			bug.ServiceID, bug.Service = &svc.ID, svc
+		}
+	}
+
+	if links.ServiceVersion != nil {
+		if version := strings.TrimSpace(*links.ServiceVersion); version != bug.ServiceVersion {
+			bug.ServiceVersion, versionChanged = version, true
+		}
+	}
+
+	if bug.ServiceVersion == "" || !versionChanged {
+		return nil
+	}
+	if bug.ServiceID == nil {
+		return fmt.Errorf("%w: serviceVersion requires serviceId", apputils.ErrBadRequest)
+	}
+	// 同时指定了环境时，版本必须是该环境中实际部署过的服务实例
+	if bug.EnvironmentID != nil {
+		exists, err := s.instanceRepo.CheckExists(ctx, *bug.ServiceID, *bug.EnvironmentID, bug.ServiceVersion, 0)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: service %d has no instance of version %q in environment %d",
+				apputils.ErrBadRequest, *bug.ServiceID, bug.ServiceVersion, *bug.EnvironmentID)
+		}
+	}
+	return nil
+}
+
+// linkChange reports whether requested differs from current, returning the new ID (0 to unlink).
+func linkChange(current, requested *uint) (uint, bool) {
+	if requested == nil {
+		return 0, false
+	}
+	if current == nil {
+		return *requested, *requested != 0
+	}
+	return *requested, *requested != *current
+}
+
+func linkLookupError(kind string, id uint, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, model.ErrServiceNotFound) {
+		return fmt.Errorf("%w: %s %d does not exist", apputils.ErrBadRequest, kind, id)
+	}
+	return err
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"EffiPlat/backend/internal/model"
+	apputils "EffiPlat/backend/internal/utils"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// bugSimilarityThreshold is the score from which an existing bug counts as a likely duplicate.
+	bugSimilarityThreshold = 0.5
+	// bugSimilarityCandidates caps how many bugs sharing a title term are scored.
+	bugSimilarityCandidates = 200
+	// bugSimilarityResults caps how many likely duplicates are returned.
+	bugSimilarityResults = 5
+)
+
+// BugDuplicatesError is returned by CreateBug, when asked to check for duplicates, instead of
+// filing a bug that likely exists already.
+type BugDuplicatesError struct {
+	Candidates []model.BugSimilarityResponse
+}
+
+func (e *BugDuplicatesError) Error() string {
+	ids := make([]string, len(e.Candidates))
+	for i, candidate := range e.Candidates {
+		ids[i] = fmt.Sprintf("#%d", candidate.Bug.ID)
+	}
+	return fmt.Sprintf("%s: %s", model.ErrBugPossibleDuplicates, strings.Join(ids, ", "))
+}
+
+func (e *BugDuplicatesError) Unwrap() error {
+	return model.ErrBugPossibleDuplicates
+}
+
+// AddBugRelation relates a bug to another one.
+//
+// A bug duplicates at most one bug; marking it as a duplicate of a duplicate links it to the
+// original instead, and moves it to the workflow's DuplicateStatus with a DUPLICATE resolution
+// unless it is resolved already. BLOCKS relations that would close a cycle are rejected.
+func (s *bugServiceImpl) AddBugRelation(ctx context.Context, bugID uint, req *model.CreateBugRelationRequest) (*model.BugRelationResponse, error) {
+	bug, err := s.bugRepo.GetByID(ctx, bugID)
+	if err != nil {
+		return nil, err
+	}
+	targetID := req.TargetBugID
+	if targetID == bugID {
+		return nil, fmt.Errorf("%w: a bug cannot be related to itself", apputils.ErrBadRequest)
+	}
+	if _, err := s.bugRepo.GetByID(ctx, targetID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.bugRepo.ListRelations(ctx, bugID)
+	if err != nil {
+		return nil, err
+	}
+
+	var transition *model.BugStatusTransition
+	switch req.Type {
+	case model.BugRelationDuplicates:
+		for _, relation := range existing {
+			if relation.Type == model.BugRelationDuplicates && relation.SourceBugID == bugID {
+				return nil, fmt.Errorf("%w: bug %d is already a duplicate of bug %d", model.ErrBugRelationExists, bugID, relation.TargetBugID)
+			}
+		}
+		if targetID, err = s.originalBugID(ctx, targetID); err != nil {
+			return nil, err
+		}
+		if targetID == bugID {
+			return nil, fmt.Errorf("%w: bug %d is a duplicate of bug %d", model.ErrBugRelationCycle, req.TargetBugID, bugID)
+		}
+		transition = s.closeAsDuplicate(ctx, bug, targetID, req.Comment)
+	case model.BugRelationBlocks:
+		blocked, err := s.blocks(ctx, targetID, bugID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, fmt.Errorf("%w: bug %d already blocks bug %d", model.ErrBugRelationCycle, targetID, bugID)
+		}
+	case model.BugRelationRelatesTo:
+	default:
+		return nil, fmt.Errorf("%w: unknown relation type %q", apputils.ErrBadRequest, req.Type)
+	}
+
+	for _, relation := range existing {
+		if relation.Type != req.Type {
+			continue
+		}
+		sameDirection := relation.SourceBugID == bugID && relation.TargetBugID == targetID
+		reversed := relation.SourceBugID == targetID && relation.TargetBugID == bugID
+		if sameDirection || (reversed && req.Type == model.BugRelationRelatesTo) {
+			return nil, fmt.Errorf("%w: bug %d already %s bug %d", model.ErrBugRelationExists, relation.SourceBugID, relation.Type, relation.TargetBugID)
+		}
+	}
+
+	relation := &model.BugRelation{
+		SourceBugID: bugID,
+		TargetBugID: targetID,
+		Type:        req.Type,
+		ActorID:     apputils.ActorUserID(ctx),
+	}
+	if err := s.bugRepo.CreateRelation(ctx, relation, bug, transition); err != nil {
+		return nil, err
+	}
+	return s.getBugRelation(ctx, bugID, relation.ID)
+}
+
+// originalBugID follows DUPLICATES relations from bugID to the bug that is not a duplicate itself.
+func (s *bugServiceImpl) originalBugID(ctx context.Context, bugID uint) (uint, error) {
+	seen := map[uint]bool{bugID: true}
+	for {
+		relations, err := s.bugRepo.ListRelationsFrom(ctx, model.BugRelationDuplicates, []uint{bugID})
+		if err != nil {
+			return 0, err
+		}
+		if len(relations) == 0 || seen[relations[0].TargetBugID] {
+			return bugID, nil
+		}
+		bugID = relations[0].TargetBugID
+		seen[bugID] = true
+	}
+}
+
+// blocks reports whether bug "from" blocks bug "to", directly or through other bugs.
+func (s *bugServiceImpl) blocks(ctx context.Context, from, to uint) (bool, error) {
+	visited := map[uint]bool{from: true}
+	frontier := []uint{from}
+	for len(frontier) > 0 {
+		relations, err := s.bugRepo.ListRelationsFrom(ctx, model.BugRelationBlocks, frontier)
+		if err != nil {
+			return false, err
+		}
+		var next []uint
+		for _, relation := range relations {
+			if relation.TargetBugID == to {
+				return true, nil
+			}
+			if !visited[relation.TargetBugID] {
+				visited[relation.TargetBugID] = true
+				next = append(next, relation.TargetBugID)
+			}
+		}
+		frontier = next
+	}
+	return false, nil
+}
+
+// closeAsDuplicate moves bug to the workflow's DuplicateStatus and returns the transition to
+// record, or nil when the workflow has none or the bug is resolved already.
+func (s *bugServiceImpl) closeAsDuplicate(ctx context.Context, bug *model.Bug, originalID uint, comment string) *model.BugStatusTransition {
+	to := s.workflow.DuplicateStatus
+	if to == "" || s.workflow.IsResolved(bug.Status) {
+		return nil
+	}
+
+	note := fmt.Sprintf("Duplicate of #%d", originalID)
+	if comment = strings.TrimSpace(comment); comment != "" {
+		note += "\n\n" + comment
+	}
+	resolution := model.BugResolutionDuplicate
+	transition := &model.BugStatusTransition{
+		BugID:      bug.ID,
+		FromStatus: bug.Status,
+		ToStatus:   to,
+		Resolution: &resolution,
+		Comment:    note,
+		ActorID:    apputils.ActorUserID(ctx),
+		Actor:      apputils.ActorName(ctx),
+		CreatedAt:  time.Now(),
+	}
+	bug.Status = to
+	bug.Resolution = &resolution
+	s.updateSLAClocks(bug, transition.CreatedAt)
+	return transition
+}
+
+// ListBugRelations returns the relations of a bug in both directions, oldest first.
+func (s *bugServiceImpl) ListBugRelations(ctx context.Context, bugID uint) ([]model.BugRelationResponse, error) {
+	if _, err := s.bugRepo.GetByID(ctx, bugID); err != nil {
+		return nil, err
+	}
+	relations, err := s.bugRepo.ListRelations(ctx, bugID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]model.BugRelationResponse, len(relations))
+	for i, relation := range relations {
+		responses[i] = relation.ToBugRelationResponse(bugID)
+	}
+	return responses, nil
+}
+
+// DeleteBugRelation removes a relation of a bug. A bug closed as a duplicate stays closed.
+func (s *bugServiceImpl) DeleteBugRelation(ctx context.Context, bugID uint, relationID uint) error {
+	if _, err := s.bugRepo.GetByID(ctx, bugID); err != nil {
+		return err
+	}
+	return s.bugRepo.DeleteRelation(ctx, bugID, relationID)
+}
+
+func (s *bugServiceImpl) getBugRelation(ctx context.Context, bugID uint, relationID uint) (*model.BugRelationResponse, error) {
+	relations, err := s.bugRepo.ListRelations(ctx, bugID)
+	if err != nil {
+		return nil, err
+	}
+	for _, relation := range relations {
+		if relation.ID == relationID {
+			resp := relation.ToBugRelationResponse(bugID)
+			return &resp, nil
+		}
+	}
+	return nil, fmt.Errorf("bug relation %d not found after creation", relationID)
+}
+
+// FindSimilarBugs returns the existing bugs most likely to describe the same problem as the
+// given title and description, best match first.
+func (s *bugServiceImpl) FindSimilarBugs(ctx context.Context, req *model.FindSimilarBugsRequest) ([]model.BugSimilarityResponse, error) {
+	title := bugTokens(req.Title)
+	all := bugTokens(req.Title + " " + req.Description)
+	terms := make([]string, 0, len(title))
+	for token := range title {
+		terms = append(terms, token)
+	}
+	sort.Strings(terms)
+
+	candidates, err := s.bugRepo.FindSimilarCandidates(ctx, terms, bugSimilarityCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	similar := make([]model.BugSimilarityResponse, 0)
+	for _, candidate := range candidates {
+		score := jaccard(title, bugTokens(candidate.Title))
+		// 双方都有描述时，描述也参与评分
+		if len(all) > len(title) && strings.TrimSpace(candidate.Description) != "" {
+			score = 0.6*score + 0.4*jaccard(all, bugTokens(candidate.Title+" "+candidate.Description))
+		}
+		if score >= bugSimilarityThreshold {
+			similar = append(similar, model.BugSimilarityResponse{Bug: candidate.ToBugResponse(), Score: math.Round(score*100) / 100})
+		}
+	}
+	sort.SliceStable(similar, func(i, j int) bool { return similar[i].Score > similar[j].Score })
+	if len(similar) > bugSimilarityResults {
+		similar = similar[:bugSimilarityResults]
+	}
+	return similar, nil
+}
+
+// bugStopWords are left out of similarity scores; they say nothing about the problem.
+var bugStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true, "to": true, "in": true,
+	"on": true, "at": true, "for": true, "with": true, "is": true, "are": true, "was": true, "be": true,
+	"it": true, "this": true, "that": true, "when": true, "not": true, "no": true, "does": true, "do": true,
+}
+
+// bugTokens normalizes text into the set of words used to compare bugs: lower-cased runs of
+// letters and digits without stop words, and single Han characters, which have no spaces
+// between words to split on.
+func bugTokens(text string) map[string]bool {
+	tokens := make(map[string]bool)
+	var word strings.Builder
+	flush := func() {
+		if w := word.String(); len(w) > 1 && !bugStopWords[w] {
+			tokens[w] = true
+		}
+		word.Reset()
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			tokens[string(r)] = true
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+// jaccard returns the share of tokens two sets have in common.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for token := range a {
+		if b[token] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
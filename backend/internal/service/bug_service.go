@@ -2,7 +2,9 @@ package service
 
 import (
 	"EffiPlat/backend/internal/model"
+	apputils "EffiPlat/backend/internal/utils"
 	"context"
+	"time"
 )
 
 // BugService defines the interface for bug business logic.
@@ -12,4 +14,46 @@ type BugService interface {
 	UpdateBug(ctx context.Context, id uint, req *model.UpdateBugRequest) (*model.BugResponse, error)
 	DeleteBug(ctx context.Context, id uint) error
 	ListBugs(ctx context.Context, params *model.BugListParams) ([]*model.BugResponse, int64, error)
+	GetBugStats(ctx context.Context, params *model.BugStatsParams) (*model.BugStatsResponse, error)
+	BulkUpdateBugs(ctx context.Context, req *model.BulkBugUpdateRequest) (*model.BulkBugUpdateResponse, error) // An atomic update that fails returns a *BulkBugUpdateError
+
+	// Status workflow
+	GetWorkflow() *model.BugWorkflow
+	ListBugTransitions(ctx context.Context, id uint) ([]*model.BugStatusTransition, error)
+
+	// Environment snapshots
+	GetBugSnapshot(ctx context.Context, id uint) (*model.BugEnvironmentSnapshot, error)
+	DiffBugSnapshot(ctx context.Context, id uint) (*BugSnapshotDiffDTO, error)
+
+	// Comments and activity
+	AddBugComment(ctx context.Context, bugID uint, req *model.CreateBugCommentRequest) (*model.BugCommentResponse, error)
+	ListBugComments(ctx context.Context, bugID uint) ([]model.BugCommentResponse, error)
+	UpdateBugComment(ctx context.Context, bugID uint, commentID uint, req *model.UpdateBugCommentRequest) (*model.BugCommentResponse, error)
+	DeleteBugComment(ctx context.Context, bugID uint, commentID uint) error
+	ListBugCommentRevisions(ctx context.Context, bugID uint, commentID uint) ([]*model.BugCommentRevision, error)
+	GetBugActivity(ctx context.Context, bugID uint) ([]model.BugActivityItem, error)
+
+	// Relations and duplicate detection
+	AddBugRelation(ctx context.Context, bugID uint, req *model.CreateBugRelationRequest) (*model.BugRelationResponse, error)
+	ListBugRelations(ctx context.Context, bugID uint) ([]model.BugRelationResponse, error)
+	DeleteBugRelation(ctx context.Context, bugID uint, relationID uint) error
+	FindSimilarBugs(ctx context.Context, req *model.FindSimilarBugsRequest) ([]model.BugSimilarityResponse, error)
+}
+
+// BugSnapshotDiffDTO compares a bug's environment snapshot with the environment as it is now.
+type BugSnapshotDiffDTO struct {
+	BugID         uint                         `json:"bugId"`
+	EnvironmentID uint                         `json:"environmentId"`
+	SnapshotAt    time.Time                    `json:"snapshotAt"`
+	Added         []model.BugSnapshotInstance  `json:"added"`   // Instances deployed since the snapshot
+	Removed       []model.BugSnapshotInstance  `json:"removed"` // Instances gone since the snapshot
+	Changed       []BugSnapshotInstanceDiffDTO `json:"changed"`
+}
+
+// BugSnapshotInstanceDiffDTO lists what changed on one service instance since the snapshot.
+// Paths are instance fields (version, status, ...) or config keys under "config.".
+type BugSnapshotInstanceDiffDTO struct {
+	ServiceInstanceID uint                  `json:"serviceInstanceId"`
+	ServiceName       string                `json:"serviceName"`
+	Changes           []apputils.JSONChange `json:"changes"`
 }
@@ -3,20 +3,59 @@ package service
 import (
 	"EffiPlat/backend/internal/model"
 	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
 	// For errors.Is(err, gorm.ErrRecordNotFound)
 )
 
 // bugServiceImpl implements the BugService interface.
 type bugServiceImpl struct {
-	bugRepo repository.BugRepository
-	// userRepo repository.UserRepository // Example: if reporter/assignee validation is needed
+	bugRepo      repository.BugRepository
+	envRepo      repository.EnvironmentRepository     // For validating environment links
+	serviceRepo  repository.ServiceRepository         // For validating service links
+	instanceRepo repository.ServiceInstanceRepository // For validating service versions
+	businessRepo repository.BusinessRepository        // For validating business links
+	userRepo     repository.UserRepository            // For resolving @mentions in comments
+	workflow     *model.BugWorkflow
+	assigner     BugAssigner        // Picks an assignee for new bugs filed without one; may be nil
+	slaPolicies  BugSLAPolicySource // Sets SLA due times; may be nil
 }
 
 // NewBugService creates a new instance of bugServiceImpl.
-func NewBugService(bugRepo repository.BugRepository) BugService {
-	return &bugServiceImpl{bugRepo: bugRepo}
+// A nil workflow selects model.DefaultBugWorkflow(); a nil assigner disables automatic assignment
+// and nil slaPolicies disables SLA tracking.
+func NewBugService(
+	bugRepo repository.BugRepository,
+	envRepo repository.EnvironmentRepository,
+	serviceRepo repository.ServiceRepository,
+	instanceRepo repository.ServiceInstanceRepository,
+	businessRepo repository.BusinessRepository,
+	userRepo repository.UserRepository,
+	workflow *model.BugWorkflow,
+	assigner BugAssigner,
+	slaPolicies BugSLAPolicySource,
+) BugService {
+	if workflow == nil {
+		workflow = model.DefaultBugWorkflow()
+	}
+	return &bugServiceImpl{
+		bugRepo:      bugRepo,
+		envRepo:      envRepo,
+		serviceRepo:  serviceRepo,
+		instanceRepo: instanceRepo,
+		businessRepo: businessRepo,
+		userRepo:     userRepo,
+		workflow:     workflow,
+		assigner:     assigner,
+		slaPolicies:  slaPolicies,
+	}
 }
 
 // CreateBug creates a new bug.
@@ -24,18 +63,78 @@ func (s *bugServiceImpl) CreateBug(ctx context.Context, req *model.CreateBugRequ
 	// TODO: Add validation for ReporterID and AssigneeID if they should exist in the User table.
 	// For example, check if s.userRepo.GetByID(ctx, *req.ReporterID) returns a user.
 
+	status := req.Status
+	if status == "" {
+		status = s.workflow.InitialStatus
+	}
+	if status != s.workflow.InitialStatus {
+		return nil, fmt.Errorf("%w: new bugs must start in %s, got %s", model.ErrInvalidBugStatusTransition, s.workflow.InitialStatus, status)
+	}
+
+	// 按需先查找疑似重复的 Bug，存在时不创建
+	if req.CheckDuplicates {
+		similar, err := s.FindSimilarBugs(ctx, &model.FindSimilarBugsRequest{Title: req.Title, Description: req.Description})
+		if err != nil {
+			return nil, err
+		}
+		if len(similar) > 0 {
+			return nil, &BugDuplicatesError{Candidates: similar}
+		}
+	}
+
 	bug := &model.Bug{
 		Title:       req.Title,
 		Description: req.Description,
-		Status:      req.Status,
+		Status:      status,
 		Priority:    req.Priority,
 		ReporterID:  req.ReporterID,
 		AssigneeID:  req.AssigneeID,
+		Labels:      normalizeBugLabels(req.Labels),
 		// ProjectID:   req.ProjectID,
-		// EnvironmentID: req.EnvironmentID,
+	}
+	if err := s.applyBugLinks(ctx, bug, bugLinks{
+		EnvironmentID:  req.EnvironmentID,
+		BusinessID:     req.BusinessID,
+		ServiceID:      req.ServiceID,
+		ServiceVersion: &req.ServiceVersion,
+	}); err != nil {
+		return nil, err
+	}
+
+	// 未指定处理人时按职责规则自动分配
+	if bug.AssigneeID == nil && s.assigner != nil {
+		decision, err := s.assigner.Assign(ctx, bug)
+		if err != nil {
+			return nil, err
+		}
+		if decision != nil {
+			bug.AssigneeID = &decision.AssigneeID
+			bug.AssignmentRuleID = &decision.RuleID
+		}
 	}
 
-	if err := s.bugRepo.Create(ctx, bug); err != nil {
+	// 按优先级的 SLA 策略计算响应和解决的截止时间
+	if s.slaPolicies != nil {
+		policy, err := s.slaPolicies.PolicyFor(ctx, bug.Priority)
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil {
+			bug.CreatedAt = time.Now()
+			bug.ApplySLAPolicy(policy)
+		}
+	}
+
+	// 关联了环境的 Bug 在创建时冻结该环境中所有服务实例的状态
+	if bug.Environment != nil {
+		snapshot, err := s.captureEnvironmentSnapshot(ctx, bug.Environment)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.bugRepo.CreateWithSnapshot(ctx, bug, snapshot); err != nil {
+			return nil, err
+		}
+	} else if err := s.bugRepo.Create(ctx, bug); err != nil {
 		return nil, err
 	}
 
@@ -65,6 +164,28 @@ func (s *bugServiceImpl) UpdateBug(ctx context.Context, id uint, req *model.Upda
 		}
 		return nil, err
 	}
+	transition, changes, err := s.applyBugUpdate(ctx, bug, req)
+	if err != nil {
+		return nil, err
+	}
+	if transition != nil || len(changes) > 0 {
+		err = s.bugRepo.UpdateWithHistory(ctx, bug, transition, changes)
+	} else {
+		err = s.bugRepo.Update(ctx, bug)
+	}
+	if err != nil {
+		// Handle potential concurrency issues or other update errors
+		return nil, err
+	}
+
+	bugResponseValue := bug.ToBugResponse()
+	return &bugResponseValue, nil
+}
+
+// applyBugUpdate applies req to bug, following the workflow, and returns the status transition
+// (nil if the status is unchanged) and field changes to record with it.
+func (s *bugServiceImpl) applyBugUpdate(ctx context.Context, bug *model.Bug, req *model.UpdateBugRequest) (*model.BugStatusTransition, []*model.BugFieldChange, error) {
+	before := *bug
 
 	// Apply updates from request
 	if req.Title != nil {
@@ -73,26 +194,158 @@ func (s *bugServiceImpl) UpdateBug(ctx context.Context, id uint, req *model.Upda
 	if req.Description != nil {
 		bug.Description = *req.Description
 	}
-	if req.Status != nil {
-		bug.Status = *req.Status
-	}
 	if req.Priority != nil {
 		bug.Priority = *req.Priority
 	}
 	if req.AssigneeID != nil {
 		// TODO: Validate AssigneeID if necessary
 		bug.AssigneeID = req.AssigneeID
+		if *req.AssigneeID == 0 { // Only bulk updates unassign
+			bug.AssigneeID = nil
+		}
+	}
+	if req.Labels != nil {
+		bug.Labels = normalizeBugLabels(*req.Labels)
 	}
 	// if req.ProjectID != nil { bug.ProjectID = req.ProjectID }
-	// if req.EnvironmentID != nil { bug.EnvironmentID = req.EnvironmentID }
+	if err := s.applyBugLinks(ctx, bug, bugLinks{
+		EnvironmentID:  req.EnvironmentID,
+		BusinessID:     req.BusinessID,
+		ServiceID:      req.ServiceID,
+		ServiceVersion: req.ServiceVersion,
+	}); err != nil {
+		return nil, nil, err
+	}
 
-	if err := s.bugRepo.Update(ctx, bug); err != nil {
-		// Handle potential concurrency issues or other update errors
-		return nil, err
+	// 状态变更必须遵循工作流，并记录流转历史
+	var transition *model.BugStatusTransition
+	var err error
+	if req.Status != nil && *req.Status != bug.Status {
+		if transition, err = s.transition(ctx, bug, *req.Status, req.Resolution, req.Comment); err != nil {
+			return nil, nil, err
+		}
+	} else if req.Resolution != nil {
+		if !s.workflow.IsResolved(bug.Status) {
+			return nil, nil, fmt.Errorf("%w: a resolution can only be set on a resolved bug, current status is %s", apputils.ErrBadRequest, bug.Status)
+		}
+		bug.Resolution = req.Resolution
 	}
 
-	bugResponseValue := bug.ToBugResponse()
-	return &bugResponseValue, nil
+	// 优先级变化时按新策略重新计算 SLA 截止时间
+	if bug.Priority != before.Priority && s.slaPolicies != nil {
+		policy, err := s.slaPolicies.PolicyFor(ctx, bug.Priority)
+		if err != nil {
+			return nil, nil, err
+		}
+		bug.ApplySLAPolicy(policy)
+	}
+
+	now := time.Now()
+	if transition != nil {
+		s.updateSLAClocks(bug, now)
+	}
+
+	// 记录字段变更历史，与状态流转一起写入
+	changes := bugFieldChanges(ctx, &before, bug, transition != nil)
+	if transition != nil {
+		transition.CreatedAt = now
+	}
+	for _, change := range changes {
+		change.CreatedAt = now
+	}
+	return transition, changes, nil
+}
+
+// updateSLAClocks stops or restarts the SLA clocks of a bug that just changed status.
+func (s *bugServiceImpl) updateSLAClocks(bug *model.Bug, now time.Time) {
+	// 首次状态变更即视为首次响应
+	if bug.FirstRespondedAt == nil {
+		bug.FirstRespondedAt = &now
+	}
+	if !s.workflow.IsResolved(bug.Status) {
+		bug.ResolvedAt = nil
+	} else if bug.ResolvedAt == nil {
+		bug.ResolvedAt = &now
+	}
+}
+
+// transition moves bug to status "to" according to the workflow and returns the record to store.
+func (s *bugServiceImpl) transition(ctx context.Context, bug *model.Bug, to model.BugStatusType, resolution *model.BugResolutionType, comment string) (*model.BugStatusTransition, error) {
+	from := bug.Status
+	if !s.workflow.HasStatus(to) {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidBugStatus, to)
+	}
+	if !s.workflow.CanTransition(from, to) {
+		return nil, fmt.Errorf("%w: cannot move bug from %s to %s (allowed: %s)", model.ErrInvalidBugStatusTransition, from, to, formatBugStatuses(s.workflow.AllowedTransitions(from)))
+	}
+
+	comment = strings.TrimSpace(comment)
+	if s.workflow.RequiresComment(to) && comment == "" {
+		return nil, fmt.Errorf("%w: moving a bug to %s needs a comment", model.ErrBugCommentRequired, to)
+	}
+	if s.workflow.IsResolved(to) {
+		if resolution != nil {
+			bug.Resolution = resolution
+		}
+		if bug.Resolution == nil {
+			return nil, fmt.Errorf("%w: moving a bug to %s needs a resolution", model.ErrBugResolutionRequired, to)
+		}
+	} else {
+		if resolution != nil {
+			return nil, fmt.Errorf("%w: a resolution cannot be set when moving a bug to %s", apputils.ErrBadRequest, to)
+		}
+		bug.Resolution = nil
+	}
+
+	bug.Status = to
+	return &model.BugStatusTransition{
+		BugID:      bug.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		Resolution: bug.Resolution,
+		Comment:    comment,
+		ActorID:    apputils.ActorUserID(ctx),
+		Actor:      apputils.ActorName(ctx),
+	}, nil
+}
+
+// normalizeBugLabels trims labels and drops empty and repeated ones, returning them sorted.
+func normalizeBugLabels(labels []string) datatypes.JSONSlice[string] {
+	seen := make(map[string]bool, len(labels))
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label != "" && !seen[label] {
+			seen[label] = true
+			normalized = append(normalized, label)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func formatBugStatuses(statuses []model.BugStatusType) string {
+	if len(statuses) == 0 {
+		return "none"
+	}
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return strings.Join(names, ", ")
+}
+
+// GetWorkflow returns the status workflow bugs follow.
+func (s *bugServiceImpl) GetWorkflow() *model.BugWorkflow {
+	return s.workflow
+}
+
+// ListBugTransitions returns the status history of a bug, oldest first.
+func (s *bugServiceImpl) ListBugTransitions(ctx context.Context, id uint) ([]*model.BugStatusTransition, error) {
+	if _, err := s.bugRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.bugRepo.ListTransitions(ctx, id)
 }
 
 // DeleteBug deletes a bug by its ID.
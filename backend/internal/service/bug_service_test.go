@@ -3,6 +3,8 @@ package service
 import (
 	"EffiPlat/backend/internal/model"
 	"EffiPlat/backend/internal/repository"
+	mock_repository "EffiPlat/backend/internal/repository/mocks"
+	apputils "EffiPlat/backend/internal/utils"
 	"context"
 	"errors"
 	"testing"
@@ -10,6 +12,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 // MockBugRepository is a mock implementation of repository.BugRepository for testing
@@ -60,10 +65,165 @@ func (m *MockBugRepository) GetBugsByStatus(ctx context.Context, status model.Bu
 	return args.Get(0).([]*model.Bug), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockBugRepository) UpdateWithHistory(ctx context.Context, bug *model.Bug, transition *model.BugStatusTransition, changes []*model.BugFieldChange) error {
+	args := m.Called(ctx, bug, transition, changes)
+	return args.Error(0)
+}
+
+func (m *MockBugRepository) UpdateManyWithHistory(ctx context.Context, updates []repository.BugUpdate) error {
+	args := m.Called(ctx, updates)
+	return args.Error(0)
+}
+
+func (m *MockBugRepository) ListFieldChanges(ctx context.Context, bugID uint) ([]*model.BugFieldChange, error) {
+	args := m.Called(ctx, bugID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BugFieldChange), args.Error(1)
+}
+
+func (m *MockBugRepository) ListSLABreachCandidates(ctx context.Context, now time.Time, limit int) ([]*model.Bug, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Bug), args.Error(1)
+}
+
+func (m *MockBugRepository) MarkFirstResponse(ctx context.Context, bugID uint, at time.Time) error {
+	args := m.Called(ctx, bugID, at)
+	return args.Error(0)
+}
+
+func (m *MockBugRepository) CreateComment(ctx context.Context, comment *model.BugComment, mentionIDs []uint) error {
+	args := m.Called(ctx, comment, mentionIDs)
+	return args.Error(0)
+}
+
+func (m *MockBugRepository) GetComment(ctx context.Context, bugID uint, commentID uint) (*model.BugComment, error) {
+	args := m.Called(ctx, bugID, commentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BugComment), args.Error(1)
+}
+
+func (m *MockBugRepository) UpdateComment(ctx context.Context, comment *model.BugComment, revision *model.BugCommentRevision, mentionIDs []uint) error {
+	args := m.Called(ctx, comment, revision, mentionIDs)
+	return args.Error(0)
+}
+
+func (m *MockBugRepository) DeleteComment(ctx context.Context, commentID uint) error {
+	args := m.Called(ctx, commentID)
+	return args.Error(0)
+}
+
+func (m *MockBugRepository) ListComments(ctx context.Context, bugID uint, includeDeleted bool) ([]*model.BugComment, error) {
+	args := m.Called(ctx, bugID, includeDeleted)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BugComment), args.Error(1)
+}
+
+func (m *MockBugRepository) ListCommentRevisions(ctx context.Context, commentID uint) ([]*model.BugCommentRevision, error) {
+	args := m.Called(ctx, commentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BugCommentRevision), args.Error(1)
+}
+
+func (m *MockBugRepository) ListTransitions(ctx context.Context, bugID uint) ([]*model.BugStatusTransition, error) {
+	args := m.Called(ctx, bugID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BugStatusTransition), args.Error(1)
+}
+
+func (m *MockBugRepository) CreateWithSnapshot(ctx context.Context, bug *model.Bug, snapshot *model.BugEnvironmentSnapshot) error {
+	args := m.Called(ctx, bug, snapshot)
+	return args.Error(0)
+}
+
+func (m *MockBugRepository) GetSnapshot(ctx context.Context, bugID uint) (*model.BugEnvironmentSnapshot, error) {
+	args := m.Called(ctx, bugID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BugEnvironmentSnapshot), args.Error(1)
+}
+
+func (m *MockBugRepository) CreateRelation(ctx context.Context, relation *model.BugRelation, bug *model.Bug, transition *model.BugStatusTransition) error {
+	args := m.Called(ctx, relation, bug, transition)
+	return args.Error(0)
+}
+
+func (m *MockBugRepository) ListRelations(ctx context.Context, bugID uint) ([]*model.BugRelation, error) {
+	args := m.Called(ctx, bugID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BugRelation), args.Error(1)
+}
+
+func (m *MockBugRepository) ListRelationsFrom(ctx context.Context, relationType model.BugRelationType, sourceIDs []uint) ([]*model.BugRelation, error) {
+	args := m.Called(ctx, relationType, sourceIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BugRelation), args.Error(1)
+}
+
+func (m *MockBugRepository) DeleteRelation(ctx context.Context, bugID uint, relationID uint) error {
+	args := m.Called(ctx, bugID, relationID)
+	return args.Error(0)
+}
+
+func (m *MockBugRepository) FindSimilarCandidates(ctx context.Context, terms []string, limit int) ([]*model.Bug, error) {
+	args := m.Called(ctx, terms, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Bug), args.Error(1)
+}
+
+func (m *MockBugRepository) CountByGroup(ctx context.Context, groupBy model.BugStatsGroupBy, from, to time.Time) ([]model.BugStatsGroup, error) {
+	args := m.Called(ctx, groupBy, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BugStatsGroup), args.Error(1)
+}
+
+func (m *MockBugRepository) CountOpenByAge(ctx context.Context, resolvedStatuses []model.BugStatusType, now time.Time) ([]model.BugAgeBucket, error) {
+	args := m.Called(ctx, resolvedStatuses, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BugAgeBucket), args.Error(1)
+}
+
+func (m *MockBugRepository) MeanTimeToResolve(ctx context.Context, from, to time.Time) (*float64, int64, error) {
+	args := m.Called(ctx, from, to)
+	hours, _ := args.Get(0).(*float64)
+	return hours, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockBugRepository) CountTrend(ctx context.Context, interval model.BugStatsInterval, resolvedStatuses []model.BugStatusType, from, to time.Time) ([]model.BugTrendPoint, error) {
+	args := m.Called(ctx, interval, resolvedStatuses, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BugTrendPoint), args.Error(1)
+}
+
 // Helper function to setup the service and mock repository
 func setupBugServiceTest(t *testing.T) (BugService, *MockBugRepository) {
 	mockRepo := new(MockBugRepository)
-	service := NewBugService(mockRepo)
+	service := NewBugService(mockRepo, nil, nil, nil, nil, nil, nil, nil, nil)
 	return service, mockRepo
 }
 
@@ -110,7 +270,7 @@ func TestBugService_CreateBug(t *testing.T) {
 
 		// Need to reset the mock between test cases
 		mockRepo := new(MockBugRepository)
-		service := NewBugService(mockRepo)
+		service := NewBugService(mockRepo, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Bug")).Return(errors.New("database error")).Once()
 
@@ -186,7 +346,7 @@ func TestBugService_UpdateBug(t *testing.T) {
 		}
 
 		mockRepo.On("GetByID", ctx, bugID).Return(existingBug, nil).Once()
-		mockRepo.On("Update", ctx, mock.AnythingOfType("*model.Bug")).Return(nil).Once()
+		mockRepo.On("UpdateWithHistory", ctx, mock.AnythingOfType("*model.Bug"), mock.AnythingOfType("*model.BugStatusTransition"), mock.Anything).Return(nil).Once()
 
 		resp, err := service.UpdateBug(ctx, bugID, updateReq)
 
@@ -231,7 +391,7 @@ func TestBugService_UpdateBug(t *testing.T) {
 		}
 
 		mockRepo.On("GetByID", ctx, bugID).Return(existingBug, nil).Once()
-		mockRepo.On("Update", ctx, mock.AnythingOfType("*model.Bug")).Return(errors.New("update error")).Once()
+		mockRepo.On("UpdateWithHistory", ctx, mock.AnythingOfType("*model.Bug"), (*model.BugStatusTransition)(nil), mock.Anything).Return(errors.New("update error")).Once()
 
 		resp, err := service.UpdateBug(ctx, bugID, updateReq)
 
@@ -242,6 +402,280 @@ func TestBugService_UpdateBug(t *testing.T) {
 	})
 }
 
+// TestBugService_StatusWorkflow tests that status changes follow the bug workflow
+func TestBugService_StatusWorkflow(t *testing.T) {
+	actorCtx := apputils.WithActor(context.Background(), apputils.Actor{UserID: 7, Username: "alice"})
+	fixed := model.BugResolutionFixed
+
+	newBug := func(status model.BugStatusType, resolution *model.BugResolutionType) *model.Bug {
+		return &model.Bug{ID: 1, Title: "Workflow Bug", Status: status, Priority: model.BugPriorityMedium, Resolution: resolution}
+	}
+
+	t.Run("Create must start in the initial status", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		resp, err := service.CreateBug(actorCtx, &model.CreateBugRequest{Title: "Closed on arrival", Status: model.BugStatusClosed, Priority: model.BugPriorityLow})
+		assert.ErrorIs(t, err, model.ErrInvalidBugStatusTransition)
+		assert.Nil(t, resp)
+
+		mockRepo.On("Create", actorCtx, mock.AnythingOfType("*model.Bug")).Return(nil).Once()
+		resp, err = service.CreateBug(actorCtx, &model.CreateBugRequest{Title: "No status given", Priority: model.BugPriorityLow})
+		assert.NoError(t, err)
+		assert.Equal(t, model.BugStatusOpen, resp.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Illegal transitions are rejected", func(t *testing.T) {
+		cases := []struct{ from, to model.BugStatusType }{
+			{model.BugStatusOpen, model.BugStatusClosed},
+			{model.BugStatusClosed, model.BugStatusInProgress},
+			{model.BugStatusResolved, model.BugStatusClosed},
+		}
+		for _, tc := range cases {
+			service, mockRepo := setupBugServiceTest(t)
+			mockRepo.On("GetByID", actorCtx, uint(1)).Return(newBug(tc.from, &fixed), nil).Once()
+
+			resp, err := service.UpdateBug(actorCtx, 1, &model.UpdateBugRequest{Status: statusPtr(tc.to), Resolution: &fixed, Comment: "done"})
+
+			assert.ErrorIs(t, err, model.ErrInvalidBugStatusTransition, "%s -> %s", tc.from, tc.to)
+			assert.Nil(t, resp)
+			mockRepo.AssertNotCalled(t, "UpdateWithHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("Resolving needs a resolution and a comment", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		mockRepo.On("GetByID", actorCtx, uint(1)).Return(newBug(model.BugStatusInProgress, nil), nil).Twice()
+
+		_, err := service.UpdateBug(actorCtx, 1, &model.UpdateBugRequest{Status: statusPtr(model.BugStatusResolved), Comment: "fixed in 1.2"})
+		assert.ErrorIs(t, err, model.ErrBugResolutionRequired)
+
+		_, err = service.UpdateBug(actorCtx, 1, &model.UpdateBugRequest{Status: statusPtr(model.BugStatusResolved), Resolution: &fixed, Comment: "  "})
+		assert.ErrorIs(t, err, model.ErrBugCommentRequired)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Legal transition is recorded with the actor", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		mockRepo.On("GetByID", actorCtx, uint(1)).Return(newBug(model.BugStatusInProgress, nil), nil).Once()
+		var recorded *model.BugStatusTransition
+		mockRepo.On("UpdateWithHistory", actorCtx, mock.AnythingOfType("*model.Bug"), mock.AnythingOfType("*model.BugStatusTransition"), mock.Anything).
+			Run(func(args mock.Arguments) { recorded = args.Get(2).(*model.BugStatusTransition) }).Return(nil).Once()
+
+		resp, err := service.UpdateBug(actorCtx, 1, &model.UpdateBugRequest{Status: statusPtr(model.BugStatusResolved), Resolution: &fixed, Comment: "fixed in 1.2"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.BugStatusResolved, resp.Status)
+		assert.Equal(t, &fixed, resp.Resolution)
+		if assert.NotNil(t, recorded) {
+			assert.Equal(t, model.BugStatusInProgress, recorded.FromStatus)
+			assert.Equal(t, model.BugStatusResolved, recorded.ToStatus)
+			assert.Equal(t, "fixed in 1.2", recorded.Comment)
+			assert.Equal(t, uint(7), *recorded.ActorID)
+			assert.Equal(t, "alice", recorded.Actor)
+		}
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Closing keeps the resolution and reopening clears it", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		mockRepo.On("GetByID", actorCtx, uint(1)).Return(newBug(model.BugStatusVerified, &fixed), nil).Once()
+		mockRepo.On("UpdateWithHistory", actorCtx, mock.AnythingOfType("*model.Bug"), mock.AnythingOfType("*model.BugStatusTransition"), mock.Anything).Return(nil).Twice()
+
+		resp, err := service.UpdateBug(actorCtx, 1, &model.UpdateBugRequest{Status: statusPtr(model.BugStatusClosed), Comment: "verified in prod"})
+		assert.NoError(t, err)
+		assert.Equal(t, &fixed, resp.Resolution)
+
+		mockRepo.On("GetByID", actorCtx, uint(1)).Return(newBug(model.BugStatusClosed, &fixed), nil).Once()
+		resp, err = service.UpdateBug(actorCtx, 1, &model.UpdateBugRequest{Status: statusPtr(model.BugStatusReopened)})
+		assert.NoError(t, err)
+		assert.Equal(t, model.BugStatusReopened, resp.Status)
+		assert.Nil(t, resp.Resolution)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Custom workflow", func(t *testing.T) {
+		mockRepo := new(MockBugRepository)
+		workflow := model.DefaultBugWorkflow()
+		workflow.Transitions[model.BugStatusOpen] = append(workflow.Transitions[model.BugStatusOpen], model.BugStatusClosed)
+		service := NewBugService(mockRepo, nil, nil, nil, nil, nil, workflow, nil, nil)
+		mockRepo.On("GetByID", actorCtx, uint(1)).Return(newBug(model.BugStatusOpen, nil), nil).Once()
+		mockRepo.On("UpdateWithHistory", actorCtx, mock.AnythingOfType("*model.Bug"), mock.AnythingOfType("*model.BugStatusTransition"), mock.Anything).Return(nil).Once()
+
+		resp, err := service.UpdateBug(actorCtx, 1, &model.UpdateBugRequest{Status: statusPtr(model.BugStatusClosed), Resolution: &fixed, Comment: "not worth it"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.BugStatusClosed, resp.Status)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestBugService_ContextLinks tests linking bugs to environments, businesses and service versions
+func TestBugService_ContextLinks(t *testing.T) {
+	ctx := context.Background()
+	env := &model.Environment{ID: 3, Name: "Production", Slug: "prod"}
+	business := &model.Business{ID: 4, Name: "Payments", Status: model.BusinessStatusActive}
+	svc := &model.Service{ID: 5, Name: "billing-api"}
+
+	setup := func(t *testing.T) (BugService, *MockBugRepository, *mock_repository.MockEnvironmentRepository, *mock_repository.MockServiceRepository, *mock_repository.MockServiceInstanceRepository, *mock_repository.MockBusinessRepository) {
+		ctrl := gomock.NewController(t)
+		bugRepo := new(MockBugRepository)
+		envRepo := mock_repository.NewMockEnvironmentRepository(ctrl)
+		serviceRepo := mock_repository.NewMockServiceRepository(ctrl)
+		instanceRepo := mock_repository.NewMockServiceInstanceRepository(ctrl)
+		businessRepo := mock_repository.NewMockBusinessRepository(ctrl)
+		return NewBugService(bugRepo, envRepo, serviceRepo, instanceRepo, businessRepo, nil, nil, nil, nil), bugRepo, envRepo, serviceRepo, instanceRepo, businessRepo
+	}
+
+	t.Run("Create embeds the linked entities", func(t *testing.T) {
+		service, bugRepo, envRepo, serviceRepo, instanceRepo, businessRepo := setup(t)
+		envRepo.EXPECT().GetByID(ctx, env.ID).Return(env, nil)
+		businessRepo.EXPECT().GetByID(ctx, business.ID).Return(business, nil)
+		serviceRepo.EXPECT().GetByID(ctx, svc.ID).Return(svc, nil)
+		instanceRepo.EXPECT().CheckExists(ctx, svc.ID, env.ID, "2.3.1", uint(0)).Return(true, nil)
+		instanceRepo.EXPECT().ListByEnvironmentID(ctx, env.ID).Return([]*model.ServiceInstance{}, nil)
+		bugRepo.On("CreateWithSnapshot", ctx, mock.AnythingOfType("*model.Bug"), mock.AnythingOfType("*model.BugEnvironmentSnapshot")).Return(nil).Once()
+
+		resp, err := service.CreateBug(ctx, &model.CreateBugRequest{
+			Title: "Refund fails", Priority: model.BugPriorityHigh,
+			EnvironmentID: &env.ID, BusinessID: &business.ID, ServiceID: &svc.ID, ServiceVersion: "2.3.1",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.BugEnvironmentSummary{ID: 3, Name: "Production", Slug: "prod"}, resp.Environment)
+		assert.Equal(t, &model.BugBusinessSummary{ID: 4, Name: "Payments", Status: model.BusinessStatusActive}, resp.Business)
+		assert.Equal(t, &model.BugServiceSummary{ID: 5, Name: "billing-api", Version: "2.3.1"}, resp.Service)
+		bugRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown entities are rejected", func(t *testing.T) {
+		service, bugRepo, envRepo, serviceRepo, _, _ := setup(t)
+		envRepo.EXPECT().GetByID(ctx, uint(99)).Return(nil, gorm.ErrRecordNotFound)
+		missing := uint(99)
+
+		_, err := service.CreateBug(ctx, &model.CreateBugRequest{Title: "Refund fails", Priority: model.BugPriorityHigh, EnvironmentID: &missing})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+		assert.Contains(t, err.Error(), "environment 99 does not exist")
+
+		serviceRepo.EXPECT().GetByID(ctx, uint(99)).Return(nil, model.ErrServiceNotFound)
+		_, err = service.CreateBug(ctx, &model.CreateBugRequest{Title: "Refund fails", Priority: model.BugPriorityHigh, ServiceID: &missing})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+		bugRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Version must be deployed in the environment", func(t *testing.T) {
+		service, bugRepo, envRepo, serviceRepo, instanceRepo, _ := setup(t)
+		envRepo.EXPECT().GetByID(ctx, env.ID).Return(env, nil)
+		serviceRepo.EXPECT().GetByID(ctx, svc.ID).Return(svc, nil)
+		instanceRepo.EXPECT().CheckExists(ctx, svc.ID, env.ID, "9.9.9", uint(0)).Return(false, nil)
+
+		_, err := service.CreateBug(ctx, &model.CreateBugRequest{
+			Title: "Refund fails", Priority: model.BugPriorityHigh,
+			EnvironmentID: &env.ID, ServiceID: &svc.ID, ServiceVersion: "9.9.9",
+		})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+		bugRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Version needs a service", func(t *testing.T) {
+		service, _, _, _, _, _ := setup(t)
+		_, err := service.CreateBug(ctx, &model.CreateBugRequest{Title: "Refund fails", Priority: model.BugPriorityHigh, ServiceVersion: "1.0.0"})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+	})
+
+	t.Run("Update unlinks with zero and leaves other links alone", func(t *testing.T) {
+		service, bugRepo, _, _, _, _ := setup(t)
+		existing := &model.Bug{ID: 1, Title: "Refund fails", Status: model.BugStatusOpen,
+			EnvironmentID: &env.ID, Environment: env, BusinessID: &business.ID, Business: business}
+		bugRepo.On("GetByID", ctx, uint(1)).Return(existing, nil).Once()
+		bugRepo.On("UpdateWithHistory", ctx, mock.AnythingOfType("*model.Bug"), (*model.BugStatusTransition)(nil),
+			mock.MatchedBy(func(changes []*model.BugFieldChange) bool {
+				return len(changes) == 1 && changes[0].Field == "environmentId" && changes[0].NewValue == ""
+			})).Return(nil).Once()
+		zero := uint(0)
+
+		resp, err := service.UpdateBug(ctx, 1, &model.UpdateBugRequest{EnvironmentID: &zero, BusinessID: &business.ID})
+
+		assert.NoError(t, err)
+		assert.Nil(t, resp.EnvironmentID)
+		assert.Nil(t, resp.Environment)
+		assert.Equal(t, "Payments", resp.Business.Name)
+		bugRepo.AssertExpectations(t)
+	})
+}
+
+// TestBugService_EnvironmentSnapshot tests the environment snapshot taken when a bug is filed
+func TestBugService_EnvironmentSnapshot(t *testing.T) {
+	ctx := context.Background()
+	env := &model.Environment{ID: 3, Name: "Production", Slug: "prod", Config: map[string]interface{}{"logLevel": "warn"}}
+	svc := &model.Service{ID: 5, Name: "billing-api", DefaultConfig: map[string]interface{}{"replicas": 1}}
+	instances := []*model.ServiceInstance{
+		{ID: 10, ServiceID: svc.ID, EnvironmentID: env.ID, Version: "2.3.1", Status: model.ServiceInstanceStatusRunning,
+			Config: map[string]interface{}{"replicas": 3, "dbPassword": "enc:v1:c2VjcmV0"}},
+		{ID: 11, ServiceID: svc.ID, EnvironmentID: env.ID, Version: "2.3.1", Status: model.ServiceInstanceStatusRunning},
+	}
+
+	ctrl := gomock.NewController(t)
+	bugRepo := new(MockBugRepository)
+	envRepo := mock_repository.NewMockEnvironmentRepository(ctrl)
+	serviceRepo := mock_repository.NewMockServiceRepository(ctrl)
+	instanceRepo := mock_repository.NewMockServiceInstanceRepository(ctrl)
+	service := NewBugService(bugRepo, envRepo, serviceRepo, instanceRepo, nil, nil, nil, nil, nil)
+
+	var snapshot *model.BugEnvironmentSnapshot
+	t.Run("Create captures the environment", func(t *testing.T) {
+		envRepo.EXPECT().GetByID(ctx, env.ID).Return(env, nil)
+		instanceRepo.EXPECT().ListByEnvironmentID(ctx, env.ID).Return(instances, nil)
+		serviceRepo.EXPECT().GetByID(ctx, svc.ID).Return(svc, nil).Times(1) // Cached per service
+		bugRepo.On("CreateWithSnapshot", ctx, mock.AnythingOfType("*model.Bug"), mock.AnythingOfType("*model.BugEnvironmentSnapshot")).
+			Run(func(args mock.Arguments) { snapshot = args.Get(2).(*model.BugEnvironmentSnapshot) }).Return(nil).Once()
+
+		_, err := service.CreateBug(ctx, &model.CreateBugRequest{Title: "Invoices are empty", Priority: model.BugPriorityHigh, EnvironmentID: &env.ID})
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, snapshot) && assert.Len(t, snapshot.Instances, 2) {
+			first := snapshot.Instances[0]
+			assert.Equal(t, "billing-api", first.ServiceName)
+			assert.Equal(t, "2.3.1", first.Version)
+			assert.Equal(t, apputils.SecretMask, first.Config["dbPassword"])
+			assert.Equal(t, "warn", first.Config["logLevel"], "snapshot holds the effective config")
+		}
+	})
+
+	t.Run("Diff against the current environment", func(t *testing.T) {
+		snapshot.BugID = 1
+		bugRepo.On("GetByID", ctx, uint(1)).Return(&model.Bug{ID: 1}, nil).Once()
+		bugRepo.On("GetSnapshot", ctx, uint(1)).Return(snapshot, nil).Once()
+		envRepo.EXPECT().GetByID(ctx, env.ID).Return(env, nil)
+		upgraded := *instances[0]
+		upgraded.Version = "2.4.0"
+		instanceRepo.EXPECT().ListByEnvironmentID(ctx, env.ID).Return([]*model.ServiceInstance{
+			&upgraded,
+			{ID: 12, ServiceID: svc.ID, EnvironmentID: env.ID, Version: "2.4.0", Status: model.ServiceInstanceStatusDeploying},
+		}, nil)
+		serviceRepo.EXPECT().GetByID(ctx, svc.ID).Return(svc, nil)
+
+		diff, err := service.DiffBugSnapshot(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, diff.Added, 1)
+		assert.Equal(t, uint(12), diff.Added[0].ServiceInstanceID)
+		assert.Len(t, diff.Removed, 1)
+		assert.Equal(t, uint(11), diff.Removed[0].ServiceInstanceID)
+		if assert.Len(t, diff.Changed, 1) {
+			assert.Equal(t, []apputils.JSONChange{{Path: "version", Op: apputils.JSONChangeChanged, OldValue: "2.3.1", NewValue: "2.4.0"}}, diff.Changed[0].Changes)
+		}
+		bugRepo.AssertExpectations(t)
+	})
+
+	t.Run("Bug without snapshot", func(t *testing.T) {
+		bugRepo.On("GetByID", ctx, uint(2)).Return(&model.Bug{ID: 2}, nil).Once()
+		bugRepo.On("GetSnapshot", ctx, uint(2)).Return(nil, repository.ErrBugSnapshotNotFound).Once()
+
+		_, err := service.GetBugSnapshot(ctx, 2)
+		assert.ErrorIs(t, err, repository.ErrBugSnapshotNotFound)
+	})
+}
+
 // TestBugService_DeleteBug tests the DeleteBug method
 func TestBugService_DeleteBug(t *testing.T) {
 	service, mockRepo := setupBugServiceTest(t)
@@ -333,3 +767,621 @@ func statusPtr(s model.BugStatusType) *model.BugStatusType {
 func priorityPtr(p model.BugPriorityType) *model.BugPriorityType {
 	return &p
 }
+
+// MockBugAssigner is a mock implementation of BugAssigner for testing
+type MockBugAssigner struct {
+	mock.Mock
+}
+
+func (m *MockBugAssigner) Assign(ctx context.Context, bug *model.Bug) (*BugAssignmentDecision, error) {
+	args := m.Called(ctx, bug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*BugAssignmentDecision), args.Error(1)
+}
+
+// TestBugService_AutoAssignment tests that CreateBug applies assignment rules
+func TestBugService_AutoAssignment(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockBugRepository)
+	assigner := new(MockBugAssigner)
+	service := NewBugService(mockRepo, nil, nil, nil, nil, nil, nil, assigner, nil)
+
+	t.Run("Unassigned bug gets the rule's pick", func(t *testing.T) {
+		assigner.On("Assign", ctx, mock.AnythingOfType("*model.Bug")).
+			Return(&BugAssignmentDecision{RuleID: 3, AssigneeID: 42}, nil).Once()
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Bug")).Return(nil).Once()
+
+		resp, err := service.CreateBug(ctx, &model.CreateBugRequest{Title: "Login fails", Priority: model.BugPriorityHigh})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(42), *resp.AssigneeID)
+		assert.Equal(t, uint(3), *resp.AssignmentRuleID)
+	})
+
+	t.Run("No rule fired leaves the bug unassigned", func(t *testing.T) {
+		assigner.On("Assign", ctx, mock.AnythingOfType("*model.Bug")).Return(nil, nil).Once()
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Bug")).Return(nil).Once()
+
+		resp, err := service.CreateBug(ctx, &model.CreateBugRequest{Title: "Login fails", Priority: model.BugPriorityHigh})
+		assert.NoError(t, err)
+		assert.Nil(t, resp.AssigneeID)
+		assert.Nil(t, resp.AssignmentRuleID)
+	})
+
+	t.Run("Explicit assignee skips the rules", func(t *testing.T) {
+		assignee := uint(7)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Bug")).Return(nil).Once()
+
+		resp, err := service.CreateBug(ctx, &model.CreateBugRequest{Title: "Login fails", Priority: model.BugPriorityHigh, AssigneeID: &assignee})
+		assert.NoError(t, err)
+		assert.Equal(t, assignee, *resp.AssigneeID)
+		assert.Nil(t, resp.AssignmentRuleID)
+	})
+
+	assigner.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+// staticSLAPolicies is a BugSLAPolicySource backed by a map
+type staticSLAPolicies map[model.BugPriorityType]*model.BugSLAPolicy
+
+func (p staticSLAPolicies) PolicyFor(ctx context.Context, priority model.BugPriorityType) (*model.BugSLAPolicy, error) {
+	return p[priority], nil
+}
+
+// TestBugService_SLA tests that bugs get SLA due times and that their clocks stop
+func TestBugService_SLA(t *testing.T) {
+	ctx := context.Background()
+	policies := staticSLAPolicies{
+		model.BugPriorityUrgent: {Priority: model.BugPriorityUrgent, FirstResponseMinutes: 30, ResolutionMinutes: 240, Enabled: true},
+		model.BugPriorityLow:    {Priority: model.BugPriorityLow, ResolutionMinutes: 7 * 24 * 60, Enabled: true},
+	}
+	priorityPtr := func(p model.BugPriorityType) *model.BugPriorityType { return &p }
+	fixed := model.BugResolutionFixed
+
+	t.Run("New bugs get due times from their priority", func(t *testing.T) {
+		mockRepo := new(MockBugRepository)
+		service := NewBugService(mockRepo, nil, nil, nil, nil, nil, nil, nil, policies)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Bug")).Return(nil).Once()
+
+		resp, err := service.CreateBug(ctx, &model.CreateBugRequest{Title: "Checkout down", Priority: model.BugPriorityUrgent})
+		assert.NoError(t, err)
+		require.NotNil(t, resp.SLA)
+		assert.Equal(t, resp.CreatedAt.Add(30*time.Minute), *resp.SLA.ResponseDueAt)
+		assert.Equal(t, resp.CreatedAt.Add(4*time.Hour), *resp.SLA.ResolveDueAt)
+		assert.False(t, resp.SLA.Breached)
+
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Bug")).Return(nil).Once()
+		resp, err = service.CreateBug(ctx, &model.CreateBugRequest{Title: "Typo in footer", Priority: model.BugPriorityMedium})
+		assert.NoError(t, err)
+		assert.Nil(t, resp.SLA, "no policy for MEDIUM")
+	})
+
+	t.Run("Re-prioritising recomputes the due times", func(t *testing.T) {
+		mockRepo := new(MockBugRepository)
+		service := NewBugService(mockRepo, nil, nil, nil, nil, nil, nil, nil, policies)
+		filed := time.Now().Add(-time.Hour)
+		mockRepo.On("GetByID", ctx, uint(1)).Return(&model.Bug{ID: 1, Title: "Slow search", Status: model.BugStatusOpen, Priority: model.BugPriorityLow, CreatedAt: filed}, nil).Once()
+		mockRepo.On("UpdateWithHistory", ctx, mock.AnythingOfType("*model.Bug"), (*model.BugStatusTransition)(nil), mock.Anything).Return(nil).Once()
+
+		resp, err := service.UpdateBug(ctx, 1, &model.UpdateBugRequest{Priority: priorityPtr(model.BugPriorityUrgent)})
+		assert.NoError(t, err)
+		require.NotNil(t, resp.SLA)
+		assert.Equal(t, filed.Add(30*time.Minute), *resp.SLA.ResponseDueAt)
+		assert.True(t, resp.SLA.ResponseBreached, "the new response target has already passed")
+	})
+
+	t.Run("Status changes stop the clocks", func(t *testing.T) {
+		mockRepo := new(MockBugRepository)
+		service := NewBugService(mockRepo, nil, nil, nil, nil, nil, nil, nil, policies)
+		bug := &model.Bug{ID: 1, Title: "Checkout down", Status: model.BugStatusOpen, Priority: model.BugPriorityUrgent}
+		mockRepo.On("GetByID", ctx, uint(1)).Return(bug, nil).Once()
+		mockRepo.On("UpdateWithHistory", ctx, bug, mock.AnythingOfType("*model.BugStatusTransition"), mock.Anything).Return(nil).Times(3)
+
+		_, err := service.UpdateBug(ctx, 1, &model.UpdateBugRequest{Status: statusPtr(model.BugStatusInProgress)})
+		assert.NoError(t, err)
+		require.NotNil(t, bug.FirstRespondedAt)
+		assert.Nil(t, bug.ResolvedAt)
+		responded := *bug.FirstRespondedAt
+
+		mockRepo.On("GetByID", ctx, uint(1)).Return(bug, nil).Once()
+		_, err = service.UpdateBug(ctx, 1, &model.UpdateBugRequest{Status: statusPtr(model.BugStatusResolved), Resolution: &fixed, Comment: "fixed"})
+		assert.NoError(t, err)
+		require.NotNil(t, bug.ResolvedAt)
+		assert.Equal(t, responded, *bug.FirstRespondedAt)
+
+		mockRepo.On("GetByID", ctx, uint(1)).Return(bug, nil).Once()
+		_, err = service.UpdateBug(ctx, 1, &model.UpdateBugRequest{Status: statusPtr(model.BugStatusReopened)})
+		assert.NoError(t, err)
+		assert.Nil(t, bug.ResolvedAt, "reopening restarts the resolution clock")
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestParseMentionHandles(t *testing.T) {
+	body := "Ping @Alice and @bob.smith@example.com, cc @alice.\n" +
+		"Mail me at carol@example.com.\n" +
+		"Not in code: `@dave` or\n```\n@erin\n```\n"
+	assert.Equal(t, []string{"alice", "bob.smith@example.com"}, parseMentionHandles(body))
+	assert.Empty(t, parseMentionHandles("no mentions here"))
+}
+
+// TestBugService_Comments tests comment creation, edits with revisions and the author check
+func TestBugService_Comments(t *testing.T) {
+	authorCtx := apputils.WithActor(context.Background(), apputils.Actor{UserID: 7, Username: "alice"})
+	otherCtx := apputils.WithActor(context.Background(), apputils.Actor{UserID: 8, Username: "bob"})
+	authorID := uint(7)
+
+	setup := func(t *testing.T) (BugService, *MockBugRepository, *mock_repository.MockUserRepository) {
+		ctrl := gomock.NewController(t)
+		bugRepo := new(MockBugRepository)
+		userRepo := mock_repository.NewMockUserRepository(ctrl)
+		return NewBugService(bugRepo, nil, nil, nil, nil, userRepo, nil, nil, nil), bugRepo, userRepo
+	}
+
+	t.Run("Add resolves unambiguous mentions", func(t *testing.T) {
+		service, bugRepo, userRepo := setup(t)
+		bugRepo.On("GetByID", authorCtx, uint(1)).Return(&model.Bug{ID: 1}, nil).Once()
+		userRepo.EXPECT().FindByMentionHandles(authorCtx, []string{"bob", "sam", "ghost"}).Return([]model.User{
+			{ID: 8, Email: "bob@example.com"},
+			{ID: 9, Email: "sam@one.example.com"},
+			{ID: 10, Email: "sam@two.example.com"},
+		}, nil)
+		bugRepo.On("CreateComment", authorCtx, mock.AnythingOfType("*model.BugComment"), []uint{8}).
+			Run(func(args mock.Arguments) {
+				comment := args.Get(1).(*model.BugComment)
+				assert.Equal(t, authorID, *comment.AuthorID)
+				assert.Equal(t, "@bob @sam @ghost please look", comment.Body)
+				comment.ID = 5
+			}).Return(nil).Once()
+		bugRepo.On("MarkFirstResponse", authorCtx, uint(1), mock.AnythingOfType("time.Time")).Return(nil).Once()
+		bugRepo.On("GetComment", authorCtx, uint(1), uint(5)).
+			Return(&model.BugComment{ID: 5, BugID: 1, AuthorID: &authorID, Body: "@bob @sam @ghost please look"}, nil).Once()
+
+		resp, err := service.AddBugComment(authorCtx, 1, &model.CreateBugCommentRequest{Body: "  @bob @sam @ghost please look\n"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(5), resp.ID)
+		bugRepo.AssertExpectations(t)
+	})
+
+	t.Run("Reporter comment is not a first response", func(t *testing.T) {
+		service, bugRepo, _ := setup(t)
+		bugRepo.On("GetByID", authorCtx, uint(1)).Return(&model.Bug{ID: 1, ReporterID: &authorID}, nil).Once()
+		bugRepo.On("CreateComment", authorCtx, mock.AnythingOfType("*model.BugComment"), []uint(nil)).
+			Run(func(args mock.Arguments) { args.Get(1).(*model.BugComment).ID = 6 }).Return(nil).Once()
+		bugRepo.On("GetComment", authorCtx, uint(1), uint(6)).
+			Return(&model.BugComment{ID: 6, BugID: 1, AuthorID: &authorID, Body: "any news?"}, nil).Once()
+
+		_, err := service.AddBugComment(authorCtx, 1, &model.CreateBugCommentRequest{Body: "any news?"})
+		assert.NoError(t, err)
+		bugRepo.AssertNotCalled(t, "MarkFirstResponse", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Add to a missing bug", func(t *testing.T) {
+		service, bugRepo, _ := setup(t)
+		bugRepo.On("GetByID", authorCtx, uint(99)).Return(nil, repository.ErrBugNotFound).Once()
+
+		_, err := service.AddBugComment(authorCtx, 99, &model.CreateBugCommentRequest{Body: "hello"})
+		assert.ErrorIs(t, err, repository.ErrBugNotFound)
+	})
+
+	t.Run("Edit keeps the previous body as a revision", func(t *testing.T) {
+		service, bugRepo, _ := setup(t)
+		bugRepo.On("GetComment", authorCtx, uint(1), uint(5)).
+			Return(&model.BugComment{ID: 5, BugID: 1, AuthorID: &authorID, Body: "first take"}, nil).Once()
+		bugRepo.On("UpdateComment", authorCtx, mock.AnythingOfType("*model.BugComment"), mock.AnythingOfType("*model.BugCommentRevision"), []uint(nil)).
+			Run(func(args mock.Arguments) {
+				comment := args.Get(1).(*model.BugComment)
+				revision := args.Get(2).(*model.BugCommentRevision)
+				assert.Equal(t, "second take", comment.Body)
+				assert.Equal(t, 1, comment.EditCount)
+				assert.NotNil(t, comment.EditedAt)
+				assert.Equal(t, "first take", revision.Body)
+				assert.Equal(t, authorID, *revision.EditorID)
+			}).Return(nil).Once()
+		bugRepo.On("GetComment", authorCtx, uint(1), uint(5)).
+			Return(&model.BugComment{ID: 5, BugID: 1, AuthorID: &authorID, Body: "second take", EditCount: 1}, nil).Once()
+
+		resp, err := service.UpdateBugComment(authorCtx, 1, 5, &model.UpdateBugCommentRequest{Body: "second take"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resp.EditCount)
+		bugRepo.AssertExpectations(t)
+	})
+
+	t.Run("Only the author may edit or delete", func(t *testing.T) {
+		service, bugRepo, _ := setup(t)
+		bugRepo.On("GetComment", otherCtx, uint(1), uint(5)).
+			Return(&model.BugComment{ID: 5, BugID: 1, AuthorID: &authorID, Body: "mine"}, nil).Twice()
+
+		_, err := service.UpdateBugComment(otherCtx, 1, 5, &model.UpdateBugCommentRequest{Body: "yours now"})
+		assert.ErrorIs(t, err, apputils.ErrForbidden)
+		err = service.DeleteBugComment(otherCtx, 1, 5)
+		assert.ErrorIs(t, err, apputils.ErrForbidden)
+		bugRepo.AssertNotCalled(t, "UpdateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		bugRepo.AssertNotCalled(t, "DeleteComment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Deleted comments hide their body", func(t *testing.T) {
+		comment := &model.BugComment{ID: 5, BugID: 1, AuthorID: &authorID, Body: "secret",
+			Mentions:  []model.BugCommentMention{{CommentID: 5, UserID: 8, User: &model.User{ID: 8, Name: "bob"}}},
+			DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+		resp := comment.ToBugCommentResponse()
+		assert.True(t, resp.Deleted)
+		assert.Empty(t, resp.Body)
+		assert.Empty(t, resp.Mentions)
+	})
+}
+
+// TestBugService_Activity tests that the feed interleaves comments, field changes and transitions
+func TestBugService_Activity(t *testing.T) {
+	ctx := context.Background()
+	service, mockRepo := setupBugServiceTest(t)
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetByID", ctx, uint(1)).Return(&model.Bug{ID: 1}, nil).Once()
+	mockRepo.On("ListComments", ctx, uint(1), true).Return([]*model.BugComment{
+		{ID: 1, BugID: 1, Body: "first", CreatedAt: t0},
+		{ID: 2, BugID: 1, Body: "at the same time as the update", CreatedAt: t0.Add(time.Hour)},
+	}, nil).Once()
+	mockRepo.On("ListFieldChanges", ctx, uint(1)).Return([]*model.BugFieldChange{
+		{ID: 1, BugID: 1, Field: "priority", OldValue: "LOW", NewValue: "HIGH", CreatedAt: t0.Add(time.Hour)},
+	}, nil).Once()
+	mockRepo.On("ListTransitions", ctx, uint(1)).Return([]*model.BugStatusTransition{
+		{ID: 1, BugID: 1, FromStatus: model.BugStatusOpen, ToStatus: model.BugStatusInProgress, CreatedAt: t0.Add(time.Hour)},
+		{ID: 2, BugID: 1, FromStatus: model.BugStatusInProgress, ToStatus: model.BugStatusResolved, CreatedAt: t0.Add(2 * time.Hour)},
+	}, nil).Once()
+
+	feed, err := service.GetBugActivity(ctx, 1)
+	assert.NoError(t, err)
+	var types []model.BugActivityType
+	for _, item := range feed {
+		types = append(types, item.Type)
+	}
+	assert.Equal(t, []model.BugActivityType{
+		model.BugActivityComment,
+		model.BugActivityFieldChange,
+		model.BugActivityStatusTransition,
+		model.BugActivityComment,
+		model.BugActivityStatusTransition,
+	}, types)
+	assert.Equal(t, "first", feed[0].Comment.Body)
+	assert.Equal(t, "priority", feed[1].FieldChange.Field)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestBugService_Relations tests relating bugs, closing duplicates and rejecting cycles
+func TestBugService_Relations(t *testing.T) {
+	ctx := context.Background()
+	actorID := uint(7)
+	actorCtx := apputils.WithActor(ctx, apputils.Actor{UserID: actorID, Username: "tess"})
+	openBug := func(id uint) *model.Bug {
+		return &model.Bug{ID: id, Title: "Login fails", Status: model.BugStatusOpen, Priority: model.BugPriorityHigh}
+	}
+	relationTo := func(id, source, target uint, relationType model.BugRelationType) *model.BugRelation {
+		return &model.BugRelation{ID: id, SourceBugID: source, TargetBugID: target, Type: relationType, SourceBug: openBug(source), TargetBug: openBug(target)}
+	}
+
+	t.Run("A bug cannot be related to itself", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		mockRepo.On("GetByID", actorCtx, uint(1)).Return(openBug(1), nil).Once()
+
+		_, err := service.AddBugRelation(actorCtx, 1, &model.CreateBugRelationRequest{Type: model.BugRelationRelatesTo, TargetBugID: 1})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+	})
+
+	t.Run("Duplicates link to the original and are closed", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		bug := openBug(3)
+		mockRepo.On("GetByID", actorCtx, uint(3)).Return(bug, nil).Once()
+		mockRepo.On("GetByID", actorCtx, uint(2)).Return(openBug(2), nil).Once()
+		mockRepo.On("ListRelations", actorCtx, uint(3)).Return([]*model.BugRelation{}, nil).Once()
+		// Bug 2 is itself a duplicate of bug 1
+		mockRepo.On("ListRelationsFrom", actorCtx, model.BugRelationDuplicates, []uint{2}).Return([]*model.BugRelation{{SourceBugID: 2, TargetBugID: 1, Type: model.BugRelationDuplicates}}, nil).Once()
+		mockRepo.On("ListRelationsFrom", actorCtx, model.BugRelationDuplicates, []uint{1}).Return([]*model.BugRelation{}, nil).Once()
+		mockRepo.On("CreateRelation", actorCtx, mock.AnythingOfType("*model.BugRelation"), bug, mock.AnythingOfType("*model.BugStatusTransition")).
+			Run(func(args mock.Arguments) {
+				relation := args.Get(1).(*model.BugRelation)
+				assert.Equal(t, uint(1), relation.TargetBugID)
+				assert.Equal(t, actorID, *relation.ActorID)
+				relation.ID = 10
+				transition := args.Get(3).(*model.BugStatusTransition)
+				assert.Equal(t, model.BugStatusOpen, transition.FromStatus)
+				assert.Equal(t, model.BugStatusClosed, transition.ToStatus)
+				assert.Equal(t, "Duplicate of #1\n\nsame stack trace", transition.Comment)
+				assert.Equal(t, "tess", transition.Actor)
+			}).Return(nil).Once()
+		mockRepo.On("ListRelations", actorCtx, uint(3)).Return([]*model.BugRelation{relationTo(10, 3, 1, model.BugRelationDuplicates)}, nil).Once()
+
+		resp, err := service.AddBugRelation(actorCtx, 3, &model.CreateBugRelationRequest{Type: model.BugRelationDuplicates, TargetBugID: 2, Comment: " same stack trace "})
+		require.NoError(t, err)
+		assert.Equal(t, model.BugRelationOutward, resp.Direction)
+		assert.Equal(t, uint(1), resp.Bug.ID)
+		assert.Equal(t, model.BugStatusClosed, bug.Status)
+		assert.Equal(t, model.BugResolutionDuplicate, *bug.Resolution)
+		assert.NotNil(t, bug.ResolvedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("A bug duplicates at most one bug", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		mockRepo.On("GetByID", actorCtx, uint(3)).Return(openBug(3), nil).Once()
+		mockRepo.On("GetByID", actorCtx, uint(4)).Return(openBug(4), nil).Once()
+		mockRepo.On("ListRelations", actorCtx, uint(3)).Return([]*model.BugRelation{relationTo(10, 3, 1, model.BugRelationDuplicates)}, nil).Once()
+
+		_, err := service.AddBugRelation(actorCtx, 3, &model.CreateBugRelationRequest{Type: model.BugRelationDuplicates, TargetBugID: 4})
+		assert.ErrorIs(t, err, model.ErrBugRelationExists)
+	})
+
+	t.Run("Duplicate of its own duplicate is a cycle", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		mockRepo.On("GetByID", actorCtx, uint(1)).Return(openBug(1), nil).Once()
+		mockRepo.On("GetByID", actorCtx, uint(2)).Return(openBug(2), nil).Once()
+		mockRepo.On("ListRelations", actorCtx, uint(1)).Return([]*model.BugRelation{relationTo(10, 2, 1, model.BugRelationDuplicates)}, nil).Once()
+		mockRepo.On("ListRelationsFrom", actorCtx, model.BugRelationDuplicates, []uint{2}).Return([]*model.BugRelation{{SourceBugID: 2, TargetBugID: 1, Type: model.BugRelationDuplicates}}, nil).Once()
+		mockRepo.On("ListRelationsFrom", actorCtx, model.BugRelationDuplicates, []uint{1}).Return([]*model.BugRelation{}, nil).Once()
+
+		_, err := service.AddBugRelation(actorCtx, 1, &model.CreateBugRelationRequest{Type: model.BugRelationDuplicates, TargetBugID: 2})
+		assert.ErrorIs(t, err, model.ErrBugRelationCycle)
+	})
+
+	t.Run("Resolved bugs keep their status when marked as duplicates", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		fixed := model.BugResolutionFixed
+		bug := &model.Bug{ID: 3, Title: "Login fails", Status: model.BugStatusVerified, Resolution: &fixed}
+		mockRepo.On("GetByID", actorCtx, uint(3)).Return(bug, nil).Once()
+		mockRepo.On("GetByID", actorCtx, uint(1)).Return(openBug(1), nil).Once()
+		mockRepo.On("ListRelations", actorCtx, uint(3)).Return([]*model.BugRelation{}, nil).Once()
+		mockRepo.On("ListRelationsFrom", actorCtx, model.BugRelationDuplicates, []uint{1}).Return([]*model.BugRelation{}, nil).Once()
+		mockRepo.On("CreateRelation", actorCtx, mock.AnythingOfType("*model.BugRelation"), bug, (*model.BugStatusTransition)(nil)).Return(nil).Once()
+		mockRepo.On("ListRelations", actorCtx, uint(3)).Return([]*model.BugRelation{relationTo(0, 3, 1, model.BugRelationDuplicates)}, nil).Once()
+
+		_, err := service.AddBugRelation(actorCtx, 3, &model.CreateBugRelationRequest{Type: model.BugRelationDuplicates, TargetBugID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, model.BugStatusVerified, bug.Status)
+		assert.Equal(t, fixed, *bug.Resolution)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Blocks cannot form a cycle", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		// 2 blocks 5 and 5 blocks 1, so 1 cannot block 2
+		mockRepo.On("GetByID", actorCtx, uint(1)).Return(openBug(1), nil).Once()
+		mockRepo.On("GetByID", actorCtx, uint(2)).Return(openBug(2), nil).Once()
+		mockRepo.On("ListRelations", actorCtx, uint(1)).Return([]*model.BugRelation{}, nil).Once()
+		mockRepo.On("ListRelationsFrom", actorCtx, model.BugRelationBlocks, []uint{2}).Return([]*model.BugRelation{{SourceBugID: 2, TargetBugID: 5, Type: model.BugRelationBlocks}}, nil).Once()
+		mockRepo.On("ListRelationsFrom", actorCtx, model.BugRelationBlocks, []uint{5}).Return([]*model.BugRelation{{SourceBugID: 5, TargetBugID: 1, Type: model.BugRelationBlocks}}, nil).Once()
+
+		_, err := service.AddBugRelation(actorCtx, 1, &model.CreateBugRelationRequest{Type: model.BugRelationBlocks, TargetBugID: 2})
+		assert.ErrorIs(t, err, model.ErrBugRelationCycle)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Relates-to is symmetric", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		mockRepo.On("GetByID", actorCtx, uint(1)).Return(openBug(1), nil).Once()
+		mockRepo.On("GetByID", actorCtx, uint(2)).Return(openBug(2), nil).Once()
+		mockRepo.On("ListRelations", actorCtx, uint(1)).Return([]*model.BugRelation{relationTo(10, 2, 1, model.BugRelationRelatesTo)}, nil).Once()
+
+		_, err := service.AddBugRelation(actorCtx, 1, &model.CreateBugRelationRequest{Type: model.BugRelationRelatesTo, TargetBugID: 2})
+		assert.ErrorIs(t, err, model.ErrBugRelationExists)
+	})
+
+	t.Run("List shows the direction", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		mockRepo.On("GetByID", ctx, uint(1)).Return(openBug(1), nil).Once()
+		mockRepo.On("ListRelations", ctx, uint(1)).Return([]*model.BugRelation{
+			relationTo(10, 1, 2, model.BugRelationBlocks),
+			relationTo(11, 3, 1, model.BugRelationDuplicates),
+		}, nil).Once()
+
+		relations, err := service.ListBugRelations(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, relations, 2)
+		assert.Equal(t, model.BugRelationOutward, relations[0].Direction)
+		assert.Equal(t, uint(2), relations[0].Bug.ID)
+		assert.Equal(t, model.BugRelationInward, relations[1].Direction)
+		assert.Equal(t, uint(3), relations[1].Bug.ID)
+	})
+}
+
+// TestBugService_FindSimilarBugs tests the duplicate search and the duplicate check on create
+func TestBugService_FindSimilarBugs(t *testing.T) {
+	ctx := context.Background()
+	candidates := []*model.Bug{
+		{ID: 1, Title: "Checkout page crashes on submit", Status: model.BugStatusOpen},
+		{ID: 2, Title: "Checkout button is misaligned", Status: model.BugStatusOpen},
+		{ID: 3, Title: "Crash on checkout submit", Status: model.BugStatusResolved},
+	}
+
+	t.Run("Scores by shared words, best first", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		mockRepo.On("FindSimilarCandidates", ctx, []string{"checkout", "crashes", "page", "submit"}, bugSimilarityCandidates).Return(candidates, nil).Once()
+
+		similar, err := service.FindSimilarBugs(ctx, &model.FindSimilarBugsRequest{Title: "The checkout page crashes on submit!"})
+		require.NoError(t, err)
+		require.Len(t, similar, 1)
+		assert.Equal(t, uint(1), similar[0].Bug.ID)
+		assert.Equal(t, 1.0, similar[0].Score)
+	})
+
+	t.Run("Create refuses likely duplicates when asked to check", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		mockRepo.On("FindSimilarCandidates", ctx, mock.Anything, bugSimilarityCandidates).Return(candidates, nil).Once()
+
+		_, err := service.CreateBug(ctx, &model.CreateBugRequest{Title: "Checkout page crashes", Priority: model.BugPriorityHigh, CheckDuplicates: true})
+		var duplicates *BugDuplicatesError
+		require.ErrorAs(t, err, &duplicates)
+		assert.ErrorIs(t, err, model.ErrBugPossibleDuplicates)
+		assert.Equal(t, uint(1), duplicates.Candidates[0].Bug.ID)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create files the bug when nothing is similar", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		mockRepo.On("FindSimilarCandidates", ctx, []string{"footer", "typo"}, bugSimilarityCandidates).Return([]*model.Bug{}, nil).Once()
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Bug")).Return(nil).Once()
+
+		_, err := service.CreateBug(ctx, &model.CreateBugRequest{Title: "Typo in the footer", Priority: model.BugPriorityLow, CheckDuplicates: true})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestBugTokens(t *testing.T) {
+	assert.Equal(t, map[string]bool{"login": true, "returns": true, "500": true, "sso": true}, bugTokens("Login returns 500 with SSO, not a 500?"))
+	assert.Equal(t, map[string]bool{"登": true, "录": true, "失": true, "败": true, "api": true}, bugTokens("登录失败 (API)"))
+	assert.Equal(t, 0.5, jaccard(bugTokens("checkout crashes"), bugTokens("checkout hangs crashes now")))
+}
+
+// TestBugService_GetBugStats tests the defaults, validation and trend gap filling of the statistics
+func TestBugService_GetBugStats(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC)
+	resolved := model.DefaultBugWorkflow().ResolvedStatuses
+
+	t.Run("Fills the periods without bugs", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		hours := 12.5
+		mockRepo.On("CountByGroup", ctx, model.BugStatsByStatus, from, to).Return([]model.BugStatsGroup{{Key: "OPEN", Count: 3}, {Key: "CLOSED", Count: 2}}, nil).Once()
+		mockRepo.On("CountOpenByAge", ctx, resolved, mock.AnythingOfType("time.Time")).Return(model.NewBugAgeBuckets(), nil).Once()
+		mockRepo.On("MeanTimeToResolve", ctx, from, to).Return(&hours, int64(2), nil).Once()
+		mockRepo.On("CountTrend", ctx, model.BugStatsDaily, resolved, from, to).Return([]model.BugTrendPoint{{Period: "2024-05-02", Created: 4, Closed: 1}}, nil).Once()
+
+		stats, err := service.GetBugStats(ctx, &model.BugStatsParams{From: &from, To: &to})
+		require.NoError(t, err)
+		assert.Equal(t, model.BugStatsByStatus, stats.GroupBy)
+		assert.Equal(t, model.BugStatsDaily, stats.Interval)
+		assert.Equal(t, int64(5), stats.Total)
+		assert.Equal(t, 12.5, *stats.MeanTimeToResolveHours)
+		assert.Equal(t, []model.BugTrendPoint{
+			{Period: "2024-05-01"},
+			{Period: "2024-05-02", Created: 4, Closed: 1},
+			{Period: "2024-05-03"},
+		}, stats.Trend)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		service, _ := setupBugServiceTest(t)
+		_, err := service.GetBugStats(ctx, &model.BugStatsParams{GroupBy: "reporter"})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+		_, err = service.GetBugStats(ctx, &model.BugStatsParams{Interval: "month"})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+		_, err = service.GetBugStats(ctx, &model.BugStatsParams{From: &to, To: &from})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+		longAgo := from.AddDate(-2, 0, 0)
+		_, err = service.GetBugStats(ctx, &model.BugStatsParams{From: &longAgo, To: &to})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest, "too many days for a daily trend")
+	})
+}
+
+func TestTrendPeriods(t *testing.T) {
+	// Wednesday 1 May to Tuesday 14 May
+	from := time.Date(2024, 5, 1, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 14, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"2024-04-29", "2024-05-06", "2024-05-13"}, trendPeriods(model.BugStatsWeekly, from, to))
+	assert.Len(t, trendPeriods(model.BugStatsDaily, from, to), 14)
+}
+
+// TestBugService_BulkUpdateBugs tests per-bug and atomic bulk updates
+func TestBugService_BulkUpdateBugs(t *testing.T) {
+	ctx := context.Background()
+	inProgress := model.BugStatusInProgress
+	bugs := func() (*model.Bug, *model.Bug) {
+		return &model.Bug{ID: 1, Title: "Open bug", Status: model.BugStatusOpen, Priority: model.BugPriorityLow, Labels: []string{"ui"}},
+			&model.Bug{ID: 2, Title: "Closed bug", Status: model.BugStatusClosed, Priority: model.BugPriorityLow}
+	}
+	req := func(atomic bool, ids ...uint) *model.BulkBugUpdateRequest {
+		return &model.BulkBugUpdateRequest{
+			IDs:    ids,
+			Update: model.BulkBugChanges{Status: &inProgress, AddLabels: []string{" triage ", "ui"}},
+			Atomic: atomic,
+		}
+	}
+
+	t.Run("Each bug is updated on its own", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		open, closed := bugs()
+		mockRepo.On("GetByID", ctx, uint(1)).Return(open, nil).Once()
+		mockRepo.On("GetByID", ctx, uint(2)).Return(closed, nil).Once()
+		mockRepo.On("GetByID", ctx, uint(3)).Return(nil, repository.ErrBugNotFound).Once()
+		mockRepo.On("UpdateWithHistory", ctx, open, mock.AnythingOfType("*model.BugStatusTransition"), mock.Anything).Return(nil).Once()
+
+		resp, err := service.BulkUpdateBugs(ctx, req(false, 1, 2, 3, 1))
+		require.NoError(t, err)
+		assert.Equal(t, 3, resp.Matched)
+		assert.Equal(t, 1, resp.Updated)
+		assert.Equal(t, 2, resp.Failed)
+		require.Len(t, resp.Results, 3)
+		assert.Equal(t, model.BulkBugUpdated, resp.Results[0].Status)
+		assert.Equal(t, []string{"triage", "ui"}, resp.Results[0].Bug.Labels)
+		assert.Equal(t, model.BugStatusInProgress, resp.Results[0].Transition.ToStatus)
+		require.Len(t, resp.Results[0].Changes, 1)
+		assert.Equal(t, "labels", resp.Results[0].Changes[0].Field)
+		assert.Equal(t, model.BulkBugFailed, resp.Results[1].Status)
+		assert.Contains(t, resp.Results[1].Error, "cannot move bug from CLOSED to IN_PROGRESS")
+		assert.Equal(t, model.BulkBugFailed, resp.Results[2].Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Atomic update is all or nothing", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		open, closed := bugs()
+		mockRepo.On("GetByID", ctx, uint(1)).Return(open, nil).Once()
+		mockRepo.On("GetByID", ctx, uint(2)).Return(closed, nil).Once()
+
+		_, err := service.BulkUpdateBugs(ctx, req(true, 1, 2))
+		var failure *BulkBugUpdateError
+		require.ErrorAs(t, err, &failure)
+		assert.ErrorIs(t, err, model.ErrBulkBugUpdateFailed)
+		assert.Equal(t, model.BulkBugSkipped, failure.Response.Results[0].Status)
+		assert.Equal(t, model.BulkBugFailed, failure.Response.Results[1].Status)
+		mockRepo.AssertNotCalled(t, "UpdateManyWithHistory", mock.Anything, mock.Anything)
+
+		open, _ = bugs()
+		mockRepo.On("GetByID", ctx, uint(1)).Return(open, nil).Once()
+		mockRepo.On("UpdateManyWithHistory", ctx, mock.MatchedBy(func(updates []repository.BugUpdate) bool {
+			return len(updates) == 1 && updates[0].Bug == open && updates[0].Transition != nil
+		})).Return(nil).Once()
+		resp, err := service.BulkUpdateBugs(ctx, req(true, 1))
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.Updated)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Bugs picked by filter", func(t *testing.T) {
+		service, mockRepo := setupBugServiceTest(t)
+		tagged := &model.Bug{ID: 4, Status: model.BugStatusOpen, Labels: []string{"flaky", "ui"}}
+		untagged := &model.Bug{ID: 5, Status: model.BugStatusOpen}
+		mockRepo.On("List", ctx, mock.MatchedBy(func(params *model.BugListParams) bool {
+			return params.Label == "flaky" && params.Page == 1 && params.PageSize == model.BulkBugUpdateMaxBugs
+		})).Return([]*model.Bug{tagged, untagged}, int64(2), nil).Once()
+		mockRepo.On("UpdateWithHistory", ctx, tagged, (*model.BugStatusTransition)(nil), mock.Anything).Return(nil).Once()
+
+		resp, err := service.BulkUpdateBugs(ctx, &model.BulkBugUpdateRequest{
+			Filter: &model.BugListParams{Label: "flaky", Page: 3},
+			Update: model.BulkBugChanges{RemoveLabels: []string{"flaky"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkBugUpdated, resp.Results[0].Status)
+		assert.Equal(t, []string{"ui"}, resp.Results[0].Bug.Labels)
+		assert.Equal(t, model.BulkBugUnchanged, resp.Results[1].Status)
+		mockRepo.AssertExpectations(t)
+
+		mockRepo.On("List", ctx, mock.Anything).Return([]*model.Bug{tagged}, int64(model.BulkBugUpdateMaxBugs+1), nil).Once()
+		_, err = service.BulkUpdateBugs(ctx, &model.BulkBugUpdateRequest{Filter: &model.BugListParams{}, Update: model.BulkBugChanges{RemoveLabels: []string{"flaky"}}})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		service, _ := setupBugServiceTest(t)
+		_, err := service.BulkUpdateBugs(ctx, &model.BulkBugUpdateRequest{Update: model.BulkBugChanges{Status: &inProgress}})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest, "neither ids nor filter")
+		_, err = service.BulkUpdateBugs(ctx, &model.BulkBugUpdateRequest{IDs: []uint{1}, Filter: &model.BugListParams{}, Update: model.BulkBugChanges{Status: &inProgress}})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest, "both ids and filter")
+		_, err = service.BulkUpdateBugs(ctx, &model.BulkBugUpdateRequest{IDs: []uint{1}})
+		assert.ErrorIs(t, err, apputils.ErrBadRequest, "no changes")
+	})
+}
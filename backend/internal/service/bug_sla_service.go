@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// bugSLACheckBatchSize caps how many bugs one check flags; the rest wait for the next run.
+	bugSLACheckBatchSize = 200
+	// bugSLACheckerActor is recorded as the actor of the field changes an escalation makes.
+	bugSLACheckerActor = "sla-checker"
+)
+
+// BugSLAEscalationDTO records a bug the SLA checker reassigned.
+type BugSLAEscalationDTO struct {
+	BugID          uint  `json:"bugId"`
+	FromAssigneeID *uint `json:"fromAssigneeId"`
+	ToAssigneeID   uint  `json:"toAssigneeId"`
+}
+
+// BugSLACheckResultDTO is the outcome of one SLA check.
+type BugSLACheckResultDTO struct {
+	CheckedAt time.Time             `json:"checkedAt"`
+	Breached  []uint                `json:"breached"` // Bugs newly flagged as breached
+	Escalated []BugSLAEscalationDTO `json:"escalated"`
+}
+
+// BugSLAPolicySource looks up the SLA policy that applies to a bug priority.
+type BugSLAPolicySource interface {
+	// PolicyFor returns the enabled policy of priority, or nil if there is none.
+	PolicyFor(ctx context.Context, priority model.BugPriorityType) (*model.BugSLAPolicy, error)
+}
+
+// BugSLAService manages bug SLA policies and checks bugs against them.
+type BugSLAService interface {
+	BugSLAPolicySource
+	ListPolicies(ctx context.Context) ([]*model.BugSLAPolicy, error)
+	UpsertPolicy(ctx context.Context, priority model.BugPriorityType, req *model.UpsertBugSLAPolicyRequest) (*model.BugSLAPolicy, error)
+	DeletePolicy(ctx context.Context, priority model.BugPriorityType) error
+	// CheckBreaches flags the bugs whose running SLA clocks are past due at "now" and
+	// escalates each of them, once, to the backup of the assignee's responsibility group.
+	CheckBreaches(ctx context.Context, now time.Time) (*BugSLACheckResultDTO, error)
+	// Run calls CheckBreaches every interval until ctx is done.
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// bugSLAServiceImpl implements BugSLAService.
+type bugSLAServiceImpl struct {
+	policyRepo repository.BugSLAPolicyRepository
+	bugRepo    repository.BugRepository
+	ruleRepo   repository.BugAssignmentRuleRepository
+	groupRepo  repository.ResponsibilityGroupRepository
+	logger     *zap.Logger
+}
+
+// NewBugSLAService creates a new BugSLAService.
+func NewBugSLAService(
+	policyRepo repository.BugSLAPolicyRepository,
+	bugRepo repository.BugRepository,
+	ruleRepo repository.BugAssignmentRuleRepository,
+	groupRepo repository.ResponsibilityGroupRepository,
+	logger *zap.Logger,
+) BugSLAService {
+	return &bugSLAServiceImpl{
+		policyRepo: policyRepo,
+		bugRepo:    bugRepo,
+		ruleRepo:   ruleRepo,
+		groupRepo:  groupRepo,
+		logger:     logger,
+	}
+}
+
+func validateBugPriority(priority model.BugPriorityType) error {
+	switch priority {
+	case model.BugPriorityLow, model.BugPriorityMedium, model.BugPriorityHigh, model.BugPriorityUrgent:
+		return nil
+	}
+	return fmt.Errorf("%w: invalid bug priority '%s'", apputils.ErrBadRequest, priority)
+}
+
+// PolicyFor implements BugSLAPolicySource.
+func (s *bugSLAServiceImpl) PolicyFor(ctx context.Context, priority model.BugPriorityType) (*model.BugSLAPolicy, error) {
+	policy, err := s.policyRepo.GetByPriority(ctx, priority)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !policy.Enabled {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// ListPolicies returns every SLA policy.
+func (s *bugSLAServiceImpl) ListPolicies(ctx context.Context) ([]*model.BugSLAPolicy, error) {
+	return s.policyRepo.List(ctx)
+}
+
+// UpsertPolicy sets the SLA targets of a priority. Bugs pick up the new targets when they are
+// filed or their priority changes; due times already computed are left alone.
+func (s *bugSLAServiceImpl) UpsertPolicy(ctx context.Context, priority model.BugPriorityType, req *model.UpsertBugSLAPolicyRequest) (*model.BugSLAPolicy, error) {
+	if err := validateBugPriority(priority); err != nil {
+		return nil, err
+	}
+	if req.FirstResponseMinutes < 0 || req.ResolutionMinutes < 0 {
+		return nil, fmt.Errorf("%w: SLA targets cannot be negative", apputils.ErrBadRequest)
+	}
+	if req.FirstResponseMinutes > 0 && req.ResolutionMinutes > 0 && req.FirstResponseMinutes > req.ResolutionMinutes {
+		return nil, fmt.Errorf("%w: the first response target cannot be later than the resolution target", apputils.ErrBadRequest)
+	}
+
+	policy := &model.BugSLAPolicy{
+		Priority:             priority,
+		FirstResponseMinutes: req.FirstResponseMinutes,
+		ResolutionMinutes:    req.ResolutionMinutes,
+		Enabled:              true,
+	}
+	if req.Enabled != nil {
+		policy.Enabled = *req.Enabled
+	}
+	if err := s.policyRepo.Upsert(ctx, policy); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Bug SLA policy saved", zap.String("priority", string(priority)),
+		zap.Int("firstResponseMinutes", policy.FirstResponseMinutes), zap.Int("resolutionMinutes", policy.ResolutionMinutes))
+	return policy, nil
+}
+
+// DeletePolicy removes the SLA policy of a priority.
+func (s *bugSLAServiceImpl) DeletePolicy(ctx context.Context, priority model.BugPriorityType) error {
+	if err := validateBugPriority(priority); err != nil {
+		return err
+	}
+	if err := s.policyRepo.DeleteByPriority(ctx, priority); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: no SLA policy for priority %s", apputils.ErrNotFound, priority)
+		}
+		return err
+	}
+	s.logger.Info("Bug SLA policy deleted", zap.String("priority", string(priority)))
+	return nil
+}
+
+// CheckBreaches implements BugSLAService. A bug that cannot be escalated or saved is logged
+// and skipped so that one bad record does not hold up the others.
+func (s *bugSLAServiceImpl) CheckBreaches(ctx context.Context, now time.Time) (*BugSLACheckResultDTO, error) {
+	result := &BugSLACheckResultDTO{CheckedAt: now, Breached: []uint{}, Escalated: []BugSLAEscalationDTO{}}
+	bugs, err := s.bugRepo.ListSLABreachCandidates(ctx, now, bugSLACheckBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = apputils.WithActor(ctx, apputils.Actor{Username: bugSLACheckerActor})
+	for _, bug := range bugs {
+		before := *bug
+		if bug.ResponseBreachedAt == nil && bug.FirstRespondedAt == nil && bug.ResponseDueAt != nil && now.After(*bug.ResponseDueAt) {
+			bug.ResponseBreachedAt = &now
+		}
+		if bug.ResolveBreachedAt == nil && bug.ResolvedAt == nil && bug.ResolveDueAt != nil && now.After(*bug.ResolveDueAt) {
+			bug.ResolveBreachedAt = &now
+		}
+
+		// 首次违约时升级给处理人所在职责组的备岗人员，每个 Bug 只升级一次
+		var escalation *BugSLAEscalationDTO
+		if bug.EscalatedAt == nil {
+			target, err := s.escalationTarget(ctx, bug)
+			if err != nil {
+				s.logger.Error("Failed to find SLA escalation target", zap.Uint("bugId", bug.ID), zap.Error(err))
+			} else if target != 0 {
+				escalation = &BugSLAEscalationDTO{BugID: bug.ID, FromAssigneeID: bug.AssigneeID, ToAssigneeID: target}
+				bug.AssigneeID = &target
+				bug.EscalatedAt = &now
+				bug.EscalatedToID = &target
+			}
+		}
+
+		changes := bugFieldChanges(ctx, &before, bug, false)
+		for _, change := range changes {
+			change.CreatedAt = now
+		}
+		if err := s.bugRepo.UpdateWithHistory(ctx, bug, nil, changes); err != nil {
+			s.logger.Error("Failed to flag SLA breach", zap.Uint("bugId", bug.ID), zap.Error(err))
+			continue
+		}
+		result.Breached = append(result.Breached, bug.ID)
+		if escalation != nil {
+			result.Escalated = append(result.Escalated, *escalation)
+			s.logger.Info("Bug escalated after SLA breach", zap.Uint("bugId", bug.ID), zap.Uint("toAssigneeId", escalation.ToAssigneeID))
+		}
+	}
+	return result, nil
+}
+
+// escalationTarget picks who an overdue bug goes to: the backup of the responsibility group
+// that owns the bug, or its primary member when there is no backup, skipping inactive users
+// and the current assignee. The group is the one of the rule that assigned the bug, else the
+// first group the assignee belongs to. It returns 0 when nobody qualifies.
+func (s *bugSLAServiceImpl) escalationTarget(ctx context.Context, bug *model.Bug) (uint, error) {
+	var groupID uint
+	if bug.AssignmentRuleID != nil {
+		rule, err := s.ruleRepo.GetByID(ctx, *bug.AssignmentRuleID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+		if rule != nil {
+			groupID = rule.ResponsibilityGroupID
+		}
+	}
+	if groupID == 0 && bug.AssigneeID != nil {
+		memberships, err := s.groupRepo.ListMembershipsByUser(ctx, *bug.AssigneeID)
+		if err != nil {
+			return 0, err
+		}
+		if len(memberships) > 0 {
+			groupID = memberships[0].GroupID
+		}
+	}
+	if groupID == 0 {
+		return 0, nil
+	}
+
+	members, err := s.groupRepo.ListMembers(ctx, groupID)
+	if err != nil {
+		return 0, err
+	}
+	var backup, primary uint
+	for _, member := range members {
+		if member.User == nil || member.User.Status != userStatusActive {
+			continue
+		}
+		if bug.AssigneeID != nil && member.UserID == *bug.AssigneeID {
+			continue
+		}
+		if member.IsBackup {
+			backup = member.UserID
+		}
+		if member.IsPrimary {
+			primary = member.UserID
+		}
+	}
+	if backup != 0 {
+		return backup, nil
+	}
+	return primary, nil
+}
+
+// Run implements BugSLAService.
+func (s *bugSLAServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	s.logger.Info("Bug SLA checker started", zap.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Bug SLA checker stopped")
+			return
+		case now := <-ticker.C:
+			result, err := s.CheckBreaches(ctx, now)
+			if err != nil {
+				s.logger.Error("Bug SLA check failed", zap.Error(err))
+				continue
+			}
+			if len(result.Breached) > 0 {
+				s.logger.Info("Bug SLA check flagged breaches", zap.Int("breached", len(result.Breached)), zap.Int("escalated", len(result.Escalated)))
+			}
+		}
+	}
+}
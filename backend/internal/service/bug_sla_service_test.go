@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	mock_repository "EffiPlat/backend/internal/repository/mocks"
+	"EffiPlat/backend/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type bugSLAFixture struct {
+	svc        BugSLAService
+	policyRepo *mock_repository.MockBugSLAPolicyRepository
+	bugRepo    *MockBugRepository
+	ruleRepo   *mock_repository.MockBugAssignmentRuleRepository
+	groupRepo  *mock_repository.MockResponsibilityGroupRepository
+}
+
+func setupBugSLAServiceTest(t *testing.T) *bugSLAFixture {
+	ctrl := gomock.NewController(t)
+	f := &bugSLAFixture{
+		policyRepo: mock_repository.NewMockBugSLAPolicyRepository(ctrl),
+		bugRepo:    new(MockBugRepository),
+		ruleRepo:   mock_repository.NewMockBugAssignmentRuleRepository(ctrl),
+		groupRepo:  mock_repository.NewMockResponsibilityGroupRepository(ctrl),
+	}
+	f.svc = NewBugSLAService(f.policyRepo, f.bugRepo, f.ruleRepo, f.groupRepo, zap.NewNop())
+	return f
+}
+
+func backupMember(userID uint) model.ResponsibilityGroupMember {
+	member := groupMember(userID, "active", false)
+	member.IsBackup = true
+	return member
+}
+
+func TestBugSLAService_Policies(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("PolicyFor ignores missing and disabled policies", func(t *testing.T) {
+		f := setupBugSLAServiceTest(t)
+		f.policyRepo.EXPECT().GetByPriority(ctx, model.BugPriorityLow).Return(nil, gorm.ErrRecordNotFound)
+		f.policyRepo.EXPECT().GetByPriority(ctx, model.BugPriorityHigh).Return(&model.BugSLAPolicy{Priority: model.BugPriorityHigh, Enabled: false}, nil)
+
+		policy, err := f.svc.PolicyFor(ctx, model.BugPriorityLow)
+		assert.NoError(t, err)
+		assert.Nil(t, policy)
+		policy, err = f.svc.PolicyFor(ctx, model.BugPriorityHigh)
+		assert.NoError(t, err)
+		assert.Nil(t, policy)
+	})
+
+	t.Run("Upsert validates the priority and targets", func(t *testing.T) {
+		f := setupBugSLAServiceTest(t)
+		_, err := f.svc.UpsertPolicy(ctx, "CRITICAL", &model.UpsertBugSLAPolicyRequest{ResolutionMinutes: 60})
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		_, err = f.svc.UpsertPolicy(ctx, model.BugPriorityUrgent, &model.UpsertBugSLAPolicyRequest{FirstResponseMinutes: 120, ResolutionMinutes: 60})
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+
+	t.Run("Upsert defaults to enabled", func(t *testing.T) {
+		f := setupBugSLAServiceTest(t)
+		f.policyRepo.EXPECT().Upsert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, policy *model.BugSLAPolicy) error {
+			assert.Equal(t, model.BugPriorityUrgent, policy.Priority)
+			assert.True(t, policy.Enabled)
+			return nil
+		})
+		policy, err := f.svc.UpsertPolicy(ctx, model.BugPriorityUrgent, &model.UpsertBugSLAPolicyRequest{FirstResponseMinutes: 30, ResolutionMinutes: 240})
+		require.NoError(t, err)
+		assert.Equal(t, 30, policy.FirstResponseMinutes)
+	})
+
+	t.Run("Delete a missing policy", func(t *testing.T) {
+		f := setupBugSLAServiceTest(t)
+		f.policyRepo.EXPECT().DeleteByPriority(ctx, model.BugPriorityLow).Return(gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, f.svc.DeletePolicy(ctx, model.BugPriorityLow), utils.ErrNotFound)
+	})
+}
+
+func TestBugSLAService_CheckBreaches(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	t.Run("Escalates to the backup of the assigning rule's group", func(t *testing.T) {
+		f := setupBugSLAServiceTest(t)
+		assignee, ruleID := uint(4), uint(2)
+		bug := &model.Bug{ID: 1, AssigneeID: &assignee, AssignmentRuleID: &ruleID, ResponseDueAt: &past, ResolveDueAt: &future}
+		f.bugRepo.On("ListSLABreachCandidates", ctx, now, bugSLACheckBatchSize).Return([]*model.Bug{bug}, nil).Once()
+		f.ruleRepo.EXPECT().GetByID(gomock.Any(), ruleID).Return(&model.BugAssignmentRule{ID: ruleID, ResponsibilityGroupID: 20}, nil)
+		f.groupRepo.EXPECT().ListMembers(gomock.Any(), uint(20)).Return([]model.ResponsibilityGroupMember{
+			groupMember(4, "active", true),
+			groupMember(5, "active", false),
+			backupMember(6),
+		}, nil)
+		f.bugRepo.On("UpdateWithHistory", mock.Anything, bug, (*model.BugStatusTransition)(nil), mock.AnythingOfType("[]*model.BugFieldChange")).
+			Run(func(args mock.Arguments) {
+				changes := args.Get(3).([]*model.BugFieldChange)
+				require.Len(t, changes, 1)
+				assert.Equal(t, "assigneeId", changes[0].Field)
+				assert.Equal(t, "4", changes[0].OldValue)
+				assert.Equal(t, "6", changes[0].NewValue)
+				assert.Equal(t, bugSLACheckerActor, changes[0].Actor)
+			}).Return(nil).Once()
+
+		result, err := f.svc.CheckBreaches(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{1}, result.Breached)
+		require.Len(t, result.Escalated, 1)
+		assert.Equal(t, uint(6), result.Escalated[0].ToAssigneeID)
+		assert.Equal(t, now, *bug.ResponseBreachedAt)
+		assert.Nil(t, bug.ResolveBreachedAt, "the resolution clock is not due yet")
+		assert.Equal(t, uint(6), *bug.EscalatedToID)
+		f.bugRepo.AssertExpectations(t)
+	})
+
+	t.Run("Falls back to the primary of the assignee's group", func(t *testing.T) {
+		f := setupBugSLAServiceTest(t)
+		assignee := uint(5)
+		bug := &model.Bug{ID: 2, AssigneeID: &assignee, ResolveDueAt: &past}
+		f.bugRepo.On("ListSLABreachCandidates", ctx, now, bugSLACheckBatchSize).Return([]*model.Bug{bug}, nil).Once()
+		f.groupRepo.EXPECT().ListMembershipsByUser(gomock.Any(), assignee).Return([]model.ResponsibilityGroupMember{{GroupID: 30, UserID: 5}}, nil)
+		f.groupRepo.EXPECT().ListMembers(gomock.Any(), uint(30)).Return([]model.ResponsibilityGroupMember{
+			groupMember(4, "active", true),
+			groupMember(5, "active", false),
+		}, nil)
+		f.bugRepo.On("UpdateWithHistory", mock.Anything, bug, (*model.BugStatusTransition)(nil), mock.AnythingOfType("[]*model.BugFieldChange")).Return(nil).Once()
+
+		result, err := f.svc.CheckBreaches(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, result.Escalated, 1)
+		assert.Equal(t, uint(4), *bug.AssigneeID)
+		assert.Equal(t, now, *bug.ResolveBreachedAt)
+	})
+
+	t.Run("Already escalated bugs are only flagged", func(t *testing.T) {
+		f := setupBugSLAServiceTest(t)
+		assignee := uint(6)
+		bug := &model.Bug{ID: 3, AssigneeID: &assignee, ResolveDueAt: &past, EscalatedAt: &past, EscalatedToID: &assignee}
+		f.bugRepo.On("ListSLABreachCandidates", ctx, now, bugSLACheckBatchSize).Return([]*model.Bug{bug}, nil).Once()
+		f.bugRepo.On("UpdateWithHistory", mock.Anything, bug, (*model.BugStatusTransition)(nil), []*model.BugFieldChange(nil)).Return(nil).Once()
+
+		result, err := f.svc.CheckBreaches(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{3}, result.Breached)
+		assert.Empty(t, result.Escalated)
+		assert.Equal(t, uint(6), *bug.AssigneeID)
+	})
+
+	t.Run("No one to escalate to", func(t *testing.T) {
+		f := setupBugSLAServiceTest(t)
+		bug := &model.Bug{ID: 4, ResponseDueAt: &past}
+		f.bugRepo.On("ListSLABreachCandidates", ctx, now, bugSLACheckBatchSize).Return([]*model.Bug{bug}, nil).Once()
+		f.bugRepo.On("UpdateWithHistory", mock.Anything, bug, (*model.BugStatusTransition)(nil), []*model.BugFieldChange(nil)).Return(nil).Once()
+
+		result, err := f.svc.CheckBreaches(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{4}, result.Breached)
+		assert.Empty(t, result.Escalated)
+		assert.Nil(t, bug.EscalatedAt)
+	})
+}
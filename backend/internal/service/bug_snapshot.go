@@ -0,0 +1,134 @@
+package service
+
+import (
+	"EffiPlat/backend/internal/model"
+	apputils "EffiPlat/backend/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// captureEnvironmentSnapshot records every service instance currently in env.
+func (s *bugServiceImpl) captureEnvironmentSnapshot(ctx context.Context, env *model.Environment) (*model.BugEnvironmentSnapshot, error) {
+	instances, err := s.snapshotInstances(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	return &model.BugEnvironmentSnapshot{
+		EnvironmentID:   env.ID,
+		EnvironmentName: env.Name,
+		Instances:       instances,
+	}, nil
+}
+
+// snapshotInstances describes the service instances of env with their effective, masked config.
+func (s *bugServiceImpl) snapshotInstances(ctx context.Context, env *model.Environment) ([]model.BugSnapshotInstance, error) {
+	instances, err := s.instanceRepo.ListByEnvironmentID(ctx, env.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make(map[uint]*model.Service)
+	out := make([]model.BugSnapshotInstance, 0, len(instances))
+	for _, instance := range instances {
+		svc, cached := services[instance.ServiceID]
+		if !cached {
+			svc, err = s.serviceRepo.GetByID(ctx, instance.ServiceID)
+			if err != nil && !errors.Is(err, model.ErrServiceNotFound) && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			services[instance.ServiceID] = svc
+		}
+
+		item := model.BugSnapshotInstance{
+			ServiceInstanceID: instance.ID,
+			ServiceID:         instance.ServiceID,
+			Version:           instance.Version,
+			Status:            instance.Status,
+			Hostname:          instance.Hostname,
+			Port:              instance.Port,
+			DeployedAt:        instance.DeployedAt,
+		}
+		if svc != nil {
+			item.ServiceName = svc.Name
+		}
+		config, _ := effectiveConfig(svc, env, instance.Config)
+		item.Config = apputils.MaskSecrets(config)
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// GetBugSnapshot returns the environment snapshot taken when the bug was filed.
+func (s *bugServiceImpl) GetBugSnapshot(ctx context.Context, id uint) (*model.BugEnvironmentSnapshot, error) {
+	if _, err := s.bugRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.bugRepo.GetSnapshot(ctx, id)
+}
+
+// DiffBugSnapshot compares the bug's snapshot with the current state of the environment.
+// Instances are matched by ID; an environment deleted since shows every instance as removed.
+func (s *bugServiceImpl) DiffBugSnapshot(ctx context.Context, id uint) (*BugSnapshotDiffDTO, error) {
+	snapshot, err := s.GetBugSnapshot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var current []model.BugSnapshotInstance
+	env, err := s.envRepo.GetByID(ctx, snapshot.EnvironmentID)
+	switch {
+	case err == nil:
+		if current, err = s.snapshotInstances(ctx, env); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	diff := &BugSnapshotDiffDTO{
+		BugID:         id,
+		EnvironmentID: snapshot.EnvironmentID,
+		SnapshotAt:    snapshot.CreatedAt,
+		Added:         []model.BugSnapshotInstance{},
+		Removed:       []model.BugSnapshotInstance{},
+		Changed:       []BugSnapshotInstanceDiffDTO{},
+	}
+	before := make(map[uint]model.BugSnapshotInstance, len(snapshot.Instances))
+	for _, instance := range snapshot.Instances {
+		before[instance.ServiceInstanceID] = instance
+	}
+	for _, now := range current {
+		then, existed := before[now.ServiceInstanceID]
+		if !existed {
+			diff.Added = append(diff.Added, now)
+			continue
+		}
+		delete(before, now.ServiceInstanceID)
+		if changes := apputils.DiffJSON(snapshotInstanceDoc(then), snapshotInstanceDoc(now)); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, BugSnapshotInstanceDiffDTO{
+				ServiceInstanceID: now.ServiceInstanceID,
+				ServiceName:       now.ServiceName,
+				Changes:           changes,
+			})
+		}
+	}
+	for _, instance := range snapshot.Instances {
+		if _, gone := before[instance.ServiceInstanceID]; gone {
+			diff.Removed = append(diff.Removed, instance)
+		}
+	}
+	return diff, nil
+}
+
+// snapshotInstanceDoc turns an instance into a JSON object for diffing, without its identity fields.
+func snapshotInstanceDoc(instance model.BugSnapshotInstance) map[string]interface{} {
+	doc := make(map[string]interface{})
+	raw, _ := json.Marshal(instance)
+	_ = json.Unmarshal(raw, &doc)
+	delete(doc, "serviceInstanceId")
+	delete(doc, "serviceId")
+	return doc
+}
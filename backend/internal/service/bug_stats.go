@@ -0,0 +1,110 @@
+package service
+
+import (
+	"EffiPlat/backend/internal/model"
+	apputils "EffiPlat/backend/internal/utils"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// bugStatsDefaultWindow is the window used when no start is given.
+	bugStatsDefaultWindow = 30 * 24 * time.Hour
+	// bugStatsMaxPeriods caps the length of the trend series.
+	bugStatsMaxPeriods = 400
+)
+
+// GetBugStats returns the bug statistics for the dashboards over the requested window.
+func (s *bugServiceImpl) GetBugStats(ctx context.Context, params *model.BugStatsParams) (*model.BugStatsResponse, error) {
+	now := time.Now()
+	stats := &model.BugStatsResponse{
+		To:       now,
+		GroupBy:  params.GroupBy,
+		Interval: params.Interval,
+	}
+	if params.To != nil {
+		stats.To = *params.To
+	}
+	stats.From = stats.To.Add(-bugStatsDefaultWindow)
+	if params.From != nil {
+		stats.From = *params.From
+	}
+	if stats.GroupBy == "" {
+		stats.GroupBy = model.BugStatsByStatus
+	}
+	if stats.Interval == "" {
+		stats.Interval = model.BugStatsDaily
+	}
+
+	switch stats.GroupBy {
+	case model.BugStatsByStatus, model.BugStatsByPriority, model.BugStatsByAssignee, model.BugStatsByEnvironment, model.BugStatsByService:
+	default:
+		return nil, fmt.Errorf("%w: cannot group bugs by %q", apputils.ErrBadRequest, stats.GroupBy)
+	}
+	if stats.Interval != model.BugStatsDaily && stats.Interval != model.BugStatsWeekly {
+		return nil, fmt.Errorf("%w: interval must be %s or %s, got %q", apputils.ErrBadRequest, model.BugStatsDaily, model.BugStatsWeekly, stats.Interval)
+	}
+	if !stats.From.Before(stats.To) {
+		return nil, fmt.Errorf("%w: from must be before to", apputils.ErrBadRequest)
+	}
+	periods := trendPeriods(stats.Interval, stats.From, stats.To)
+	if len(periods) > bugStatsMaxPeriods {
+		return nil, fmt.Errorf("%w: the window spans %d %ss, at most %d are allowed", apputils.ErrBadRequest, len(periods), stats.Interval, bugStatsMaxPeriods)
+	}
+
+	groups, err := s.bugRepo.CountByGroup(ctx, stats.GroupBy, stats.From, stats.To)
+	if err != nil {
+		return nil, err
+	}
+	stats.Groups = groups
+	for _, group := range groups {
+		stats.Total += group.Count
+	}
+
+	if stats.OpenAge, err = s.bugRepo.CountOpenByAge(ctx, s.workflow.ResolvedStatuses, now); err != nil {
+		return nil, err
+	}
+	if stats.MeanTimeToResolveHours, stats.ResolvedCount, err = s.bugRepo.MeanTimeToResolve(ctx, stats.From, stats.To); err != nil {
+		return nil, err
+	}
+
+	// 补齐没有数据的周期，使趋势序列连续
+	points, err := s.bugRepo.CountTrend(ctx, stats.Interval, s.workflow.ResolvedStatuses, stats.From, stats.To)
+	if err != nil {
+		return nil, err
+	}
+	byPeriod := make(map[string]model.BugTrendPoint, len(points))
+	for _, point := range points {
+		byPeriod[point.Period] = point
+	}
+	stats.Trend = make([]model.BugTrendPoint, len(periods))
+	for i, period := range periods {
+		stats.Trend[i] = model.BugTrendPoint{Period: period}
+		if point, ok := byPeriod[period]; ok {
+			stats.Trend[i] = point
+		}
+	}
+	return stats, nil
+}
+
+// trendPeriods lists the first days (UTC, YYYY-MM-DD) of the periods overlapping [from, to).
+// Weeks start on Monday, as in the repository's grouping.
+func trendPeriods(interval model.BugStatsInterval, from, to time.Time) []string {
+	from, to = from.UTC(), to.UTC()
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	step := 24 * time.Hour
+	if interval == model.BugStatsWeekly {
+		day = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		step = 7 * 24 * time.Hour
+	}
+
+	var periods []string
+	for ; day.Before(to); day = day.Add(step) {
+		periods = append(periods, day.Format("2006-01-02"))
+		if len(periods) > bugStatsMaxPeriods {
+			break
+		}
+	}
+	return periods
+}
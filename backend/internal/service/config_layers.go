@@ -0,0 +1,44 @@
+package service
+
+import (
+	"EffiPlat/backend/internal/model"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"gorm.io/datatypes"
+)
+
+// Config layers, from lowest to highest precedence.
+const (
+	ConfigLayerService     = "service"     // model.Service.DefaultConfig
+	ConfigLayerEnvironment = "environment" // model.Environment.Config
+	ConfigLayerInstance    = "instance"    // model.ServiceInstance.Config
+)
+
+// inheritedConfigLayers returns the layers an instance config is applied on top of.
+func inheritedConfigLayers(svc *model.Service, env *model.Environment) []apputils.JSONLayer {
+	var layers []apputils.JSONLayer
+	if svc != nil {
+		layers = append(layers, apputils.JSONLayer{Name: ConfigLayerService, Doc: svc.DefaultConfig})
+	}
+	if env != nil {
+		layers = append(layers, apputils.JSONLayer{Name: ConfigLayerEnvironment, Doc: env.Config})
+	}
+	return layers
+}
+
+// effectiveConfig merges service defaults, environment overrides and the instance config,
+// returning the result and the layer each leaf key came from.
+func effectiveConfig(svc *model.Service, env *model.Environment, instanceConfig map[string]interface{}) (map[string]interface{}, map[string]string) {
+	layers := append(inheritedConfigLayers(svc, env), apputils.JSONLayer{Name: ConfigLayerInstance, Doc: instanceConfig})
+	return apputils.MergeJSONLayers(layers...)
+}
+
+// instanceConfigOverrides drops the parts of an instance config that it would inherit anyway,
+// so that only instance-specific values are stored.
+func instanceConfigOverrides(svc *model.Service, env *model.Environment, config map[string]interface{}) datatypes.JSONMap {
+	if config == nil {
+		return nil
+	}
+	inherited, _ := apputils.MergeJSONLayers(inheritedConfigLayers(svc, env)...)
+	return apputils.StripInheritedJSON(config, inherited)
+}
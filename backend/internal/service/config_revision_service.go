@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ListConfigRevisionsResponseDTO wraps the paginated list of config revisions.
+type ListConfigRevisionsResponseDTO struct {
+	Items []*model.ConfigRevision `json:"items"`
+	Total int64                   `json:"total"`
+	Page  int                     `json:"page"`
+	Size  int                     `json:"pageSize"`
+}
+
+// ConfigDiffDTO is the JSON diff between two config revisions of an instance.
+type ConfigDiffDTO struct {
+	ServiceInstanceID uint                  `json:"serviceInstanceId"`
+	FromRevision      int                   `json:"fromRevision"`
+	ToRevision        int                   `json:"toRevision"`
+	Changes           []apputils.JSONChange `json:"changes"`
+}
+
+// ConfigRollbackInputDTO carries the optional comment for a rollback.
+type ConfigRollbackInputDTO struct {
+	Comment string `json:"comment" binding:"omitempty,max=1000"`
+}
+
+// ConfigRevisionService defines the interface for versioned service instance configuration.
+type ConfigRevisionService interface {
+	ListRevisions(ctx context.Context, instanceID uint, params *model.ConfigRevisionListParams) (*ListConfigRevisionsResponseDTO, error)
+	GetRevision(ctx context.Context, instanceID uint, revision int) (*model.ConfigRevision, error)
+	DiffRevisions(ctx context.Context, instanceID uint, fromRevision, toRevision int) (*ConfigDiffDTO, error)
+	Rollback(ctx context.Context, instanceID uint, revision int, input *ConfigRollbackInputDTO) (*model.ConfigRevision, error)
+}
+
+// configRevisionServiceImpl implements ConfigRevisionService.
+type configRevisionServiceImpl struct {
+	repo         repository.ConfigRevisionRepository
+	instanceRepo repository.ServiceInstanceRepository
+	serviceRepo  repository.ServiceRepository
+	envRepo      repository.EnvironmentRepository
+	cipher       *apputils.SecretCipher
+	logger       *zap.Logger
+}
+
+// NewConfigRevisionService creates a new ConfigRevisionService.
+func NewConfigRevisionService(
+	repo repository.ConfigRevisionRepository,
+	instanceRepo repository.ServiceInstanceRepository,
+	serviceRepo repository.ServiceRepository,
+	envRepo repository.EnvironmentRepository,
+	cipher *apputils.SecretCipher,
+	logger *zap.Logger,
+) ConfigRevisionService {
+	return &configRevisionServiceImpl{
+		repo:         repo,
+		instanceRepo: instanceRepo,
+		serviceRepo:  serviceRepo,
+		envRepo:      envRepo,
+		cipher:       cipher,
+		logger:       logger,
+	}
+}
+
+func (s *configRevisionServiceImpl) getInstance(ctx context.Context, instanceID uint) (*model.ServiceInstance, error) {
+	instance, err := s.instanceRepo.GetByID(ctx, instanceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: service instance with ID %d not found", apputils.ErrNotFound, instanceID)
+		}
+		s.logger.Error("Failed to get service instance", zap.Uint("instanceId", instanceID), zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve service instance: %w", err)
+	}
+	return instance, nil
+}
+
+// ListRevisions returns an instance's config revisions, newest first.
+func (s *configRevisionServiceImpl) ListRevisions(ctx context.Context, instanceID uint, params *model.ConfigRevisionListParams) (*ListConfigRevisionsResponseDTO, error) {
+	if _, err := s.getInstance(ctx, instanceID); err != nil {
+		return nil, err
+	}
+
+	revisions, total, err := s.repo.List(ctx, instanceID, params)
+	if err != nil {
+		s.logger.Error("Failed to list config revisions", zap.Uint("instanceId", instanceID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list config revisions: %w", err)
+	}
+
+	for i, rev := range revisions {
+		revisions[i] = maskRevision(rev)
+	}
+
+	return &ListConfigRevisionsResponseDTO{
+		Items: revisions,
+		Total: total,
+		Page:  params.Page,
+		Size:  params.PageSize,
+	}, nil
+}
+
+// GetRevision returns a single config revision of an instance, with secret values masked.
+func (s *configRevisionServiceImpl) GetRevision(ctx context.Context, instanceID uint, revision int) (*model.ConfigRevision, error) {
+	rev, err := s.getRevision(ctx, instanceID, revision)
+	if err != nil {
+		return nil, err
+	}
+	return maskRevision(rev), nil
+}
+
+// getRevision loads a revision as stored, i.e. with secrets still encrypted.
+func (s *configRevisionServiceImpl) getRevision(ctx context.Context, instanceID uint, revision int) (*model.ConfigRevision, error) {
+	rev, err := s.repo.GetByRevision(ctx, instanceID, revision)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: config revision %d not found for service instance %d", apputils.ErrNotFound, revision, instanceID)
+		}
+		s.logger.Error("Failed to get config revision", zap.Uint("instanceId", instanceID), zap.Int("revision", revision), zap.Error(err))
+		return nil, fmt.Errorf("failed to get config revision: %w", err)
+	}
+	return rev, nil
+}
+
+// DiffRevisions computes the JSON diff from one revision to another.
+func (s *configRevisionServiceImpl) DiffRevisions(ctx context.Context, instanceID uint, fromRevision, toRevision int) (*ConfigDiffDTO, error) {
+	if fromRevision <= 0 || toRevision <= 0 {
+		return nil, fmt.Errorf("%w: revisions must be positive numbers", apputils.ErrBadRequest)
+	}
+
+	from, err := s.getRevision(ctx, instanceID, fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.getRevision(ctx, instanceID, toRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfigDiffDTO{
+		ServiceInstanceID: instanceID,
+		FromRevision:      fromRevision,
+		ToRevision:        toRevision,
+		Changes:           apputils.MaskJSONChanges(apputils.DiffJSON(from.Config, to.Config)),
+	}, nil
+}
+
+// Rollback restores the config of an earlier revision onto the instance.
+// The restored config is stored as a new revision so history stays append-only. It is validated
+// against the service's current schema, which may have changed since the revision was saved.
+func (s *configRevisionServiceImpl) Rollback(ctx context.Context, instanceID uint, revision int, input *ConfigRollbackInputDTO) (*model.ConfigRevision, error) {
+	s.logger.Info("Rolling back service instance config", zap.Uint("instanceId", instanceID), zap.Int("revision", revision))
+
+	instance, err := s.getInstance(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.getRevision(ctx, instanceID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateRestoredConfig(ctx, instance, target.Config); err != nil {
+		return nil, err
+	}
+
+	instance.Config = target.Config
+	if instance.Config == nil {
+		// Updates skips nil fields, so an empty map is needed to clear the current config.
+		instance.Config = datatypes.JSONMap{}
+	}
+	if err := s.instanceRepo.Update(ctx, instance); err != nil {
+		s.logger.Error("Failed to update service instance config during rollback", zap.Uint("instanceId", instanceID), zap.Error(err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apputils.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to update service instance: %w", err)
+	}
+
+	comment := fmt.Sprintf("Rollback to revision %d", revision)
+	if input != nil && input.Comment != "" {
+		comment = input.Comment
+	}
+	rolledBackFrom := revision
+	newRevision := &model.ConfigRevision{
+		ServiceInstanceID: instanceID,
+		Config:            target.Config,
+		AuthorID:          apputils.ActorUserID(ctx),
+		Author:            apputils.ActorName(ctx),
+		Comment:           comment,
+		RolledBackFrom:    &rolledBackFrom,
+	}
+	if err := s.repo.Create(ctx, newRevision); err != nil {
+		s.logger.Error("Failed to record rollback config revision", zap.Uint("instanceId", instanceID), zap.Error(err))
+		return nil, fmt.Errorf("failed to record config revision: %w", err)
+	}
+
+	s.logger.Info("Service instance config rolled back", zap.Uint("instanceId", instanceID), zap.Int("fromRevision", revision), zap.Int("newRevision", newRevision.Revision))
+	return maskRevision(newRevision), nil
+}
+
+// validateRestoredConfig checks the effective config that restoring config onto instance would
+// produce, the same way ServiceInstanceService.UpdateServiceInstance checks a submitted config.
+func (s *configRevisionServiceImpl) validateRestoredConfig(ctx context.Context, instance *model.ServiceInstance, config map[string]interface{}) error {
+	svc, err := s.serviceRepo.GetByID(ctx, instance.ServiceID)
+	if err != nil {
+		s.logger.Error("Failed to get service for config validation", zap.Uint("serviceId", instance.ServiceID), zap.Error(err))
+		return fmt.Errorf("failed to load service for config validation: %w", err)
+	}
+	env, err := s.envRepo.GetByID(ctx, instance.EnvironmentID)
+	if err != nil {
+		s.logger.Error("Failed to get environment for config inheritance", zap.Uint("environmentId", instance.EnvironmentID), zap.Error(err))
+		return fmt.Errorf("failed to load environment for config inheritance: %w", err)
+	}
+	// Masked secrets resolve to their stored plaintext, so the schema sees the real values.
+	plainConfig, _, err := sealConfig(s.logger, s.cipher, svc, apputils.MaskSecrets(config), config)
+	if err != nil {
+		return err
+	}
+	effective, _ := effectiveConfig(svc, env, plainConfig)
+	return validateConfig(s.logger, svc, effective)
+}
+
+// maskRevision returns a copy of rev whose encrypted config values are masked for output.
+func maskRevision(rev *model.ConfigRevision) *model.ConfigRevision {
+	if rev == nil {
+		return nil
+	}
+	masked := *rev
+	masked.Config = apputils.MaskSecrets(rev.Config)
+	return &masked
+}
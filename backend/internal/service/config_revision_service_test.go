@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"EffiPlat/backend/internal/model"
+	mock_repository "EffiPlat/backend/internal/repository/mocks"
+	"EffiPlat/backend/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestConfigRevisionService_DiffRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockConfigRevisionRepository(ctrl)
+	svc := NewConfigRevisionService(mockRepo, mock_repository.NewMockServiceInstanceRepository(ctrl), mock_repository.NewMockServiceRepository(ctrl), mock_repository.NewMockEnvironmentRepository(ctrl), nil, zap.NewNop())
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetByRevision(ctx, uint(1), 1).Return(&model.ConfigRevision{Revision: 1, Config: datatypes.JSONMap{
+		"replicas": float64(1),
+		"db":       map[string]interface{}{"host": "db-old", "pool": float64(5)},
+		"debug":    true,
+	}}, nil)
+	mockRepo.EXPECT().GetByRevision(ctx, uint(1), 2).Return(&model.ConfigRevision{Revision: 2, Config: datatypes.JSONMap{
+		"replicas": float64(3),
+		"db":       map[string]interface{}{"host": "db-new", "pool": float64(5)},
+		"region":   "eu",
+	}}, nil)
+
+	diff, err := svc.DiffRevisions(ctx, 1, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []utils.JSONChange{
+		{Path: "db.host", Op: utils.JSONChangeChanged, OldValue: "db-old", NewValue: "db-new"},
+		{Path: "debug", Op: utils.JSONChangeRemoved, OldValue: true},
+		{Path: "region", Op: utils.JSONChangeAdded, NewValue: "eu"},
+		{Path: "replicas", Op: utils.JSONChangeChanged, OldValue: float64(1), NewValue: float64(3)},
+	}, diff.Changes)
+
+	t.Run("Unknown revision", func(t *testing.T) {
+		mockRepo.EXPECT().GetByRevision(ctx, uint(1), 9).Return(nil, gorm.ErrRecordNotFound)
+		_, err := svc.DiffRevisions(ctx, 1, 9, 2)
+		assert.True(t, errors.Is(err, utils.ErrNotFound))
+	})
+
+	t.Run("Invalid revision numbers", func(t *testing.T) {
+		_, err := svc.DiffRevisions(ctx, 1, 0, 2)
+		assert.True(t, errors.Is(err, utils.ErrBadRequest))
+	})
+}
+
+func TestConfigRevisionService_Rollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockConfigRevisionRepository(ctrl)
+	mockInstanceRepo := mock_repository.NewMockServiceInstanceRepository(ctrl)
+	mockServiceRepo := mock_repository.NewMockServiceRepository(ctrl)
+	mockEnvRepo := mock_repository.NewMockEnvironmentRepository(ctrl)
+	svc := NewConfigRevisionService(mockRepo, mockInstanceRepo, mockServiceRepo, mockEnvRepo, nil, zap.NewNop())
+	ctx := utils.WithActor(context.Background(), utils.Actor{UserID: 3, Username: "ops"})
+
+	oldConfig := datatypes.JSONMap{"replicas": float64(1)}
+	mockInstanceRepo.EXPECT().GetByID(ctx, uint(4)).Return(&model.ServiceInstance{ID: 4, ServiceID: 2, EnvironmentID: 5, Config: datatypes.JSONMap{"replicas": float64(9)}}, nil)
+	mockRepo.EXPECT().GetByRevision(ctx, uint(4), 1).Return(&model.ConfigRevision{ServiceInstanceID: 4, Revision: 1, Config: oldConfig}, nil)
+	mockServiceRepo.EXPECT().GetByID(ctx, uint(2)).Return(&model.Service{ID: 2}, nil)
+	mockEnvRepo.EXPECT().GetByID(ctx, uint(5)).Return(&model.Environment{ID: 5}, nil)
+	mockInstanceRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, si *model.ServiceInstance) error {
+		assert.Equal(t, oldConfig, si.Config)
+		return nil
+	})
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rev *model.ConfigRevision) error {
+		rev.Revision = 3
+		return nil
+	})
+
+	rev, err := svc.Rollback(ctx, 4, 1, &ConfigRollbackInputDTO{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, rev.Revision)
+	assert.Equal(t, oldConfig, rev.Config)
+	assert.Equal(t, "Rollback to revision 1", rev.Comment)
+	if assert.NotNil(t, rev.RolledBackFrom) {
+		assert.Equal(t, 1, *rev.RolledBackFrom)
+	}
+	if assert.NotNil(t, rev.AuthorID) {
+		assert.Equal(t, uint(3), *rev.AuthorID)
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/pkg/jsonschema"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"gorm.io/datatypes"
+)
+
+// ConfigValidationError is returned when a service instance config violates its service's JSON Schema.
+// It unwraps to apputils.ErrBadRequest so existing handlers map it to 400, while handlers that
+// know about it can return the field-level errors.
+type ConfigValidationError struct {
+	Errors []apputils.FieldError
+}
+
+func (e *ConfigValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		if fe.Field == "" {
+			msgs = append(msgs, fe.Message)
+		} else {
+			msgs = append(msgs, fe.Field+": "+fe.Message)
+		}
+	}
+	return "config does not match the service config schema: " + strings.Join(msgs, "; ")
+}
+
+func (e *ConfigValidationError) Unwrap() error {
+	return apputils.ErrBadRequest
+}
+
+// hasConfigSchema reports whether the raw schema actually constrains anything.
+func hasConfigSchema(schema datatypes.JSON) bool {
+	trimmed := bytes.TrimSpace(schema)
+	return len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null")) && !bytes.Equal(trimmed, []byte("{}"))
+}
+
+// compileConfigSchema compiles a service config schema, wrapping failures in model.ErrInvalidConfigSchema.
+func compileConfigSchema(schema datatypes.JSON) (*jsonschema.Schema, error) {
+	compiled, err := jsonschema.Compile(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidConfigSchema, err)
+	}
+	return compiled, nil
+}
+
+// validateConfigAgainstSchema returns the field-level violations of config against schema.
+// A service without a schema accepts any config.
+func validateConfigAgainstSchema(schema datatypes.JSON, config map[string]interface{}) ([]apputils.FieldError, error) {
+	if !hasConfigSchema(schema) {
+		return nil, nil
+	}
+	compiled, err := compileConfigSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	violations := compiled.Validate(config)
+	if len(violations) == 0 {
+		return nil, nil
+	}
+	fieldErrors := make([]apputils.FieldError, len(violations))
+	for i, v := range violations {
+		fieldErrors[i] = apputils.FieldError{Field: v.Path, Message: v.Message}
+	}
+	return fieldErrors, nil
+}
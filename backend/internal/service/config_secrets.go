@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"EffiPlat/backend/internal/model"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"gorm.io/datatypes"
+)
+
+// configSecretKeyword is the schema annotation that marks a config property as secret.
+const configSecretKeyword = "secret"
+
+// configSecretPaths returns the config paths that the service's schema marks with "secret": true.
+func configSecretPaths(svc *model.Service) ([]string, error) {
+	if svc == nil || !hasConfigSchema(svc.ConfigSchema) {
+		return nil, nil
+	}
+	compiled, err := compileConfigSchema(svc.ConfigSchema)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.FlaggedPaths(configSecretKeyword), nil
+}
+
+// configSecretSealer turns a config submitted by a client into the form that is stored.
+// A value is treated as secret when its path is marked secret in the schema, when it is
+// written as "secret://<value>", or when it is the mask placeholder for a secret that is
+// already stored (meaning "keep the current value").
+type configSecretSealer struct {
+	cipher        *apputils.SecretCipher
+	stored        map[string]interface{}
+	schemaSecrets map[string]bool
+}
+
+// sealConfigSecrets returns the plaintext config (used for schema validation) and the sealed
+// config (secrets encrypted) for input. Unchanged secrets keep their stored ciphertext so that
+// re-submitting the same value does not register as a config change.
+func sealConfigSecrets(cipher *apputils.SecretCipher, input, stored map[string]interface{}, schemaSecrets []string) (plain, sealed datatypes.JSONMap, err error) {
+	if input == nil {
+		return nil, nil, nil
+	}
+	sealer := &configSecretSealer{cipher: cipher, stored: stored, schemaSecrets: make(map[string]bool, len(schemaSecrets))}
+	for _, p := range schemaSecrets {
+		sealer.schemaSecrets[p] = true
+	}
+	plainMap, sealedMap, err := sealer.sealObject(input, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return plainMap, sealedMap, nil
+}
+
+func (s *configSecretSealer) sealObject(in map[string]interface{}, prefix string) (map[string]interface{}, map[string]interface{}, error) {
+	plain := make(map[string]interface{}, len(in))
+	sealed := make(map[string]interface{}, len(in))
+	for key, value := range in {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if s.schemaSecrets[path] {
+				return nil, nil, fmt.Errorf("%w: config key '%s' is secret and must be a string", apputils.ErrBadRequest, path)
+			}
+			p, sv, err := s.sealObject(v, path)
+			if err != nil {
+				return nil, nil, err
+			}
+			plain[key], sealed[key] = p, sv
+		case string:
+			secret, isSecret, err := s.resolveString(path, v)
+			if err != nil {
+				return nil, nil, err
+			}
+			plain[key] = secret
+			sealed[key] = secret
+			if isSecret {
+				if sealed[key], err = s.seal(path, secret); err != nil {
+					return nil, nil, err
+				}
+			}
+		case []interface{}:
+			// Secrets are addressed by dot path, so they cannot live inside arrays
+			if s.schemaSecrets[path] {
+				return nil, nil, fmt.Errorf("%w: config key '%s' is secret and must be a string", apputils.ErrBadRequest, path)
+			}
+			if apputils.ContainsSecretRef(v) {
+				return nil, nil, fmt.Errorf("%w: config key '%s' is an array; secrets inside arrays are not supported", apputils.ErrBadRequest, path)
+			}
+			plain[key], sealed[key] = value, value
+		default:
+			if s.schemaSecrets[path] && value != nil {
+				return nil, nil, fmt.Errorf("%w: config key '%s' is secret and must be a string", apputils.ErrBadRequest, path)
+			}
+			plain[key], sealed[key] = value, value
+		}
+	}
+	return plain, sealed, nil
+}
+
+// resolveString returns the plaintext for a submitted string value and whether it is secret.
+func (s *configSecretSealer) resolveString(path, value string) (string, bool, error) {
+	switch {
+	case value == apputils.SecretMask:
+		stored, ok := apputils.LookupJSONPath(s.stored, path)
+		if !ok || !apputils.IsEncryptedSecret(stored) {
+			return "", false, fmt.Errorf("%w: config key '%s' has no stored secret to keep", apputils.ErrBadRequest, path)
+		}
+		plaintext, err := s.decrypt(stored.(string))
+		if err != nil {
+			return "", false, fmt.Errorf("failed to decrypt stored secret '%s': %w", path, err)
+		}
+		return plaintext, true, nil
+	case strings.HasPrefix(value, apputils.SecretRefPrefix):
+		return strings.TrimPrefix(value, apputils.SecretRefPrefix), true, nil
+	case apputils.IsEncryptedSecret(value):
+		return "", false, fmt.Errorf("%w: config key '%s' cannot be set to an encrypted value directly", apputils.ErrBadRequest, path)
+	default:
+		return value, s.schemaSecrets[path], nil
+	}
+}
+
+// seal encrypts plaintext, reusing the stored ciphertext when the secret has not changed.
+func (s *configSecretSealer) seal(path, plaintext string) (string, error) {
+	if s.cipher == nil {
+		return "", errors.New("config secret encryption is not configured")
+	}
+	if stored, ok := apputils.LookupJSONPath(s.stored, path); ok && apputils.IsEncryptedSecret(stored) {
+		if current, err := s.cipher.Decrypt(stored.(string)); err == nil && current == plaintext {
+			return stored.(string), nil
+		}
+	}
+	ciphertext, err := s.cipher.Encrypt(plaintext)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt config secret '%s': %w", path, err)
+	}
+	return ciphertext, nil
+}
+
+func (s *configSecretSealer) decrypt(value string) (string, error) {
+	if s.cipher == nil {
+		return "", errors.New("config secret encryption is not configured")
+	}
+	return s.cipher.Decrypt(value)
+}
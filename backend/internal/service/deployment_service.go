@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ReportDeploymentInputDTO is the payload CI/CD pipelines send to report a deployment outcome.
+type ReportDeploymentInputDTO struct {
+	Version     string     `json:"version" binding:"required,min=1,max=100"`
+	Status      string     `json:"status" binding:"required,oneof=success failed in_progress rolled_back"`
+	TriggeredBy string     `json:"triggeredBy" binding:"omitempty,max=255"` // CI identity, defaults to the authenticated user
+	Notes       string     `json:"notes"`
+	StartedAt   *time.Time `json:"startedAt"`
+	FinishedAt  *time.Time `json:"finishedAt"`
+}
+
+// ListDeploymentsResponseDTO wraps the paginated list of deployment records.
+type ListDeploymentsResponseDTO struct {
+	Items []*model.Deployment `json:"items"`
+	Total int64               `json:"total"`
+	Page  int                 `json:"page"`
+	Size  int                 `json:"pageSize"`
+}
+
+// DeploymentService defines the interface for deployment history business logic.
+type DeploymentService interface {
+	ReportDeployment(ctx context.Context, instanceID uint, input *ReportDeploymentInputDTO) (*model.Deployment, error)
+	ListInstanceDeployments(ctx context.Context, instanceID uint, params *model.DeploymentListParams) (*ListDeploymentsResponseDTO, error)
+	ListEnvironmentDeployments(ctx context.Context, environmentID uint, params *model.DeploymentListParams) (*ListDeploymentsResponseDTO, error)
+}
+
+// deploymentServiceImpl implements DeploymentService.
+type deploymentServiceImpl struct {
+	repo         repository.DeploymentRepository
+	instanceRepo repository.ServiceInstanceRepository
+	envRepo      repository.EnvironmentRepository
+	logger       *zap.Logger
+}
+
+// NewDeploymentService creates a new DeploymentService.
+func NewDeploymentService(
+	repo repository.DeploymentRepository,
+	instanceRepo repository.ServiceInstanceRepository,
+	envRepo repository.EnvironmentRepository,
+	logger *zap.Logger,
+) DeploymentService {
+	return &deploymentServiceImpl{
+		repo:         repo,
+		instanceRepo: instanceRepo,
+		envRepo:      envRepo,
+		logger:       logger,
+	}
+}
+
+// ReportDeployment records a deployment reported by CI and reflects its outcome on the instance.
+// A successful deployment moves the instance to the reported version; failed and in-progress
+// deployments only change the instance status so the current version stays accurate.
+func (s *deploymentServiceImpl) ReportDeployment(ctx context.Context, instanceID uint, input *ReportDeploymentInputDTO) (*model.Deployment, error) {
+	s.logger.Info("Reporting deployment", zap.Uint("instanceId", instanceID), zap.Any("input", input))
+
+	status := model.DeploymentStatusType(input.Status)
+	if !status.IsValid() {
+		return nil, fmt.Errorf("%w: invalid deployment status '%s'", apputils.ErrBadRequest, input.Status)
+	}
+
+	instance, err := s.instanceRepo.GetByID(ctx, instanceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Warn("Service instance not found for deployment report", zap.Uint("instanceId", instanceID))
+			return nil, apputils.ErrNotFound
+		}
+		s.logger.Error("Failed to get service instance for deployment report", zap.Uint("instanceId", instanceID), zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve service instance: %w", err)
+	}
+
+	now := time.Now()
+	finishedAt := input.FinishedAt
+	if finishedAt == nil && status != model.DeploymentStatusInProgress {
+		finishedAt = &now
+	}
+
+	previousVersion := instance.Version
+	switch status {
+	case model.DeploymentStatusSuccess:
+		if input.Version != instance.Version {
+			exists, err := s.instanceRepo.CheckExists(ctx, instance.ServiceID, instance.EnvironmentID, input.Version, instance.ID)
+			if err != nil {
+				s.logger.Error("Failed to check for existing service instance during deployment report", zap.Error(err))
+				return nil, fmt.Errorf("failed to check for existing instance: %w", err)
+			}
+			if exists {
+				msg := fmt.Sprintf("another instance of service ID %d in environment ID %d already runs version '%s'", instance.ServiceID, instance.EnvironmentID, input.Version)
+				s.logger.Warn(msg)
+				return nil, fmt.Errorf("%w: %s", apputils.ErrAlreadyExists, msg)
+			}
+			instance.Version = input.Version
+		}
+		instance.Status = model.ServiceInstanceStatusRunning
+		instance.DeployedAt = finishedAt
+	case model.DeploymentStatusFailed:
+		instance.Status = model.ServiceInstanceStatusError
+	case model.DeploymentStatusInProgress:
+		instance.Status = model.ServiceInstanceStatusDeploying
+	case model.DeploymentStatusRolledBack:
+		// The reported version was reverted; the instance keeps running its current version.
+		instance.Status = model.ServiceInstanceStatusRunning
+	}
+
+	if err := s.instanceRepo.Update(ctx, instance); err != nil {
+		s.logger.Error("Failed to update service instance for deployment report", zap.Uint("instanceId", instanceID), zap.Error(err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apputils.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to update service instance: %w", err)
+	}
+
+	triggeredBy := input.TriggeredBy
+	if triggeredBy == "" {
+		triggeredBy = apputils.ActorName(ctx)
+	}
+	deployment := &model.Deployment{
+		ServiceInstanceID: instance.ID,
+		ServiceID:         instance.ServiceID,
+		EnvironmentID:     instance.EnvironmentID,
+		Version:           input.Version,
+		PreviousVersion:   previousVersion,
+		Status:            status,
+		Source:            model.DeploymentSourceCI,
+		TriggeredByID:     apputils.ActorUserID(ctx),
+		TriggeredBy:       triggeredBy,
+		Notes:             input.Notes,
+		StartedAt:         input.StartedAt,
+		FinishedAt:        finishedAt,
+	}
+	if err := s.repo.Create(ctx, deployment); err != nil {
+		s.logger.Error("Failed to create deployment record", zap.Uint("instanceId", instanceID), zap.Error(err))
+		return nil, fmt.Errorf("failed to record deployment: %w", err)
+	}
+
+	s.logger.Info("Deployment recorded", zap.Uint("deploymentId", deployment.ID), zap.Uint("instanceId", instanceID), zap.String("status", string(status)))
+	return deployment, nil
+}
+
+// ListInstanceDeployments returns the deployment history of a single service instance.
+func (s *deploymentServiceImpl) ListInstanceDeployments(ctx context.Context, instanceID uint, params *model.DeploymentListParams) (*ListDeploymentsResponseDTO, error) {
+	if _, err := s.instanceRepo.GetByID(ctx, instanceID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apputils.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve service instance: %w", err)
+	}
+	params.ServiceInstanceID = &instanceID
+	return s.list(ctx, params)
+}
+
+// ListEnvironmentDeployments returns the deployment timeline of an environment across all its services.
+func (s *deploymentServiceImpl) ListEnvironmentDeployments(ctx context.Context, environmentID uint, params *model.DeploymentListParams) (*ListDeploymentsResponseDTO, error) {
+	if _, err := s.envRepo.GetByID(ctx, environmentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apputils.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve environment: %w", err)
+	}
+	params.EnvironmentID = &environmentID
+	return s.list(ctx, params)
+}
+
+func (s *deploymentServiceImpl) list(ctx context.Context, params *model.DeploymentListParams) (*ListDeploymentsResponseDTO, error) {
+	if params.Status != "" && !model.DeploymentStatusType(params.Status).IsValid() {
+		return nil, fmt.Errorf("%w: invalid status value '%s' for listing deployments", apputils.ErrBadRequest, params.Status)
+	}
+
+	deployments, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		s.logger.Error("Failed to list deployments from repository", zap.Error(err))
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	return &ListDeploymentsResponseDTO{
+		Items: deployments,
+		Total: total,
+		Page:  params.Page,
+		Size:  params.PageSize,
+	}, nil
+}
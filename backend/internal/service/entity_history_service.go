@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ListEntityVersionsResponseDTO wraps the paginated version history of an entity.
+type ListEntityVersionsResponseDTO struct {
+	Items []*model.EntityVersion `json:"items"`
+	Total int64                  `json:"total"`
+	Page  int                    `json:"page"`
+	Size  int                    `json:"pageSize"`
+}
+
+// EntityHistoryService defines the interface for the version history of CMDB entities.
+type EntityHistoryService interface {
+	// ListVersions returns an entity's versions, newest first.
+	ListVersions(ctx context.Context, resource string, id uint, params *model.EntityHistoryListParams) (*ListEntityVersionsResponseDTO, error)
+	// GetAsOf returns the state of an entity at the given time.
+	GetAsOf(ctx context.Context, resource string, id uint, at time.Time) (*model.EntityVersion, error)
+}
+
+// entityHistoryServiceImpl implements EntityHistoryService.
+type entityHistoryServiceImpl struct {
+	repo   repository.EntityHistoryRepository
+	logger *zap.Logger
+}
+
+// NewEntityHistoryService creates a new EntityHistoryService.
+func NewEntityHistoryService(repo repository.EntityHistoryRepository, logger *zap.Logger) EntityHistoryService {
+	return &entityHistoryServiceImpl{repo: repo, logger: logger}
+}
+
+// ListVersions returns an entity's versions, newest first. An entity that has not changed
+// since history was first kept is listed with its current state as the only (baseline) version.
+func (s *entityHistoryServiceImpl) ListVersions(ctx context.Context, resource string, id uint, params *model.EntityHistoryListParams) (*ListEntityVersionsResponseDTO, error) {
+	versions, total, err := s.repo.List(ctx, resource, id, params)
+	if err != nil {
+		s.logger.Error("Failed to list entity versions", zap.String("resource", resource), zap.Uint("id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to list entity versions: %w", err)
+	}
+	if total == 0 {
+		current, err := s.GetAsOf(ctx, resource, id, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		total = 1
+		if params.Page == 1 {
+			versions = []*model.EntityVersion{current}
+		}
+	}
+	return &ListEntityVersionsResponseDTO{
+		Items: versions,
+		Total: total,
+		Page:  params.Page,
+		Size:  params.PageSize,
+	}, nil
+}
+
+// GetAsOf returns the version of an entity that was current at the given time.
+func (s *entityHistoryServiceImpl) GetAsOf(ctx context.Context, resource string, id uint, at time.Time) (*model.EntityVersion, error) {
+	version, err := s.repo.GetAsOf(ctx, resource, id, at)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s with ID %d did not exist at %s", apputils.ErrNotFound, strings.ToLower(strings.ReplaceAll(resource, "_", " ")), id, at.Format(time.RFC3339))
+		}
+		s.logger.Error("Failed to get entity version", zap.String("resource", resource), zap.Uint("id", id), zap.Time("asOf", at), zap.Error(err))
+		return nil, fmt.Errorf("failed to get entity version: %w", err)
+	}
+	return version, nil
+}
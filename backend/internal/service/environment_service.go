@@ -54,6 +54,10 @@ func (s *environmentServiceImpl) CreateEnvironment(ctx context.Context, req mode
 		return nil, fmt.Errorf("%w: %s", apputils.ErrBadRequest, apputils.FormatValidationError(err))
 	}
 
+	if apputils.HasSecretRefs(req.Config) {
+		return nil, fmt.Errorf("%w: secrets can only be set on service instance configs", apputils.ErrBadRequest)
+	}
+
 	// Check for existing slug
 	_, err := s.repo.GetBySlug(ctx, req.Slug)
 	if err == nil {
@@ -71,6 +75,7 @@ func (s *environmentServiceImpl) CreateEnvironment(ctx context.Context, req mode
 		Name:        req.Name,
 		Description: req.Description,
 		Slug:        req.Slug,
+		Config:      req.Config,
 	}
 
 	createdEnv, err := s.repo.Create(ctx, env)
@@ -185,6 +190,19 @@ func (s *environmentServiceImpl) UpdateEnvironment(ctx context.Context, id uint,
 		}
 	}
 
+	if req.Config != nil {
+		if apputils.HasSecretRefs(*req.Config) {
+			return nil, fmt.Errorf("%w: secrets can only be set on service instance configs", apputils.ErrBadRequest)
+		}
+		if len(apputils.DiffJSON(existingEnv.Config, *req.Config)) > 0 {
+			existingEnv.Config = *req.Config
+			if len(existingEnv.Config) == 0 {
+				existingEnv.Config = nil
+			}
+			updated = true
+		}
+	}
+
 	if !updated {
 		s.logger.Info("Service: No changes detected for environment update", zap.Uint("id", id))
 		resp := existingEnv.ToEnvironmentResponse() // Return current state if no updates
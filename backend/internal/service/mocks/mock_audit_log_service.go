@@ -6,7 +6,9 @@ package mocks
 
 import (
 	model "EffiPlat/backend/internal/model"
+	service "EffiPlat/backend/internal/service"
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	gin "github.com/gin-gonic/gin"
@@ -36,6 +38,20 @@ func (m *MockAuditLogService) EXPECT() *MockAuditLogServiceMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockAuditLogService) Close(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockAuditLogServiceMockRecorder) Close(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAuditLogService)(nil).Close), ctx)
+}
+
 // CreateLog mocks base method.
 func (m *MockAuditLogService) CreateLog(ctx context.Context, log *model.AuditLog) error {
 	m.ctrl.T.Helper()
@@ -50,6 +66,37 @@ func (mr *MockAuditLogServiceMockRecorder) CreateLog(ctx, log interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLog", reflect.TypeOf((*MockAuditLogService)(nil).CreateLog), ctx, log)
 }
 
+// ExportLogs mocks base method.
+func (m *MockAuditLogService) ExportLogs(ctx context.Context, params model.AuditLogQueryParams, format service.AuditExportFormat, w io.Writer) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportLogs", ctx, params, format, w)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportLogs indicates an expected call of ExportLogs.
+func (mr *MockAuditLogServiceMockRecorder) ExportLogs(ctx, params, format, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportLogs", reflect.TypeOf((*MockAuditLogService)(nil).ExportLogs), ctx, params, format, w)
+}
+
+// FindChanges mocks base method.
+func (m *MockAuditLogService) FindChanges(ctx context.Context, params model.AuditLogChangeQueryParams) ([]model.AuditLogChange, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindChanges", ctx, params)
+	ret0, _ := ret[0].([]model.AuditLogChange)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindChanges indicates an expected call of FindChanges.
+func (mr *MockAuditLogServiceMockRecorder) FindChanges(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChanges", reflect.TypeOf((*MockAuditLogService)(nil).FindChanges), ctx, params)
+}
+
 // FindLogByID mocks base method.
 func (m *MockAuditLogService) FindLogByID(ctx context.Context, id uint) (*model.AuditLog, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +112,22 @@ func (mr *MockAuditLogServiceMockRecorder) FindLogByID(ctx, id interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLogByID", reflect.TypeOf((*MockAuditLogService)(nil).FindLogByID), ctx, id)
 }
 
+// FindLogPage mocks base method.
+func (m *MockAuditLogService) FindLogPage(ctx context.Context, params model.AuditLogQueryParams, cursor string) ([]model.AuditLog, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLogPage", ctx, params, cursor)
+	ret0, _ := ret[0].([]model.AuditLog)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindLogPage indicates an expected call of FindLogPage.
+func (mr *MockAuditLogServiceMockRecorder) FindLogPage(ctx, params, cursor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLogPage", reflect.TypeOf((*MockAuditLogService)(nil).FindLogPage), ctx, params, cursor)
+}
+
 // FindLogs mocks base method.
 func (m *MockAuditLogService) FindLogs(ctx context.Context, params model.AuditLogQueryParams) ([]model.AuditLog, int64, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +144,34 @@ func (mr *MockAuditLogServiceMockRecorder) FindLogs(ctx, params interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLogs", reflect.TypeOf((*MockAuditLogService)(nil).FindLogs), ctx, params)
 }
 
+// Flush mocks base method.
+func (m *MockAuditLogService) Flush(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockAuditLogServiceMockRecorder) Flush(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockAuditLogService)(nil).Flush), ctx)
+}
+
+// LogFailedAction mocks base method.
+func (m *MockAuditLogService) LogFailedAction(c *gin.Context, action, resource string, resourceID uint, outcome model.AuditOutcome, reason string, details interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogFailedAction", c, action, resource, resourceID, outcome, reason, details)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogFailedAction indicates an expected call of LogFailedAction.
+func (mr *MockAuditLogServiceMockRecorder) LogFailedAction(c, action, resource, resourceID, outcome, reason, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogFailedAction", reflect.TypeOf((*MockAuditLogService)(nil).LogFailedAction), c, action, resource, resourceID, outcome, reason, details)
+}
+
 // LogUserAction mocks base method.
 func (m *MockAuditLogService) LogUserAction(c *gin.Context, action, resource string, resourceID uint, details interface{}) error {
 	m.ctrl.T.Helper()
@@ -94,3 +185,59 @@ func (mr *MockAuditLogServiceMockRecorder) LogUserAction(c, action, resource, re
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogUserAction", reflect.TypeOf((*MockAuditLogService)(nil).LogUserAction), c, action, resource, resourceID, details)
 }
+
+// OpenStream mocks base method.
+func (m *MockAuditLogService) OpenStream(ctx context.Context, params model.AuditLogQueryParams, lastEventID *uint) (*service.AuditLogStream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenStream", ctx, params, lastEventID)
+	ret0, _ := ret[0].(*service.AuditLogStream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenStream indicates an expected call of OpenStream.
+func (mr *MockAuditLogServiceMockRecorder) OpenStream(ctx, params, lastEventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenStream", reflect.TypeOf((*MockAuditLogService)(nil).OpenStream), ctx, params, lastEventID)
+}
+
+// PipelineStats mocks base method.
+func (m *MockAuditLogService) PipelineStats() model.AuditPipelineStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PipelineStats")
+	ret0, _ := ret[0].(model.AuditPipelineStats)
+	return ret0
+}
+
+// PipelineStats indicates an expected call of PipelineStats.
+func (mr *MockAuditLogServiceMockRecorder) PipelineStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineStats", reflect.TypeOf((*MockAuditLogService)(nil).PipelineStats))
+}
+
+// Record mocks base method.
+func (m *MockAuditLogService) Record(ctx context.Context, entry *model.AuditLog) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Record", ctx, entry)
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockAuditLogServiceMockRecorder) Record(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockAuditLogService)(nil).Record), ctx, entry)
+}
+
+// VerifyChain mocks base method.
+func (m *MockAuditLogService) VerifyChain(ctx context.Context) (*model.AuditChainVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyChain", ctx)
+	ret0, _ := ret[0].(*model.AuditChainVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyChain indicates an expected call of VerifyChain.
+func (mr *MockAuditLogServiceMockRecorder) VerifyChain(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyChain", reflect.TypeOf((*MockAuditLogService)(nil).VerifyChain), ctx)
+}
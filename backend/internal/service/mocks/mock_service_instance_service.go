@@ -71,6 +71,21 @@ func (mr *MockServiceInstanceServiceMockRecorder) DeleteServiceInstance(ctx, id
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceInstance", reflect.TypeOf((*MockServiceInstanceService)(nil).DeleteServiceInstance), ctx, id)
 }
 
+// GetEffectiveConfig mocks base method.
+func (m *MockServiceInstanceService) GetEffectiveConfig(ctx context.Context, id uint) (*service.EffectiveConfigDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEffectiveConfig", ctx, id)
+	ret0, _ := ret[0].(*service.EffectiveConfigDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEffectiveConfig indicates an expected call of GetEffectiveConfig.
+func (mr *MockServiceInstanceServiceMockRecorder) GetEffectiveConfig(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEffectiveConfig", reflect.TypeOf((*MockServiceInstanceService)(nil).GetEffectiveConfig), ctx, id)
+}
+
 // GetServiceInstanceByID mocks base method.
 func (m *MockServiceInstanceService) GetServiceInstanceByID(ctx context.Context, id uint) (*service.ServiceInstanceOutputDTO, error) {
 	m.ctrl.T.Helper()
@@ -101,6 +116,21 @@ func (mr *MockServiceInstanceServiceMockRecorder) ListServiceInstances(ctx, para
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceInstances", reflect.TypeOf((*MockServiceInstanceService)(nil).ListServiceInstances), ctx, params)
 }
 
+// RevealConfigSecrets mocks base method.
+func (m *MockServiceInstanceService) RevealConfigSecrets(ctx context.Context, id uint, keys []string) (*service.RevealedConfigSecretsDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevealConfigSecrets", ctx, id, keys)
+	ret0, _ := ret[0].(*service.RevealedConfigSecretsDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevealConfigSecrets indicates an expected call of RevealConfigSecrets.
+func (mr *MockServiceInstanceServiceMockRecorder) RevealConfigSecrets(ctx, id, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevealConfigSecrets", reflect.TypeOf((*MockServiceInstanceService)(nil).RevealConfigSecrets), ctx, id, keys)
+}
+
 // UpdateServiceInstance mocks base method.
 func (m *MockServiceInstanceService) UpdateServiceInstance(ctx context.Context, id uint, input *service.ServiceInstanceInputDTO) (*service.ServiceInstanceOutputDTO, error) {
 	m.ctrl.T.Helper()
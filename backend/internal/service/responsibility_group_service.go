@@ -21,6 +21,9 @@ type ResponsibilityGroupService interface {
 	DeleteResponsibilityGroup(ctx context.Context, id uint) error
 	AddResponsibilityToGroup(ctx context.Context, groupID uint, responsibilityID uint) error
 	RemoveResponsibilityFromGroup(ctx context.Context, groupID uint, responsibilityID uint) error
+	ListGroupMembers(ctx context.Context, groupID uint) ([]model.ResponsibilityGroupMember, error)
+	SetGroupMember(ctx context.Context, groupID uint, userID uint, isPrimary bool, isBackup bool) (*model.ResponsibilityGroupMember, error)
+	RemoveGroupMember(ctx context.Context, groupID uint, userID uint) error
 }
 
 type responsibilityGroupServiceImpl struct {
@@ -147,3 +150,50 @@ func (s *responsibilityGroupServiceImpl) RemoveResponsibilityFromGroup(ctx conte
 	}
 	return nil
 }
+
+func (s *responsibilityGroupServiceImpl) ListGroupMembers(ctx context.Context, groupID uint) ([]model.ResponsibilityGroupMember, error) {
+	s.logger.Info("Service: Listing members of group", zap.Uint("groupID", groupID))
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("responsibility group with id %d not found: %w", groupID, utils.ErrNotFound)
+		}
+		return nil, err
+	}
+	return s.groupRepo.ListMembers(ctx, groupID)
+}
+
+// SetGroupMember adds a user to a group, or updates their primary and backup flags if they already belong to it.
+func (s *responsibilityGroupServiceImpl) SetGroupMember(ctx context.Context, groupID uint, userID uint, isPrimary bool, isBackup bool) (*model.ResponsibilityGroupMember, error) {
+	s.logger.Info("Service: Setting group member", zap.Uint("groupID", groupID), zap.Uint("userID", userID), zap.Bool("isPrimary", isPrimary), zap.Bool("isBackup", isBackup))
+	if isPrimary && isBackup {
+		return nil, fmt.Errorf("a member cannot be both primary and backup: %w", utils.ErrBadRequest)
+	}
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("responsibility group with id %d not found: %w", groupID, utils.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	member := &model.ResponsibilityGroupMember{GroupID: groupID, UserID: userID, IsPrimary: isPrimary, IsBackup: isBackup}
+	if err := s.groupRepo.SetMember(ctx, member); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user with id %d not found: %w", userID, utils.ErrNotFound)
+		}
+		s.logger.Error("Service: Failed to set group member", zap.Error(err))
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *responsibilityGroupServiceImpl) RemoveGroupMember(ctx context.Context, groupID uint, userID uint) error {
+	s.logger.Info("Service: Removing group member", zap.Uint("groupID", groupID), zap.Uint("userID", userID))
+	if err := s.groupRepo.RemoveMember(ctx, groupID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("user %d is not a member of group %d: %w", userID, groupID, utils.ErrNotFound)
+		}
+		s.logger.Error("Service: Failed to remove group member", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -25,7 +25,8 @@ type ServiceInstanceInputDTO struct {
 	Status        string            `json:"status" binding:"required,oneof=running stopped deploying error unknown"`
 	Hostname      *string           `json:"hostname" binding:"omitempty,max=255"`
 	Port          *int              `json:"port" binding:"omitempty,min=1,max=65535"`
-	Config        datatypes.JSONMap `json:"config"` // No specific binding here, handled as raw JSON
+	Config        datatypes.JSONMap `json:"config"`                                     // No specific binding here, handled as raw JSON
+	ConfigComment string            `json:"configComment" binding:"omitempty,max=1000"` // Stored on the config revision when Config changes
 	DeployedAt    *time.Time        `json:"deployedAt"`
 }
 
@@ -45,6 +46,25 @@ type ServiceInstanceOutputDTO struct {
 	UpdatedAt     time.Time         `json:"updatedAt"`
 }
 
+// RevealConfigSecretsInputDTO selects which secret config keys to reveal. An empty list reveals all of them.
+type RevealConfigSecretsInputDTO struct {
+	Keys []string `json:"keys"`
+}
+
+// RevealedConfigSecretsDTO holds decrypted secret config values keyed by their dot path.
+type RevealedConfigSecretsDTO struct {
+	ServiceInstanceID uint              `json:"serviceInstanceId"`
+	Secrets           map[string]string `json:"secrets"`
+}
+
+// EffectiveConfigDTO is the merged config an instance actually runs with.
+// Provenance maps each leaf key (dot path) to the layer that set it: service, environment or instance.
+type EffectiveConfigDTO struct {
+	ServiceInstanceID uint                   `json:"serviceInstanceId"`
+	Config            map[string]interface{} `json:"config"`
+	Provenance        map[string]string      `json:"provenance"`
+}
+
 // ListServiceInstancesResponseDTO wraps the paginated list of service instances.
 type ListServiceInstancesResponseDTO struct {
 	Items []*ServiceInstanceOutputDTO `json:"items"`
@@ -62,13 +82,19 @@ type ServiceInstanceService interface {
 	ListServiceInstances(ctx context.Context, params *repository.ListServiceInstancesParams) (*ListServiceInstancesResponseDTO, error)
 	UpdateServiceInstance(ctx context.Context, id uint, input *ServiceInstanceInputDTO) (*ServiceInstanceOutputDTO, error)
 	DeleteServiceInstance(ctx context.Context, id uint) error
+	RevealConfigSecrets(ctx context.Context, id uint, keys []string) (*RevealedConfigSecretsDTO, error)
+	GetEffectiveConfig(ctx context.Context, id uint) (*EffectiveConfigDTO, error)
 }
 
 // serviceInstanceServiceImpl implements ServiceInstanceService.
 type serviceInstanceServiceImpl struct {
 	repo        repository.ServiceInstanceRepository
-	serviceRepo repository.ServiceRepository     // For validating ServiceID
-	envRepo     repository.EnvironmentRepository // For validating EnvironmentID
+	serviceRepo repository.ServiceRepository        // For validating ServiceID
+	envRepo     repository.EnvironmentRepository    // For validating EnvironmentID
+	deployRepo  repository.DeploymentRepository     // For recording version history
+	configRepo  repository.ConfigRevisionRepository // For recording config history
+	permRepo    repository.PermissionRepository     // For gating secret reveal
+	cipher      *apputils.SecretCipher              // For encrypting secret config values
 	logger      *zap.Logger
 }
 
@@ -77,12 +103,20 @@ func NewServiceInstanceService(
 	repo repository.ServiceInstanceRepository,
 	serviceRepo repository.ServiceRepository,
 	envRepo repository.EnvironmentRepository,
+	deployRepo repository.DeploymentRepository,
+	configRepo repository.ConfigRevisionRepository,
+	permRepo repository.PermissionRepository,
+	cipher *apputils.SecretCipher,
 	logger *zap.Logger,
 ) ServiceInstanceService {
 	return &serviceInstanceServiceImpl{
 		repo:        repo,
 		serviceRepo: serviceRepo,
 		envRepo:     envRepo,
+		deployRepo:  deployRepo,
+		configRepo:  configRepo,
+		permRepo:    permRepo,
+		cipher:      cipher,
 		logger:      logger,
 	}
 }
@@ -99,7 +133,7 @@ func convertModelToOutputDTO(instance *model.ServiceInstance) *ServiceInstanceOu
 		Status:        string(instance.Status),
 		Hostname:      instance.Hostname,
 		Port:          instance.Port,
-		Config:        instance.Config,
+		Config:        apputils.MaskSecrets(instance.Config),
 		DeployedAt:    instance.DeployedAt,
 		CreatedAt:     instance.CreatedAt,
 		UpdatedAt:     instance.UpdatedAt,
@@ -116,12 +150,12 @@ func convertModelsToOutputDTOs(instances []*model.ServiceInstance) []*ServiceIns
 
 // CreateServiceInstance creates a new service instance.
 func (s *serviceInstanceServiceImpl) CreateServiceInstance(ctx context.Context, input *ServiceInstanceInputDTO) (*ServiceInstanceOutputDTO, error) {
-	s.logger.Info("Attempting to create service instance", zap.Any("input", input))
+	s.logger.Info("Attempting to create service instance", zap.Uint("serviceId", input.ServiceID), zap.Uint("environmentId", input.EnvironmentID), zap.String("version", input.Version))
 
 	// Validate ServiceID
-	_, err := s.serviceRepo.GetByID(ctx, input.ServiceID)
+	svc, err := s.serviceRepo.GetByID(ctx, input.ServiceID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, model.ErrServiceNotFound) {
 			s.logger.Warn("Service not found during instance creation", zap.Uint("serviceId", input.ServiceID))
 			return nil, fmt.Errorf("%w: service with ID %d not found", apputils.ErrBadRequest, input.ServiceID)
 		}
@@ -130,7 +164,7 @@ func (s *serviceInstanceServiceImpl) CreateServiceInstance(ctx context.Context,
 	}
 
 	// Validate EnvironmentID
-	_, err = s.envRepo.GetByID(ctx, input.EnvironmentID)
+	env, err := s.envRepo.GetByID(ctx, input.EnvironmentID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			s.logger.Warn("Environment not found during instance creation", zap.Uint("environmentId", input.EnvironmentID))
@@ -140,6 +174,16 @@ func (s *serviceInstanceServiceImpl) CreateServiceInstance(ctx context.Context,
 		return nil, fmt.Errorf("failed to validate environment: %w", err)
 	}
 
+	// Encrypt secret values, then validate the effective plaintext config against the service's config schema
+	plainConfig, sealedConfig, err := sealConfig(s.logger, s.cipher, svc, input.Config, nil)
+	if err != nil {
+		return nil, err
+	}
+	effective, _ := effectiveConfig(svc, env, plainConfig)
+	if err := validateConfig(s.logger, svc, effective); err != nil {
+		return nil, err
+	}
+
 	// Check for existing instance
 	exists, err := s.repo.CheckExists(ctx, input.ServiceID, input.EnvironmentID, input.Version, 0)
 	if err != nil {
@@ -159,7 +203,7 @@ func (s *serviceInstanceServiceImpl) CreateServiceInstance(ctx context.Context,
 		Status:        model.ServiceInstanceStatusType(input.Status),
 		Hostname:      input.Hostname,
 		Port:          input.Port,
-		Config:        input.Config,
+		Config:        instanceConfigOverrides(svc, env, sealedConfig),
 		DeployedAt:    input.DeployedAt,
 	}
 
@@ -168,6 +212,9 @@ func (s *serviceInstanceServiceImpl) CreateServiceInstance(ctx context.Context,
 		return nil, fmt.Errorf("failed to create service instance: %w", err)
 	}
 
+	s.recordDeployment(ctx, instance, "")
+	s.recordConfigRevision(ctx, instance, input.ConfigComment)
+
 	s.logger.Info("Service instance created successfully", zap.Uint("instanceId", instance.ID))
 	return convertModelToOutputDTO(instance), nil
 }
@@ -215,7 +262,7 @@ func (s *serviceInstanceServiceImpl) ListServiceInstances(ctx context.Context, p
 
 // UpdateServiceInstance updates an existing service instance.
 func (s *serviceInstanceServiceImpl) UpdateServiceInstance(ctx context.Context, id uint, input *ServiceInstanceInputDTO) (*ServiceInstanceOutputDTO, error) {
-	s.logger.Info("Updating service instance", zap.Uint("id", id), zap.Any("input", input))
+	s.logger.Info("Updating service instance", zap.Uint("id", id), zap.String("version", input.Version), zap.String("status", input.Status))
 
 	instance, err := s.repo.GetByID(ctx, id)
 	if err != nil {
@@ -237,6 +284,7 @@ func (s *serviceInstanceServiceImpl) UpdateServiceInstance(ctx context.Context,
 		return nil, fmt.Errorf("%w: environmentId cannot be changed after creation", apputils.ErrBadRequest)
 	}
 
+	previousVersion := instance.Version
 	if input.Version != instance.Version {
 		exists, err := s.repo.CheckExists(ctx, instance.ServiceID, instance.EnvironmentID, input.Version, id)
 		if err != nil {
@@ -254,7 +302,29 @@ func (s *serviceInstanceServiceImpl) UpdateServiceInstance(ctx context.Context,
 	instance.Status = model.ServiceInstanceStatusType(input.Status)
 	instance.Hostname = input.Hostname
 	instance.Port = input.Port
-	instance.Config = input.Config
+	svc, err := s.serviceRepo.GetByID(ctx, instance.ServiceID)
+	if err != nil {
+		s.logger.Error("Failed to get service for config validation", zap.Uint("serviceId", instance.ServiceID), zap.Error(err))
+		return nil, fmt.Errorf("failed to load service for config validation: %w", err)
+	}
+	env, err := s.envRepo.GetByID(ctx, instance.EnvironmentID)
+	if err != nil {
+		s.logger.Error("Failed to get environment for config inheritance", zap.Uint("environmentId", instance.EnvironmentID), zap.Error(err))
+		return nil, fmt.Errorf("failed to load environment for config inheritance: %w", err)
+	}
+	plainConfig, sealedConfig, err := sealConfig(s.logger, s.cipher, svc, input.Config, instance.Config)
+	if err != nil {
+		return nil, err
+	}
+	overrides := instanceConfigOverrides(svc, env, sealedConfig)
+	configChanged := input.Config != nil && len(apputils.DiffJSON(instance.Config, overrides)) > 0
+	if configChanged {
+		effective, _ := effectiveConfig(svc, env, plainConfig)
+		if err := validateConfig(s.logger, svc, effective); err != nil {
+			return nil, err
+		}
+		instance.Config = overrides
+	}
 	instance.DeployedAt = input.DeployedAt
 
 	if err := s.repo.Update(ctx, instance); err != nil {
@@ -265,10 +335,170 @@ func (s *serviceInstanceServiceImpl) UpdateServiceInstance(ctx context.Context,
 		return nil, fmt.Errorf("failed to update service instance: %w", err)
 	}
 
+	if instance.Version != previousVersion {
+		s.recordDeployment(ctx, instance, previousVersion)
+	}
+	if configChanged {
+		s.recordConfigRevision(ctx, instance, input.ConfigComment)
+	}
+
 	s.logger.Info("Service instance updated successfully", zap.Uint("id", instance.ID))
 	return convertModelToOutputDTO(instance), nil
 }
 
+// sealConfig encrypts the secret values of a submitted config; see sealConfigSecrets.
+func sealConfig(logger *zap.Logger, cipher *apputils.SecretCipher, svc *model.Service, input, stored map[string]interface{}) (plain, sealed datatypes.JSONMap, err error) {
+	secretPaths, err := configSecretPaths(svc)
+	if err != nil {
+		logger.Error("Service has an invalid config schema", zap.Uint("serviceId", svc.ID), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to read config schema: %w", err)
+	}
+	plain, sealed, err = sealConfigSecrets(cipher, input, stored, secretPaths)
+	if err != nil && !errors.Is(err, apputils.ErrBadRequest) {
+		logger.Error("Failed to encrypt config secrets", zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to encrypt config secrets: %w", err)
+	}
+	return plain, sealed, err
+}
+
+// GetEffectiveConfig merges the service's default config, the environment's overrides and the
+// instance's own config, and reports which layer each key came from. Secrets stay masked.
+func (s *serviceInstanceServiceImpl) GetEffectiveConfig(ctx context.Context, id uint) (*EffectiveConfigDTO, error) {
+	instance, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apputils.ErrNotFound
+		}
+		s.logger.Error("Failed to get service instance for effective config", zap.Uint("id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get service instance: %w", err)
+	}
+	svc, err := s.serviceRepo.GetByID(ctx, instance.ServiceID)
+	if err != nil {
+		s.logger.Error("Failed to get service for effective config", zap.Uint("serviceId", instance.ServiceID), zap.Error(err))
+		return nil, fmt.Errorf("failed to load service: %w", err)
+	}
+	env, err := s.envRepo.GetByID(ctx, instance.EnvironmentID)
+	if err != nil {
+		s.logger.Error("Failed to get environment for effective config", zap.Uint("environmentId", instance.EnvironmentID), zap.Error(err))
+		return nil, fmt.Errorf("failed to load environment: %w", err)
+	}
+
+	merged, provenance := effectiveConfig(svc, env, instance.Config)
+	return &EffectiveConfigDTO{
+		ServiceInstanceID: instance.ID,
+		Config:            apputils.MaskSecrets(merged),
+		Provenance:        provenance,
+	}, nil
+}
+
+// RevealConfigSecrets decrypts secret config values of an instance. The caller must hold
+// the model.PermissionRevealConfigSecrets permission; asking for a key that is not a
+// stored secret is a bad request.
+func (s *serviceInstanceServiceImpl) RevealConfigSecrets(ctx context.Context, id uint, keys []string) (*RevealedConfigSecretsDTO, error) {
+	userID := apputils.ActorUserID(ctx)
+	if userID == nil {
+		return nil, apputils.ErrUnauthorized
+	}
+	allowed, err := s.permRepo.UserHasPermission(ctx, *userID, model.PermissionRevealConfigSecrets)
+	if err != nil {
+		s.logger.Error("Failed to check secret reveal permission", zap.Uint("userId", *userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to check permission: %w", err)
+	}
+	if !allowed {
+		s.logger.Warn("User is not allowed to reveal config secrets", zap.Uint("userId", *userID), zap.Uint("instanceId", id))
+		return nil, fmt.Errorf("%w: missing permission '%s'", apputils.ErrForbidden, model.PermissionRevealConfigSecrets)
+	}
+
+	instance, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apputils.ErrNotFound
+		}
+		s.logger.Error("Failed to get service instance for secret reveal", zap.Uint("id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get service instance: %w", err)
+	}
+
+	if len(keys) == 0 {
+		keys = apputils.SecretPaths(instance.Config)
+	}
+	secrets := make(map[string]string, len(keys))
+	for _, key := range keys {
+		value, ok := apputils.LookupJSONPath(instance.Config, key)
+		if !ok || !apputils.IsEncryptedSecret(value) {
+			return nil, fmt.Errorf("%w: config key '%s' is not a secret", apputils.ErrBadRequest, key)
+		}
+		if s.cipher == nil {
+			return nil, errors.New("config secret encryption is not configured")
+		}
+		plaintext, err := s.cipher.Decrypt(value.(string))
+		if err != nil {
+			s.logger.Error("Failed to decrypt config secret", zap.Uint("instanceId", id), zap.String("key", key), zap.Error(err))
+			return nil, fmt.Errorf("failed to decrypt config secret '%s': %w", key, err)
+		}
+		secrets[key] = plaintext
+	}
+
+	s.logger.Info("Config secrets revealed", zap.Uint("instanceId", id), zap.Uint("userId", *userID), zap.Strings("keys", keys))
+	return &RevealedConfigSecretsDTO{ServiceInstanceID: id, Secrets: secrets}, nil
+}
+
+// validateConfig checks config against the service's JSON Schema, if it has one.
+func validateConfig(logger *zap.Logger, svc *model.Service, config map[string]interface{}) error {
+	if svc == nil {
+		return nil
+	}
+	fieldErrors, err := validateConfigAgainstSchema(svc.ConfigSchema, config)
+	if err != nil {
+		// A stored schema that no longer compiles is a server-side problem, not the caller's.
+		logger.Error("Service has an invalid config schema", zap.Uint("serviceId", svc.ID), zap.Error(err))
+		return fmt.Errorf("failed to validate config: %w", err)
+	}
+	if len(fieldErrors) > 0 {
+		logger.Warn("Service instance config failed schema validation", zap.Uint("serviceId", svc.ID), zap.Any("errors", fieldErrors))
+		return &ConfigValidationError{Errors: fieldErrors}
+	}
+	return nil
+}
+
+// recordDeployment appends a manual deployment record for the instance's current version.
+// The instance change has already been persisted, so a failure here is logged rather than returned.
+func (s *serviceInstanceServiceImpl) recordDeployment(ctx context.Context, instance *model.ServiceInstance, previousVersion string) {
+	now := time.Now()
+	deployment := &model.Deployment{
+		ServiceInstanceID: instance.ID,
+		ServiceID:         instance.ServiceID,
+		EnvironmentID:     instance.EnvironmentID,
+		Version:           instance.Version,
+		PreviousVersion:   previousVersion,
+		Status:            model.DeploymentStatusSuccess,
+		Source:            model.DeploymentSourceManual,
+		TriggeredByID:     apputils.ActorUserID(ctx),
+		TriggeredBy:       apputils.ActorName(ctx),
+		FinishedAt:        &now,
+	}
+	if instance.DeployedAt != nil {
+		deployment.FinishedAt = instance.DeployedAt
+	}
+	if err := s.deployRepo.Create(ctx, deployment); err != nil {
+		s.logger.Error("Failed to record deployment for service instance", zap.Uint("instanceId", instance.ID), zap.String("version", instance.Version), zap.Error(err))
+	}
+}
+
+// recordConfigRevision appends an immutable revision holding the instance's current config.
+// Like recordDeployment, a failure is logged because the instance itself is already saved.
+func (s *serviceInstanceServiceImpl) recordConfigRevision(ctx context.Context, instance *model.ServiceInstance, comment string) {
+	revision := &model.ConfigRevision{
+		ServiceInstanceID: instance.ID,
+		Config:            instance.Config,
+		AuthorID:          apputils.ActorUserID(ctx),
+		Author:            apputils.ActorName(ctx),
+		Comment:           comment,
+	}
+	if err := s.configRepo.Create(ctx, revision); err != nil {
+		s.logger.Error("Failed to record config revision for service instance", zap.Uint("instanceId", instance.ID), zap.Error(err))
+	}
+}
+
 // DeleteServiceInstance deletes a service instance by its ID.
 func (s *serviceInstanceServiceImpl) DeleteServiceInstance(ctx context.Context, id uint) error {
 	s.logger.Info("Deleting service instance", zap.Uint("id", id))
@@ -25,9 +25,13 @@ func newTestServiceInstanceServiceWithMocks(t *testing.T, ctrl *gomock.Controlle
 	mockInstanceRepo := mock_repository.NewMockServiceInstanceRepository(ctrl)
 	mockServiceRepo := mock_repository.NewMockServiceRepository(ctrl)
 	mockEnvRepo := mock_repository.NewMockEnvironmentRepository(ctrl)
+	mockDeployRepo := mock_repository.NewMockDeploymentRepository(ctrl)
+	mockDeployRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockConfigRepo := mock_repository.NewMockConfigRevisionRepository(ctrl)
+	mockConfigRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	testLogger := zap.NewNop()
 
-	svc := NewServiceInstanceService(mockInstanceRepo, mockServiceRepo, mockEnvRepo, testLogger)
+	svc := NewServiceInstanceService(mockInstanceRepo, mockServiceRepo, mockEnvRepo, mockDeployRepo, mockConfigRepo, mock_repository.NewMockPermissionRepository(ctrl), newTestSecretCipher(t), testLogger)
 	return svc, mockInstanceRepo, mockServiceRepo, mockEnvRepo
 }
 
@@ -186,5 +190,244 @@ func TestServiceInstanceServiceImpl_CreateServiceInstance(t *testing.T) {
 	})
 }
 
-// TODO: Add tests for GetServiceInstanceByID, ListServiceInstances, UpdateServiceInstance, DeleteServiceInstance
+func TestServiceInstanceServiceImpl_UpdateServiceInstance_RecordsDeployment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstanceRepo := mock_repository.NewMockServiceInstanceRepository(ctrl)
+	mockDeployRepo := mock_repository.NewMockDeploymentRepository(ctrl)
+	mockServiceRepo := mock_repository.NewMockServiceRepository(ctrl)
+	mockEnvRepo := mock_repository.NewMockEnvironmentRepository(ctrl)
+	svc := NewServiceInstanceService(mockInstanceRepo, mockServiceRepo, mockEnvRepo, mockDeployRepo, mock_repository.NewMockConfigRevisionRepository(ctrl), mock_repository.NewMockPermissionRepository(ctrl), newTestSecretCipher(t), zap.NewNop())
+
+	ctx := utils.WithActor(context.Background(), utils.Actor{UserID: 7, Username: "deployer"})
+	existing := &model.ServiceInstance{ID: 5, ServiceID: 1, EnvironmentID: 2, Version: "1.0.0", Status: model.ServiceInstanceStatusRunning}
+	inputDTO := &ServiceInstanceInputDTO{ServiceID: 1, EnvironmentID: 2, Version: "1.1.0", Status: string(model.ServiceInstanceStatusRunning)}
+
+	mockInstanceRepo.EXPECT().GetByID(ctx, uint(5)).Return(existing, nil)
+	mockInstanceRepo.EXPECT().CheckExists(ctx, uint(1), uint(2), "1.1.0", uint(5)).Return(false, nil)
+	mockServiceRepo.EXPECT().GetByID(ctx, uint(1)).Return(&model.Service{ID: 1}, nil).Times(2)
+	mockEnvRepo.EXPECT().GetByID(ctx, uint(2)).Return(&model.Environment{ID: 2}, nil).Times(2)
+	mockInstanceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	mockDeployRepo.EXPECT().Create(ctx, gomock.AssignableToTypeOf(&model.Deployment{})).
+		DoAndReturn(func(_ context.Context, d *model.Deployment) error {
+			assert.Equal(t, uint(5), d.ServiceInstanceID)
+			assert.Equal(t, uint(2), d.EnvironmentID)
+			assert.Equal(t, "1.1.0", d.Version)
+			assert.Equal(t, "1.0.0", d.PreviousVersion)
+			assert.Equal(t, model.DeploymentStatusSuccess, d.Status)
+			if assert.NotNil(t, d.TriggeredByID) {
+				assert.Equal(t, uint(7), *d.TriggeredByID)
+			}
+			assert.Equal(t, "deployer", d.TriggeredBy)
+			return nil
+		})
+
+	outputDTO, err := svc.UpdateServiceInstance(ctx, 5, inputDTO)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.0", outputDTO.Version)
+
+	// Updating without a version change must not add another record.
+	mockInstanceRepo.EXPECT().GetByID(ctx, uint(5)).Return(existing, nil)
+	mockInstanceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	_, err = svc.UpdateServiceInstance(ctx, 5, inputDTO)
+	assert.NoError(t, err)
+}
+
+func newTestSecretCipher(t *testing.T) *utils.SecretCipher {
+	cipher, err := utils.NewSecretCipher([]byte("service-instance-test-key"))
+	assert.NoError(t, err)
+	return cipher
+}
+
+func TestServiceInstanceServiceImpl_ConfigSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cipher := newTestSecretCipher(t)
+	mockInstanceRepo := mock_repository.NewMockServiceInstanceRepository(ctrl)
+	mockServiceRepo := mock_repository.NewMockServiceRepository(ctrl)
+	mockEnvRepo := mock_repository.NewMockEnvironmentRepository(ctrl)
+	mockDeployRepo := mock_repository.NewMockDeploymentRepository(ctrl)
+	mockDeployRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockConfigRepo := mock_repository.NewMockConfigRevisionRepository(ctrl)
+	mockPermRepo := mock_repository.NewMockPermissionRepository(ctrl)
+	svc := NewServiceInstanceService(mockInstanceRepo, mockServiceRepo, mockEnvRepo, mockDeployRepo, mockConfigRepo, mockPermRepo, cipher, zap.NewNop())
+
+	ctx := utils.WithActor(context.Background(), utils.Actor{UserID: 3, Username: "ops"})
+	mockEnvRepo.EXPECT().GetByID(ctx, uint(2)).Return(&model.Environment{ID: 2}, nil).AnyTimes()
+	schemaService := &model.Service{ID: 1, ConfigSchema: datatypes.JSON(`{"properties": {"db": {"properties": {"password": {"type": "string", "minLength": 4, "secret": true}}}}}`)}
+
+	var stored *model.ServiceInstance
+	t.Run("Create encrypts schema and secret:// values", func(t *testing.T) {
+		input := &ServiceInstanceInputDTO{
+			ServiceID: 1, EnvironmentID: 2, Version: "1.0.0", Status: string(model.ServiceInstanceStatusRunning),
+			Config: datatypes.JSONMap{
+				"db":     map[string]interface{}{"password": "hunter22", "host": "db"},
+				"apiKey": "secret://abc123",
+			},
+		}
+		mockServiceRepo.EXPECT().GetByID(ctx, uint(1)).Return(schemaService, nil)
+		mockInstanceRepo.EXPECT().CheckExists(ctx, uint(1), uint(2), "1.0.0", uint(0)).Return(false, nil)
+		mockInstanceRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, inst *model.ServiceInstance) error {
+			inst.ID = 9
+			stored = inst
+			return nil
+		})
+		mockConfigRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rev *model.ConfigRevision) error {
+			assert.True(t, utils.IsEncryptedSecret(rev.Config["apiKey"]))
+			return nil
+		})
+
+		out, err := svc.CreateServiceInstance(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, utils.SecretMask, out.Config["apiKey"])
+		assert.Equal(t, map[string]interface{}{"password": utils.SecretMask, "host": "db"}, out.Config["db"])
+
+		assert.Equal(t, []string{"apiKey", "db.password"}, utils.SecretPaths(stored.Config))
+		plaintext, err := cipher.Decrypt(stored.Config["apiKey"].(string))
+		assert.NoError(t, err)
+		assert.Equal(t, "abc123", plaintext)
+	})
+
+	t.Run("Schema validation sees the plaintext", func(t *testing.T) {
+		input := &ServiceInstanceInputDTO{
+			ServiceID: 1, EnvironmentID: 2, Version: "1.0.1", Status: string(model.ServiceInstanceStatusRunning),
+			Config: datatypes.JSONMap{"db": map[string]interface{}{"password": "abc"}},
+		}
+		mockServiceRepo.EXPECT().GetByID(ctx, uint(1)).Return(schemaService, nil)
+		_, err := svc.CreateServiceInstance(ctx, input)
+		var cfgErr *ConfigValidationError
+		assert.ErrorAs(t, err, &cfgErr)
+	})
+
+	t.Run("Update with masked values keeps stored secrets", func(t *testing.T) {
+		before := stored.Config["apiKey"]
+		input := &ServiceInstanceInputDTO{
+			ServiceID: 1, EnvironmentID: 2, Version: "1.0.0", Status: string(model.ServiceInstanceStatusRunning),
+			Config: datatypes.JSONMap{
+				"db":     map[string]interface{}{"password": utils.SecretMask, "host": "db"},
+				"apiKey": utils.SecretMask,
+			},
+		}
+		mockInstanceRepo.EXPECT().GetByID(ctx, uint(9)).Return(stored, nil)
+		mockServiceRepo.EXPECT().GetByID(ctx, uint(1)).Return(schemaService, nil)
+		mockInstanceRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		// No config revision is recorded: nothing changed.
+
+		_, err := svc.UpdateServiceInstance(ctx, 9, input)
+		assert.NoError(t, err)
+		assert.Equal(t, before, stored.Config["apiKey"])
+	})
+
+	t.Run("Mask without a stored secret is rejected", func(t *testing.T) {
+		input := &ServiceInstanceInputDTO{
+			ServiceID: 1, EnvironmentID: 2, Version: "1.0.0", Status: string(model.ServiceInstanceStatusRunning),
+			Config: datatypes.JSONMap{"token": utils.SecretMask},
+		}
+		mockInstanceRepo.EXPECT().GetByID(ctx, uint(9)).Return(stored, nil)
+		mockServiceRepo.EXPECT().GetByID(ctx, uint(1)).Return(schemaService, nil)
+		_, err := svc.UpdateServiceInstance(ctx, 9, input)
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+
+	t.Run("Secrets inside arrays are rejected", func(t *testing.T) {
+		input := &ServiceInstanceInputDTO{
+			ServiceID: 1, EnvironmentID: 2, Version: "1.0.0", Status: string(model.ServiceInstanceStatusRunning),
+			Config: datatypes.JSONMap{"replicas": []interface{}{map[string]interface{}{"password": "secret://x"}}},
+		}
+		mockInstanceRepo.EXPECT().GetByID(ctx, uint(9)).Return(stored, nil)
+		mockServiceRepo.EXPECT().GetByID(ctx, uint(1)).Return(schemaService, nil)
+		_, err := svc.UpdateServiceInstance(ctx, 9, input)
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+
+	t.Run("Reveal requires permission", func(t *testing.T) {
+		mockPermRepo.EXPECT().UserHasPermission(ctx, uint(3), model.PermissionRevealConfigSecrets).Return(false, nil)
+		_, err := svc.RevealConfigSecrets(ctx, 9, nil)
+		assert.ErrorIs(t, err, utils.ErrForbidden)
+
+		_, err = svc.RevealConfigSecrets(context.Background(), 9, nil)
+		assert.ErrorIs(t, err, utils.ErrUnauthorized)
+	})
+
+	t.Run("Reveal decrypts requested keys", func(t *testing.T) {
+		mockPermRepo.EXPECT().UserHasPermission(ctx, uint(3), model.PermissionRevealConfigSecrets).Return(true, nil).Times(3)
+		mockInstanceRepo.EXPECT().GetByID(ctx, uint(9)).Return(stored, nil).Times(3)
+
+		revealed, err := svc.RevealConfigSecrets(ctx, 9, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"apiKey": "abc123", "db.password": "hunter22"}, revealed.Secrets)
+
+		revealed, err = svc.RevealConfigSecrets(ctx, 9, []string{"db.password"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"db.password": "hunter22"}, revealed.Secrets)
+
+		_, err = svc.RevealConfigSecrets(ctx, 9, []string{"db.host"})
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+}
+
+func TestServiceInstanceServiceImpl_LayeredConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc, mockInstanceRepo, mockServiceRepo, mockEnvRepo := newTestServiceInstanceServiceWithMocks(t, ctrl)
+	ctx := context.Background()
+
+	service := &model.Service{
+		ID:            1,
+		ConfigSchema:  datatypes.JSON(`{"required": ["replicas", "logLevel"]}`),
+		DefaultConfig: datatypes.JSONMap{"replicas": 1, "logLevel": "info", "db": map[string]interface{}{"host": "db", "pool": 5}},
+	}
+	env := &model.Environment{ID: 2, Config: datatypes.JSONMap{"replicas": 3, "db": map[string]interface{}{"host": "prod-db"}}}
+	mockServiceRepo.EXPECT().GetByID(ctx, uint(1)).Return(service, nil).AnyTimes()
+	mockEnvRepo.EXPECT().GetByID(ctx, uint(2)).Return(env, nil).AnyTimes()
+
+	var stored *model.ServiceInstance
+	t.Run("Create stores only instance overrides", func(t *testing.T) {
+		mockInstanceRepo.EXPECT().CheckExists(ctx, uint(1), uint(2), "1.0.0", uint(0)).Return(false, nil)
+		mockInstanceRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, inst *model.ServiceInstance) error {
+			inst.ID = 4
+			stored = inst
+			return nil
+		})
+
+		_, err := svc.CreateServiceInstance(ctx, &ServiceInstanceInputDTO{
+			ServiceID: 1, EnvironmentID: 2, Version: "1.0.0", Status: string(model.ServiceInstanceStatusRunning),
+			// The caller sends the full config; inherited values are dropped.
+			Config: datatypes.JSONMap{"replicas": 3, "logLevel": "debug", "db": map[string]interface{}{"host": "prod-db", "pool": 10}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, datatypes.JSONMap{"logLevel": "debug", "db": map[string]interface{}{"pool": float64(10)}}, stored.Config)
+	})
+
+	t.Run("Effective config reports provenance", func(t *testing.T) {
+		mockInstanceRepo.EXPECT().GetByID(ctx, uint(4)).Return(stored, nil)
+
+		effective, err := svc.GetEffectiveConfig(ctx, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"replicas": float64(3),
+			"logLevel": "debug",
+			"db":       map[string]interface{}{"host": "prod-db", "pool": float64(10)},
+		}, effective.Config)
+		assert.Equal(t, map[string]string{
+			"replicas": ConfigLayerEnvironment,
+			"logLevel": ConfigLayerInstance,
+			"db.host":  ConfigLayerEnvironment,
+			"db.pool":  ConfigLayerInstance,
+		}, effective.Provenance)
+	})
+
+	t.Run("Schema validation applies to the effective config", func(t *testing.T) {
+		mockInstanceRepo.EXPECT().CheckExists(ctx, uint(1), uint(2), "1.0.1", uint(0)).Return(false, nil)
+		mockInstanceRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		// Required keys come from the service defaults, so an empty instance config is valid.
+		_, err := svc.CreateServiceInstance(ctx, &ServiceInstanceInputDTO{
+			ServiceID: 1, EnvironmentID: 2, Version: "1.0.1", Status: string(model.ServiceInstanceStatusRunning),
+		})
+		assert.NoError(t, err)
+	})
+}
+
+// TODO: Add tests for GetServiceInstanceByID, ListServiceInstances, DeleteServiceInstance
 // using gomock patterns.
@@ -7,6 +7,7 @@ import (
 
 	"EffiPlat/backend/internal/model"
 	"EffiPlat/backend/internal/repository"
+	apputils "EffiPlat/backend/internal/utils"
 
 	"go.uber.org/zap"
 )
@@ -26,6 +27,16 @@ type ServiceService interface {
 	ListServices(ctx context.Context, params model.ServiceListParams) ([]model.ServiceResponse, *model.PaginatedData, error)
 	UpdateService(ctx context.Context, id uint, req model.UpdateServiceRequest) (*model.ServiceResponse, error)
 	DeleteService(ctx context.Context, id uint) error
+
+	// ValidateServiceConfig checks a config document against the service's config schema without saving anything.
+	ValidateServiceConfig(ctx context.Context, id uint, config map[string]interface{}) (*ConfigValidationResultDTO, error)
+}
+
+// ConfigValidationResultDTO is the outcome of validating a config against a service's schema.
+type ConfigValidationResultDTO struct {
+	Valid     bool                  `json:"valid"`
+	HasSchema bool                  `json:"hasSchema"`
+	Errors    []apputils.FieldError `json:"errors"`
 }
 
 type serviceService struct {
@@ -205,6 +216,17 @@ func (s *serviceService) CreateService(ctx context.Context, req model.CreateServ
 		return nil, model.ErrServiceNameExists
 	}
 
+	if hasConfigSchema(req.ConfigSchema) {
+		if _, err := compileConfigSchema(req.ConfigSchema); err != nil {
+			s.logger.Warn("Rejected invalid config schema on service creation", zap.String("name", req.Name), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if apputils.HasSecretRefs(req.DefaultConfig) {
+		return nil, fmt.Errorf("%w: secrets can only be set on service instance configs", model.ErrInvalidDefaultConfig)
+	}
+
 	service := &model.Service{
 		Name:          req.Name,
 		Description:   req.Description,
@@ -213,6 +235,12 @@ func (s *serviceService) CreateService(ctx context.Context, req model.CreateServ
 		ExternalLink:  req.ExternalLink,
 		ServiceTypeID: req.ServiceTypeID,
 	}
+	if hasConfigSchema(req.ConfigSchema) {
+		service.ConfigSchema = req.ConfigSchema
+	}
+	if len(req.DefaultConfig) > 0 {
+		service.DefaultConfig = req.DefaultConfig
+	}
 	if service.Status == "" { // Default status if not provided
 		service.Status = model.ServiceStatusUnknown
 	}
@@ -333,6 +361,31 @@ func (s *serviceService) UpdateService(ctx context.Context, id uint, req model.U
 		service.ExternalLink = *req.ExternalLink
 		updated = true
 	}
+	if req.ConfigSchema != nil {
+		if !hasConfigSchema(*req.ConfigSchema) {
+			if service.ConfigSchema != nil {
+				service.ConfigSchema = nil
+				updated = true
+			}
+		} else {
+			if _, err := compileConfigSchema(*req.ConfigSchema); err != nil {
+				s.logger.Warn("Rejected invalid config schema on service update", zap.Uint("id", id), zap.Error(err))
+				return nil, err
+			}
+			service.ConfigSchema = *req.ConfigSchema
+			updated = true
+		}
+	}
+	if req.DefaultConfig != nil && len(apputils.DiffJSON(service.DefaultConfig, *req.DefaultConfig)) > 0 {
+		if apputils.HasSecretRefs(*req.DefaultConfig) {
+			return nil, fmt.Errorf("%w: secrets can only be set on service instance configs", model.ErrInvalidDefaultConfig)
+		}
+		service.DefaultConfig = *req.DefaultConfig
+		if len(service.DefaultConfig) == 0 {
+			service.DefaultConfig = nil
+		}
+		updated = true
+	}
 
 	if !updated {
 		s.logger.Info("No changes detected for service update", zap.Uint("id", id))
@@ -373,3 +426,26 @@ func (s *serviceService) DeleteService(ctx context.Context, id uint) error {
 	s.logger.Info("Service deleted successfully", zap.Uint("id", id))
 	return nil
 }
+
+// ValidateServiceConfig validates config against the service's config schema.
+// Services without a schema accept any config.
+func (s *serviceService) ValidateServiceConfig(ctx context.Context, id uint, config map[string]interface{}) (*ConfigValidationResultDTO, error) {
+	service, err := s.serviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err // Handles ErrServiceNotFound
+	}
+
+	fieldErrors, err := validateConfigAgainstSchema(service.ConfigSchema, config)
+	if err != nil {
+		s.logger.Error("Service has an invalid config schema", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	if fieldErrors == nil {
+		fieldErrors = []apputils.FieldError{}
+	}
+	return &ConfigValidationResultDTO{
+		Valid:     len(fieldErrors) == 0,
+		HasSchema: hasConfigSchema(service.ConfigSchema),
+		Errors:    fieldErrors,
+	}, nil
+}
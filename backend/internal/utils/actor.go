@@ -0,0 +1,47 @@
+package utils
+
+import "context"
+
+// Actor identifies the authenticated user performing an operation.
+type Actor struct {
+	UserID   uint
+	Username string
+	Email    string
+}
+
+type actorContextKey struct{}
+
+// WithActor returns a copy of ctx carrying the given actor.
+// The JWT middleware stores the verified actor on the request context so that
+// services can attribute changes without depending on gin.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx, if any.
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorContextKey{}).(Actor)
+	return actor, ok
+}
+
+// ActorUserID returns a pointer to the actor's user ID, or nil when ctx carries no actor.
+func ActorUserID(ctx context.Context) *uint {
+	actor, ok := ActorFromContext(ctx)
+	if !ok || actor.UserID == 0 {
+		return nil
+	}
+	id := actor.UserID
+	return &id
+}
+
+// ActorName returns the actor's display name, falling back to the email, or "" when unknown.
+func ActorName(ctx context.Context) string {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if actor.Username != "" {
+		return actor.Username
+	}
+	return actor.Email
+}
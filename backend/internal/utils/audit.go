@@ -9,12 +9,16 @@ type AuditActionType string
 
 const (
 	// Audit action types
-	AuditActionCreate AuditActionType = "CREATE"
-	AuditActionRead   AuditActionType = "READ"
-	AuditActionUpdate AuditActionType = "UPDATE"
-	AuditActionDelete AuditActionType = "DELETE"
-	AuditActionLogin  AuditActionType = "LOGIN"
-	AuditActionLogout AuditActionType = "LOGOUT"
+	AuditActionCreate  AuditActionType = "CREATE"
+	AuditActionRead    AuditActionType = "READ"
+	AuditActionUpdate  AuditActionType = "UPDATE"
+	AuditActionDelete  AuditActionType = "DELETE"
+	AuditActionLogin   AuditActionType = "LOGIN"
+	AuditActionLogout  AuditActionType = "LOGOUT"
+	AuditActionReveal  AuditActionType = "REVEAL"  // Viewing a secret value in plain text
+	AuditActionArchive AuditActionType = "ARCHIVE" // Moving expired audit logs to archive files
+	AuditActionRestore AuditActionType = "RESTORE" // Importing archived audit logs back for investigation
+	AuditActionExport  AuditActionType = "EXPORT"  // Downloading data in bulk, e.g. an audit log export
 )
 
 // SetAuditDetails sets operation details to be captured in audit logs
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"strings"
+)
+
+// sensitiveAuditKeys are the normalized key fragments whose values never appear in audit details.
+var sensitiveAuditKeys = []string{"password", "passwd", "secret", "token", "apikey", "privatekey", "credential"}
+
+// ignoredAuditDiffFields are bookkeeping fields that change on every update and are left out of diffs.
+var ignoredAuditDiffFields = map[string]bool{"updatedAt": true, "deletedAt": true}
+
+// IsSensitiveAuditField reports whether a JSON key or dot path names a credential, e.g.
+// "password", "user.passwordHash", "api_key" or "refreshToken".
+func IsSensitiveAuditField(path string) bool {
+	for _, key := range strings.Split(path, ".") {
+		normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+		for _, sensitive := range sensitiveAuditKeys {
+			if strings.Contains(normalized, sensitive) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RedactAuditDetails returns a copy of decoded JSON details in which the values of sensitive
+// keys and encrypted config values are replaced by SecretMask. The input is not modified.
+func RedactAuditDetails(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			if IsSensitiveAuditField(k) && val != nil {
+				out[k] = SecretMask
+				continue
+			}
+			out[k] = RedactAuditDetails(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = RedactAuditDetails(val)
+		}
+		return out
+	default:
+		return maskValue(v)
+	}
+}
+
+// AuditUpdateDiff returns the field-level changes recorded in the decoded details of an UPDATE
+// audit log:
+//   - "before" and "after" snapshots are compared with DiffJSON, leaving out updatedAt/deletedAt;
+//   - otherwise a "changes" list of {field, oldValue, newValue} (e.g. bulk bug updates) is used as is.
+//
+// Sensitive fields are reported as changed, but both values are masked. Details without either
+// shape yield no changes.
+func AuditUpdateDiff(details map[string]interface{}) []JSONChange {
+	changes := make([]JSONChange, 0)
+	before, hasBefore := asJSONObject(details["before"])
+	after, hasAfter := asJSONObject(details["after"])
+	switch {
+	case hasBefore && hasAfter:
+		for _, change := range DiffJSON(before, after) {
+			if !ignoredAuditDiffFields[change.Path[strings.LastIndex(change.Path, ".")+1:]] {
+				changes = append(changes, change)
+			}
+		}
+	default:
+		list, _ := details["changes"].([]interface{})
+		for _, item := range list {
+			obj, ok := asJSONObject(item)
+			if !ok {
+				continue
+			}
+			field, _ := obj["field"].(string)
+			if field == "" {
+				continue
+			}
+			changes = append(changes, JSONChange{Path: field, Op: JSONChangeChanged, OldValue: obj["oldValue"], NewValue: obj["newValue"]})
+		}
+	}
+	for i := range changes {
+		if IsSensitiveAuditField(changes[i].Path) {
+			if changes[i].OldValue != nil {
+				changes[i].OldValue = SecretMask
+			}
+			if changes[i].NewValue != nil {
+				changes[i].NewValue = SecretMask
+			}
+		}
+	}
+	return MaskJSONChanges(changes)
+}
@@ -0,0 +1,23 @@
+package utils
+
+import "context"
+
+// ClientInfo describes where a request came from.
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+type clientInfoContextKey struct{}
+
+// WithClientInfo returns a copy of ctx carrying the given client info.
+// It lets services that do not see the gin context, such as login, record where a request came from.
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoContextKey{}, info)
+}
+
+// ClientInfoFromContext returns the client info stored in ctx, if any.
+func ClientInfoFromContext(ctx context.Context) (ClientInfo, bool) {
+	info, ok := ctx.Value(clientInfoContextKey{}).(ClientInfo)
+	return info, ok
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// JSONChangeOp describes the kind of change found at a JSON path.
+type JSONChangeOp string
+
+const (
+	JSONChangeAdded   JSONChangeOp = "added"
+	JSONChangeRemoved JSONChangeOp = "removed"
+	JSONChangeChanged JSONChangeOp = "changed"
+)
+
+// JSONChange is a single difference between two JSON documents.
+// Path uses dot notation for nested objects, e.g. "database.pool.max".
+type JSONChange struct {
+	Path     string       `json:"path"`
+	Op       JSONChangeOp `json:"op"`
+	OldValue interface{}  `json:"oldValue,omitempty"`
+	NewValue interface{}  `json:"newValue,omitempty"`
+}
+
+// DiffJSON compares two decoded JSON objects and returns their differences sorted by path.
+// Nested objects are compared key by key; arrays and scalars are compared as whole values.
+// Both sides are normalized first, so json.Number and float64 encodings of the same value
+// (e.g. datatypes.JSONMap read from the database vs. a request body) compare equal.
+func DiffJSON(before, after map[string]interface{}) []JSONChange {
+	changes := make([]JSONChange, 0)
+	diffJSONObjects("", normalizeJSONObject(before), normalizeJSONObject(after), &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func diffJSONObjects(prefix string, before, after map[string]interface{}, changes *[]JSONChange) {
+	for key, oldValue := range before {
+		path := joinJSONPath(prefix, key)
+		newValue, ok := after[key]
+		if !ok {
+			*changes = append(*changes, JSONChange{Path: path, Op: JSONChangeRemoved, OldValue: oldValue})
+			continue
+		}
+		oldObj, oldIsObj := asJSONObject(oldValue)
+		newObj, newIsObj := asJSONObject(newValue)
+		if oldIsObj && newIsObj {
+			diffJSONObjects(path, oldObj, newObj, changes)
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			*changes = append(*changes, JSONChange{Path: path, Op: JSONChangeChanged, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	for key, newValue := range after {
+		if _, ok := before[key]; !ok {
+			*changes = append(*changes, JSONChange{Path: joinJSONPath(prefix, key), Op: JSONChangeAdded, NewValue: newValue})
+		}
+	}
+}
+
+// normalizeJSONObject round-trips m through encoding/json so that values use the
+// standard decoded types (float64, string, bool, []interface{}, map[string]interface{}).
+func normalizeJSONObject(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return m
+	}
+	out := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return m
+	}
+	return out
+}
+
+func asJSONObject(v interface{}) (map[string]interface{}, bool) {
+	m, ok := v.(map[string]interface{})
+	return m, ok
+}
+
+func joinJSONPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return strings.Join([]string{prefix, key}, ".")
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+)
+
+// JSONLayer is one named level of a layered JSON document, e.g. defaults or overrides.
+type JSONLayer struct {
+	Name string
+	Doc  map[string]interface{}
+}
+
+// MergeJSONLayers deep-merges layers in order, later layers winning. Nested objects are
+// merged key by key; any other value (including arrays and null) replaces what was below it.
+// It also returns, for every leaf dot path of the result, the name of the layer it came from.
+func MergeJSONLayers(layers ...JSONLayer) (map[string]interface{}, map[string]string) {
+	merged := make(map[string]interface{})
+	provenance := make(map[string]string)
+	for _, layer := range layers {
+		mergeJSONLayer(merged, normalizeJSONObject(layer.Doc), "", layer.Name, provenance)
+	}
+	return merged, provenance
+}
+
+func mergeJSONLayer(dst, src map[string]interface{}, prefix, name string, provenance map[string]string) {
+	for key, value := range src {
+		path := joinJSONPath(prefix, key)
+		srcObj, srcIsObj := asJSONObject(value)
+		dstObj, dstIsObj := asJSONObject(dst[key])
+		if srcIsObj && dstIsObj {
+			mergeJSONLayer(dstObj, srcObj, path, name, provenance)
+			continue
+		}
+		clearJSONProvenance(provenance, path)
+		if srcIsObj {
+			copied := make(map[string]interface{}, len(srcObj))
+			dst[key] = copied
+			if len(srcObj) == 0 {
+				provenance[path] = name
+			}
+			mergeJSONLayer(copied, srcObj, path, name, provenance)
+			continue
+		}
+		dst[key] = value
+		provenance[path] = name
+	}
+}
+
+// clearJSONProvenance drops path and everything below it, for when a value is replaced wholesale.
+func clearJSONProvenance(provenance map[string]string, path string) {
+	delete(provenance, path)
+	for p := range provenance {
+		if strings.HasPrefix(p, path+".") {
+			delete(provenance, p)
+		}
+	}
+}
+
+// StripInheritedJSON returns the part of doc that differs from inherited, i.e. the minimal
+// override that MergeJSONLayers would turn back into the same result. Objects are compared
+// key by key; an object left empty after stripping is dropped.
+func StripInheritedJSON(doc, inherited map[string]interface{}) map[string]interface{} {
+	if doc == nil {
+		return nil
+	}
+	return stripInheritedJSON(normalizeJSONObject(doc), normalizeJSONObject(inherited))
+}
+
+func stripInheritedJSON(doc, inherited map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for key, value := range doc {
+		base, inheritedHasKey := inherited[key]
+		if !inheritedHasKey {
+			out[key] = value
+			continue
+		}
+		valueObj, valueIsObj := asJSONObject(value)
+		baseObj, baseIsObj := asJSONObject(base)
+		if valueIsObj && baseIsObj {
+			if rest := stripInheritedJSON(valueObj, baseObj); len(rest) > 0 {
+				out[key] = rest
+			}
+			continue
+		}
+		if !reflect.DeepEqual(value, base) {
+			out[key] = value
+		}
+	}
+	return out
+}
@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	// SecretMask replaces secret values in API responses and audit details.
+	// Sending it back unchanged on update keeps the stored secret.
+	SecretMask = "******"
+	// SecretRefPrefix marks a config value as secret inline, e.g. "secret://s3cr3t".
+	SecretRefPrefix = "secret://"
+	// encryptedSecretPrefix tags ciphertext stored in config documents so it can be
+	// recognised (and masked) without knowing the schema.
+	encryptedSecretPrefix = "enc:v1:"
+)
+
+// ErrInvalidSecret is returned when a stored secret cannot be decrypted.
+var ErrInvalidSecret = errors.New("invalid encrypted secret")
+
+// SecretCipher encrypts config secret values with AES-256-GCM.
+type SecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewSecretCipher creates a SecretCipher. The key may be any non-empty byte string;
+// it is stretched to 32 bytes with SHA-256.
+func NewSecretCipher(key []byte) (*SecretCipher, error) {
+	if len(key) == 0 {
+		return nil, errors.New("secret cipher key must not be empty")
+	}
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secret cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secret cipher: %w", err)
+	}
+	return &SecretCipher{aead: aead}, nil
+}
+
+// Encrypt returns the tagged ciphertext for plaintext.
+func (c *SecretCipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedSecretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt.
+func (c *SecretCipher) Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedSecretPrefix) {
+		return "", ErrInvalidSecret
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedSecretPrefix))
+	if err != nil || len(raw) < c.aead.NonceSize() {
+		return "", ErrInvalidSecret
+	}
+	nonce, ciphertext := raw[:c.aead.NonceSize()], raw[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrInvalidSecret
+	}
+	return string(plaintext), nil
+}
+
+// IsEncryptedSecret reports whether v is ciphertext produced by SecretCipher.Encrypt.
+func IsEncryptedSecret(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && strings.HasPrefix(s, encryptedSecretPrefix)
+}
+
+// MaskSecrets returns a copy of config with every encrypted value replaced by SecretMask.
+// The input is not modified.
+func MaskSecrets(config map[string]interface{}) map[string]interface{} {
+	if config == nil {
+		return nil
+	}
+	return maskValue(config).(map[string]interface{})
+}
+
+func maskValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			out[k] = maskValue(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = maskValue(val)
+		}
+		return out
+	default:
+		if IsEncryptedSecret(v) {
+			return SecretMask
+		}
+		return v
+	}
+}
+
+// SecretPaths returns the sorted dot paths of all encrypted values in config.
+func SecretPaths(config map[string]interface{}) []string {
+	paths := make([]string, 0)
+	collectSecretPaths(config, "", &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+func collectSecretPaths(v interface{}, path string, paths *[]string) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			collectSecretPaths(val, joinJSONPath(path, k), paths)
+		}
+	default:
+		if IsEncryptedSecret(v) {
+			*paths = append(*paths, path)
+		}
+	}
+}
+
+// LookupJSONPath returns the value at a dot path inside nested objects.
+func LookupJSONPath(doc map[string]interface{}, path string) (interface{}, bool) {
+	var cur interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// MaskJSONChanges masks encrypted values in a diff. A secret that changed still shows up as
+// a change, but neither side reveals its value.
+func MaskJSONChanges(changes []JSONChange) []JSONChange {
+	for i := range changes {
+		changes[i].OldValue = maskValue(changes[i].OldValue)
+		changes[i].NewValue = maskValue(changes[i].NewValue)
+	}
+	return changes
+}
+
+// HasSecretRefs reports whether doc contains a "secret://" value anywhere, including inside
+// arrays. Only instance configs are encrypted, so other config layers use this to refuse secrets.
+func HasSecretRefs(doc map[string]interface{}) bool {
+	return ContainsSecretRef(doc)
+}
+
+// ContainsSecretRef reports whether v is, or contains, a "secret://" value.
+func ContainsSecretRef(v interface{}) bool {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, val := range t {
+			if ContainsSecretRef(val) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, val := range t {
+			if ContainsSecretRef(val) {
+				return true
+			}
+		}
+	case string:
+		return strings.HasPrefix(t, SecretRefPrefix)
+	}
+	return false
+}
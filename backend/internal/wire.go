@@ -11,8 +11,11 @@ package internal
 
 import (
 	"EffiPlat/backend/internal/handler"
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/pkg/storage"
 	"EffiPlat/backend/internal/repository"
 	"EffiPlat/backend/internal/service"
+	apputils "EffiPlat/backend/internal/utils"
 
 	"github.com/google/wire"
 	"go.uber.org/zap"
@@ -33,7 +36,7 @@ var UserSet = wire.NewSet(
 // InitializeUserHandler is the injector for UserHandler and its dependencies.
 // It takes the database connection as input.
 // This function will be callable from other packages (like main) because it's exported.
-func InitializeUserHandler(db *gorm.DB, logger *zap.Logger) (*handler.UserHandler, error) {
+func InitializeUserHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.UserHandler, error) {
 	wire.Build(
 		UserSet,
 	)
@@ -50,7 +53,7 @@ var RoleSet = wire.NewSet(
 )
 
 // InitializeRoleHandler is the injector for RoleHandler and its dependencies.
-func InitializeRoleHandler(db *gorm.DB, logger *zap.Logger) (*handler.RoleHandler, error) {
+func InitializeRoleHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.RoleHandler, error) {
 	wire.Build(
 		RoleSet,
 		// We provide db and logger as parameters to this injector, so they are available
@@ -71,7 +74,7 @@ var AuthSet = wire.NewSet(
 // Make sure it has the //go:build wireinject tags if it's in a wireinject file.
 // If wire.go is itself a wireinject file (based on build tags at the top),
 // then this function template is fine.
-func InitializeAuthHandler(db *gorm.DB, jwtKey []byte, logger *zap.Logger) (*handler.AuthHandler, error) {
+func InitializeAuthHandler(db *gorm.DB, jwtKey []byte, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.AuthHandler, error) {
 	wire.Build(
 		AuthSet,
 		// If NewUserRepository needs logger, and logger is provided to InitializeAuthHandler,
@@ -99,7 +102,7 @@ var PermissionSet = wire.NewSet(
 )
 
 // InitializePermissionHandler is the injector for PermissionHandler and its dependencies.
-func InitializePermissionHandler(db *gorm.DB, logger *zap.Logger) (*handler.PermissionHandler, error) {
+func InitializePermissionHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.PermissionHandler, error) {
 	wire.Build(
 		PermissionSet,
 	)
@@ -114,7 +117,7 @@ var ResponsibilitySet = wire.NewSet(
 )
 
 // InitializeResponsibilityHandler is the injector for ResponsibilityHandler and its dependencies.
-func InitializeResponsibilityHandler(db *gorm.DB, logger *zap.Logger) (*handler.ResponsibilityHandler, error) {
+func InitializeResponsibilityHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.ResponsibilityHandler, error) {
 	wire.Build(
 		ResponsibilitySet,
 	)
@@ -130,7 +133,7 @@ var ResponsibilityGroupSet = wire.NewSet(
 )
 
 // InitializeResponsibilityGroupHandler is the injector for ResponsibilityGroupHandler and its dependencies.
-func InitializeResponsibilityGroupHandler(db *gorm.DB, logger *zap.Logger) (*handler.ResponsibilityGroupHandler, error) {
+func InitializeResponsibilityGroupHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.ResponsibilityGroupHandler, error) {
 	wire.Build(
 		ResponsibilityGroupSet,
 	)
@@ -145,7 +148,7 @@ var EnvironmentSet = wire.NewSet(
 )
 
 // InitializeEnvironmentHandler is the injector for EnvironmentHandler and its dependencies.
-func InitializeEnvironmentHandler(db *gorm.DB, logger *zap.Logger) (*handler.EnvironmentHandler, error) {
+func InitializeEnvironmentHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.EnvironmentHandler, error) {
 	wire.Build(
 		EnvironmentSet,
 	)
@@ -168,7 +171,7 @@ var AssetSet = wire.NewSet(
 )
 
 // InitializeAssetHandler is the injector for AssetHandler and its dependencies.
-func InitializeAssetHandler(db *gorm.DB, logger *zap.Logger, envRepo repository.EnvironmentRepository) (*handler.AssetHandler, error) {
+func InitializeAssetHandler(db *gorm.DB, logger *zap.Logger, envRepo repository.EnvironmentRepository, auditLogService service.AuditLogService) (*handler.AssetHandler, error) {
 	wire.Build(
 		AssetSet,
 	)
@@ -184,7 +187,7 @@ var ServiceSet = wire.NewSet(
 )
 
 // InitializeServiceHandler is the injector for ServiceHandler and its dependencies.
-func InitializeServiceHandler(db *gorm.DB, logger *zap.Logger) (*handler.ServiceHandler, error) {
+func InitializeServiceHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.ServiceHandler, error) {
 	wire.Build(
 		ServiceSet,
 	)
@@ -210,6 +213,10 @@ func InitializeServiceTypeRepository(db *gorm.DB) (repository.ServiceTypeReposit
 // ProviderSet for service instance components
 var ServiceInstanceSet = wire.NewSet(
 	repository.NewServiceInstanceRepository,
+	repository.NewDeploymentRepository,
+	repository.NewConfigRevisionRepository,
+	repository.NewPermissionRepository,
+	wire.Bind(new(repository.PermissionRepository), new(*repository.PermissionRepositoryImpl)),
 	service.NewServiceInstanceService,
 	handler.NewServiceInstanceHandler,
 	// We need ServiceRepository and EnvironmentRepository for NewServiceInstanceService
@@ -222,6 +229,8 @@ func InitializeServiceInstanceHandler(
 	logger *zap.Logger,
 	serviceRepo repository.ServiceRepository,
 	envRepo repository.EnvironmentRepository,
+	secretCipher *apputils.SecretCipher,
+	auditLogService service.AuditLogService,
 ) (*handler.ServiceInstanceHandler, error) {
 	wire.Build(
 		ServiceInstanceSet,
@@ -233,6 +242,66 @@ func InitializeServiceInstanceHandler(
 	return nil, nil // Wire will replace this
 }
 
+// ProviderSet for deployment history components
+var DeploymentSet = wire.NewSet(
+	repository.NewDeploymentRepository,
+	repository.NewServiceInstanceRepository,
+	service.NewDeploymentService,
+	handler.NewDeploymentHandler,
+)
+
+// InitializeDeploymentHandler is the injector for DeploymentHandler.
+func InitializeDeploymentHandler(
+	db *gorm.DB,
+	logger *zap.Logger,
+	envRepo repository.EnvironmentRepository,
+	auditLogService service.AuditLogService,
+) (*handler.DeploymentHandler, error) {
+	wire.Build(
+		DeploymentSet,
+	)
+	return nil, nil // Wire will replace this
+}
+
+// ProviderSet for config revision components
+var ConfigRevisionSet = wire.NewSet(
+	repository.NewConfigRevisionRepository,
+	repository.NewServiceInstanceRepository,
+	service.NewConfigRevisionService,
+	handler.NewConfigRevisionHandler,
+)
+
+// InitializeConfigRevisionHandler is the injector for ConfigRevisionHandler.
+func InitializeConfigRevisionHandler(
+	db *gorm.DB,
+	logger *zap.Logger,
+	serviceRepo repository.ServiceRepository,
+	envRepo repository.EnvironmentRepository,
+	secretCipher *apputils.SecretCipher,
+	auditLogService service.AuditLogService,
+) (*handler.ConfigRevisionHandler, error) {
+	wire.Build(
+		ConfigRevisionSet,
+	)
+	return nil, nil // Wire will replace this
+}
+
+// ProviderSet for entity history components
+var EntityHistorySet = wire.NewSet(
+	repository.NewEntityHistoryRepository,
+	service.NewEntityHistoryService,
+	handler.NewEntityHistoryHandler,
+)
+
+// InitializeEntityHistoryHandler is the injector for EntityHistoryHandler.
+// Creating it registers the database callbacks that record entity versions.
+func InitializeEntityHistoryHandler(db *gorm.DB, logger *zap.Logger) (*handler.EntityHistoryHandler, error) {
+	wire.Build(
+		EntityHistorySet,
+	)
+	return nil, nil // Wire will replace this
+}
+
 // 环境组件的Provider Set和Initialize函数已在上方定义
 
 // ProviderSet for business components
@@ -253,38 +322,155 @@ func InitializeBusinessHandler(db *gorm.DB, logger *zap.Logger) (*handler.Busine
 // ProviderSet for bug management components
 var BugSet = wire.NewSet(
 	repository.NewBugRepository,
+	repository.NewServiceInstanceRepository,
+	repository.NewBusinessRepository,
+	repository.NewUserRepository,
+	wire.Bind(new(service.BugAssigner), new(service.BugAssignmentService)),
+	wire.Bind(new(service.BugSLAPolicySource), new(service.BugSLAService)),
 	service.NewBugService,
 	handler.NewBugHandler,
 )
 
+// ProviderSet for bug assignment rule components
+var BugAssignmentSet = wire.NewSet(
+	repository.NewBugAssignmentRuleRepository,
+	repository.NewGormResponsibilityGroupRepository,
+	repository.NewBusinessRepository,
+	service.NewBugAssignmentService,
+)
+
+// ProviderSet for bug SLA components
+var BugSLASet = wire.NewSet(
+	repository.NewBugSLAPolicyRepository,
+	repository.NewBugRepository,
+	repository.NewBugAssignmentRuleRepository,
+	repository.NewGormResponsibilityGroupRepository,
+	service.NewBugSLAService,
+)
+
 // ProviderSet for audit log components
 var AuditLogSet = wire.NewSet(
 	repository.NewAuditLogRepository,
+	repository.NewPermissionRepository,
+	wire.Bind(new(repository.PermissionRepository), new(*repository.PermissionRepositoryImpl)),
+	service.NewAuditPipeline,
 	service.NewAuditLogService,
-	handler.NewAuditLogHandler,
+)
+
+// ProviderSet for audit log archive components
+var AuditArchiveSet = wire.NewSet(
+	repository.NewAuditLogRepository,
+	service.NewAuditArchiveService,
 )
 
 // InitializeBugHandler is the injector for BugHandler and its dependencies.
-func InitializeBugHandler(db *gorm.DB, logger *zap.Logger) (*handler.BugHandler, error) {
+func InitializeBugHandler(
+	db *gorm.DB,
+	logger *zap.Logger,
+	serviceRepo repository.ServiceRepository,
+	envRepo repository.EnvironmentRepository,
+	bugAssignmentService service.BugAssignmentService,
+	bugSLAService service.BugSLAService,
+	workflow *model.BugWorkflow,
+	auditLogService service.AuditLogService,
+) (*handler.BugHandler, error) {
 	wire.Build(
 		BugSet,
 	)
 	return nil, nil // Wire will replace this
 }
 
+// InitializeBugAssignmentService is the injector for BugAssignmentService.
+func InitializeBugAssignmentService(
+	db *gorm.DB,
+	logger *zap.Logger,
+	serviceRepo repository.ServiceRepository,
+	envRepo repository.EnvironmentRepository,
+) (service.BugAssignmentService, error) {
+	wire.Build(
+		BugAssignmentSet,
+	)
+	return nil, nil // Wire will replace this
+}
+
+// InitializeBugAssignmentHandler is the injector for BugAssignmentHandler.
+func InitializeBugAssignmentHandler(db *gorm.DB, logger *zap.Logger, bugAssignmentService service.BugAssignmentService, auditLogService service.AuditLogService) (*handler.BugAssignmentHandler, error) {
+	wire.Build(
+		handler.NewBugAssignmentHandler,
+	)
+	return nil, nil // Wire will replace this
+}
+
+// InitializeBugSLAService is the injector for BugSLAService.
+func InitializeBugSLAService(db *gorm.DB, logger *zap.Logger) (service.BugSLAService, error) {
+	wire.Build(
+		BugSLASet,
+	)
+	return nil, nil // Wire will replace this
+}
+
+// InitializeBugSLAHandler is the injector for BugSLAHandler.
+func InitializeBugSLAHandler(db *gorm.DB, logger *zap.Logger, bugSLAService service.BugSLAService, auditLogService service.AuditLogService) (*handler.BugSLAHandler, error) {
+	wire.Build(
+		handler.NewBugSLAHandler,
+	)
+	return nil, nil // Wire will replace this
+}
+
+// ProviderSet for attachment components
+var AttachmentSet = wire.NewSet(
+	repository.NewAttachmentRepository,
+	repository.NewBugRepository,
+	repository.NewBusinessRepository,
+	repository.NewPermissionRepository,
+	wire.Bind(new(repository.PermissionRepository), new(*repository.PermissionRepositoryImpl)),
+	service.NewAttachmentService,
+	handler.NewAttachmentHandler,
+)
+
+// InitializeAttachmentHandler is the injector for AttachmentHandler.
+func InitializeAttachmentHandler(
+	db *gorm.DB,
+	logger *zap.Logger,
+	serviceRepo repository.ServiceRepository,
+	store storage.Storage,
+	limits service.AttachmentLimits,
+	auditLogService service.AuditLogService,
+) (*handler.AttachmentHandler, error) {
+	wire.Build(
+		AttachmentSet,
+	)
+	return nil, nil // Wire will replace this
+}
+
 // InitializeAuditLogHandler is the injector for AuditLogHandler and its dependencies.
-func InitializeAuditLogHandler(db *gorm.DB, logger *zap.Logger) (*handler.AuditLogHandler, error) {
+func InitializeAuditLogHandler(logger *zap.Logger, auditLogService service.AuditLogService, auditArchiveService service.AuditArchiveService) (*handler.AuditLogHandler, error) {
 	wire.Build(
-		AuditLogSet,
+		handler.NewAuditLogHandler,
 	)
 	return nil, nil // Wire will replace this
 }
 
 // InitializeAuditLogService is the injector for AuditLogService.
-func InitializeAuditLogService(db *gorm.DB, logger *zap.Logger) (service.AuditLogService, error) {
+// Create it once and pass it to the other injectors, so that all audit logs share one write pipeline.
+func InitializeAuditLogService(db *gorm.DB, hashKey repository.AuditHashKey, logger *zap.Logger, pipelineConfig service.AuditPipelineConfig) (service.AuditLogService, error) {
+	wire.Build(
+		AuditLogSet,
+	)
+	return nil, nil // Wire will replace this
+}
+
+// InitializeAuditArchiveService is the injector for AuditArchiveService.
+func InitializeAuditArchiveService(
+	db *gorm.DB,
+	hashKey repository.AuditHashKey,
+	logger *zap.Logger,
+	store storage.Storage,
+	policy service.AuditRetentionPolicy,
+	auditLogService service.AuditLogService,
+) (service.AuditArchiveService, error) {
 	wire.Build(
-		repository.NewAuditLogRepository,
-		service.NewAuditLogService,
+		AuditArchiveSet,
 	)
 	return nil, nil // Wire will replace this
 }
@@ -8,8 +8,11 @@ package internal
 
 import (
 	"EffiPlat/backend/internal/handler"
+	"EffiPlat/backend/internal/model"
+	"EffiPlat/backend/internal/pkg/storage"
 	"EffiPlat/backend/internal/repository"
 	"EffiPlat/backend/internal/service"
+	"EffiPlat/backend/internal/utils"
 	"github.com/google/wire"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -20,22 +23,18 @@ import (
 // InitializeUserHandler is the injector for UserHandler and its dependencies.
 // It takes the database connection as input.
 // This function will be callable from other packages (like main) because it's exported.
-func InitializeUserHandler(db *gorm.DB, logger *zap.Logger) (*handler.UserHandler, error) {
+func InitializeUserHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.UserHandler, error) {
 	userRepository := repository.NewUserRepository(db, logger)
 	roleRepositoryImpl := repository.NewRoleRepository(db, logger)
 	userService := service.NewUserService(userRepository, roleRepositoryImpl, logger)
-	auditLogRepository := repository.NewAuditLogRepository(db, logger)
-	auditLogService := service.NewAuditLogService(auditLogRepository, logger)
 	userHandler := handler.NewUserHandler(userService, auditLogService, logger)
 	return userHandler, nil
 }
 
 // InitializeRoleHandler is the injector for RoleHandler and its dependencies.
-func InitializeRoleHandler(db *gorm.DB, logger *zap.Logger) (*handler.RoleHandler, error) {
+func InitializeRoleHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.RoleHandler, error) {
 	roleRepositoryImpl := repository.NewRoleRepository(db, logger)
 	roleServiceImpl := service.NewRoleService(roleRepositoryImpl, logger)
-	auditLogRepository := repository.NewAuditLogRepository(db, logger)
-	auditLogService := service.NewAuditLogService(auditLogRepository, logger)
 	roleHandler := handler.NewRoleHandler(roleServiceImpl, auditLogService, logger)
 	return roleHandler, nil
 }
@@ -44,54 +43,43 @@ func InitializeRoleHandler(db *gorm.DB, logger *zap.Logger) (*handler.RoleHandle
 // Make sure it has the //go:build wireinject tags if it's in a wireinject file.
 // If wire.go is itself a wireinject file (based on build tags at the top),
 // then this function template is fine.
-func InitializeAuthHandler(db *gorm.DB, jwtKey []byte, logger *zap.Logger) (*handler.AuthHandler, error) {
+func InitializeAuthHandler(db *gorm.DB, jwtKey []byte, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.AuthHandler, error) {
 	userRepository := repository.NewUserRepository(db, logger)
-	authService := service.NewAuthService(userRepository, jwtKey, logger)
+	authService := service.NewAuthService(userRepository, jwtKey, auditLogService, logger)
 	authHandler := handler.NewAuthHandler(authService)
 	return authHandler, nil
 }
 
 // InitializePermissionHandler is the injector for PermissionHandler and its dependencies.
-func InitializePermissionHandler(db *gorm.DB, logger *zap.Logger) (*handler.PermissionHandler, error) {
+func InitializePermissionHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.PermissionHandler, error) {
 	permissionRepositoryImpl := repository.NewPermissionRepository(db, logger)
 	roleRepositoryImpl := repository.NewRoleRepository(db, logger)
-	auditLogRepositoryImpl := repository.NewAuditLogRepository(db, logger)
-	auditLogService := service.NewAuditLogService(auditLogRepositoryImpl, logger)
 	permissionService := service.NewPermissionService(permissionRepositoryImpl, roleRepositoryImpl, logger)
 	permissionHandler := handler.NewPermissionHandler(permissionService, auditLogService, logger)
 	return permissionHandler, nil
 }
 
 // InitializeResponsibilityHandler is the injector for ResponsibilityHandler and its dependencies.
-func InitializeResponsibilityHandler(db *gorm.DB, logger *zap.Logger) (*handler.ResponsibilityHandler, error) {
+func InitializeResponsibilityHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.ResponsibilityHandler, error) {
 	responsibilityRepository := repository.NewGormResponsibilityRepository(db, logger)
-	auditLogRepositoryImpl := repository.NewAuditLogRepository(db, logger)
-	auditLogService := service.NewAuditLogService(auditLogRepositoryImpl, logger)
 	responsibilityService := service.NewResponsibilityService(responsibilityRepository, logger)
 	responsibilityHandler := handler.NewResponsibilityHandler(responsibilityService, auditLogService, logger)
 	return responsibilityHandler, nil
 }
 
 // InitializeResponsibilityGroupHandler is the injector for ResponsibilityGroupHandler and its dependencies.
-func InitializeResponsibilityGroupHandler(db *gorm.DB, logger *zap.Logger) (*handler.ResponsibilityGroupHandler, error) {
+func InitializeResponsibilityGroupHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.ResponsibilityGroupHandler, error) {
 	responsibilityGroupRepository := repository.NewGormResponsibilityGroupRepository(db, logger)
 	responsibilityRepository := repository.NewGormResponsibilityRepository(db, logger)
-	auditLogRepositoryImpl := repository.NewAuditLogRepository(db, logger)
-	auditLogService := service.NewAuditLogService(auditLogRepositoryImpl, logger)
 	responsibilityGroupService := service.NewResponsibilityGroupService(responsibilityGroupRepository, responsibilityRepository, logger)
 	responsibilityGroupHandler := handler.NewResponsibilityGroupHandler(responsibilityGroupService, auditLogService, logger)
 	return responsibilityGroupHandler, nil
 }
 
 // InitializeEnvironmentHandler is the injector for EnvironmentHandler and its dependencies.
-func InitializeEnvironmentHandler(db *gorm.DB, logger *zap.Logger) (*handler.EnvironmentHandler, error) {
+func InitializeEnvironmentHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.EnvironmentHandler, error) {
 	environmentRepository := repository.NewGormEnvironmentRepository(db, logger)
 	environmentService := service.NewEnvironmentService(environmentRepository, logger)
-	
-	// 添加审计日志服务
-	auditLogRepository := repository.NewAuditLogRepository(db, logger)
-	auditLogService := service.NewAuditLogService(auditLogRepository, logger)
-	
 	environmentHandler := handler.NewEnvironmentHandler(environmentService, auditLogService, logger)
 	return environmentHandler, nil
 }
@@ -103,28 +91,18 @@ func InitializeEnvironmentRepository(db *gorm.DB, logger *zap.Logger) (repositor
 }
 
 // InitializeAssetHandler is the injector for AssetHandler and its dependencies.
-func InitializeAssetHandler(db *gorm.DB, logger *zap.Logger, envRepo repository.EnvironmentRepository) (*handler.AssetHandler, error) {
+func InitializeAssetHandler(db *gorm.DB, logger *zap.Logger, envRepo repository.EnvironmentRepository, auditLogService service.AuditLogService) (*handler.AssetHandler, error) {
 	assetRepository := repository.NewGormAssetRepository(db, logger)
 	assetService := service.NewAssetService(assetRepository, envRepo, logger)
-	
-	// 添加审计日志服务
-	auditLogRepository := repository.NewAuditLogRepository(db, logger)
-	auditLogService := service.NewAuditLogService(auditLogRepository, logger)
-	
 	assetHandler := handler.NewAssetHandler(assetService, auditLogService, logger)
 	return assetHandler, nil
 }
 
 // InitializeServiceHandler is the injector for ServiceHandler and its dependencies.
-func InitializeServiceHandler(db *gorm.DB, logger *zap.Logger) (*handler.ServiceHandler, error) {
+func InitializeServiceHandler(db *gorm.DB, logger *zap.Logger, auditLogService service.AuditLogService) (*handler.ServiceHandler, error) {
 	serviceRepository := repository.NewGormServiceRepository(db)
 	serviceTypeRepository := repository.NewGormServiceTypeRepository(db)
 	serviceService := service.NewServiceService(serviceRepository, serviceTypeRepository, logger)
-	
-	// 添加审计日志服务
-	auditLogRepository := repository.NewAuditLogRepository(db, logger)
-	auditLogService := service.NewAuditLogService(auditLogRepository, logger)
-	
 	serviceHandler := handler.NewServiceHandler(serviceService, auditLogService, logger)
 	return serviceHandler, nil
 }
@@ -142,15 +120,43 @@ func InitializeServiceTypeRepository(db *gorm.DB) (repository.ServiceTypeReposit
 }
 
 // InitializeServiceInstanceHandler is the injector for ServiceInstanceHandler.
-func InitializeServiceInstanceHandler(db *gorm.DB, logger *zap.Logger, serviceRepo repository.ServiceRepository, envRepo repository.EnvironmentRepository) (*handler.ServiceInstanceHandler, error) {
+func InitializeServiceInstanceHandler(db *gorm.DB, logger *zap.Logger, serviceRepo repository.ServiceRepository, envRepo repository.EnvironmentRepository, secretCipher *utils.SecretCipher, auditLogService service.AuditLogService) (*handler.ServiceInstanceHandler, error) {
 	serviceInstanceRepository := repository.NewServiceInstanceRepository(db, logger)
-	serviceInstanceService := service.NewServiceInstanceService(serviceInstanceRepository, serviceRepo, envRepo, logger)
-	auditLogRepository := repository.NewAuditLogRepository(db, logger)
-	auditLogService := service.NewAuditLogService(auditLogRepository, logger)
+	deploymentRepository := repository.NewDeploymentRepository(db, logger)
+	configRevisionRepository := repository.NewConfigRevisionRepository(db, logger)
+	permissionRepositoryImpl := repository.NewPermissionRepository(db, logger)
+	serviceInstanceService := service.NewServiceInstanceService(serviceInstanceRepository, serviceRepo, envRepo, deploymentRepository, configRevisionRepository, permissionRepositoryImpl, secretCipher, logger)
 	serviceInstanceHandler := handler.NewServiceInstanceHandler(serviceInstanceService, auditLogService, logger)
 	return serviceInstanceHandler, nil
 }
 
+// InitializeDeploymentHandler is the injector for DeploymentHandler.
+func InitializeDeploymentHandler(db *gorm.DB, logger *zap.Logger, envRepo repository.EnvironmentRepository, auditLogService service.AuditLogService) (*handler.DeploymentHandler, error) {
+	deploymentRepository := repository.NewDeploymentRepository(db, logger)
+	serviceInstanceRepository := repository.NewServiceInstanceRepository(db, logger)
+	deploymentService := service.NewDeploymentService(deploymentRepository, serviceInstanceRepository, envRepo, logger)
+	deploymentHandler := handler.NewDeploymentHandler(deploymentService, auditLogService, logger)
+	return deploymentHandler, nil
+}
+
+// InitializeConfigRevisionHandler is the injector for ConfigRevisionHandler.
+func InitializeConfigRevisionHandler(db *gorm.DB, logger *zap.Logger, serviceRepo repository.ServiceRepository, envRepo repository.EnvironmentRepository, secretCipher *utils.SecretCipher, auditLogService service.AuditLogService) (*handler.ConfigRevisionHandler, error) {
+	configRevisionRepository := repository.NewConfigRevisionRepository(db, logger)
+	serviceInstanceRepository := repository.NewServiceInstanceRepository(db, logger)
+	configRevisionService := service.NewConfigRevisionService(configRevisionRepository, serviceInstanceRepository, serviceRepo, envRepo, secretCipher, logger)
+	configRevisionHandler := handler.NewConfigRevisionHandler(configRevisionService, auditLogService, logger)
+	return configRevisionHandler, nil
+}
+
+// InitializeEntityHistoryHandler is the injector for EntityHistoryHandler.
+// Creating it registers the database callbacks that record entity versions.
+func InitializeEntityHistoryHandler(db *gorm.DB, logger *zap.Logger) (*handler.EntityHistoryHandler, error) {
+	entityHistoryRepository := repository.NewEntityHistoryRepository(db, logger)
+	entityHistoryService := service.NewEntityHistoryService(entityHistoryRepository, logger)
+	entityHistoryHandler := handler.NewEntityHistoryHandler(entityHistoryService, logger)
+	return entityHistoryHandler, nil
+}
+
 // InitializeBusinessHandler is the injector for BusinessHandler and its dependencies.
 func InitializeBusinessHandler(db *gorm.DB, logger *zap.Logger) (*handler.BusinessHandler, error) {
 	businessRepository := repository.NewBusinessRepository(db, logger)
@@ -159,29 +165,82 @@ func InitializeBusinessHandler(db *gorm.DB, logger *zap.Logger) (*handler.Busine
 	return businessHandler, nil
 }
 
+// InitializeBugAssignmentService is the injector for BugAssignmentService.
+func InitializeBugAssignmentService(db *gorm.DB, logger *zap.Logger, serviceRepo repository.ServiceRepository, envRepo repository.EnvironmentRepository) (service.BugAssignmentService, error) {
+	bugAssignmentRuleRepository := repository.NewBugAssignmentRuleRepository(db, logger)
+	responsibilityGroupRepository := repository.NewGormResponsibilityGroupRepository(db, logger)
+	businessRepository := repository.NewBusinessRepository(db, logger)
+	bugAssignmentService := service.NewBugAssignmentService(bugAssignmentRuleRepository, responsibilityGroupRepository, envRepo, serviceRepo, businessRepository, logger)
+	return bugAssignmentService, nil
+}
+
+// InitializeBugAssignmentHandler is the injector for BugAssignmentHandler.
+func InitializeBugAssignmentHandler(db *gorm.DB, logger *zap.Logger, bugAssignmentService service.BugAssignmentService, auditLogService service.AuditLogService) (*handler.BugAssignmentHandler, error) {
+	bugAssignmentHandler := handler.NewBugAssignmentHandler(bugAssignmentService, auditLogService, logger)
+	return bugAssignmentHandler, nil
+}
+
+// InitializeBugSLAService is the injector for BugSLAService.
+func InitializeBugSLAService(db *gorm.DB, logger *zap.Logger) (service.BugSLAService, error) {
+	bugSLAPolicyRepository := repository.NewBugSLAPolicyRepository(db, logger)
+	bugRepository := repository.NewBugRepository(db, logger)
+	bugAssignmentRuleRepository := repository.NewBugAssignmentRuleRepository(db, logger)
+	responsibilityGroupRepository := repository.NewGormResponsibilityGroupRepository(db, logger)
+	bugSLAService := service.NewBugSLAService(bugSLAPolicyRepository, bugRepository, bugAssignmentRuleRepository, responsibilityGroupRepository, logger)
+	return bugSLAService, nil
+}
+
+// InitializeBugSLAHandler is the injector for BugSLAHandler.
+func InitializeBugSLAHandler(db *gorm.DB, logger *zap.Logger, bugSLAService service.BugSLAService, auditLogService service.AuditLogService) (*handler.BugSLAHandler, error) {
+	bugSLAHandler := handler.NewBugSLAHandler(bugSLAService, auditLogService, logger)
+	return bugSLAHandler, nil
+}
+
 // InitializeBugHandler is the injector for BugHandler and its dependencies.
-func InitializeBugHandler(db *gorm.DB, logger *zap.Logger) (*handler.BugHandler, error) {
+func InitializeBugHandler(db *gorm.DB, logger *zap.Logger, serviceRepo repository.ServiceRepository, envRepo repository.EnvironmentRepository, bugAssignmentService service.BugAssignmentService, bugSLAService service.BugSLAService, workflow *model.BugWorkflow, auditLogService service.AuditLogService) (*handler.BugHandler, error) {
 	bugRepository := repository.NewBugRepository(db, logger)
-	bugService := service.NewBugService(bugRepository)
-	bugHandler := handler.NewBugHandler(bugService)
+	serviceInstanceRepository := repository.NewServiceInstanceRepository(db, logger)
+	businessRepository := repository.NewBusinessRepository(db, logger)
+	userRepository := repository.NewUserRepository(db, logger)
+	bugService := service.NewBugService(bugRepository, envRepo, serviceRepo, serviceInstanceRepository, businessRepository, userRepository, workflow, bugAssignmentService, bugSLAService)
+	bugHandler := handler.NewBugHandler(bugService, auditLogService)
 	return bugHandler, nil
 }
 
+// InitializeAttachmentHandler is the injector for AttachmentHandler.
+func InitializeAttachmentHandler(db *gorm.DB, logger *zap.Logger, serviceRepo repository.ServiceRepository, store storage.Storage, limits service.AttachmentLimits, auditLogService service.AuditLogService) (*handler.AttachmentHandler, error) {
+	attachmentRepository := repository.NewAttachmentRepository(db, logger)
+	bugRepository := repository.NewBugRepository(db, logger)
+	businessRepository := repository.NewBusinessRepository(db, logger)
+	permissionRepositoryImpl := repository.NewPermissionRepository(db, logger)
+	attachmentService := service.NewAttachmentService(attachmentRepository, bugRepository, businessRepository, serviceRepo, permissionRepositoryImpl, store, limits, logger)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService, auditLogService, logger)
+	return attachmentHandler, nil
+}
+
 // InitializeAuditLogHandler is the injector for AuditLogHandler and its dependencies.
-func InitializeAuditLogHandler(db *gorm.DB, logger *zap.Logger) (*handler.AuditLogHandler, error) {
-	auditLogRepository := repository.NewAuditLogRepository(db, logger)
-	auditLogService := service.NewAuditLogService(auditLogRepository, logger)
-	auditLogHandler := handler.NewAuditLogHandler(auditLogService, logger)
+func InitializeAuditLogHandler(logger *zap.Logger, auditLogService service.AuditLogService, auditArchiveService service.AuditArchiveService) (*handler.AuditLogHandler, error) {
+	auditLogHandler := handler.NewAuditLogHandler(auditLogService, auditArchiveService, logger)
 	return auditLogHandler, nil
 }
 
 // InitializeAuditLogService is the injector for AuditLogService.
-func InitializeAuditLogService(db *gorm.DB, logger *zap.Logger) (service.AuditLogService, error) {
-	auditLogRepository := repository.NewAuditLogRepository(db, logger)
-	auditLogService := service.NewAuditLogService(auditLogRepository, logger)
+// Create it once and pass it to the other injectors, so that all audit logs share one write pipeline.
+func InitializeAuditLogService(db *gorm.DB, hashKey repository.AuditHashKey, logger *zap.Logger, pipelineConfig service.AuditPipelineConfig) (service.AuditLogService, error) {
+	auditLogRepository := repository.NewAuditLogRepository(db, hashKey, logger)
+	auditPipeline := service.NewAuditPipeline(auditLogRepository, pipelineConfig, logger)
+	permissionRepositoryImpl := repository.NewPermissionRepository(db, logger)
+	auditLogService := service.NewAuditLogService(auditLogRepository, auditPipeline, permissionRepositoryImpl, logger)
 	return auditLogService, nil
 }
 
+// InitializeAuditArchiveService is the injector for AuditArchiveService.
+func InitializeAuditArchiveService(db *gorm.DB, hashKey repository.AuditHashKey, logger *zap.Logger, store storage.Storage, policy service.AuditRetentionPolicy, auditLogService service.AuditLogService) (service.AuditArchiveService, error) {
+	auditLogRepository := repository.NewAuditLogRepository(db, hashKey, logger)
+	auditArchiveService := service.NewAuditArchiveService(auditLogRepository, store, auditLogService, policy, logger)
+	return auditArchiveService, nil
+}
+
 // wire.go:
 
 // ProviderSet for user components
@@ -212,13 +271,34 @@ var AssetSet = wire.NewSet(repository.NewGormAssetRepository, service.NewAssetSe
 var ServiceSet = wire.NewSet(repository.NewGormServiceRepository, repository.NewGormServiceTypeRepository, service.NewServiceService, handler.NewServiceHandler)
 
 // ProviderSet for service instance components
-var ServiceInstanceSet = wire.NewSet(repository.NewServiceInstanceRepository, service.NewServiceInstanceService, handler.NewServiceInstanceHandler)
+var ServiceInstanceSet = wire.NewSet(repository.NewServiceInstanceRepository, repository.NewDeploymentRepository, repository.NewConfigRevisionRepository, repository.NewPermissionRepository, wire.Bind(new(repository.PermissionRepository), new(*repository.PermissionRepositoryImpl)), service.NewServiceInstanceService, handler.NewServiceInstanceHandler)
+
+// ProviderSet for deployment history components
+var DeploymentSet = wire.NewSet(repository.NewDeploymentRepository, repository.NewServiceInstanceRepository, service.NewDeploymentService, handler.NewDeploymentHandler)
+
+// ProviderSet for config revision components
+var ConfigRevisionSet = wire.NewSet(repository.NewConfigRevisionRepository, repository.NewServiceInstanceRepository, service.NewConfigRevisionService, handler.NewConfigRevisionHandler)
+
+// ProviderSet for entity history components
+var EntityHistorySet = wire.NewSet(repository.NewEntityHistoryRepository, service.NewEntityHistoryService, handler.NewEntityHistoryHandler)
 
 // ProviderSet for business components
 var BusinessSet = wire.NewSet(repository.NewBusinessRepository, service.NewBusinessService, handler.NewBusinessHandler)
 
 // ProviderSet for bug management components
-var BugSet = wire.NewSet(repository.NewBugRepository, service.NewBugService, handler.NewBugHandler)
+var BugSet = wire.NewSet(repository.NewBugRepository, repository.NewServiceInstanceRepository, repository.NewBusinessRepository, repository.NewUserRepository, wire.Bind(new(service.BugAssigner), new(service.BugAssignmentService)), wire.Bind(new(service.BugSLAPolicySource), new(service.BugSLAService)), service.NewBugService, handler.NewBugHandler)
+
+// ProviderSet for bug assignment rule components
+var BugAssignmentSet = wire.NewSet(repository.NewBugAssignmentRuleRepository, repository.NewGormResponsibilityGroupRepository, repository.NewBusinessRepository, service.NewBugAssignmentService)
+
+// ProviderSet for bug SLA components
+var BugSLASet = wire.NewSet(repository.NewBugSLAPolicyRepository, repository.NewBugRepository, repository.NewBugAssignmentRuleRepository, repository.NewGormResponsibilityGroupRepository, service.NewBugSLAService)
+
+// ProviderSet for attachment components
+var AttachmentSet = wire.NewSet(repository.NewAttachmentRepository, repository.NewBugRepository, repository.NewBusinessRepository, repository.NewPermissionRepository, wire.Bind(new(repository.PermissionRepository), new(*repository.PermissionRepositoryImpl)), service.NewAttachmentService, handler.NewAttachmentHandler)
 
 // ProviderSet for audit log components
-var AuditLogSet = wire.NewSet(repository.NewAuditLogRepository, service.NewAuditLogService, handler.NewAuditLogHandler)
+var AuditLogSet = wire.NewSet(repository.NewAuditLogRepository, repository.NewPermissionRepository, wire.Bind(new(repository.PermissionRepository), new(*repository.PermissionRepositoryImpl)), service.NewAuditPipeline, service.NewAuditLogService)
+
+// ProviderSet for audit log archive components
+var AuditArchiveSet = wire.NewSet(repository.NewAuditLogRepository, service.NewAuditArchiveService)